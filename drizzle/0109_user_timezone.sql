@@ -0,0 +1 @@
+ALTER TABLE "users" ADD COLUMN "timezone" varchar(64);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE "system_settings" ADD COLUMN "min_client_version" varchar(32);
\ No newline at end of file
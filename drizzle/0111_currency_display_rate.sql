@@ -0,0 +1 @@
+ALTER TABLE "system_settings" ADD COLUMN "currency_display_rate" numeric(18, 8) DEFAULT '1' NOT NULL;
\ No newline at end of file
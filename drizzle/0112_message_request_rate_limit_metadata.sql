@@ -0,0 +1 @@
+ALTER TABLE "message_request" ADD COLUMN "rate_limit_metadata" jsonb;
\ No newline at end of file
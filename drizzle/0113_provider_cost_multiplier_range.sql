@@ -0,0 +1,10 @@
+-- Cost multipliers must now be in (0, 100].
+-- Legacy rows with 0 (previously allowed, used for "free" providers) move to 0.0001, the smallest
+-- positive value the numeric(10, 4) column can hold, so they stay effectively free and can be saved
+-- again. Values above 100 are clamped to the new upper bound.
+UPDATE "providers"
+SET "cost_multiplier" = '0.0001'
+WHERE "cost_multiplier" <= 0;--> statement-breakpoint
+UPDATE "providers"
+SET "cost_multiplier" = '100'
+WHERE "cost_multiplier" > 100;
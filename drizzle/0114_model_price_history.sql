@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "model_price_history" (
+	"id" serial PRIMARY KEY NOT NULL,
+	"model_name" varchar NOT NULL,
+	"price_data" jsonb NOT NULL,
+	"source" varchar(20) NOT NULL,
+	"created_at" timestamp with time zone DEFAULT now()
+);
+--> statement-breakpoint
+CREATE INDEX IF NOT EXISTS "idx_model_price_history_model_created" ON "model_price_history" USING btree ("model_name","created_at" DESC NULLS LAST);--> statement-breakpoint
+-- Seed the history with the current price of every model so the first change has a baseline.
+INSERT INTO "model_price_history" ("model_name", "price_data", "source", "created_at")
+SELECT "model_name", "price_data", "source", "created_at" FROM "model_prices";
@@ -938,6 +938,10 @@ export async function getSessionRequests(
       inputTokens: number | null;
       outputTokens: number | null;
       errorMessage: string | null;
+      providerId: number;
+      providerName: string | null;
+      userId: number;
+      userName: string | null;
     }>;
     total: number;
     hasMore: boolean;
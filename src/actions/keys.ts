@@ -1,18 +1,18 @@
 "use server";
 
-import { randomBytes } from "node:crypto";
 import { and, count, eq, inArray, isNull } from "drizzle-orm";
 import { revalidatePath } from "next/cache";
 import { getTranslations } from "next-intl/server";
 import { db } from "@/drizzle/db";
 import { keys as keysTable, users as usersTable } from "@/drizzle/schema";
-import { emitActionAudit } from "@/lib/audit/emit";
+import { buildActionAuditEntry, emitActionAudit } from "@/lib/audit/emit";
 import { type AuthSession, getSession } from "@/lib/auth";
 import { PROVIDER_GROUP } from "@/lib/constants/provider.constants";
 import { logger } from "@/lib/logger";
 import { resolveKeyConcurrentSessionLimit } from "@/lib/rate-limit/concurrent-session-limit";
 import { resolveKeyCostResetAt } from "@/lib/rate-limit/cost-reset-utils";
 import { invalidateCachedKey } from "@/lib/security/api-key-auth-cache";
+import { generateApiKey } from "@/lib/utils/api-key";
 import { parseDateInputAsTimezone } from "@/lib/utils/date-input";
 import { ERROR_CODES } from "@/lib/utils/error-messages";
 import { normalizeProviderGroup, parseProviderGroups } from "@/lib/utils/provider-group";
@@ -209,7 +209,7 @@ export async function addKey(data: {
         // U04: carry a machine-readable code so the self-service REST route can
         // surface the specific reason instead of a generic OPERATION_FAILED.
         errorCode: ERROR_CODES.DUPLICATE_NAME,
-        errorParams: { name: validatedData.name },
+        errorParams: { name: validatedData.name, existingId: existingKey.id },
       };
     }
 
@@ -340,7 +340,7 @@ export async function addKey(data: {
       };
     }
 
-    const generatedKey = `sk-${randomBytes(16).toString("hex")}`;
+    const generatedKey = generateApiKey();
 
     // 转换 expiresAt: undefined → null（永不过期），string → Date（按系统时区解析）
     const timezone = await resolveSystemTimezone();
@@ -516,6 +516,23 @@ export async function editKey(
 
     const validatedData = KeyFormSchema.parse(data);
 
+    // 改名时检查是否与同用户下其它生效 key 重名
+    if (validatedData.name !== key.name) {
+      const existingKey = await findActiveKeyByUserIdAndName(
+        key.userId,
+        validatedData.name,
+        keyId
+      );
+      if (existingKey) {
+        return {
+          ok: false,
+          error: `名为"${validatedData.name}"的密钥已存在且正在生效中，请使用不同的名称`,
+          errorCode: ERROR_CODES.DUPLICATE_NAME,
+          errorParams: { name: validatedData.name, existingId: existingKey.id },
+        };
+      }
+    }
+
     // 服务端验证：Key限额不能超过用户限额
     const { findUserById } = await import("@/repository/user");
     const user = await findUserById(key.userId);
@@ -897,7 +914,8 @@ export async function getKeys(userId: number): Promise<ActionResult<Key[]>> {
 
 // 获取用户密钥的统计信息
 export async function getKeysWithStatistics(
-  userId: number
+  userId: number,
+  options: { includeWarmup?: boolean } = {}
 ): Promise<ActionResult<KeyStatistics[]>> {
   try {
     const session = await getSession();
@@ -910,7 +928,9 @@ export async function getKeysWithStatistics(
       return { ok: false, error: "无权限执行此操作" };
     }
 
-    const stats = await findKeysWithStatistics(userId);
+    const stats = await findKeysWithStatistics(userId, {
+      includeWarmup: options.includeWarmup === true,
+    });
     return { ok: true, data: stats };
   } catch (error) {
     logger.error("获取密钥统计失败:", error);
@@ -1125,7 +1145,7 @@ export async function getKeyLimitUsage(keyId: number): Promise<
   }
 }
 
-export async function resetKeyLimitsOnly(keyId: number): Promise<ActionResult> {
+export async function resetKeyLimitsOnly(keyId: number, reason?: string): Promise<ActionResult> {
   try {
     const tError = await getTranslations("errors");
 
@@ -1147,7 +1167,19 @@ export async function resetKeyLimitsOnly(keyId: number): Promise<ActionResult> {
       };
     }
 
-    const updated = await resetKeyCostResetAt(keyId, new Date());
+    const resetAt = new Date();
+    // 审计记录与重置标记同事务写入，避免出现无审计的重置
+    const audit = await buildActionAuditEntry({
+      category: "key",
+      action: "key.reset_limits",
+      targetType: "key",
+      targetId: String(keyId),
+      targetName: key.name,
+      before: { costResetAt: key.costResetAt ?? null },
+      after: { costResetAt: resetAt, reason: reason?.trim() || null },
+      success: true,
+    });
+    const updated = await resetKeyCostResetAt(keyId, resetAt, audit);
     if (!updated) {
       return {
         ok: false,
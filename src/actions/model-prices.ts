@@ -11,9 +11,10 @@ import {
 } from "@/lib/price-sync/cloud-price-updater";
 import { convertCptTable } from "@/lib/price-sync/cpt-convert";
 import { isCptTableLike, parseCptTableValue } from "@/lib/price-sync/cpt-schema";
+import { ERROR_CODES } from "@/lib/utils/error-messages";
+import { isModelCapability, modelSupportsCapability } from "@/lib/utils/model-capabilities";
 import { isPriceLikeFieldPath } from "@/lib/utils/model-price-fields";
 import {
-  createModelPrice,
   deleteModelPriceByName,
   findAllLatestPrices,
   findAllLatestPricesPaginated,
@@ -24,6 +25,7 @@ import {
   type PaginatedResult,
   type PaginationParams,
   upsertModelPrice,
+  upsertModelPricesBatch,
 } from "@/repository/model-price";
 import type {
   ModelPrice,
@@ -163,6 +165,13 @@ export async function processPriceTableInternal(
       skippedConflicts: [],
     };
 
+    // 新增或变化的模型先收集起来，循环结束后一次性批量写入
+    const pendingWrites: Array<{
+      modelName: string;
+      priceData: ModelPriceData;
+      isNew: boolean;
+    }> = [];
+
     // 处理每个模型的价格
     for (const [rawModelName, priceData] of entries) {
       // 与 manual 记录入库时（upsertModelPrice 使用 trim 后的名称）保持一致地归一化，
@@ -199,16 +208,13 @@ export async function processPriceTableInternal(
 
         if (!existingPrice) {
           // 模型不存在，新增记录
-          await createModelPrice(modelName, priceData, source);
-          result.added.push(modelName);
+          pendingWrites.push({ modelName, priceData, isNew: true });
         } else if (
           existingPrice.source !== source ||
           !isPriceDataEqual(existingPrice.priceData, priceData)
         ) {
-          // 价格或来源发生变化：用事务原子地“删旧 + 插新”替换该模型的所有记录，
-          // 既保证不会在崩溃时丢失价格，又避免同名记录堆积（litellm 孤儿行，或 manual + litellm 并存）。
-          await upsertModelPrice(modelName, priceData, source);
-          result.updated.push(modelName);
+          // 价格或来源发生变化：与新增模型一起批量替换
+          pendingWrites.push({ modelName, priceData, isNew: false });
         } else {
           // 价格未发生变化，不需要更新
           result.unchanged.push(modelName);
@@ -219,6 +225,20 @@ export async function processPriceTableInternal(
       }
     }
 
+    if (pendingWrites.length > 0) {
+      try {
+        // 单个事务内原子地“删旧 + 插新”，既保证不会在崩溃时丢失价格，
+        // 又避免同名记录堆积（litellm 孤儿行，或 manual + litellm 并存）
+        await upsertModelPricesBatch(pendingWrites, source);
+        for (const { modelName, isNew } of pendingWrites) {
+          (isNew ? result.added : result.updated).push(modelName);
+        }
+      } catch (error) {
+        logger.error(`批量写入 ${pendingWrites.length} 个模型价格失败:`, error);
+        result.failed.push(...pendingWrites.map(({ modelName }) => modelName));
+      }
+    }
+
     // 刷新页面数据
     try {
       revalidatePath("/settings/prices");
@@ -453,6 +473,39 @@ export async function getAvailableModelsByProviderType(): Promise<string[]> {
   }
 }
 
+/**
+ * 获取最新价格声明支持指定能力（vision、function_calling 等）的模型名称列表。
+ * feature 必须是 MODEL_CAPABILITY_FIELDS 中的已知能力。
+ */
+export async function getModelsSupporting(feature: string): Promise<ActionResult<string[]>> {
+  try {
+    const session = await getSession();
+    if (!session || session.user.role !== "admin") {
+      return { ok: false, error: "无权限执行此操作", errorCode: ERROR_CODES.UNAUTHORIZED };
+    }
+
+    if (!isModelCapability(feature)) {
+      return {
+        ok: false,
+        error: `未知的模型能力: ${feature}`,
+        errorCode: ERROR_CODES.INVALID_FORMAT,
+        errorParams: { field: "feature" },
+      };
+    }
+
+    const prices = await findAllLatestPrices();
+    const modelNames = prices
+      .filter((price) => modelSupportsCapability(price.priceData, feature))
+      .map((price) => price.modelName)
+      .sort((left, right) => left.localeCompare(right));
+
+    return { ok: true, data: modelNames };
+  } catch (error) {
+    logger.error("获取支持指定能力的模型失败:", error);
+    return { ok: false, error: "获取模型列表失败，请稍后重试" };
+  }
+}
+
 /**
  * 获取指定模型的最新价格
  */
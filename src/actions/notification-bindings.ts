@@ -4,6 +4,7 @@ import { z } from "zod";
 import { getSession } from "@/lib/auth";
 import { logger } from "@/lib/logger";
 import { scheduleNotifications } from "@/lib/notification/notification-queue";
+import { isValidCronExpression } from "@/lib/utils/cron";
 import {
   type BindingInput,
   getBindingsByType,
@@ -23,7 +24,15 @@ const NotificationTypeSchema = z.enum([
 const BindingInputSchema: z.ZodType<BindingInput> = z.object({
   targetId: z.number().int().positive(),
   isEnabled: z.boolean().optional(),
-  scheduleCron: z.string().trim().max(100).optional().nullable(),
+  scheduleCron: z
+    .string()
+    .trim()
+    .max(100)
+    .refine((val) => val === "" || isValidCronExpression(val), {
+      message: "无效的 Cron 表达式，请使用 5 段或带秒的 6 段格式（如 0 9 * * *）",
+    })
+    .optional()
+    .nullable(),
   scheduleTimezone: z.string().trim().max(50).optional().nullable(),
   templateOverride: z.record(z.string(), z.unknown()).optional().nullable(),
 });
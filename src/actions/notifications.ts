@@ -3,6 +3,8 @@
 import { emitActionAudit } from "@/lib/audit/emit";
 import { getSession } from "@/lib/auth";
 import type { NotificationJobType } from "@/lib/constants/notification.constants";
+import { ERROR_CODES } from "@/lib/utils/error-messages";
+import { isValidHHMM } from "@/lib/utils/time-of-day";
 import { resolveSystemTimezone } from "@/lib/utils/timezone";
 import { WebhookNotifier } from "@/lib/webhook";
 import { buildTestMessage } from "@/lib/webhook/templates/test-messages";
@@ -37,6 +39,14 @@ export async function updateNotificationSettingsAction(
       return { ok: false, error: "无权限执行此操作" };
     }
 
+    if (payload.dailyLeaderboardTime !== undefined && !isValidHHMM(payload.dailyLeaderboardTime)) {
+      return {
+        ok: false,
+        error: "每日排行榜发送时间格式必须为 HH:mm",
+        errorCode: ERROR_CODES.INVALID_FORMAT,
+      };
+    }
+
     const before = await getNotificationSettings();
     const updated = await updateNotificationSettings(payload);
 
@@ -3,9 +3,11 @@
 import { getTranslations } from "next-intl/server";
 import { emitActionAudit } from "@/lib/audit/emit";
 import { getSession } from "@/lib/auth";
+import { publishProviderCacheInvalidation } from "@/lib/cache/provider-cache";
 import { PROVIDER_GROUP } from "@/lib/constants/provider.constants";
 import { logger } from "@/lib/logger";
 import { bootstrapProviderGroupsFromProviders } from "@/lib/provider-groups/bootstrap";
+import { collectGroupUsage, type GroupUsageInfo } from "@/lib/provider-groups/usage";
 import {
   parsePublicStatusDescription,
   serializePublicStatusDescription,
@@ -18,9 +20,11 @@ import {
   findProviderGroupByName,
   createProviderGroup as repoCreateProviderGroup,
   deleteProviderGroup as repoDeleteProviderGroup,
+  ensureProviderGroupsExist,
+  reassignProviderGroup as repoReassignProviderGroup,
   updateProviderGroup as repoUpdateProviderGroup,
 } from "@/repository/provider-groups";
-import type { ProviderGroup } from "@/types/provider-group";
+import type { ProviderGroup, ReassignProviderGroupResult } from "@/types/provider-group";
 import type { ActionResult } from "./types";
 
 // ---------------------------------------------------------------------------
@@ -72,6 +76,29 @@ export async function getProviderGroups(): Promise<ActionResult<ProviderGroupWit
   }
 }
 
+/**
+ * Return every group referenced by providers or users, with provider and user counts.
+ * Admin-only.
+ */
+export async function getAllGroups(): Promise<ActionResult<GroupUsageInfo[]>> {
+  const tError = await getTranslations("errors");
+  try {
+    const session = await getSession();
+    if (!session || session.user.role !== "admin") {
+      return { ok: false, error: tError("UNAUTHORIZED"), errorCode: ERROR_CODES.UNAUTHORIZED };
+    }
+
+    return { ok: true, data: await collectGroupUsage() };
+  } catch (error) {
+    logger.error("Failed to fetch group usage:", error);
+    return {
+      ok: false,
+      error: tError("OPERATION_FAILED"),
+      errorCode: ERROR_CODES.OPERATION_FAILED,
+    };
+  }
+}
+
 /**
  * Create a new provider group.
  * Admin-only. Validates name is non-empty and not duplicate, costMultiplier >= 0.
@@ -302,3 +329,69 @@ export async function deleteProviderGroup(id: number): Promise<ActionResult<void
     return { ok: false, error: t("deleteFailed"), errorCode: ERROR_CODES.DELETE_FAILED };
   }
 }
+
+/**
+ * Move every user, key and provider reference from one group to another.
+ * Admin-only. An empty `to` removes the group from the lists instead.
+ */
+export async function reassignProviderGroup(input: {
+  from: string;
+  to: string;
+}): Promise<ActionResult<ReassignProviderGroupResult>> {
+  const t = await getTranslations("settings.providers.providerGroups");
+  const tError = await getTranslations("errors");
+  const from = input.from?.trim() ?? "";
+  const to = input.to?.trim() ?? "";
+  try {
+    const session = await getSession();
+    if (!session || session.user.role !== "admin") {
+      return { ok: false, error: tError("UNAUTHORIZED"), errorCode: ERROR_CODES.UNAUTHORIZED };
+    }
+
+    if (!from) {
+      return { ok: false, error: t("nameRequired"), errorCode: "NAME_REQUIRED" };
+    }
+
+    if (from === to) {
+      return { ok: false, error: t("reassignSameGroup"), errorCode: "SAME_GROUP" };
+    }
+
+    const result = await repoReassignProviderGroup(from, to);
+
+    if (to) {
+      await ensureProviderGroupsExist([to]);
+    }
+
+    if (result.providersUpdated > 0) {
+      try {
+        await publishProviderCacheInvalidation();
+      } catch (error) {
+        logger.warn("reassignProviderGroup:cache_invalidation_failed", {
+          error: error instanceof Error ? error.message : String(error),
+        });
+      }
+    }
+
+    emitActionAudit({
+      category: "provider_group",
+      action: "provider_group.reassign",
+      targetType: "provider_group",
+      targetName: from,
+      before: { group: from },
+      after: { group: to || null, ...result },
+      success: true,
+    });
+    return { ok: true, data: result };
+  } catch (error) {
+    logger.error("Failed to reassign provider group:", error);
+    emitActionAudit({
+      category: "provider_group",
+      action: "provider_group.reassign",
+      targetType: "provider_group",
+      targetName: from || null,
+      success: false,
+      errorMessage: "REASSIGN_FAILED",
+    });
+    return { ok: false, error: t("reassignFailed"), errorCode: ERROR_CODES.UPDATE_FAILED };
+  }
+}
@@ -4,13 +4,18 @@ import { eq } from "drizzle-orm";
 import { z } from "zod";
 import { GeminiAuth } from "@/app/v1/_lib/gemini/auth";
 import { resolveAnthropicAuthHeaders as resolveAnthropicAuthHeaderSet } from "@/app/v1/_lib/headers";
-import { isClientAbortError } from "@/app/v1/_lib/proxy/errors";
+import {
+  categorizeErrorAsync,
+  ErrorCategory,
+  isClientAbortError,
+  ProxyError,
+} from "@/app/v1/_lib/proxy/errors";
 import { buildProxyUrl } from "@/app/v1/_lib/url";
 import { db } from "@/drizzle/db";
 import { providers as providersTable } from "@/drizzle/schema";
 import { normalizeAllowedModelRules } from "@/lib/allowed-model-rules";
 import { redactUrlCredentials } from "@/lib/api/v1/_shared/redaction";
-import { emitActionAudit } from "@/lib/audit/emit";
+import { buildActionAuditEntry, emitActionAudit } from "@/lib/audit/emit";
 import { getSession } from "@/lib/auth";
 import { publishProviderCacheInvalidation } from "@/lib/cache/provider-cache";
 import {
@@ -404,7 +409,7 @@ export async function getProviders(): Promise<ProviderDisplay[]> {
 }
 
 /**
- * Async get provider statistics data (today cost, call count, last call info)
+ * Async get provider statistics data (today cost, call count, latency percentiles, last call info)
  * Called independently by frontend, does not block main list loading
  */
 export async function getProviderStatisticsAsync(): Promise<ProviderStatisticsMap> {
@@ -435,6 +440,9 @@ export async function getProviderStatisticsAsync(): Promise<ProviderStatisticsMa
       result[s.id] = {
         todayCost: s.today_cost,
         todayCalls: s.today_calls,
+        p50DurationMs: s.p50_duration_ms ?? null,
+        p95DurationMs: s.p95_duration_ms ?? null,
+        p99DurationMs: s.p99_duration_ms ?? null,
         lastCallTime: lastCallTimeStr,
         lastCallModel: s.last_call_model,
       };
@@ -1279,15 +1287,35 @@ export async function resetProviderCircuit(providerId: number): Promise<ActionRe
  *
  * 说明：
  * - 不删除历史请求日志，仅更新 providers.total_cost_reset_at 作为聚合下限。
+ * - 审计记录（含可选的 reason）与重置标记在同一事务内写入。
  */
-export async function resetProviderTotalUsage(providerId: number): Promise<ActionResult> {
+export async function resetProviderTotalUsage(
+  providerId: number,
+  reason?: string
+): Promise<ActionResult> {
   try {
     const session = await getSession();
     if (!session || session.user.role !== "admin") {
       return { ok: false, error: "无权限执行此操作" };
     }
 
-    const ok = await resetProviderTotalCostResetAt(providerId, new Date());
+    const provider = await findProviderById(providerId);
+    if (!provider) {
+      return { ok: false, error: "供应商不存在" };
+    }
+
+    const resetAt = new Date();
+    const audit = await buildActionAuditEntry({
+      category: "provider",
+      action: "provider.reset_total_usage",
+      targetType: "provider",
+      targetId: String(providerId),
+      targetName: provider.name,
+      before: { totalCostResetAt: provider.totalCostResetAt },
+      after: { totalCostResetAt: resetAt, reason: reason?.trim() || null },
+      success: true,
+    });
+    const ok = await resetProviderTotalCostResetAt(providerId, resetAt, audit);
     if (!ok) {
       return { ok: false, error: "供应商不存在" };
     }
@@ -5113,6 +5141,70 @@ export async function fetchUpstreamModels(
   }
 }
 
+/**
+ * 按供应商类型发送模型列表请求（获取上游模型与连通性测试共用）
+ *
+ * - claude / claude-auth：复用 resolveAnthropicAuthHeaders（官方 API 用 x-api-key，代理用 Bearer）
+ * - gemini / gemini-cli：JSON 凭证换取 access token 后走 Bearer；API Key 走 x-goog-api-key，
+ *   header 认证返回 401/403 时回退为 URL 参数（?key=）认证
+ * - 其余（codex、openai-compatible）：OpenAI 兼容 Bearer token
+ *
+ * extraHeaders（如供应商自定义请求头）先于认证头合并，不会覆盖认证头。
+ */
+async function requestUpstreamModelList(
+  data: FetchUpstreamModelsArgs,
+  normalizedUrl: string,
+  timeoutMs: number,
+  extraHeaders: Record<string, string> = {}
+): Promise<Response> {
+  const proxyConfig = {
+    proxyUrl: data.proxyUrl ?? null,
+    proxyFallbackToDirect: data.proxyFallbackToDirect ?? false,
+  };
+  const send = (url: string, authHeaders: Record<string, string>) =>
+    executeProxiedFetch(proxyConfig, url, { ...extraHeaders, ...authHeaders }, timeoutMs);
+
+  if (data.providerType === "claude" || data.providerType === "claude-auth") {
+    const authHeaders = resolveAnthropicAuthHeaders(data.apiKey, normalizedUrl, {
+      forceBearerOnly: data.providerType === "claude-auth",
+    });
+    return await send(`${normalizedUrl}/v1/models`, authHeaders);
+  }
+
+  if (data.providerType === "gemini" || data.providerType === "gemini-cli") {
+    // Gemini 认证处理
+    let processedApiKey = data.apiKey;
+    let isJsonCreds = false;
+
+    try {
+      processedApiKey = await GeminiAuth.getAccessToken(data.apiKey);
+      isJsonCreds = GeminiAuth.isJson(data.apiKey);
+    } catch (e) {
+      logger.warn("requestUpstreamModelList: gemini auth process failed", { error: e });
+    }
+
+    const url = `${normalizedUrl}/v1beta/models?pageSize=100`;
+    if (isJsonCreds) {
+      return await send(url, { Authorization: `Bearer ${processedApiKey}` });
+    }
+
+    const response = await send(url, { "x-goog-api-key": processedApiKey });
+    if (response.status !== 401 && response.status !== 403) {
+      return response;
+    }
+
+    // header 认证失败（401/403），尝试 URL 参数认证
+    logger.debug("requestUpstreamModelList: header auth failed, trying URL param auth");
+    await response.body?.cancel().catch(() => undefined);
+    return await send(`${url}&key=${encodeURIComponent(processedApiKey)}`, {
+      "x-goog-api-key": processedApiKey,
+    });
+  }
+
+  // OpenAI 兼容 API (codex, openai-compatible)
+  return await send(`${normalizedUrl}/v1/models`, { Authorization: `Bearer ${data.apiKey}` });
+}
+
 /**
  * 从 OpenAI 兼容 API 获取模型列表
  */
@@ -5121,18 +5213,8 @@ async function fetchOpenAIModels(
   normalizedUrl: string,
   timeoutMs: number
 ): Promise<FetchUpstreamModelsResult> {
-  const url = `${normalizedUrl}/v1/models`;
-
   try {
-    const response = await executeProxiedFetch(
-      {
-        proxyUrl: data.proxyUrl ?? null,
-        proxyFallbackToDirect: data.proxyFallbackToDirect ?? false,
-      },
-      url,
-      { Authorization: `Bearer ${data.apiKey}` },
-      timeoutMs
-    );
+    const response = await requestUpstreamModelList(data, normalizedUrl, timeoutMs);
 
     if (!response.ok) {
       return handleHttpError(response, await response.text(), "fetchOpenAIModels");
@@ -5152,48 +5234,15 @@ async function fetchOpenAIModels(
 
 /**
  * 从 Gemini API 获取模型列表
- * 注意：保留了 401/403 重试逻辑，因为 Gemini 支持多种认证方式
+ * 认证方式及 401/403 回退见 requestUpstreamModelList
  */
 async function fetchGeminiModels(
   data: FetchUpstreamModelsArgs,
   normalizedUrl: string,
   timeoutMs: number
 ): Promise<FetchUpstreamModelsResult> {
-  const proxyConfig = {
-    proxyUrl: data.proxyUrl ?? null,
-    proxyFallbackToDirect: data.proxyFallbackToDirect ?? false,
-  };
-
-  // Gemini 认证处理
-  let processedApiKey = data.apiKey;
-  let isJsonCreds = false;
-
-  try {
-    processedApiKey = await GeminiAuth.getAccessToken(data.apiKey);
-    isJsonCreds = GeminiAuth.isJson(data.apiKey);
-  } catch (e) {
-    logger.warn("fetchGeminiModels: auth process failed", { error: e });
-  }
-
-  const url = `${normalizedUrl}/v1beta/models?pageSize=100`;
-  const headers: Record<string, string> = isJsonCreds
-    ? { Authorization: `Bearer ${processedApiKey}` }
-    : { "x-goog-api-key": processedApiKey };
-
   try {
-    let response = await executeProxiedFetch(proxyConfig, url, headers, timeoutMs);
-
-    // 如果 header 认证失败（401/403），尝试 URL 参数认证（不动此逻辑）
-    if (!isJsonCreds && (response.status === 401 || response.status === 403)) {
-      logger.debug("fetchGeminiModels: header auth failed, trying URL param auth");
-      const urlWithKey = `${normalizedUrl}/v1beta/models?pageSize=100&key=${encodeURIComponent(processedApiKey)}`;
-      response = await executeProxiedFetch(
-        proxyConfig,
-        urlWithKey,
-        { "x-goog-api-key": processedApiKey },
-        timeoutMs
-      );
-    }
+    const response = await requestUpstreamModelList(data, normalizedUrl, timeoutMs);
 
     if (!response.ok) {
       return handleHttpError(response, await response.text(), "fetchGeminiModels");
@@ -5229,23 +5278,8 @@ async function fetchAnthropicModels(
   normalizedUrl: string,
   timeoutMs: number
 ): Promise<FetchUpstreamModelsResult> {
-  const url = `${normalizedUrl}/v1/models`;
-
-  // 复用认证逻辑：官方 API 用 x-api-key，代理用 Bearer token
-  const authHeaders = resolveAnthropicAuthHeaders(data.apiKey, normalizedUrl, {
-    forceBearerOnly: data.providerType === "claude-auth",
-  });
-
   try {
-    const response = await executeProxiedFetch(
-      {
-        proxyUrl: data.proxyUrl ?? null,
-        proxyFallbackToDirect: data.proxyFallbackToDirect ?? false,
-      },
-      url,
-      authHeaders,
-      timeoutMs
-    );
+    const response = await requestUpstreamModelList(data, normalizedUrl, timeoutMs);
 
     if (!response.ok) {
       return handleHttpError(response, await response.text(), "fetchAnthropicModels");
@@ -5263,6 +5297,144 @@ async function fetchAnthropicModels(
   }
 }
 
+// ============================================================================
+// Provider Connectivity Test
+// ============================================================================
+
+/**
+ * 连通性测试的错误分类（与代理层 ErrorCategory 一一对应）
+ */
+export type ProviderConnectivityErrorCategory =
+  | "provider_error"
+  | "system_error"
+  | "client_abort"
+  | "non_retryable_client_error"
+  | "resource_not_found";
+
+const CONNECTIVITY_ERROR_CATEGORY_NAMES: Record<ErrorCategory, ProviderConnectivityErrorCategory> =
+  {
+    [ErrorCategory.PROVIDER_ERROR]: "provider_error",
+    [ErrorCategory.SYSTEM_ERROR]: "system_error",
+    [ErrorCategory.CLIENT_ABORT]: "client_abort",
+    [ErrorCategory.NON_RETRYABLE_CLIENT_ERROR]: "non_retryable_client_error",
+    [ErrorCategory.RESOURCE_NOT_FOUND]: "resource_not_found",
+  };
+
+/**
+ * 供应商连通性测试结果
+ */
+export type ProviderConnectivityTestResult = ActionResult<{
+  success: boolean;
+  latencyMs: number;
+  /** 上游 HTTP 状态码；网络层失败（DNS/连接/超时）时为 null */
+  httpStatus: number | null;
+  errorCategory: ProviderConnectivityErrorCategory | null;
+  errorMessage: string | null;
+  testedAt: string;
+}>;
+
+/**
+ * 供应商连通性测试（设置页"测试供应商"按钮）
+ *
+ * 使用库内的 URL、密钥与代理配置，向上游发送一次模型列表请求（GET /v1/models，
+ * Gemini 为 /v1beta/models），超时沿用 API_TEST_TIMEOUT_MS。返回延迟、HTTP 状态码，
+ * 失败时附带解析后的上游错误信息及 categorizeErrorAsync 分类。
+ *
+ * 不生成任何推理请求，不写入 message_request，不影响熔断器与用量统计。
+ */
+export async function testProviderConnectivity(
+  providerId: number
+): Promise<ProviderConnectivityTestResult> {
+  try {
+    const session = await getSession();
+    if (!session || session.user.role !== "admin") {
+      return { ok: false, error: "未授权" };
+    }
+
+    const provider = await findProviderById(providerId);
+    if (!provider) {
+      return { ok: false, error: "供应商不存在", errorCode: "provider.not_found" };
+    }
+
+    const urlValidation = validateProviderUrlForConnectivity(provider.url);
+    if (!urlValidation.valid) {
+      return { ok: false, error: urlValidation.error.message };
+    }
+
+    const normalizedUrl = urlValidation.normalizedUrl.replace(/\/$/, "");
+    const startedAt = Date.now();
+
+    let failure: Error;
+    let httpStatus: number | null = null;
+    try {
+      const response = await requestUpstreamModelList(
+        {
+          providerUrl: provider.url,
+          apiKey: provider.key,
+          providerType: provider.providerType,
+          proxyUrl: provider.proxyUrl,
+          proxyFallbackToDirect: provider.proxyFallbackToDirect,
+        },
+        normalizedUrl,
+        API_TEST_CONFIG.TIMEOUT_MS,
+        provider.customHeaders ?? {}
+      );
+      httpStatus = response.status;
+
+      if (response.ok) {
+        const latencyMs = Date.now() - startedAt;
+        // 只关心连通性，不读取模型列表
+        await response.body?.cancel().catch(() => undefined);
+        return {
+          ok: true,
+          data: {
+            success: true,
+            latencyMs,
+            httpStatus,
+            errorCategory: null,
+            errorMessage: null,
+            testedAt: new Date().toISOString(),
+          },
+        };
+      }
+
+      failure = await ProxyError.fromUpstreamResponse(response, {
+        id: provider.id,
+        name: provider.name,
+      });
+    } catch (error) {
+      failure = error instanceof Error ? error : new Error(String(error));
+    }
+
+    const latencyMs = Date.now() - startedAt;
+    const category = await categorizeErrorAsync(failure);
+    logger.warn("testProviderConnectivity: provider unreachable", {
+      providerId,
+      httpStatus,
+      category: CONNECTIVITY_ERROR_CATEGORY_NAMES[category],
+      error: failure.message,
+    });
+
+    return {
+      ok: true,
+      data: {
+        success: false,
+        latencyMs,
+        httpStatus,
+        errorCategory: CONNECTIVITY_ERROR_CATEGORY_NAMES[category],
+        errorMessage: failure.message,
+        testedAt: new Date().toISOString(),
+      },
+    };
+  } catch (error) {
+    logger.error("testProviderConnectivity error", { error, providerId });
+    return {
+      ok: false,
+      error: error instanceof Error ? error.message : "供应商连通性测试失败",
+    };
+  }
+}
+
 /**
  * 解析分组字符串为数组
  */
@@ -4,6 +4,9 @@ import { getSession } from "@/lib/auth";
 import { logger } from "@/lib/logger";
 import { getStatisticsWithCache } from "@/lib/redis";
 import { formatCostForStorage } from "@/lib/utils/currency";
+import { ERROR_CODES } from "@/lib/utils/error-messages";
+import { resolveUserTimezone } from "@/lib/utils/timezone";
+import { StatisticsQueryTimeoutError } from "@/repository/_shared/statistics-query-timeout";
 import { getActiveKeysForUserFromDB, getActiveUsersFromDB } from "@/repository/statistics";
 import { getSystemSettings } from "@/repository/system-config";
 import type {
@@ -31,9 +34,11 @@ function serializeChartBucketDate(value: string | Date): string {
 
 /**
  * 获取用户统计数据，用于图表展示
+ * options.signal 为请求的中止信号：客户端断开时取消尚在执行的统计查询
  */
 export async function getUserStatistics(
-  timeRange: TimeRange = DEFAULT_TIME_RANGE
+  timeRange: TimeRange = DEFAULT_TIME_RANGE,
+  options: { signal?: AbortSignal } = {}
 ): Promise<ActionResult<UserStatisticsData>> {
   try {
     const session = await getSession();
@@ -52,6 +57,8 @@ export async function getUserStatistics(
 
     const settings = await getSystemSettings();
     const isAdmin = session.user.role === "admin";
+    // 优先使用当前用户自己的时区，未设置时回退系统时区
+    const timezone = await resolveUserTimezone(session.user.timezone);
 
     // 确定显示模式
     const mode: "users" | "keys" | "mixed" = isAdmin
@@ -68,7 +75,7 @@ export async function getUserStatistics(
     if (mode === "users") {
       // Admin: 显示所有用户
       const [cachedData, userList] = await Promise.all([
-        getStatisticsWithCache(timeRange, "users"),
+        getStatisticsWithCache(timeRange, "users", undefined, timezone, options),
         getActiveUsersFromDB(),
       ]);
       statsData = cachedData as DatabaseStatRow[];
@@ -77,7 +84,7 @@ export async function getUserStatistics(
       // 非 Admin + allowGlobalUsageView: 自己的密钥明细 + 其他用户汇总
       const [ownKeysList, cachedData] = await Promise.all([
         getActiveKeysForUserFromDB(session.user.id),
-        getStatisticsWithCache(timeRange, "mixed", session.user.id),
+        getStatisticsWithCache(timeRange, "mixed", session.user.id, timezone, options),
       ]);
 
       const mixedData = cachedData as {
@@ -93,7 +100,7 @@ export async function getUserStatistics(
     } else {
       // 非 Admin + !allowGlobalUsageView: 仅显示自己的密钥
       const [cachedData, keyList] = await Promise.all([
-        getStatisticsWithCache(timeRange, "keys", session.user.id),
+        getStatisticsWithCache(timeRange, "keys", session.user.id, timezone, options),
         getActiveKeysForUserFromDB(session.user.id),
       ]);
       statsData = cachedData as DatabaseKeyStatRow[];
@@ -147,6 +154,14 @@ export async function getUserStatistics(
   } catch (error) {
     logger.error("Failed to get user statistics:", error);
 
+    if (error instanceof StatisticsQueryTimeoutError) {
+      return {
+        ok: false,
+        error: "统计查询超时，请缩小时间范围后重试",
+        errorCode: ERROR_CODES.TIMEOUT,
+      };
+    }
+
     // 提供更具体的错误信息
     const errorMessage = error instanceof Error ? error.message : "未知错误";
     if (errorMessage.includes("numeric field overflow")) {
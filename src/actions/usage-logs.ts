@@ -19,6 +19,7 @@ import {
   findUsageLogsBatch,
   findUsageLogsStats,
   findUsageLogsWithDetails,
+  getUsedApiTypes,
   getUsedEndpoints,
   getUsedModels,
   getUsedStatusCodes,
@@ -39,6 +40,7 @@ let filterOptionsCache: {
   models: string[];
   statusCodes: number[];
   endpoints: string[];
+  apiTypes: string[];
   expiresAt: number;
 } | null = null;
 
@@ -518,6 +520,24 @@ export async function getEndpointList(): Promise<ActionResult<string[]>> {
   }
 }
 
+/**
+ * 获取 API 类型列表（用于筛选器）
+ */
+export async function getApiTypeList(): Promise<ActionResult<string[]>> {
+  try {
+    const session = await getSession();
+    if (!session) {
+      return { ok: false, error: "未登录" };
+    }
+
+    const apiTypes = await getUsedApiTypes();
+    return { ok: true, data: apiTypes };
+  } catch (error) {
+    logger.error("获取 API 类型列表失败:", error);
+    return { ok: false, error: "获取 API 类型列表失败" };
+  }
+}
+
 /**
  * 筛选器选项数据类型
  */
@@ -525,14 +545,15 @@ export interface FilterOptions {
   models: string[];
   statusCodes: number[];
   endpoints: string[];
+  apiTypes: string[];
 }
 
 /**
  * 获取筛选器选项（带缓存）
- * 合并获取 models、statusCodes、endpoints，使用内存缓存减少 DISTINCT 全表扫描
+ * 合并获取 models、statusCodes、endpoints、apiTypes，使用内存缓存减少 DISTINCT 全表扫描
  *
  * 优化效果：
- * - 首次加载：3 次 DISTINCT 查询
+ * - 首次加载：4 次 DISTINCT 查询
  * - 5 分钟内再次加载：0 次查询（命中缓存）
  */
 export async function getFilterOptions(): Promise<ActionResult<FilterOptions>> {
@@ -553,16 +574,18 @@ export async function getFilterOptions(): Promise<ActionResult<FilterOptions>> {
           models: filterOptionsCache.models,
           statusCodes: filterOptionsCache.statusCodes,
           endpoints: filterOptionsCache.endpoints,
+          apiTypes: filterOptionsCache.apiTypes,
         },
       };
     }
 
     // 缓存过期或不存在，重新查询
     logger.debug("筛选器选项缓存未命中，执行 DISTINCT 查询");
-    const [models, statusCodes, endpoints] = await Promise.all([
+    const [models, statusCodes, endpoints, apiTypes] = await Promise.all([
       getUsedModels(),
       getUsedStatusCodes(),
       getUsedEndpoints(),
+      getUsedApiTypes(),
     ]);
 
     // 更新缓存
@@ -570,12 +593,13 @@ export async function getFilterOptions(): Promise<ActionResult<FilterOptions>> {
       models,
       statusCodes,
       endpoints,
+      apiTypes,
       expiresAt: now + FILTER_OPTIONS_CACHE_TTL_MS,
     };
 
     return {
       ok: true,
-      data: { models, statusCodes, endpoints },
+      data: { models, statusCodes, endpoints, apiTypes },
     };
   } catch (error) {
     logger.error("获取筛选器选项失败:", error);
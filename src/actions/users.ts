@@ -1,12 +1,11 @@
 "use server";
 
-import { randomBytes } from "node:crypto";
 import { and, eq, inArray, isNull } from "drizzle-orm";
 import { revalidatePath } from "next/cache";
 import { getLocale, getTranslations } from "next-intl/server";
 import { db } from "@/drizzle/db";
 import { messageRequest, usageLedger, users as usersTable } from "@/drizzle/schema";
-import { emitActionAudit } from "@/lib/audit/emit";
+import { buildActionAuditEntry, emitActionAudit } from "@/lib/audit/emit";
 import { getSession } from "@/lib/auth";
 import { PROVIDER_GROUP } from "@/lib/constants/provider.constants";
 import { logger } from "@/lib/logger";
@@ -14,6 +13,7 @@ import { getUnauthorizedFields } from "@/lib/permissions/user-field-permissions"
 import { clipStartByResetAt, resolveUser5hCostResetAt } from "@/lib/rate-limit/cost-reset-utils";
 import { getRedisClient } from "@/lib/redis";
 import { invalidateCachedUser } from "@/lib/security/api-key-auth-cache";
+import { generateApiKey } from "@/lib/utils/api-key";
 import { parseDateInputAsTimezone } from "@/lib/utils/date-input";
 import { ERROR_CODES } from "@/lib/utils/error-messages";
 import { normalizeProviderGroup, parseProviderGroups } from "@/lib/utils/provider-group";
@@ -21,6 +21,7 @@ import { resolveSystemTimezone } from "@/lib/utils/timezone";
 import { maskKey } from "@/lib/utils/validation";
 import { formatZodError } from "@/lib/utils/zod-i18n";
 import { CreateUserSchema, UpdateUserSchema } from "@/lib/validation/schemas";
+import { insertAuditLogInTransaction } from "@/repository/audit-log";
 import {
   createKey,
   findKeyList,
@@ -1437,7 +1438,7 @@ export async function addUser(data: {
     });
 
     // 为新用户创建默认密钥
-    const generatedKey = `sk-${randomBytes(16).toString("hex")}`;
+    const generatedKey = generateApiKey();
     const newKey = await createKey({
       user_id: newUser.id,
       name: "default",
@@ -1705,6 +1706,7 @@ export async function editUser(
     allowedClients?: string[];
     blockedClients?: string[];
     allowedModels?: string[];
+    timezone?: string | null;
   }
 ): Promise<ActionResult> {
   // Snapshot operator-visible state BEFORE entering the try block so failure
@@ -1813,6 +1815,7 @@ export async function editUser(
       allowedClients: validatedData.allowedClients,
       blockedClients: validatedData.blockedClients,
       allowedModels: validatedData.allowedModels,
+      timezone: validatedData.timezone,
     });
 
     if (
@@ -2229,7 +2232,7 @@ export async function getUserAllLimitUsage(userId: number): Promise<
  *
  * Admin only.
  */
-export async function resetUserLimitsOnly(userId: number): Promise<ActionResult> {
+export async function resetUserLimitsOnly(userId: number, reason?: string): Promise<ActionResult> {
   try {
     const tError = await getTranslations("errors");
 
@@ -2270,10 +2273,29 @@ export async function resetUserLimitsOnly(userId: number): Promise<ActionResult>
 
     // 同时推进 full reset marker 和 5H-only marker，避免 later-of 仍读到旧边界。
     const resetAt = new Date();
-    const updated = await updateUserCostResetMarkers(userId, {
-      costResetAt: resetAt,
-      limit5hCostResetAt: resetAt,
+    // 审计记录与重置标记同事务写入；后续 Redis 清理失败不影响已落库的审计
+    const audit = await buildActionAuditEntry({
+      category: "user",
+      action: "user.reset_limits",
+      targetType: "user",
+      targetId: String(userId),
+      targetName: user.name,
+      before: {
+        costResetAt: user.costResetAt ?? null,
+        limit5hCostResetAt: user.limit5hCostResetAt ?? null,
+      },
+      after: {
+        costResetAt: resetAt,
+        limit5hCostResetAt: resetAt,
+        reason: reason?.trim() || null,
+      },
+      success: true,
     });
+    const updated = await updateUserCostResetMarkers(
+      userId,
+      { costResetAt: resetAt, limit5hCostResetAt: resetAt },
+      audit
+    );
     if (!updated) {
       return { ok: false, error: tError("USER_NOT_FOUND"), errorCode: ERROR_CODES.NOT_FOUND };
     }
@@ -2343,7 +2365,10 @@ export async function resetUserLimitsOnly(userId: number): Promise<ActionResult>
  *
  * Admin only.
  */
-export async function resetUserAllStatistics(userId: number): Promise<ActionResult> {
+export async function resetUserAllStatistics(
+  userId: number,
+  reason?: string
+): Promise<ActionResult> {
   try {
     const tError = await getTranslations("errors");
 
@@ -2382,8 +2407,27 @@ export async function resetUserAllStatistics(userId: number): Promise<ActionResu
       }
     }
 
+    const audit = await buildActionAuditEntry({
+      category: "user",
+      action: "user.reset_statistics",
+      targetType: "user",
+      targetId: String(userId),
+      targetName: user.name,
+      before: {
+        costResetAt: user.costResetAt ?? null,
+        limit5hCostResetAt: user.limit5hCostResetAt ?? null,
+      },
+      after: {
+        costResetAt: null,
+        limit5hCostResetAt: null,
+        logsDeleted: true,
+        reason: reason?.trim() || null,
+      },
+      success: true,
+    });
+
     // 1. Delete all messageRequest logs for this user
-    // Atomic: delete logs + ledger + clear costResetAt in a single transaction
+    // Atomic: delete logs + ledger + clear costResetAt + audit row in a single transaction
     await db.transaction(async (tx) => {
       await tx.delete(messageRequest).where(eq(messageRequest.userId, userId));
       await tx.delete(usageLedger).where(eq(usageLedger.userId, userId));
@@ -2391,6 +2435,7 @@ export async function resetUserAllStatistics(userId: number): Promise<ActionResu
         .update(usersTable)
         .set({ costResetAt: null, limit5hCostResetAt: null, updatedAt: new Date() })
         .where(and(eq(usersTable.id, userId), isNull(usersTable.deletedAt)));
+      await insertAuditLogInTransaction(tx, audit);
     });
     // Invalidate auth cache outside transaction (Redis, not DB)
     await invalidateCachedUser(userId).catch(() => {});
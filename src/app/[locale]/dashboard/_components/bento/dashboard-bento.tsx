@@ -11,7 +11,7 @@ import { getOverviewData } from "@/lib/api-client/v1/actions/overview";
 import { getUserStatistics } from "@/lib/api-client/v1/actions/statistics";
 import type { CurrencyCode } from "@/lib/utils";
 import { cn } from "@/lib/utils";
-import { formatCurrency } from "@/lib/utils/currency";
+import { convertCurrency, formatCurrency } from "@/lib/utils/currency";
 import type {
   LeaderboardEntry,
   ModelLeaderboardEntry,
@@ -35,6 +35,8 @@ const REFRESH_INTERVAL = 5000;
 interface DashboardBentoProps {
   isAdmin: boolean;
   currencyCode: CurrencyCode;
+  /** USD → currencyCode 展示汇率；概览与统计数据始终为 USD，仅在格式化时换算 */
+  currencyDisplayRate?: string;
   allowGlobalUsageView: boolean;
   enableHighConcurrencyMode: boolean;
   initialStatistics?: UserStatisticsData;
@@ -119,6 +121,7 @@ function calcPercentageChange(current: number, previous: number): number {
 export function DashboardBento({
   isAdmin,
   currencyCode,
+  currencyDisplayRate,
   allowGlobalUsageView,
   enableHighConcurrencyMode,
   initialStatistics,
@@ -252,7 +255,10 @@ export function DashboardBento({
           />
           <BentoMetricCard
             title={t("metrics.todayCost")}
-            value={formatCurrency(metrics.todayCost, currencyCode)}
+            value={formatCurrency(
+              convertCurrency(metrics.todayCost, currencyCode, currencyDisplayRate),
+              currencyCode
+            )}
             icon={DollarSign}
             accentColor="amber"
             className="min-h-[120px]"
@@ -276,6 +282,7 @@ export function DashboardBento({
           data={statistics}
           onTimeRangeChange={setTimeRange}
           currencyCode={currencyCode}
+          currencyDisplayRate={currencyDisplayRate}
         />
       )}
 
@@ -6,7 +6,8 @@ import * as React from "react";
 import { Area, AreaChart, CartesianGrid, XAxis, YAxis } from "recharts";
 import { type ChartConfig, ChartContainer, ChartTooltip } from "@/components/ui/chart";
 import type { CurrencyCode } from "@/lib/utils";
-import { cn, Decimal, formatCurrency, toDecimal } from "@/lib/utils";
+import { cn, convertCurrency, Decimal, formatCurrency, toDecimal } from "@/lib/utils";
+import type { DecimalInput } from "@/lib/utils/currency";
 import { getDateFnsLocale } from "@/lib/utils/date-format";
 import type { TimeRange, UserStatisticsData } from "@/types/statistics";
 import { TIME_RANGE_OPTIONS } from "@/types/statistics";
@@ -46,6 +47,8 @@ export interface StatisticsChartCardProps {
   data: UserStatisticsData;
   onTimeRangeChange?: (timeRange: TimeRange) => void;
   currencyCode?: CurrencyCode;
+  /** USD → currencyCode 展示汇率；统计数据始终为 USD，仅在格式化时换算 */
+  currencyDisplayRate?: string;
   className?: string;
 }
 
@@ -53,12 +56,15 @@ export function StatisticsChartCard({
   data,
   onTimeRangeChange,
   currencyCode = "USD",
+  currencyDisplayRate,
   className,
 }: StatisticsChartCardProps) {
   const t = useTranslations("dashboard.statistics");
   const locale = useLocale();
   const timeZone = useTimeZone() ?? "UTC";
   const dateFnsLocale = getDateFnsLocale(locale);
+  const formatCost = (value: DecimalInput) =>
+    formatCurrency(convertCurrency(value, currencyCode, currencyDisplayRate), currencyCode);
   const [activeChart, setActiveChart] = React.useState<"cost" | "calls">("cost");
   const [chartMode, setChartMode] = React.useState<"stacked" | "overlay">("overlay");
 
@@ -388,7 +394,7 @@ export function StatisticsChartCard({
             {t("totalCost")}
           </span>
           <span className="text-base font-bold tabular-nums">
-            {formatCurrency(visibleTotals.cost, currencyCode)}
+            {formatCost(visibleTotals.cost)}
           </span>
         </button>
         <button
@@ -455,7 +461,7 @@ export function StatisticsChartCard({
               domain={[0, "dataMax"]}
               tickFormatter={(value) =>
                 activeChart === "cost"
-                  ? formatCurrency(value, currencyCode)
+                  ? formatCost(value)
                   : Number(value).toLocaleString()
               }
               className="text-[10px] fill-muted-foreground"
@@ -512,7 +518,7 @@ export function StatisticsChartCard({
                                 </div>
                                 <span className="font-mono font-medium">
                                   {activeChart === "cost"
-                                    ? formatCurrency(value, currencyCode)
+                                    ? formatCost(value)
                                     : value.toLocaleString()}
                                 </span>
                               </div>
@@ -629,7 +635,7 @@ export function StatisticsChartCard({
                       </span>
                       <span className="text-muted-foreground">
                         {activeChart === "cost"
-                          ? formatCurrency(userTotal?.cost ?? 0, currencyCode)
+                          ? formatCost(userTotal?.cost ?? 0)
                           : (userTotal?.calls ?? 0).toLocaleString()}
                       </span>
                     </button>
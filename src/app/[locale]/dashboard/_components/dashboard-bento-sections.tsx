@@ -22,6 +22,7 @@ export async function DashboardBentoSection({ isAdmin }: DashboardBentoSectionPr
     <DashboardBento
       isAdmin={isAdmin}
       currencyCode={systemSettings.currencyDisplay}
+      currencyDisplayRate={systemSettings.currencyDisplayRate}
       allowGlobalUsageView={systemSettings.allowGlobalUsageView}
       enableHighConcurrencyMode={systemSettings.enableHighConcurrencyMode}
       initialStatistics={statistics.ok ? statistics.data : undefined}
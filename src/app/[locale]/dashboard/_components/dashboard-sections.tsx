@@ -15,7 +15,13 @@ const getCachedSystemSettings = cache(getSystemSettings);
 export async function DashboardOverviewSection({ isAdmin }: { isAdmin: boolean }) {
   const systemSettings = await getCachedSystemSettings();
 
-  return <OverviewPanel currencyCode={systemSettings.currencyDisplay} isAdmin={isAdmin} />;
+  return (
+    <OverviewPanel
+      currencyCode={systemSettings.currencyDisplay}
+      currencyDisplayRate={systemSettings.currencyDisplayRate}
+      isAdmin={isAdmin}
+    />
+  );
 }
 
 export async function DashboardStatisticsSection() {
@@ -28,6 +34,7 @@ export async function DashboardStatisticsSection() {
     <StatisticsWrapper
       initialData={statistics.ok ? statistics.data : undefined}
       currencyCode={systemSettings.currencyDisplay}
+      currencyDisplayRate={systemSettings.currencyDisplayRate}
     />
   );
 }
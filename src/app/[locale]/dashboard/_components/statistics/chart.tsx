@@ -7,7 +7,8 @@ import { Area, AreaChart, CartesianGrid, XAxis, YAxis } from "recharts";
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
 import { type ChartConfig, ChartContainer, ChartLegend, ChartTooltip } from "@/components/ui/chart";
 import type { CurrencyCode } from "@/lib/utils";
-import { cn, Decimal, formatCurrency, toDecimal } from "@/lib/utils";
+import { cn, convertCurrency, Decimal, formatCurrency, toDecimal } from "@/lib/utils";
+import type { DecimalInput } from "@/lib/utils/currency";
 import { getDateFnsLocale } from "@/lib/utils/date-format";
 import type { TimeRange, UserStatisticsData } from "@/types/statistics";
 import { TimeRangeSelector } from "./time-range-selector";
@@ -47,6 +48,8 @@ export interface UserStatisticsChartProps {
   data: UserStatisticsData;
   onTimeRangeChange?: (timeRange: TimeRange) => void;
   currencyCode?: CurrencyCode;
+  /** USD → currencyCode 展示汇率；统计数据始终为 USD，仅在格式化时换算 */
+  currencyDisplayRate?: string;
 }
 
 /**
@@ -57,11 +60,14 @@ export function UserStatisticsChart({
   data,
   onTimeRangeChange,
   currencyCode = "USD",
+  currencyDisplayRate,
 }: UserStatisticsChartProps) {
   const t = useTranslations("dashboard.statistics");
   const locale = useLocale();
   const timeZone = useTimeZone() ?? "UTC";
   const dateFnsLocale = getDateFnsLocale(locale);
+  const formatCost = (value: DecimalInput) =>
+    formatCurrency(convertCurrency(value, currencyCode, currencyDisplayRate), currencyCode);
   const [activeChart, setActiveChart] = React.useState<"cost" | "calls">("cost");
   const [chartMode, setChartMode] = React.useState<"stacked" | "overlay">("overlay");
 
@@ -308,7 +314,7 @@ export function UserStatisticsChart({
             >
               <span className="text-muted-foreground text-xs">{t("totalCost")}</span>
               <span className="text-lg leading-none font-bold sm:text-3xl">
-                {formatCurrency(visibleTotals.cost, currencyCode)}
+                {formatCost(visibleTotals.cost)}
               </span>
             </button>
             <button
@@ -334,7 +340,7 @@ export function UserStatisticsChart({
           >
             <span className="text-muted-foreground text-xs">{t("totalCost")}</span>
             <span className="text-lg leading-none font-bold sm:text-xl">
-              {formatCurrency(visibleTotals.cost, currencyCode)}
+              {formatCost(visibleTotals.cost)}
             </span>
           </button>
           <button
@@ -415,7 +421,7 @@ export function UserStatisticsChart({
               domain={[0, "dataMax"]}
               tickFormatter={(value) => {
                 if (activeChart === "cost") {
-                  return formatCurrency(value, currencyCode);
+                  return formatCost(value);
                 }
                 return Number(value).toLocaleString();
               }}
@@ -474,7 +480,7 @@ export function UserStatisticsChart({
                                 </div>
                                 <span className="ml-auto font-mono flex-shrink-0">
                                   {activeChart === "cost"
-                                    ? formatCurrency(value, currencyCode)
+                                    ? formatCost(value)
                                     : value.toLocaleString()}
                                 </span>
                               </div>
@@ -576,7 +582,7 @@ export function UserStatisticsChart({
                           {/* 下方：数据值 */}
                           <div className="text-xs font-bold text-foreground">
                             {activeChart === "cost"
-                              ? formatCurrency(userTotal.cost, currencyCode)
+                              ? formatCost(userTotal.cost)
                               : userTotal.calls.toLocaleString()}
                           </div>
                         </button>
@@ -13,6 +13,7 @@ import { UserStatisticsChart } from "./chart";
 interface StatisticsWrapperProps {
   initialData?: UserStatisticsData;
   currencyCode?: CurrencyCode;
+  currencyDisplayRate?: string;
 }
 
 const STATISTICS_REFRESH_INTERVAL = 5000; // 5秒刷新一次
@@ -21,7 +22,11 @@ const STATISTICS_REFRESH_INTERVAL = 5000; // 5秒刷新一次
  * 统计组件包装器
  * 处理时间范围状态管理和数据获取
  */
-export function StatisticsWrapper({ initialData, currencyCode = "USD" }: StatisticsWrapperProps) {
+export function StatisticsWrapper({
+  initialData,
+  currencyCode = "USD",
+  currencyDisplayRate,
+}: StatisticsWrapperProps) {
   const t = useTranslations("dashboard.statistics");
   const [timeRange, setTimeRange] = React.useState<TimeRange>(
     initialData?.timeRange ?? DEFAULT_TIME_RANGE
@@ -67,6 +72,7 @@ export function StatisticsWrapper({ initialData, currencyCode = "USD" }: Statist
       data={data}
       onTimeRangeChange={handleTimeRangeChange}
       currencyCode={currencyCode}
+      currencyDisplayRate={currencyDisplayRate}
     />
   );
 }
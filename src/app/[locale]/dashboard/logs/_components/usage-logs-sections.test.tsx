@@ -22,10 +22,11 @@ import { getSystemSettings } from "@/repository/system-config";
 import { UsageLogsDataSection } from "./usage-logs-sections";
 
 describe("UsageLogsDataSection", () => {
-  it("passes billingModelSource and currencyCode from system settings", async () => {
+  it("passes billingModelSource, currencyCode and display rate from system settings", async () => {
     vi.mocked(getSystemSettings).mockResolvedValue({
       billingModelSource: "redirected",
       currencyDisplay: "CNY",
+      currencyDisplayRate: "7.20000000",
     } as Awaited<ReturnType<typeof getSystemSettings>>);
 
     const searchParams = Promise.resolve({});
@@ -38,6 +39,7 @@ describe("UsageLogsDataSection", () => {
     expect(element.props).toMatchObject({
       billingModelSource: "redirected",
       currencyCode: "CNY",
+      currencyDisplayRate: "7.20000000",
     });
   });
 
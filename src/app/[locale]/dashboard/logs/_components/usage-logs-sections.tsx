@@ -13,7 +13,10 @@ interface UsageLogsDataSectionProps {
   isAdmin: boolean;
   userId: number;
   searchParams: Promise<{ [key: string]: string | string[] | undefined }>;
-  systemSettings?: Pick<SystemSettings, "billingModelSource" | "currencyDisplay">;
+  systemSettings?: Pick<
+    SystemSettings,
+    "billingModelSource" | "currencyDisplay" | "currencyDisplayRate"
+  >;
 }
 
 export function UsageLogsActiveSessionsSection({ currencyCode }: { currencyCode: CurrencyCode }) {
@@ -40,6 +43,7 @@ export async function UsageLogsDataSection({
       serverTimeZone={serverTimeZone}
       billingModelSource={systemSettings.billingModelSource}
       currencyCode={systemSettings.currencyDisplay}
+      currencyDisplayRate={systemSettings.currencyDisplayRate}
       logsRefreshIntervalMs={getEnvConfig().DASHBOARD_LOGS_POLL_INTERVAL_MS}
     />
   );
@@ -72,3 +72,56 @@ describe("UsageLogsStatsPanel manual refresh", () => {
     expect(getUsageLogsStatsMock).toHaveBeenCalledTimes(1);
   });
 });
+
+describe("UsageLogsStatsPanel display currency", () => {
+  let container: HTMLElement;
+  let root: Root;
+
+  beforeEach(() => {
+    getUsageLogsStatsMock.mockReset();
+    getUsageLogsStatsMock.mockResolvedValue({
+      ok: true,
+      data: {
+        totalRequests: 3,
+        totalCost: 2,
+        totalTokens: 0,
+        totalInputTokens: 0,
+        totalOutputTokens: 0,
+        totalCacheCreationTokens: 0,
+        totalCacheReadTokens: 0,
+        totalCacheCreation5mTokens: 0,
+        totalCacheCreation1hTokens: 0,
+      },
+    });
+    container = document.createElement("div");
+    document.body.appendChild(container);
+    root = createRoot(container);
+  });
+
+  afterEach(async () => {
+    await act(async () => {
+      root.unmount();
+    });
+    container.remove();
+  });
+
+  it("converts the USD total with the display rate only when formatting", async () => {
+    await act(async () => {
+      root.render(
+        <UsageLogsStatsPanel filters={FILTERS} currencyCode="CNY" currencyDisplayRate="7.2" />
+      );
+    });
+
+    expect(container.textContent).toContain("¥14.40");
+  });
+
+  it("keeps the USD total when the display currency is USD", async () => {
+    await act(async () => {
+      root.render(
+        <UsageLogsStatsPanel filters={FILTERS} currencyCode="USD" currencyDisplayRate="7.2" />
+      );
+    });
+
+    expect(container.textContent).toContain("$2.00");
+  });
+});
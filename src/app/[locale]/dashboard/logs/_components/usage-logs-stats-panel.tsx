@@ -7,7 +7,7 @@ import { Skeleton } from "@/components/ui/skeleton";
 import { getUsageLogsStats } from "@/lib/api-client/v1/actions/usage-logs";
 import { cn, formatTokenAmount } from "@/lib/utils";
 import type { CurrencyCode } from "@/lib/utils/currency";
-import { formatCurrency } from "@/lib/utils/currency";
+import { convertCurrency, formatCurrency } from "@/lib/utils/currency";
 import type { UsageLogSummary } from "@/repository/usage-logs";
 
 interface UsageLogsStatsPanelProps {
@@ -25,6 +25,8 @@ interface UsageLogsStatsPanelProps {
     minRetryCount?: number;
   };
   currencyCode?: CurrencyCode;
+  /** USD → currencyCode 的展示汇率；统计接口返回值始终为 USD，仅在格式化时换算 */
+  currencyDisplayRate?: string;
   /**
    * 手动刷新计数器：每次递增都会触发一次统计汇总重新拉取（与列表的手动刷新联动）。
    * 仅在值变化时生效，不影响按 filters 变化的常规重拉。
@@ -40,6 +42,7 @@ interface UsageLogsStatsPanelProps {
 export function UsageLogsStatsPanel({
   filters,
   currencyCode = "USD",
+  currencyDisplayRate,
   refreshKey = 0,
 }: UsageLogsStatsPanelProps) {
   const t = useTranslations("dashboard");
@@ -116,7 +119,11 @@ export function UsageLogsStatsPanel({
           ) : error ? (
             <div className="text-center py-4 text-destructive">{error}</div>
           ) : stats ? (
-            <StatsContent stats={stats} currencyCode={currencyCode} />
+            <StatsContent
+              stats={stats}
+              currencyCode={currencyCode}
+              currencyDisplayRate={currencyDisplayRate}
+            />
           ) : null}
         </div>
       </div>
@@ -146,9 +153,11 @@ function StatsSkeletons() {
 function StatsContent({
   stats,
   currencyCode,
+  currencyDisplayRate,
 }: {
   stats: UsageLogSummary;
   currencyCode: CurrencyCode;
+  currencyDisplayRate?: string;
 }) {
   const t = useTranslations("dashboard");
 
@@ -166,7 +175,10 @@ function StatsContent({
       <div className="p-4 border border-border/50 rounded-lg bg-card/20">
         <div className="text-sm text-muted-foreground mb-1">{t("logs.stats.totalAmount")}</div>
         <div className="text-2xl font-mono font-semibold">
-          {formatCurrency(stats.totalCost, currencyCode)}
+          {formatCurrency(
+            convertCurrency(stats.totalCost, currencyCode, currencyDisplayRate),
+            currencyCode
+          )}
         </div>
       </div>
 
@@ -20,7 +20,7 @@ import { Tooltip, TooltipContent, TooltipProvider, TooltipTrigger } from "@/comp
 import { cn, formatTokenAmount } from "@/lib/utils";
 import { copyTextToClipboard } from "@/lib/utils/clipboard";
 import type { CurrencyCode } from "@/lib/utils/currency";
-import { formatCurrency } from "@/lib/utils/currency";
+import { convertCurrency, type DecimalInput, formatCurrency } from "@/lib/utils/currency";
 import { buildHedgeBillingTable } from "@/lib/utils/hedge-billing";
 import {
   calculateOutputRate,
@@ -49,6 +49,7 @@ interface UsageLogsTableProps {
   isPending: boolean;
   newLogIds?: Set<number>; // 新增记录 ID 集合（用于动画高亮）
   currencyCode?: CurrencyCode;
+  currencyDisplayRate?: string; // USD → currencyCode 展示汇率，日志金额始终为 USD
   billingModelSource?: BillingModelSource;
 }
 
@@ -61,11 +62,18 @@ export function UsageLogsTable({
   isPending,
   newLogIds,
   currencyCode = "USD",
+  currencyDisplayRate,
   billingModelSource = "original",
 }: UsageLogsTableProps) {
   const t = useTranslations("dashboard");
   const tChain = useTranslations("provider-chain");
   const totalPages = Math.ceil(total / pageSize);
+  const formatCost = (value: DecimalInput, fractionDigits: number) =>
+    formatCurrency(
+      convertCurrency(value, currencyCode, currencyDisplayRate),
+      currencyCode,
+      fractionDigits
+    );
   const getPricingSourceLabel = (source: string) =>
     t(`logs.billingDetails.pricingSource.${source}`);
 
@@ -405,7 +413,7 @@ export function UsageLogsTable({
                           <Tooltip delayDuration={250}>
                             <TooltipTrigger asChild>
                               <span className="cursor-help inline-flex items-center gap-1">
-                                {formatCurrency(log.costUsd, currencyCode, 6)}
+                                {formatCost(log.costUsd, 6)}
                                 {hasPriorityServiceTierSpecialSetting(log.specialSettings) && (
                                   <Badge
                                     variant="outline"
@@ -507,7 +515,7 @@ export function UsageLogsTable({
                                         {t("logs.billingDetails.hedgeWinner")}
                                       </span>
                                       <span className="font-mono">
-                                        {formatCurrency(hedgeTable.winnerCost, currencyCode, 6)}
+                                        {formatCost(hedgeTable.winnerCost, 6)}
                                       </span>
                                     </div>
                                     {hedgeTable.attempts
@@ -522,7 +530,7 @@ export function UsageLogsTable({
                                               t("logs.billingDetails.hedgeLoserShort")}
                                           </span>
                                           <span className="font-mono">
-                                            {formatCurrency(loser.costUsd, currencyCode, 6)}
+                                            {formatCost(loser.costUsd, 6)}
                                           </span>
                                         </div>
                                       ))}
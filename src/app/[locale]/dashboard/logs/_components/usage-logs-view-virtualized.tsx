@@ -20,7 +20,7 @@ import { getSystemSettings } from "@/lib/api-client/v1/actions/system-config";
 import { getHiddenColumns, type LogsTableColumn } from "@/lib/column-visibility";
 import { cn } from "@/lib/utils";
 import type { CurrencyCode } from "@/lib/utils/currency";
-import { formatCurrency } from "@/lib/utils/currency";
+import { convertCurrency, formatCurrency } from "@/lib/utils/currency";
 import type { Key } from "@/types/key";
 import type { ProviderDisplay } from "@/types/provider";
 import type { BillingModelSource, SystemSettings } from "@/types/system-config";
@@ -40,6 +40,7 @@ interface UsageLogsViewVirtualizedProps {
   initialKeys?: Key[];
   searchParams: { [key: string]: string | string[] | undefined };
   currencyCode?: CurrencyCode;
+  currencyDisplayRate?: string;
   billingModelSource?: BillingModelSource;
   serverTimeZone?: string;
   logsRefreshIntervalMs?: number;
@@ -60,6 +61,7 @@ function UsageLogsViewContent({
   initialKeys,
   searchParams: _searchParams, // Kept for SSR hydration, but filters use useSearchParams
   currencyCode = "USD",
+  currencyDisplayRate,
   billingModelSource = "original",
   serverTimeZone,
   logsRefreshIntervalMs,
@@ -132,6 +134,7 @@ function UsageLogsViewContent({
   });
 
   const resolvedCurrencyCode = currencyCode ?? systemSettings?.currencyDisplay ?? "USD";
+  const resolvedCurrencyDisplayRate = currencyDisplayRate ?? systemSettings?.currencyDisplayRate;
   const resolvedBillingModelSource =
     billingModelSource ?? systemSettings?.billingModelSource ?? "original";
 
@@ -281,6 +284,7 @@ function UsageLogsViewContent({
           <UsageLogsStatsPanel
             filters={statsFilters}
             currencyCode={resolvedCurrencyCode}
+            currencyDisplayRate={resolvedCurrencyDisplayRate}
             refreshKey={statsRefreshKey}
           />
         )}
@@ -385,6 +389,7 @@ function UsageLogsViewContent({
           <VirtualizedLogsTable
             filters={filters}
             currencyCode={resolvedCurrencyCode}
+            currencyDisplayRate={resolvedCurrencyDisplayRate}
             billingModelSource={resolvedBillingModelSource}
             autoRefreshEnabled={!isFullscreenOpen && isAutoRefresh}
             autoRefreshIntervalMs={logsRefreshIntervalMs ?? 5000}
@@ -440,7 +445,15 @@ function UsageLogsViewContent({
                     {tc("metrics.todayCost")}
                   </div>
                   <div className="font-mono text-xl font-bold tabular-nums leading-none">
-                    {formatCurrency(overviewData?.todayCost ?? 0, resolvedCurrencyCode, 2)}
+                    {formatCurrency(
+                      convertCurrency(
+                        overviewData?.todayCost ?? 0,
+                        resolvedCurrencyCode,
+                        resolvedCurrencyDisplayRate
+                      ),
+                      resolvedCurrencyCode,
+                      2
+                    )}
                   </div>
                 </div>
                 <div className="pl-5 flex flex-col justify-center">
@@ -473,6 +486,7 @@ function UsageLogsViewContent({
             <VirtualizedLogsTable
               filters={filters}
               currencyCode={resolvedCurrencyCode}
+              currencyDisplayRate={resolvedCurrencyDisplayRate}
               billingModelSource={resolvedBillingModelSource}
               autoRefreshEnabled={true}
               autoRefreshIntervalMs={3000}
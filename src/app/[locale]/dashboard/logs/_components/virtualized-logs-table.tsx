@@ -28,7 +28,13 @@ import type { LogsTableColumn } from "@/lib/column-visibility";
 import { cn, formatTokenAmount } from "@/lib/utils";
 import { copyTextToClipboard } from "@/lib/utils/clipboard";
 import type { CurrencyCode } from "@/lib/utils/currency";
-import { Decimal, formatCurrency, toDecimal } from "@/lib/utils/currency";
+import {
+  convertCurrency,
+  Decimal,
+  type DecimalInput,
+  formatCurrency,
+  toDecimal,
+} from "@/lib/utils/currency";
 import { buildHedgeBillingTable } from "@/lib/utils/hedge-billing";
 import {
   calculateOutputRate,
@@ -98,6 +104,8 @@ function StatusBadgeOnly({ statusCode }: { statusCode: number | null }) {
 interface VirtualizedLogsTableProps {
   filters: VirtualizedLogsTableFilters;
   currencyCode?: CurrencyCode;
+  /** USD → currencyCode display rate; log costs stay in USD and are converted when formatted */
+  currencyDisplayRate?: string;
   billingModelSource?: BillingModelSource;
   autoRefreshEnabled?: boolean;
   autoRefreshIntervalMs?: number;
@@ -119,6 +127,7 @@ interface VirtualizedLogsTableProps {
 export function VirtualizedLogsTable({
   filters,
   currencyCode = "USD",
+  currencyDisplayRate,
   billingModelSource = "original",
   autoRefreshEnabled = true,
   autoRefreshIntervalMs = 5000,
@@ -147,6 +156,13 @@ export function VirtualizedLogsTable({
   const hideCostColumn = hiddenColumns?.includes("cost") ?? false;
   const hidePerformanceColumn = hiddenColumns?.includes("performance") ?? false;
 
+  const formatCost = (value: DecimalInput, fractionDigits: number) =>
+    formatCurrency(
+      convertCurrency(value, currencyCode, currencyDisplayRate),
+      currencyCode,
+      fractionDigits
+    );
+
   // Dialog state for model redirect click and chain item click
   const [dialogState, setDialogState] = useState<{
     logId: number | null;
@@ -372,10 +388,10 @@ export function VirtualizedLogsTable({
         ttl,
         unitPrice: unitPrice
           ? t("logs.billingDetails.unitPricePer1M", {
-              price: formatCurrency(unitPrice, currencyCode, 2),
+              price: formatCost(unitPrice, 2),
             })
           : null,
-        amount: formatCurrency(parsedAmount, currencyCode, 6),
+        amount: formatCost(parsedAmount, 6),
       };
     };
 
@@ -454,7 +470,7 @@ export function VirtualizedLogsTable({
         <div className="space-y-2">
           {renderSummaryRow({
             label: t("logs.billingDetails.hedgeWinner"),
-            primary: formatCurrency(hedgeTable.winnerCost, currencyCode, 6),
+            primary: formatCost(hedgeTable.winnerCost, 6),
           })}
           {hedgeTable.attempts
             .filter((attempt) => attempt.kind === "loser")
@@ -467,7 +483,7 @@ export function VirtualizedLogsTable({
                   {loser.providerName ?? t("logs.billingDetails.hedgeLoserShort")}
                 </span>
                 <span className={cn(amountClassName, "text-rose-300/80")}>
-                  {formatCurrency(loser.costUsd, currencyCode, 6)}
+                  {formatCost(loser.costUsd, 6)}
                 </span>
               </div>
             ))}
@@ -505,7 +521,7 @@ export function VirtualizedLogsTable({
             <div className="border-t border-background/20 pt-2">
               {renderSummaryRow({
                 label: totalCostLabel,
-                primary: formatCurrency(log.costUsd, currencyCode, 6),
+                primary: formatCost(log.costUsd, 6),
                 emphasize: true,
               })}
             </div>
@@ -547,14 +563,13 @@ export function VirtualizedLogsTable({
     ].filter((row): row is NonNullable<typeof row> => row !== null);
 
     const hasActiveMultipliers = activeMultiplierRows.length > 0;
-    const baseTotal = formatCurrency(log.costBreakdown.base_total, currencyCode, 6);
+    const baseTotal = formatCost(log.costBreakdown.base_total, 6);
     // costBreakdown.total is the winner-only base; when hedge losers were billed the grand
     // total lives in costUsd, so prefer it then (keeps the tooltip total == sum of its rows).
-    const finalTotal = formatCurrency(
+    const finalTotal = formatCost(
       log.hedgeLosers && log.hedgeLosers.length > 0
         ? (log.costUsd ?? log.costBreakdown.total)
         : log.costBreakdown.total,
-      currencyCode,
       6
     );
 
@@ -1102,7 +1117,7 @@ export function VirtualizedLogsTable({
                             <Tooltip delayDuration={250}>
                               <TooltipTrigger asChild>
                                 <span className="cursor-help inline-flex items-center gap-1">
-                                  {formatCurrency(log.costUsd, currencyCode, 6)}
+                                  {formatCost(log.costUsd, 6)}
                                   {hasPriorityServiceTierSpecialSetting(log.specialSettings) && (
                                     <Badge
                                       variant="outline"
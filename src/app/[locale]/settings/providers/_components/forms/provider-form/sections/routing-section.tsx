@@ -18,6 +18,7 @@ import {
 } from "@/components/ui/select";
 import { Switch } from "@/components/ui/switch";
 import { TagInput } from "@/components/ui/tag-input";
+import { PROVIDER_LIMITS } from "@/lib/constants/provider.constants";
 import { getProviderTypeConfig } from "@/lib/provider-type-utils";
 import type { ProviderType } from "@/types/provider";
 import { AllowedModelRuleEditor } from "../../../allowed-model-rule-editor";
@@ -419,7 +420,8 @@ export function RoutingSection({ subSectionRefs }: RoutingSectionProps) {
                   onFocus={(e) => e.target.select()}
                   placeholder={t("sections.routing.scheduleParams.costMultiplier.placeholder")}
                   disabled={state.ui.isPending}
-                  min="0"
+                  min="0.0001"
+                  max={PROVIDER_LIMITS.COST_MULTIPLIER.MAX}
                   step="0.0001"
                 />
                 {isBatch && batchAnalysis?.routing.costMultiplier.status === "mixed" && (
@@ -218,7 +218,9 @@ function ProviderRichListItemInner({
   const validateCostMultiplier = (raw: string) => {
     if (raw.length === 0) return tInline("costMultiplierInvalid");
     const value = Number(raw);
-    if (!Number.isFinite(value) || value < 0) return tInline("costMultiplierInvalid");
+    // 与服务端 ProviderCostMultiplierSchema 保持一致：(0, 100]
+    if (!Number.isFinite(value) || value <= 0 || value > PROVIDER_LIMITS.COST_MULTIPLIER.MAX)
+      return tInline("costMultiplierInvalid");
     return null;
   };
 
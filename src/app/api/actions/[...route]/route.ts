@@ -52,6 +52,7 @@ import { NOTIFICATION_JOB_TYPES } from "@/lib/constants/notification.constants";
 import { logger } from "@/lib/logger";
 import { PROVIDER_MODEL_REDIRECT_RULE_SCHEMA } from "@/lib/provider-model-redirect-schema";
 import { appendPublicStatusOpenApi } from "@/lib/public-status/openapi";
+import { HHMM_PATTERN } from "@/lib/utils/time-of-day";
 // 导入 validation schemas
 import {
   CreateProviderSchema,
@@ -1833,7 +1834,11 @@ const { route: updateNotificationSettingsRoute, handler: updateNotificationSetti
           .nullable()
           .optional()
           .describe("每日排行榜 Webhook URL（旧版模式）"),
-        dailyLeaderboardTime: z.string().optional().describe("每日排行榜发送时间（HH:mm）"),
+        dailyLeaderboardTime: z
+          .string()
+          .regex(HHMM_PATTERN)
+          .optional()
+          .describe("每日排行榜发送时间（HH:mm）"),
         dailyLeaderboardTopN: z
           .number()
           .int()
@@ -41,6 +41,7 @@ export async function GET() {
  *   siteTitle: string;
  *   allowGlobalUsageView: boolean;
  *   currencyDisplay?: string;
+ *   currencyDisplayRate?: number;
  *   enableAutoCleanup?: boolean;
  *   cleanupRetentionDays?: number;
  *   cleanupSchedule?: string;
@@ -65,6 +66,7 @@ export async function POST(req: Request) {
       siteTitle: validated.siteTitle?.trim(),
       allowGlobalUsageView: validated.allowGlobalUsageView,
       currencyDisplay: validated.currencyDisplay,
+      currencyDisplayRate: validated.currencyDisplayRate,
       billingModelSource: validated.billingModelSource,
       codexPriorityBillingSource: validated.codexPriorityBillingSource,
       timezone: validated.timezone,
@@ -73,6 +75,7 @@ export async function POST(req: Request) {
       cleanupSchedule: validated.cleanupSchedule,
       cleanupBatchSize: validated.cleanupBatchSize,
       enableClientVersionCheck: validated.enableClientVersionCheck,
+      minClientVersion: validated.minClientVersion,
       verboseProviderError: validated.verboseProviderError,
       passThroughUpstreamErrorMessage: validated.passThroughUpstreamErrorMessage,
       enableHttp2: validated.enableHttp2,
@@ -15,6 +15,7 @@ import { getEnvConfig } from "@/lib/config/env.schema";
 import { getClientIpWithFreshSettings } from "@/lib/ip";
 import { logger } from "@/lib/logger";
 import { withAuthResponseHeaders } from "@/lib/security/auth-response-headers";
+import { isAdminToken } from "@/lib/security/constant-time-compare";
 import { createCsrfOriginGuard } from "@/lib/security/csrf-origin-guard";
 import { LoginAbusePolicy } from "@/lib/security/login-abuse-policy";
 import { createAuditLogAsync } from "@/repository/audit-log";
@@ -145,7 +146,7 @@ function classifyLoginCredential(
   key: string,
   session: AuthSession
 ): Extract<AuthCredentialType, "admin-token" | "session" | "user-api-key"> {
-  if (getEnvConfig().ADMIN_TOKEN && key === getEnvConfig().ADMIN_TOKEN) {
+  if (isAdminToken(key, getEnvConfig().ADMIN_TOKEN)) {
     return "admin-token";
   }
 
@@ -7,7 +7,7 @@ import type {
   LeaderboardPeriod,
   LeaderboardScope,
 } from "@/lib/redis/leaderboard-cache";
-import { formatCurrency } from "@/lib/utils";
+import { convertCurrency, formatCurrency } from "@/lib/utils";
 import { getSystemSettings } from "@/repository/system-config";
 import type { ProviderType } from "@/types/provider";
 
@@ -185,11 +185,18 @@ export async function GET(request: NextRequest) {
       }
     );
 
+    // 金额以 USD 存储与缓存，仅在格式化展示时按系统汇率换算
+    const formatDisplayCost = (value: number) =>
+      formatCurrency(
+        convertCurrency(value, systemSettings.currencyDisplay, systemSettings.currencyDisplayRate),
+        systemSettings.currencyDisplay
+      );
+
     // 格式化金额字段
     const data = rawData.map((entry) => {
       const base = {
         ...entry,
-        totalCostFormatted: formatCurrency(entry.totalCost, systemSettings.currencyDisplay),
+        totalCostFormatted: formatDisplayCost(entry.totalCost),
       };
 
       // Provider scope: add avgCost formatted fields
@@ -205,14 +212,11 @@ export async function GET(request: NextRequest) {
           ? {
               avgCostPerRequestFormatted:
                 typedEntry.avgCostPerRequest != null
-                  ? formatCurrency(typedEntry.avgCostPerRequest, systemSettings.currencyDisplay)
+                  ? formatDisplayCost(typedEntry.avgCostPerRequest)
                   : null,
               avgCostPerMillionTokensFormatted:
                 typedEntry.avgCostPerMillionTokens != null
-                  ? formatCurrency(
-                      typedEntry.avgCostPerMillionTokens,
-                      systemSettings.currencyDisplay
-                    )
+                  ? formatDisplayCost(typedEntry.avgCostPerMillionTokens)
                   : null,
             }
           : {};
@@ -220,10 +224,7 @@ export async function GET(request: NextRequest) {
       const cacheFields =
         typeof typedEntry.cacheCreationCost === "number"
           ? {
-              cacheCreationCostFormatted: formatCurrency(
-                typedEntry.cacheCreationCost,
-                systemSettings.currencyDisplay
-              ),
+              cacheCreationCostFormatted: formatDisplayCost(typedEntry.cacheCreationCost),
             }
           : {};
 
@@ -238,14 +239,14 @@ export async function GET(request: NextRequest) {
 
               return {
                 ...stat,
-                totalCostFormatted: formatCurrency(stat.totalCost, systemSettings.currencyDisplay),
+                totalCostFormatted: formatDisplayCost(stat.totalCost),
                 avgCostPerRequestFormatted:
                   stat.avgCostPerRequest != null
-                    ? formatCurrency(stat.avgCostPerRequest, systemSettings.currencyDisplay)
+                    ? formatDisplayCost(stat.avgCostPerRequest)
                     : null,
                 avgCostPerMillionTokensFormatted:
                   stat.avgCostPerMillionTokens != null
-                    ? formatCurrency(stat.avgCostPerMillionTokens, systemSettings.currencyDisplay)
+                    ? formatDisplayCost(stat.avgCostPerMillionTokens)
                     : null,
               };
             })
@@ -261,10 +262,7 @@ export async function GET(request: NextRequest) {
                 ...stat,
                 ...("totalCost" in stat && typeof stat.totalCost === "number"
                   ? {
-                      totalCostFormatted: formatCurrency(
-                        stat.totalCost,
-                        systemSettings.currencyDisplay
-                      ),
+                      totalCostFormatted: formatDisplayCost(stat.totalCost),
                     }
                   : {}),
               };
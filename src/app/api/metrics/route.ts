@@ -0,0 +1,42 @@
+import { type NextRequest, NextResponse } from "next/server";
+import { config } from "@/lib/config/config";
+import { logger } from "@/lib/logger";
+import { appMetrics, PROMETHEUS_CONTENT_TYPE } from "@/lib/metrics";
+import { collectDependencyMetrics } from "@/lib/metrics/dependencies";
+import { isAdminToken } from "@/lib/security/constant-time-compare";
+
+export const runtime = "nodejs";
+export const dynamic = "force-dynamic";
+
+function extractBearerToken(request: NextRequest): string | null {
+  const authorization = request.headers.get("authorization");
+  if (!authorization) return null;
+  const match = authorization.match(/^Bearer\s+(.+)$/i);
+  return match ? match[1].trim() : null;
+}
+
+/**
+ * Prometheus 抓取端点，需在 Authorization: Bearer 中携带 ADMIN_TOKEN
+ */
+export async function GET(request: NextRequest) {
+  const token = extractBearerToken(request);
+  if (!token || !isAdminToken(token, config.auth.adminToken)) {
+    return NextResponse.json({ error: "Unauthorized" }, { status: 401 });
+  }
+
+  try {
+    await collectDependencyMetrics();
+  } catch (error) {
+    logger.warn("[Metrics] Failed to collect dependency metrics", {
+      error: error instanceof Error ? error.message : String(error),
+    });
+  }
+
+  return new NextResponse(appMetrics.registry.render(), {
+    status: 200,
+    headers: {
+      "Content-Type": PROMETHEUS_CONTENT_TYPE,
+      "Cache-Control": "no-store",
+    },
+  });
+}
@@ -23,10 +23,25 @@ export async function getDashboardStatistics(c: Context): Promise<Response> {
   const query = DashboardStatisticsQuerySchema.safeParse({ timeRange: c.req.query("timeRange") });
   if (!query.success) return fromZodError(query.error, new URL(c.req.url).pathname);
   const actions = await import("@/actions/statistics");
-  return actionJson(
-    c,
-    await callAction(c, actions.getUserStatistics, [query.data.timeRange] as never[], c.get("auth"))
-  );
+  const args = [query.data.timeRange, { signal: c.req.raw.signal }] as never[];
+  return actionJson(c, await callAction(c, actions.getUserStatistics, args, c.get("auth")));
+}
+
+export async function exportDashboardStatistics(c: Context): Promise<Response> {
+  const query = DashboardStatisticsQuerySchema.safeParse({ timeRange: c.req.query("timeRange") });
+  if (!query.success) return fromZodError(query.error, new URL(c.req.url).pathname);
+  const [{ createUserStatisticsCsvStream }, { resolveSystemTimezone }] = await Promise.all([
+    import("@/lib/statistics/export-csv"),
+    import("@/lib/utils/timezone"),
+  ]);
+  const { timeRange } = query.data;
+  const stream = await createUserStatisticsCsvStream(timeRange, await resolveSystemTimezone());
+  return new Response(stream.pipeThrough(new TextEncoderStream()), {
+    headers: {
+      "Content-Type": "text/csv; charset=utf-8",
+      "Content-Disposition": `attachment; filename="statistics-${timeRange}.csv"`,
+    },
+  });
 }
 
 export async function getDashboardConcurrentSessions(c: Context): Promise<Response> {
@@ -8,10 +8,12 @@ import {
   DashboardGenericObjectSchema,
   DashboardOverviewResponseSchema,
   DashboardRateLimitStatsQuerySchema,
+  DashboardStatisticsCsvSchema,
   DashboardStatisticsQuerySchema,
   DispatchSimulatorInputSchema,
 } from "@/lib/api/v1/schemas/dashboard";
 import {
+  exportDashboardStatistics,
   getDashboardClientVersions,
   getDashboardConcurrentSessions,
   getDashboardOverview,
@@ -93,6 +95,28 @@ dashboardRouter.openapi(
   getDashboardStatistics as never
 );
 
+dashboardRouter.openapi(
+  createRoute({
+    method: "get",
+    path: "/dashboard/statistics/export",
+    middleware: requireAuth("admin"),
+    tags: ["Dashboard"],
+    summary: "Export dashboard statistics",
+    description: "Streams the per-user statistics timeline for the selected time range as CSV.",
+    "x-required-access": "admin",
+    security,
+    request: { query: DashboardStatisticsQuerySchema },
+    responses: {
+      200: {
+        description: "Statistics CSV.",
+        content: { "text/csv": { schema: DashboardStatisticsCsvSchema } },
+      },
+      ...problemResponses,
+    },
+  }),
+  exportDashboardStatistics as never
+);
+
 dashboardRouter.openapi(
   createRoute({
     method: "get",
@@ -22,6 +22,7 @@ export async function getSystemDisplaySettings(_c: Context): Promise<Response> {
   return jsonResponse({
     siteTitle: settings.siteTitle,
     currencyDisplay: settings.currencyDisplay,
+    currencyDisplayRate: settings.currencyDisplayRate,
     billingModelSource: settings.billingModelSource,
   });
 }
@@ -64,6 +64,12 @@ export async function getEndpointList(c: Context): Promise<Response> {
   return result.ok ? jsonResponse({ items: result.data }) : actionError(c, result);
 }
 
+export async function getApiTypeList(c: Context): Promise<Response> {
+  const actions = await import("@/actions/usage-logs");
+  const result = await callAction(c, actions.getApiTypeList, [], c.get("auth"));
+  return result.ok ? jsonResponse({ items: result.data }) : actionError(c, result);
+}
+
 export async function suggestSessionIds(c: Context): Promise<Response> {
   const query = UsageLogSessionSuggestionsQuerySchema.safeParse({
     term: c.req.query("term") ?? c.req.query("q") ?? "",
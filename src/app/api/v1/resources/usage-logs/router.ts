@@ -14,6 +14,7 @@ import {
 import {
   createUsageLogsExport,
   downloadUsageLogsExport,
+  getApiTypeList,
   getEndpointList,
   getFilterOptions,
   getModelList,
@@ -106,7 +107,8 @@ usageLogsRouter.openapi(
     middleware: requireAuth("admin"),
     tags: ["Usage Logs"],
     summary: "Get usage log filter options",
-    description: "Returns admin-scoped cached model, status-code, and endpoint filter options.",
+    description:
+      "Returns admin-scoped cached model, status-code, endpoint, and API type filter options.",
     "x-required-access": "admin",
     security,
     responses: {
@@ -183,6 +185,27 @@ usageLogsRouter.openapi(
   getEndpointList as never
 );
 
+usageLogsRouter.openapi(
+  createRoute({
+    method: "get",
+    path: "/usage-logs/api-types",
+    middleware: requireAuth("admin"),
+    tags: ["Usage Logs"],
+    summary: "List usage log API types",
+    description: "Returns distinct API type values used in admin-visible logs.",
+    "x-required-access": "admin",
+    security,
+    responses: {
+      200: {
+        description: "API types.",
+        content: { "application/json": { schema: StringListResponseSchema } },
+      },
+      ...problemResponses,
+    },
+  }),
+  getApiTypeList as never
+);
+
 usageLogsRouter.openapi(
   createRoute({
     method: "get",
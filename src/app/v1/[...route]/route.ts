@@ -9,6 +9,13 @@ import {
 } from "@/app/v1/_lib/models/available-models";
 import { handleProxyRequest } from "@/app/v1/_lib/proxy-handler";
 import { logger } from "@/lib/logger";
+import { onStreamComplete, recordProxyRequest } from "@/lib/metrics";
+import {
+  getRequestLogger,
+  REQUEST_ID_HEADER,
+  resolveRequestId,
+  runWithRequestId,
+} from "@/lib/request-id";
 import { sensitiveWordDetector } from "@/lib/sensitive-word-detector";
 import { SessionTracker } from "@/lib/session-tracker";
 
@@ -36,6 +43,52 @@ if (hasDsn) {
 
 const app = new Hono().basePath("/v1");
 
+// 请求 ID：复用客户端 X-Request-Id 或生成新值，写入异步上下文与响应头
+app.use("*", async (c, next) => {
+  const requestId = resolveRequestId(c.req.header(REQUEST_ID_HEADER));
+  await runWithRequestId(requestId, next);
+
+  try {
+    c.res.headers.set(REQUEST_ID_HEADER, requestId);
+  } catch {
+    // 透传的上游 Response 头不可变，复制一份后再写入
+    c.res = new Response(c.res.body, c.res);
+    c.res.headers.set(REQUEST_ID_HEADER, requestId);
+  }
+});
+
+// Prometheus 请求计数与耗时（流式响应在响应体结束时记录）
+app.use("*", async (c, next) => {
+  const start = performance.now();
+  // 流结束回调可能脱离请求的异步上下文，提前绑定 requestId
+  const requestLogger = getRequestLogger();
+  const record = () => {
+    const durationMs = performance.now() - start;
+    recordProxyRequest(c.req.method, c.req.path, c.res.status, durationMs);
+    requestLogger.debug("[Proxy] Request completed", {
+      method: c.req.method,
+      path: c.req.path,
+      status: c.res.status,
+      durationMs: Math.round(durationMs),
+    });
+  };
+
+  try {
+    await next();
+  } catch (error) {
+    record();
+    throw error;
+  }
+
+  const body = c.res.body;
+  if (!body || !c.res.headers.get("content-type")?.includes("text/event-stream")) {
+    record();
+    return;
+  }
+
+  c.res = new Response(onStreamComplete(body, record), c.res);
+});
+
 registerCors(app);
 
 // 模型列表端点
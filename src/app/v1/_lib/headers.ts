@@ -100,6 +100,52 @@ export function resolveAnthropicAuthHeaders(
   };
 }
 
+/**
+ * 构造供应商开启 preserveClientIp 时需要覆盖的客户端 IP 头
+ *
+ * - preserve 为 false 时返回空对象，相关头由 HeaderProcessor 默认黑名单剥离
+ * - clientIp 优先使用鉴权阶段按 ip_extraction_config 解析出的 session.clientIp，
+ *   缺失时回退到请求头中的第一个候选 IP
+ * - 已有 x-forwarded-for 链时保留原链，若链中不含 clientIp 则追加到末尾
+ */
+export function buildClientIpForwardHeaders(
+  headers: Headers,
+  clientIp: string | null | undefined,
+  preserve: boolean
+): Record<string, string> {
+  if (!preserve) {
+    return {};
+  }
+
+  const xffParts =
+    headers
+      .get("x-forwarded-for")
+      ?.split(",")
+      .map((ip) => ip.trim())
+      .filter(Boolean) ?? [];
+
+  const resolvedIp =
+    clientIp?.trim() ||
+    [
+      ...xffParts,
+      headers.get("x-real-ip")?.trim(),
+      headers.get("x-client-ip")?.trim(),
+      headers.get("x-originating-ip")?.trim(),
+      headers.get("x-remote-ip")?.trim(),
+      headers.get("x-remote-addr")?.trim(),
+    ].find((ip): ip is string => !!ip);
+
+  if (!resolvedIp) {
+    return {};
+  }
+
+  const chain = xffParts.includes(resolvedIp) ? xffParts : [...xffParts, resolvedIp];
+  return {
+    "x-forwarded-for": chain.join(", "),
+    "x-real-ip": resolvedIp,
+  };
+}
+
 /**
  * 代理请求 Header 处理器
  */
@@ -6,10 +6,11 @@ import {
   isValidErrorOverrideResponse,
 } from "@/lib/error-override-validator";
 import { emitProxyLangfuseTrace } from "@/lib/langfuse/emit-proxy-trace";
-import { logger } from "@/lib/logger";
 import { ProxyStatusTracker } from "@/lib/proxy-status-tracker";
+import { getRequestLogger } from "@/lib/request-id";
 import { sanitizeErrorTextForDetail } from "@/lib/utils/upstream-error-detection";
 import { updateMessageRequestDetails, updateMessageRequestDuration } from "@/repository/message";
+import type { RateLimitMetadata } from "@/types/statistics";
 import type { SystemSettings } from "@/types/system-config";
 import { deriveClientSafeUpstreamErrorMessage } from "./client-error-message";
 import { attachSessionIdToErrorResponse } from "./error-session-id";
@@ -174,7 +175,7 @@ export class ProxyErrorHandler {
     let clientErrorMessage: string;
     let logErrorMessage: string;
     let statusCode = 500;
-    let rateLimitMetadata: Record<string, unknown> | null = null;
+    let rateLimitMetadata: RateLimitMetadata | null = null;
     let settingsResolved = false;
     let cachedSettings: SystemSettings | null = null;
 
@@ -186,9 +187,12 @@ export class ProxyErrorHandler {
         return cachedSettings;
       } catch (settingsError) {
         settingsResolved = true;
-        logger.warn("ProxyErrorHandler: failed to load system settings, using defaults", {
-          error: settingsError instanceof Error ? settingsError.message : String(settingsError),
-        });
+        getRequestLogger().warn(
+          "ProxyErrorHandler: failed to load system settings, using defaults",
+          {
+            error: settingsError instanceof Error ? settingsError.message : String(settingsError),
+          }
+        );
         return null;
       }
     };
@@ -292,10 +296,13 @@ export class ProxyErrorHandler {
             validatedStatusCode < OVERRIDE_STATUS_CODE_MIN ||
             validatedStatusCode > OVERRIDE_STATUS_CODE_MAX)
         ) {
-          logger.warn("ProxyErrorHandler: Invalid override status code, falling back to upstream", {
-            overrideStatusCode: validatedStatusCode,
-            upstreamStatusCode: statusCode,
-          });
+          getRequestLogger().warn(
+            "ProxyErrorHandler: Invalid override status code, falling back to upstream",
+            {
+              overrideStatusCode: validatedStatusCode,
+              upstreamStatusCode: statusCode,
+            }
+          );
           validatedStatusCode = null;
         }
 
@@ -313,9 +320,12 @@ export class ProxyErrorHandler {
           // 运行时守卫：验证覆写响应格式是否合法（双重保护，加载时已过滤一次）
           // 防止数据库中存在畸形数据导致返回不合规响应
           if (!isValidErrorOverrideResponse(override.response)) {
-            logger.warn("ProxyErrorHandler: Invalid override response in database, skipping", {
-              response: JSON.stringify(override.response).substring(0, 200),
-            });
+            getRequestLogger().warn(
+              "ProxyErrorHandler: Invalid override response in database, skipping",
+              {
+                response: JSON.stringify(override.response).substring(0, 200),
+              }
+            );
             // 跳过响应体覆写，但仍可应用状态码覆写
             if (override.statusCode !== null) {
               const finalClientErrorMessage = resolveFinalClientErrorMessage({
@@ -383,7 +393,7 @@ export class ProxyErrorHandler {
             },
           };
 
-          logger.info("ProxyErrorHandler: Applied error override response", {
+          getRequestLogger().info("ProxyErrorHandler: Applied error override response", {
             original: logErrorMessage.substring(0, 200),
             format: isClaudeErrorFormat(override.response)
               ? "claude"
@@ -395,7 +405,7 @@ export class ProxyErrorHandler {
             statusCode: responseStatusCode,
           });
 
-          logger.error("ProxyErrorHandler: Request failed (overridden)", {
+          getRequestLogger().error("ProxyErrorHandler: Request failed (overridden)", {
             error: logErrorMessage,
             statusCode: responseStatusCode,
             overridden: true,
@@ -412,14 +422,14 @@ export class ProxyErrorHandler {
         }
 
         // 情况 2: 仅状态码覆写 - 返回客户端安全消息，但使用覆写的状态码
-        logger.info("ProxyErrorHandler: Applied status code override only", {
+        getRequestLogger().info("ProxyErrorHandler: Applied status code override only", {
           original: logErrorMessage.substring(0, 200),
           originalStatusCode: statusCode,
           overrideStatusCode: responseStatusCode,
           hasRequestId: !!safeRequestId,
         });
 
-        logger.error("ProxyErrorHandler: Request failed (status overridden)", {
+        getRequestLogger().error("ProxyErrorHandler: Request failed (status overridden)", {
           error: logErrorMessage,
           statusCode: responseStatusCode,
           overridden: true,
@@ -446,7 +456,7 @@ export class ProxyErrorHandler {
       }
     }
 
-    logger.error("ProxyErrorHandler: Request failed", {
+    getRequestLogger().error("ProxyErrorHandler: Request failed", {
       error: logErrorMessage,
       statusCode,
       overridden: false,
@@ -502,7 +512,7 @@ export class ProxyErrorHandler {
           }
         }
       } catch (verboseError) {
-        logger.warn("ProxyErrorHandler: failed to gather verbose details, skipping", {
+        getRequestLogger().warn("ProxyErrorHandler: failed to gather verbose details, skipping", {
           error: verboseError instanceof Error ? verboseError.message : String(verboseError),
         });
       }
@@ -609,14 +619,14 @@ export class ProxyErrorHandler {
   /**
    * 记录错误到数据库
    *
-   * 如果提供了 rateLimitMetadata，将其 JSON 序列化后存入 errorMessage
+   * 如果提供了 rateLimitMetadata，存入 rate_limit_metadata 列，同时继续附加到 errorMessage（兼容旧读取方）
    * 供应商决策链保持不变，存入 providerChain 字段
    */
   private static async logErrorToDatabase(
     session: ProxySession,
     errorMessage: string,
     statusCode: number,
-    rateLimitMetadata: Record<string, unknown> | null
+    rateLimitMetadata: RateLimitMetadata | null
   ): Promise<void> {
     if (!session.messageContext) {
       return;
@@ -636,6 +646,7 @@ export class ProxyErrorHandler {
       errorMessage: finalErrorMessage,
       providerChain: session.getProviderChain(),
       statusCode: statusCode,
+      rateLimitMetadata: rateLimitMetadata ?? undefined,
       model: session.getCurrentModel() ?? undefined,
       providerId: session.provider?.id, // ⭐ 更新最终供应商ID（重试切换后）
       context1mApplied: session.getContext1mApplied(),
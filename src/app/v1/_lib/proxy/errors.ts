@@ -3,7 +3,7 @@
  *
  * 设计原则：
  * 1. 数据结构优先：错误不是字符串，而是结构化对象
- * 2. 智能截断：JSON 完整保存，文本限制 500 字符
+ * 2. 智能截断：JSON 优先保留 error/message 字段，超长响应体按字符截断
  * 3. 可读性优先：纯文本格式化，便于排查问题
  */
 import { getEnvConfig } from "@/lib/config/env.schema";
@@ -31,6 +31,13 @@ export class ProxyError extends Error {
        * 因此不要在这里放入“大段原文”或未脱敏的敏感内容。
        */
       body: string;
+      /**
+       * 未截断的上游响应体（JSON 为完整序列化结果）。
+       *
+       * 仅用于错误规则匹配，保证规则能命中截断后被省略的字段或超出长度的内容；
+       * 不进入 getDetailedErrorMessage()，也不会被持久化。
+       */
+      detectionBody?: string;
       parsed?: unknown; // 解析后的 JSON（如果有）
       providerId?: number;
       providerName?: string;
@@ -81,7 +88,7 @@ export class ProxyError extends Error {
    * 1. 读取响应体
    * 2. 识别 Content-Type 并解析 JSON
    * 3. 从 JSON 提取错误消息（支持多种格式）
-   * 4. 智能截断（JSON 完整，文本 500 字符）
+   * 4. 智能截断（JSON 2000 字符，文本 500 字符）
    */
   static async fromUpstreamResponse(
     response: Response,
@@ -112,7 +119,8 @@ export class ProxyError extends Error {
     const fallbackMessage = `Provider returned ${response.status}: ${response.statusText}`;
     const message = extractedMessage || fallbackMessage;
 
-    // 4. 智能截断响应体
+    // 4. 智能截断响应体（截断结果用于存储/日志，完整内容保留给错误规则匹配）
+    const detectionBody = parsed ? JSON.stringify(parsed) : body;
     const truncatedBody = ProxyError.smartTruncate(body, parsed);
 
     // 5. 提取 request_id（从响应体或响应头）
@@ -122,6 +130,7 @@ export class ProxyError extends Error {
 
     return new ProxyError(message, response.status, {
       body: truncatedBody,
+      detectionBody,
       parsed,
       providerId: provider.id,
       providerName: provider.name,
@@ -442,21 +451,15 @@ export class ProxyError extends Error {
 
   /**
    * 智能截断响应体
-   * - JSON: 完整保存（序列化后）
+   * - JSON: 序列化后限制 2000 字符（超长时优先保留 error/message 字段）
    * - 文本: 限制 500 字符
    */
   private static smartTruncate(body: string, parsed?: unknown): string {
     if (parsed) {
-      // JSON 格式：完整保存
-      return JSON.stringify(parsed);
+      return truncateUpstreamBody(JSON.stringify(parsed), UPSTREAM_JSON_BODY_MAX_LENGTH);
     }
 
-    // 纯文本：截断到 500 字符
-    if (body.length > 500) {
-      return `${body.substring(0, 500)}...`;
-    }
-
-    return body;
+    return truncateUpstreamBody(body, UPSTREAM_TEXT_BODY_MAX_LENGTH);
   }
 
   /**
@@ -559,13 +562,14 @@ export enum ErrorCategory {
 /**
  * 从错误对象中提取用于规则匹配的内容
  *
- * 优先使用整个响应体（upstreamError.body），这样规则可以匹配响应中的任何内容
- * 如果没有响应体，则使用错误消息
+ * 优先使用未截断的整个响应体（upstreamError.detectionBody），这样规则可以匹配响应中的任何内容；
+ * 没有完整响应体时退回存储用的 upstreamError.body，都没有则使用错误消息
  */
 function extractErrorContentForDetection(error: Error): string {
   // 优先使用整个响应体进行规则匹配
-  if (error instanceof ProxyError && error.upstreamError?.body) {
-    return error.upstreamError.body;
+  if (error instanceof ProxyError) {
+    const content = error.upstreamError?.detectionBody || error.upstreamError?.body;
+    if (content) return content;
   }
   return error.message;
 }
@@ -1135,6 +1139,10 @@ const SENSITIVE_URL_PARAMS = new Set([
 
 const REQUEST_BODY_MAX_LENGTH = 2000;
 
+/** 上游错误响应体截断长度（字符）：JSON / 纯文本 */
+const UPSTREAM_JSON_BODY_MAX_LENGTH = 2000;
+const UPSTREAM_TEXT_BODY_MAX_LENGTH = 500;
+
 /** 敏感值遮罩：保留前缀长度 */
 const MASK_PREFIX_LENGTH = 4;
 /** 敏感值遮罩：保留后缀长度 */
@@ -1289,6 +1297,46 @@ export function sanitizeUrl(url: string | URL): string {
   return `${parsedUrl.origin}${parsedUrl.pathname}${search}${parsedUrl.hash}`;
 }
 
+/**
+ * 截断上游错误响应体
+ *
+ * 按字符（Unicode 码点）而非字节截断，避免切断多字节 UTF-8 字符，
+ * 并追加 `…(truncated N bytes)` 标记（N 为被省略的 UTF-8 字节数）。
+ * 响应体为 JSON 对象时优先保留顶层 error/message 字段，丢弃其余内容后再按需截断。
+ *
+ * @param body - 上游响应体原文
+ * @param maxLength - 最大字符数
+ */
+export function truncateUpstreamBody(body: string, maxLength: number): string {
+  const chars = Array.from(body);
+  if (chars.length <= maxLength) {
+    return body;
+  }
+
+  let candidate = body;
+  try {
+    const parsed = JSON.parse(body) as unknown;
+    if (parsed && typeof parsed === "object" && !Array.isArray(parsed)) {
+      const obj = parsed as Record<string, unknown>;
+      const preserved: Record<string, unknown> = {};
+      if (obj.error !== undefined) preserved.error = obj.error;
+      if (obj.message !== undefined) preserved.message = obj.message;
+      if (Object.keys(preserved).length > 0) {
+        candidate = JSON.stringify(preserved);
+      }
+    }
+  } catch {
+    // 非 JSON，按原文截断
+  }
+
+  const candidateChars = Array.from(candidate);
+  const kept =
+    candidateChars.length <= maxLength ? candidate : candidateChars.slice(0, maxLength).join("");
+  const omittedBytes = Buffer.byteLength(body, "utf8") - Buffer.byteLength(kept, "utf8");
+
+  return `${kept}…(truncated ${omittedBytes} bytes)`;
+}
+
 /**
  * 截断请求体
  *
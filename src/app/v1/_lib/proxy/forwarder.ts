@@ -18,7 +18,6 @@ import {
 import { applyCodexProviderOverridesWithAudit } from "@/lib/codex/provider-overrides";
 import { getCachedSystemSettings, isHttp2Enabled } from "@/lib/config";
 import { getEnvConfig } from "@/lib/config/env.schema";
-import { PROVIDER_DEFAULTS, PROVIDER_LIMITS } from "@/lib/constants/provider.constants";
 import { PROTECTED_AUTH_HEADER_NAMES } from "@/lib/custom-headers";
 import { recordEndpointFailure, recordEndpointSuccess } from "@/lib/endpoint-circuit-breaker";
 import { applyGeminiGoogleSearchOverrideWithAudit } from "@/lib/gemini/provider-overrides";
@@ -50,7 +49,11 @@ import type { SystemSettings } from "@/types/system-config";
 
 import { GeminiAuth } from "../gemini/auth";
 import { GEMINI_PROTOCOL } from "../gemini/protocol";
-import { HeaderProcessor, resolveAnthropicAuthHeaders } from "../headers";
+import {
+  buildClientIpForwardHeaders,
+  HeaderProcessor,
+  resolveAnthropicAuthHeaders,
+} from "../headers";
 import {
   evaluateResponsesWsEligibility,
   getResponsesWsSessionId,
@@ -97,6 +100,7 @@ import {
 } from "./openai-image-compat";
 import { ProxyProviderResolver } from "./provider-selector";
 import { finalizeHedgeLoserBilling } from "./response-handler";
+import { clampRetryAttempts, resolveMaxAttemptsForProvider } from "./retry-budget";
 import type { ProxySession } from "./session";
 import { setDeferredStreamingFinalization } from "./stream-finalization";
 import {
@@ -186,7 +190,6 @@ function applyProviderCustomHeaders(
   }
 }
 
-const RETRY_LIMITS = PROVIDER_LIMITS.MAX_RETRY_ATTEMPTS;
 const MAX_PROVIDER_SWITCHES = 20; // 保险栓：最多切换 20 次供应商（防止无限循环）
 
 type CacheTtlOption = CacheTtlPreference | null | undefined;
@@ -457,23 +460,6 @@ export function mergeAnthropicCacheTtlBetaFlag(existing: string | null | undefin
   return Array.from(betaFlags).join(", ");
 }
 
-function clampRetryAttempts(value: number): number {
-  const numeric = Number(value);
-  if (!Number.isFinite(numeric)) return RETRY_LIMITS.MIN;
-  return Math.min(Math.max(numeric, RETRY_LIMITS.MIN), RETRY_LIMITS.MAX);
-}
-
-function resolveMaxAttemptsForProvider(
-  provider: ProxySession["provider"],
-  envDefault: number
-): number {
-  const baseDefault = clampRetryAttempts(envDefault ?? PROVIDER_DEFAULTS.MAX_RETRY_ATTEMPTS);
-  if (!provider || provider.maxRetryAttempts === null || provider.maxRetryAttempts === undefined) {
-    return baseDefault;
-  }
-  return clampRetryAttempts(provider.maxRetryAttempts);
-}
-
 function buildEndpointAttemptKey(endpointId: number | null, endpointUrl: string): string {
   return endpointId != null ? `id:${endpointId}` : `url:${endpointUrl}`;
 }
@@ -5019,7 +5005,6 @@ export class ProxyForwarder {
   ): Headers {
     const outboundKey = provider.key;
     const preserveClientIp = provider.preserveClientIp ?? false;
-    const { clientIp, xForwardedFor } = ProxyForwarder.resolveClientIp(session.headers);
 
     // 构建请求头覆盖规则
     const overrides: Record<string, string> = {
@@ -5071,14 +5056,10 @@ export class ProxyForwarder {
       });
     }
 
-    if (preserveClientIp) {
-      if (xForwardedFor) {
-        overrides["x-forwarded-for"] = xForwardedFor;
-      }
-      if (clientIp) {
-        overrides["x-real-ip"] = clientIp;
-      }
-    }
+    Object.assign(
+      overrides,
+      buildClientIpForwardHeaders(session.headers, session.clientIp, preserveClientIp)
+    );
 
     // 针对 1h 缓存 TTL，补充 Anthropic beta header（避免客户端遗漏）
     if (session.getCacheTtlResolved && session.getCacheTtlResolved() === "1h") {
@@ -5103,7 +5084,6 @@ export class ProxyForwarder {
     isApiKey: boolean
   ): Headers {
     const preserveClientIp = provider.preserveClientIp ?? false;
-    const { clientIp, xForwardedFor } = ProxyForwarder.resolveClientIp(session.headers);
 
     const overrides: Record<string, string> = {
       host: HeaderProcessor.extractHost(baseUrl),
@@ -5125,14 +5105,10 @@ export class ProxyForwarder {
       overrides[GEMINI_PROTOCOL.HEADERS.API_CLIENT] = "GeminiCLI/1.0";
     }
 
-    if (preserveClientIp) {
-      if (xForwardedFor) {
-        overrides["x-forwarded-for"] = xForwardedFor;
-      }
-      if (clientIp) {
-        overrides["x-real-ip"] = clientIp;
-      }
-    }
+    Object.assign(
+      overrides,
+      buildClientIpForwardHeaders(session.headers, session.clientIp, preserveClientIp)
+    );
 
     const headerProcessor = HeaderProcessor.createForProxy({
       blacklist: [
@@ -5147,32 +5123,6 @@ export class ProxyForwarder {
     return headerProcessor.process(session.headers);
   }
 
-  private static resolveClientIp(headers: Headers): {
-    clientIp: string | null;
-    xForwardedFor: string | null;
-  } {
-    const xffRaw = headers.get("x-forwarded-for");
-    const xffParts =
-      xffRaw
-        ?.split(",")
-        .map((ip) => ip.trim())
-        .filter(Boolean) ?? [];
-
-    const candidateIps = [
-      ...xffParts,
-      headers.get("x-real-ip")?.trim(),
-      headers.get("x-client-ip")?.trim(),
-      headers.get("x-originating-ip")?.trim(),
-      headers.get("x-remote-ip")?.trim(),
-      headers.get("x-remote-addr")?.trim(),
-    ].filter((ip): ip is string => !!ip);
-
-    const clientIp = candidateIps[0] ?? null;
-    const xForwardedFor = xffParts.length > 0 ? xffParts.join(", ") : clientIp;
-
-    return { clientIp, xForwardedFor: xForwardedFor ?? null };
-  }
-
   /**
    * 使用 undici.request 绕过 fetch 的自动解压
    *
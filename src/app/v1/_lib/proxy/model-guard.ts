@@ -1,6 +1,54 @@
+import { matchesAllowedModelRules } from "@/lib/allowed-model-rules";
+import type { Provider } from "@/types/provider";
+import type { User } from "@/types/user";
 import { ProxyResponses } from "./responses";
 import type { ProxySession } from "./session";
 
+export type ModelAccessLayer = "user" | "provider";
+
+export type ModelAccessResult =
+  | { allowed: true }
+  | { allowed: false; reason: "model_not_allowed"; layer: ModelAccessLayer };
+
+/**
+ * User-level allowlist check: case-insensitive exact match.
+ * Empty or undefined allowedModels means no restriction.
+ */
+function userAllowsModel(allowedModels: string[], requestedModel: string): boolean {
+  const requestedModelLower = requestedModel.toLowerCase();
+  return allowedModels.some((pattern) => pattern.toLowerCase() === requestedModelLower);
+}
+
+/**
+ * Combined model access check across allowlist layers, evaluated in order:
+ * user allowlist → provider allowlist.
+ *
+ * - An empty allowlist means "allow all" at that layer
+ * - A missing model is rejected by the user layer only when it has restrictions;
+ *   the provider layer (like the scheduler) only checks concrete model names
+ * - Provider rules support exact/prefix/suffix/contains/regex matching
+ *
+ * Returns the first layer that rejected the model.
+ */
+export function resolveModelAccess(
+  user: Pick<User, "allowedModels"> | null | undefined,
+  provider: Pick<Provider, "allowedModels"> | null | undefined,
+  requestedModel: string | null | undefined
+): ModelAccessResult {
+  const model = requestedModel?.trim() ? requestedModel : null;
+
+  const userAllowedModels = user?.allowedModels ?? [];
+  if (userAllowedModels.length > 0 && (!model || !userAllowsModel(userAllowedModels, model))) {
+    return { allowed: false, reason: "model_not_allowed", layer: "user" };
+  }
+
+  if (model && provider && !matchesAllowedModelRules(model, provider.allowedModels)) {
+    return { allowed: false, reason: "model_not_allowed", layer: "provider" };
+  }
+
+  return { allowed: true };
+}
+
 /**
  * Model restriction guard
  *
@@ -43,13 +91,7 @@ export class ProxyModelGuard {
       );
     }
 
-    // Case-insensitive exact match
-    const requestedModelLower = requestedModel.toLowerCase();
-    const isAllowed = allowedModels.some(
-      (pattern) => pattern.toLowerCase() === requestedModelLower
-    );
-
-    if (!isAllowed) {
+    if (!userAllowsModel(allowedModels, requestedModel)) {
       return ProxyResponses.buildError(
         400,
         `Model not allowed. The requested model '${requestedModel}' is not in the allowed list.`,
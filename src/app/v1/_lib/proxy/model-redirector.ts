@@ -1,5 +1,6 @@
 import { logger } from "@/lib/logger";
 import {
+  buildModelRedirectSpecialSetting,
   findMatchingProviderModelRedirectRule,
   getProviderModelRedirectTarget,
   hasProviderModelRedirectRules,
@@ -148,6 +149,9 @@ export class ModelRedirector {
     // 否则保留快照，等待后续 hedge_winner / retry_failed 等真正属于该 provider 的链路项来消费。
     session.setCurrentModelRedirect(provider.id, redirectInfo);
     session.attachCurrentModelRedirectToLastChainItem(provider.id);
+    session.addSpecialSetting(
+      buildModelRedirectSpecialSetting(provider, originalModel, redirectedModel)
+    );
     logger.debug("[ModelRedirector] Recorded modelRedirect for current provider attempt", {
       providerId: provider.id,
       originalModel,
@@ -2,6 +2,7 @@ import { matchesAllowedModelRules } from "@/lib/allowed-model-rules";
 import { getCircuitState, isCircuitOpen } from "@/lib/circuit-breaker";
 import { PROVIDER_GROUP } from "@/lib/constants/provider.constants";
 import { logger } from "@/lib/logger";
+import { getEffectiveWeight, selectWeightedProvider } from "@/lib/provider-weighted-selection";
 import { RateLimitService } from "@/lib/rate-limit";
 import { SessionManager } from "@/lib/session-manager";
 import { parseProviderGroups, resolveProviderGroupsWithDefault } from "@/lib/utils/provider-group";
@@ -1001,16 +1002,17 @@ export class ProxyProviderResolver {
     );
 
     // Step 6: 成本排序 + 加权选择 + 计算概率
-    const totalWeight = topPriorityProviders.reduce((sum, p) => sum + p.weight, 0);
+    // 概率按与 selectWeightedProvider 相同的有效权重计算（weight <= 0 视为 1）
+    const totalWeight = topPriorityProviders.reduce((sum, p) => sum + getEffectiveWeight(p), 0);
     context.candidatesAtPriority = topPriorityProviders.map((p) => ({
       id: p.id,
       name: p.name,
       weight: p.weight,
       costMultiplier: p.costMultiplier,
-      probability: totalWeight > 0 ? p.weight / totalWeight : 0,
+      probability: getEffectiveWeight(p) / totalWeight,
     }));
 
-    const selected = ProxyProviderResolver.selectOptimal(topPriorityProviders);
+    const selected = ProxyProviderResolver.selectOptimal(topPriorityProviders, effectiveGroupPick);
 
     // 详细的选择日志
     logger.info("ProviderSelector: Selection decision", {
@@ -1151,8 +1153,9 @@ export class ProxyProviderResolver {
 
   /**
    * 成本排序 + 加权选择：在同优先级内，按成本排序后加权随机
+   * 优先级按分组覆盖后的有效优先级解析，与 selectTopPriority 保持一致
    */
-  private static selectOptimal(providers: Provider[]): Provider {
+  private static selectOptimal(providers: Provider[], userGroup?: string | null): Provider {
     if (providers.length === 0) {
       throw new Error("No providers available for selection");
     }
@@ -1168,32 +1171,11 @@ export class ProxyProviderResolver {
       return costA - costB;
     });
 
-    // 加权随机选择（复用现有逻辑）
-    return ProxyProviderResolver.weightedRandom(sorted);
-  }
-
-  /**
-   * 加权随机选择
-   */
-  private static weightedRandom(providers: Provider[]): Provider {
-    const totalWeight = providers.reduce((sum, p) => sum + p.weight, 0);
-
-    if (totalWeight === 0) {
-      const randomIndex = Math.floor(Math.random() * providers.length);
-      return providers[randomIndex];
-    }
-
-    const random = Math.random() * totalWeight;
-    let cumulativeWeight = 0;
-
-    for (const provider of providers) {
-      cumulativeWeight += provider.weight;
-      if (random < cumulativeWeight) {
-        return provider;
-      }
-    }
-
-    return providers[providers.length - 1];
+    const group = userGroup ?? null;
+    const selected = selectWeightedProvider(sorted, {
+      resolvePriority: (p) => ProxyProviderResolver.resolveEffectivePriority(p, group),
+    });
+    return selected ?? sorted[0];
   }
 
   /**
@@ -1295,16 +1277,17 @@ export class ProxyProviderResolver {
     );
 
     // 成本排序 + 加权随机选择
-    const selected = ProxyProviderResolver.selectOptimal(topPriorityProviders);
+    const selected = ProxyProviderResolver.selectOptimal(topPriorityProviders, effectiveGroupPick);
 
     // 计算候选者概率
-    const totalWeight = topPriorityProviders.reduce((sum, p) => sum + p.weight, 0);
+    // 概率按与 selectWeightedProvider 相同的有效权重计算（weight <= 0 视为 1）
+    const totalWeight = topPriorityProviders.reduce((sum, p) => sum + getEffectiveWeight(p), 0);
     const candidates = topPriorityProviders.map((p) => ({
       id: p.id,
       name: p.name,
       weight: p.weight,
       costMultiplier: p.costMultiplier,
-      probability: totalWeight > 0 ? p.weight / totalWeight : 1 / topPriorityProviders.length,
+      probability: getEffectiveWeight(p) / totalWeight,
     }));
 
     return {
@@ -0,0 +1,77 @@
+import { getEnvConfig } from "@/lib/config/env.schema";
+import type { Provider } from "@/types/provider";
+
+/**
+ * 全局 fetch 超时（与全局 undici Agent 使用同一套环境变量）
+ * - headersTimeoutMs: FETCH_HEADERS_TIMEOUT，等待响应头的上限
+ * - bodyTimeoutMs: FETCH_BODY_TIMEOUT，响应体两次数据之间的最大间隔
+ */
+export interface GlobalFetchTimeouts {
+  headersTimeoutMs: number;
+  bodyTimeoutMs: number;
+}
+
+/**
+ * 解析后的实际生效超时（毫秒），0 表示不限制
+ */
+export interface ResolvedProviderTimeouts {
+  /** 流式：首字节超时；非流式：等待响应头超时 */
+  firstByteMs: number;
+  /** 响应体静默期超时（两次数据之间的最大间隔） */
+  idleMs: number;
+  /** 非流式总超时；流式请求不设总超时 */
+  totalMs: number;
+}
+
+type ProviderTimeoutFields = Pick<
+  Provider,
+  "firstByteTimeoutStreamingMs" | "streamingIdleTimeoutMs" | "requestTimeoutNonStreamingMs"
+>;
+
+export function getGlobalFetchTimeouts(): GlobalFetchTimeouts {
+  const env = getEnvConfig();
+  return {
+    headersTimeoutMs: env.FETCH_HEADERS_TIMEOUT,
+    bodyTimeoutMs: env.FETCH_BODY_TIMEOUT,
+  };
+}
+
+function pickTimeout(providerValue: number | null | undefined, fallback: number): number {
+  if (typeof providerValue === "number" && Number.isFinite(providerValue) && providerValue > 0) {
+    return providerValue;
+  }
+  return Number.isFinite(fallback) && fallback > 0 ? fallback : 0;
+}
+
+// 取两个超时中更严格的一个，0（不限制）不参与比较
+function stricterTimeout(a: number, b: number): number {
+  if (a <= 0) return b;
+  if (b <= 0) return a;
+  return Math.min(a, b);
+}
+
+/**
+ * 合并供应商超时配置与全局 fetch 超时：供应商字段为 0（默认值）时继承全局配置
+ */
+export function resolveProviderTimeouts(
+  provider: ProviderTimeoutFields,
+  streaming: boolean,
+  global: GlobalFetchTimeouts = getGlobalFetchTimeouts()
+): ResolvedProviderTimeouts {
+  if (streaming) {
+    return {
+      firstByteMs: pickTimeout(provider.firstByteTimeoutStreamingMs, global.headersTimeoutMs),
+      idleMs: pickTimeout(provider.streamingIdleTimeoutMs, global.bodyTimeoutMs),
+      totalMs: 0,
+    };
+  }
+
+  // 非流式没有全局总超时，未配置时仅受全局 headers/body 超时约束
+  const totalMs = pickTimeout(provider.requestTimeoutNonStreamingMs, 0);
+  return {
+    // 总超时同样覆盖等待响应头阶段
+    firstByteMs: stricterTimeout(totalMs, pickTimeout(0, global.headersTimeoutMs)),
+    idleMs: pickTimeout(0, global.bodyTimeoutMs),
+    totalMs,
+  };
+}
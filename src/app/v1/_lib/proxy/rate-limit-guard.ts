@@ -207,7 +207,7 @@ export class ProxyRateLimitGuard {
       if (!rpmCheck.allowed) {
         logger.warn(`[RateLimit] User RPM exceeded: user=${user.id}, ${rpmCheck.reason}`);
 
-        const resetTime = new Date(Date.now() + 60 * 1000).toISOString();
+        const resetTime = (rpmCheck.resetAt ?? new Date(Date.now() + 60 * 1000)).toISOString();
 
         const { getLocale } = await import("next-intl/server");
         const locale = await getLocale();
@@ -61,6 +61,21 @@ export const MAX_COMPRESSED_REQUEST_BYTES = parseByteLimitEnv(
   MAX_DECOMPRESSED_REQUEST_BYTES
 );
 
+/**
+ * 入站请求体（线上原始字节）上限，防御超大请求体在鉴权前占满内存。超过按 413 拒绝。
+ *
+ * 默认 10MB，可经环境变量 MAX_REQUEST_BODY_BYTES 覆盖（字节数）；设为 0 表示不限制
+ * （兼容引入该上限之前的行为，需要转发超大图片/上下文请求的部署可使用）。
+ */
+export const MAX_REQUEST_BODY_BYTES = parseRequestBodyLimitEnv(10 * 1024 * 1024);
+
+function parseRequestBodyLimitEnv(fallback: number): number {
+  const raw = process.env.MAX_REQUEST_BODY_BYTES;
+  if (!raw) return fallback;
+  const n = Number(raw);
+  return Number.isFinite(n) && n >= 0 ? Math.trunc(n) : fallback;
+}
+
 /**
  * content-encoding 编码链最大层数。真实客户端（含 Codex）只发单层编码；允许多层会让一个
  * 很小的压缩体经多次同步解压放大 CPU 开销与峰值内存（每层最多解到 maxOutputBytes，层间
@@ -152,6 +167,56 @@ function bufferToArrayBuffer(buf: Buffer): ArrayBuffer {
   return buf.buffer.slice(buf.byteOffset, buf.byteOffset + buf.byteLength) as ArrayBuffer;
 }
 
+function requestBodyTooLargeError(maxBytes: number): ProxyError {
+  return new ProxyError(`Request body exceeds the maximum allowed size (${maxBytes} bytes).`, 413);
+}
+
+/**
+ * 读取入站请求体原始字节，超过 maxBytes 即中止读取并抛 413（maxBytes 为 0 时不限制）。
+ *
+ * 先按 content-length 头快速拒绝；chunked 等无长度请求逐块累加，超限后取消读取，
+ * 避免把超大请求体完整读入内存。
+ */
+export async function readRequestBodyWithLimit(
+  request: Request,
+  maxBytes: number = MAX_REQUEST_BODY_BYTES
+): Promise<ArrayBuffer> {
+  if (maxBytes <= 0) {
+    return request.arrayBuffer();
+  }
+
+  const contentLength = Number(request.headers.get("content-length"));
+  if (Number.isFinite(contentLength) && contentLength > maxBytes) {
+    throw requestBodyTooLargeError(maxBytes);
+  }
+
+  if (!request.body) {
+    return new ArrayBuffer(0);
+  }
+
+  const reader = request.body.getReader();
+  const chunks: Uint8Array[] = [];
+  let totalBytes = 0;
+  while (true) {
+    const { done, value } = await reader.read();
+    if (done) break;
+    totalBytes += value.byteLength;
+    if (totalBytes > maxBytes) {
+      await reader.cancel().catch(() => undefined);
+      throw requestBodyTooLargeError(maxBytes);
+    }
+    chunks.push(value);
+  }
+
+  const body = new Uint8Array(totalBytes);
+  let offset = 0;
+  for (const chunk of chunks) {
+    body.set(chunk, offset);
+    offset += chunk.byteLength;
+  }
+  return body.buffer;
+}
+
 /**
  * 按 `content-encoding` 解压入站请求体。
  *
@@ -433,6 +433,10 @@ export type UsageMetrics = {
   // 图片 modality tokens（从 candidatesTokensDetails/promptTokensDetails 提取）
   input_image_tokens?: number;
   output_image_tokens?: number;
+  // Claude 服务端搜索次数（server_tool_use.web_search_requests）
+  web_search_requests?: number;
+  // OpenAI Images API 生成图片张数（data[] 长度）
+  output_images?: number;
 };
 
 function maybeSetCodexContext1m(
@@ -3578,6 +3582,17 @@ export function extractUsageMetrics(value: unknown): UsageMetrics | null {
     hasAny = true;
   }
 
+  // Claude 服务端工具：web search 按次计费
+  const serverToolUse = usage.server_tool_use as Record<string, unknown> | undefined;
+  if (
+    serverToolUse &&
+    typeof serverToolUse.web_search_requests === "number" &&
+    serverToolUse.web_search_requests > 0
+  ) {
+    result.web_search_requests = serverToolUse.web_search_requests;
+    hasAny = true;
+  }
+
   if (result.cache_read_input_tokens === undefined) {
     const inputTokensDetails = usage.input_tokens_details as Record<string, unknown> | undefined;
     if (inputTokensDetails && typeof inputTokensDetails.cached_tokens === "number") {
@@ -3603,6 +3618,20 @@ export function extractUsageMetrics(value: unknown): UsageMetrics | null {
   return hasAny ? result : null;
 }
 
+function countGeneratedImages(data: unknown): number {
+  if (!Array.isArray(data)) {
+    return 0;
+  }
+
+  return data.filter(
+    (item) =>
+      item &&
+      typeof item === "object" &&
+      (typeof (item as Record<string, unknown>).b64_json === "string" ||
+        typeof (item as Record<string, unknown>).url === "string")
+  ).length;
+}
+
 export function parseUsageFromResponseText(
   responseText: string,
   providerType: string | null | undefined
@@ -3664,6 +3693,12 @@ export function parseUsageFromResponseText(
           applyUsageValue(item.usage, "json.output");
         }
       }
+
+      // OpenAI Images API：data[] 为生成的图片（url / b64_json），按张计费
+      const imageCount = countGeneratedImages(parsed.data);
+      if (imageCount > 0) {
+        usageMetrics = { ...(usageMetrics ?? {}), output_images: imageCount };
+      }
     }
 
     if (!usageMetrics && Array.isArray(parsedValue)) {
@@ -3716,6 +3751,10 @@ export function parseUsageFromResponseText(
           patch.cache_creation_1h_input_tokens ?? base.cache_creation_1h_input_tokens,
         cache_ttl: patch.cache_ttl ?? base.cache_ttl,
         cache_read_input_tokens: patch.cache_read_input_tokens ?? base.cache_read_input_tokens,
+        input_image_tokens: patch.input_image_tokens ?? base.input_image_tokens,
+        output_image_tokens: patch.output_image_tokens ?? base.output_image_tokens,
+        web_search_requests: patch.web_search_requests ?? base.web_search_requests,
+        output_images: patch.output_images ?? base.output_images,
       };
     };
 
@@ -58,6 +58,8 @@ export class ProxyResponses {
         return "permission_error";
       case 404:
         return "not_found_error";
+      case 413:
+        return "invalid_request_error";
       case 429:
         return "rate_limit_error";
       case 500:
@@ -0,0 +1,87 @@
+import { PROVIDER_DEFAULTS, PROVIDER_LIMITS } from "@/lib/constants/provider.constants";
+import type { ProviderChainItem } from "@/types/message";
+import type { Provider } from "@/types/provider";
+
+const RETRY_LIMITS = PROVIDER_LIMITS.MAX_RETRY_ATTEMPTS;
+
+export function clampRetryAttempts(value: number): number {
+  const numeric = Number(value);
+  if (!Number.isFinite(numeric)) return RETRY_LIMITS.MIN;
+  return Math.min(Math.max(numeric, RETRY_LIMITS.MIN), RETRY_LIMITS.MAX);
+}
+
+/**
+ * 计算供应商的有效最大尝试次数：供应商配置优先，否则使用环境默认值（MAX_RETRY_ATTEMPTS_DEFAULT）
+ */
+export function resolveMaxAttemptsForProvider(
+  provider: Pick<Provider, "maxRetryAttempts"> | null | undefined,
+  envDefault: number
+): number {
+  const baseDefault = clampRetryAttempts(envDefault ?? PROVIDER_DEFAULTS.MAX_RETRY_ATTEMPTS);
+  if (!provider || provider.maxRetryAttempts === null || provider.maxRetryAttempts === undefined) {
+    return baseDefault;
+  }
+  return clampRetryAttempts(provider.maxRetryAttempts);
+}
+
+export interface RetryBudgetOptions {
+  /** 未配置 maxRetryAttempts 的供应商使用的默认尝试次数 */
+  defaultMaxAttempts: number;
+  /** 已有的供应商决策链：其中出现过的供应商视为已尝试，不再分配 */
+  chain?: ReadonlyArray<Pick<ProviderChainItem, "id">>;
+}
+
+/**
+ * 供应商链路的重试预算
+ *
+ * 按给定顺序遍历候选供应商，每个供应商最多分配其有效最大尝试次数，
+ * 用尽后切换到下一个；已出现在决策链中的供应商直接跳过，避免重复重试同一个失败的上游。
+ */
+export class RetryBudget {
+  private readonly candidates: Array<{ id: number; maxAttempts: number }>;
+  private index = 0;
+  private attemptsUsed = 0;
+
+  constructor(
+    providers: ReadonlyArray<Pick<Provider, "id" | "maxRetryAttempts">>,
+    options: RetryBudgetOptions
+  ) {
+    const skipped = new Set((options.chain ?? []).map((item) => item.id));
+
+    this.candidates = [];
+    for (const provider of providers) {
+      if (skipped.has(provider.id)) continue;
+      // 同一供应商在候选列表中重复出现时只保留第一次
+      skipped.add(provider.id);
+      this.candidates.push({
+        id: provider.id,
+        maxAttempts: resolveMaxAttemptsForProvider(provider, options.defaultMaxAttempts),
+      });
+    }
+  }
+
+  /**
+   * 分配下一次尝试的供应商，预算耗尽时返回 null
+   */
+  next(): number | null {
+    while (this.index < this.candidates.length) {
+      const candidate = this.candidates[this.index];
+      if (this.attemptsUsed < candidate.maxAttempts) {
+        this.attemptsUsed++;
+        return candidate.id;
+      }
+      this.index++;
+      this.attemptsUsed = 0;
+    }
+    return null;
+  }
+
+  /** 剩余可分配的尝试次数 */
+  remaining(): number {
+    let total = 0;
+    for (let i = this.index; i < this.candidates.length; i++) {
+      total += this.candidates[i].maxAttempts;
+    }
+    return total - this.attemptsUsed;
+  }
+}
@@ -29,7 +29,7 @@ import {
   type OpenAIImageRequestMetadata,
   parseOpenAIImageMultipartMetadata,
 } from "./openai-image-compat";
-import { decodeRequestBody } from "./request-body-codec";
+import { decodeRequestBody, readRequestBodyWithLimit } from "./request-body-codec";
 
 /**
  * Classification of an auth failure, used to decide whether to record the
@@ -1179,7 +1179,8 @@ async function parseRequestBody(c: Context): Promise<RequestBodyResult> {
   const contentEncoding = c.req.header("content-encoding") ?? null;
   const pathname = new URL(c.req.url).pathname;
   // 原始（可能被压缩的）入站字节：用于截断检测与 multipart 透传。
-  const rawBodyBuffer = await c.req.raw.clone().arrayBuffer();
+  // 超过 MAX_REQUEST_BODY_BYTES 时抛 413，不再继续读取。
+  const rawBodyBuffer = await readRequestBodyWithLimit(c.req.raw.clone());
   const receivedBodyBytes = rawBodyBuffer.byteLength;
 
   // Truncation detection: warn only when both conditions are met
@@ -1,6 +1,12 @@
 import { ClientVersionChecker } from "@/lib/client-version-checker";
 import { logger } from "@/lib/logger";
-import { getClientTypeDisplayName, parseUserAgent } from "@/lib/ua-parser";
+import {
+  type ClientInfo,
+  getClientTypeDisplayName,
+  isClaudeCodeClientType,
+  parseUserAgent,
+} from "@/lib/ua-parser";
+import { isVersionLess } from "@/lib/version";
 import { getSystemSettings } from "@/repository/system-config";
 import type { ProxySession } from "./session";
 
@@ -12,7 +18,7 @@ import type { ProxySession } from "./session";
  * 2. 解析客户端 UA 并提取版本信息
  * 3. 检查用户版本是否需要升级
  * 4. 异步更新用户版本追踪
- * 5. 拦截旧版本用户或放行
+ * 5. 拦截低于最低版本（minClientVersion，仅 Claude 客户端）或 GA 版本的旧版本用户，或放行
  *
  * 特点：
  * - Fail Open: 任何错误都放行，不影响服务
@@ -68,7 +74,26 @@ export class ProxyVersionGuard {
         logger.error({ err }, "[ProxyVersionGuard] 更新用户版本失败");
       });
 
-      // 5. 检查是否需要升级（同时获取 GA 版本，避免重复查询）
+      // 5. 配置了最低版本时，Claude 客户端低于该版本直接拦截（不依赖 GA 检测）
+      const minClientVersion = settings.minClientVersion?.trim();
+      if (
+        minClientVersion &&
+        isClaudeCodeClientType(clientInfo.clientType) &&
+        isVersionLess(clientInfo.version, minClientVersion)
+      ) {
+        logger.warn(
+          {
+            userId,
+            clientType: clientInfo.clientType,
+            currentVersion: clientInfo.version,
+            minClientVersion,
+          },
+          "[ProxyVersionGuard] 客户端版本低于最低版本，已拦截"
+        );
+        return ProxyVersionGuard.buildUpgradeRequiredResponse(clientInfo, minClientVersion);
+      }
+
+      // 6. 检查是否需要升级（同时获取 GA 版本，避免重复查询）
       const { needsUpgrade, gaVersion } = await ClientVersionChecker.shouldUpgrade(
         clientInfo.clientType,
         clientInfo.version
@@ -82,9 +107,6 @@ export class ProxyVersionGuard {
         return null; // 版本符合要求，放行
       }
 
-      // 6. 构建错误提示（重用第 5 步获取的 gaVersion，避免重复查询）
-      const clientDisplayName = getClientTypeDisplayName(clientInfo.clientType);
-
       logger.warn(
         {
           userId,
@@ -95,27 +117,39 @@ export class ProxyVersionGuard {
         "[ProxyVersionGuard] 客户端版本过旧，已拦截"
       );
 
-      // 7. 返回 HTTP 400 + 明确的升级提示
-      return new Response(
-        JSON.stringify({
-          error: {
-            type: "client_upgrade_required",
-            message: `Your ${clientDisplayName} (v${clientInfo.version}) is outdated. Please upgrade to v${gaVersion} or later to continue using this service.`,
-            current_version: clientInfo.version,
-            required_version: gaVersion,
-            client_type: clientInfo.clientType,
-            client_display_name: clientDisplayName,
-          },
-        }),
-        {
-          status: 400,
-          headers: { "Content-Type": "application/json" },
-        }
-      );
+      // 7. 返回 HTTP 400 + 明确的升级提示（重用第 6 步获取的 gaVersion，避免重复查询）
+      return ProxyVersionGuard.buildUpgradeRequiredResponse(clientInfo, gaVersion);
     } catch (error) {
       // Fail Open: 任何错误都放行
       logger.error({ error }, "[ProxyVersionGuard] 版本检查失败，放行请求");
       return null;
     }
   }
+
+  /**
+   * 构建客户端需要升级的 HTTP 400 响应
+   */
+  private static buildUpgradeRequiredResponse(
+    clientInfo: ClientInfo,
+    requiredVersion: string | null
+  ): Response {
+    const clientDisplayName = getClientTypeDisplayName(clientInfo.clientType);
+
+    return new Response(
+      JSON.stringify({
+        error: {
+          type: "client_upgrade_required",
+          message: `Your ${clientDisplayName} (v${clientInfo.version}) is outdated. Please upgrade to v${requiredVersion} or later to continue using this service.`,
+          current_version: clientInfo.version,
+          required_version: requiredVersion,
+          client_type: clientInfo.clientType,
+          client_display_name: clientDisplayName,
+        },
+      }),
+      {
+        status: 400,
+        headers: { "Content-Type": "application/json" },
+      }
+    );
+  }
 }
@@ -8,7 +8,7 @@ import { useRouter } from "@/i18n/routing";
 import type { OverviewData } from "@/lib/api-client/v1/actions/overview";
 import { getOverviewData } from "@/lib/api-client/v1/actions/overview";
 import type { CurrencyCode } from "@/lib/utils";
-import { formatCurrency } from "@/lib/utils/currency";
+import { convertCurrency, formatCurrency } from "@/lib/utils/currency";
 import { ActiveSessionsList } from "./active-sessions-list";
 import { MetricCard } from "./metric-card";
 
@@ -24,6 +24,8 @@ async function fetchOverviewData(): Promise<OverviewData> {
 
 interface OverviewPanelProps {
   currencyCode?: CurrencyCode;
+  /** USD → currencyCode 展示汇率（概览数据始终为 USD，仅在格式化时换算） */
+  currencyDisplayRate?: string;
   isAdmin?: boolean;
 }
 
@@ -32,7 +34,11 @@ interface OverviewPanelProps {
  * 左侧：4个指标卡片
  * 右侧：简洁的活跃 Session 列表
  */
-export function OverviewPanel({ currencyCode = "USD", isAdmin = false }: OverviewPanelProps) {
+export function OverviewPanel({
+  currencyCode = "USD",
+  currencyDisplayRate,
+  isAdmin = false,
+}: OverviewPanelProps) {
   const router = useRouter();
   const tc = useTranslations("customs");
   const tu = useTranslations("ui");
@@ -112,7 +118,10 @@ export function OverviewPanel({ currencyCode = "USD", isAdmin = false }: Overvie
           />
           <MetricCard
             title={tc("metrics.todayCost")}
-            value={formatCurrency(metrics.todayCost, currencyCode)}
+            value={formatCurrency(
+              convertCurrency(metrics.todayCost, currencyCode, currencyDisplayRate),
+              currencyCode
+            )}
             icon={DollarSign}
           />
           <MetricCard
@@ -0,0 +1,32 @@
+import { getEnvConfig } from '@/lib/config/env.schema';
+
+export const DEFAULT_DB_APPLICATION_NAME = 'claude-code-hub';
+
+/**
+ * postgres.js 启动参数（connection）
+ *
+ * application_name 便于在 pg_stat_activity 中识别 CCH 连接；PGAPPNAME 可覆盖默认值。
+ * postgres.js 中 DSN 查询参数优先于 connection 选项，DSN 已显式设置 application_name 时保持不变。
+ */
+export function getDbConnectionParams(): { application_name: string } {
+  return {
+    application_name: process.env.PGAPPNAME?.trim() || DEFAULT_DB_APPLICATION_NAME,
+  };
+}
+
+/**
+ * 应用连接池配置（主库与只读副本共用）
+ */
+export function getDbPoolOptions() {
+  const env = getEnvConfig();
+
+  // postgres.js 默认 max=10，在高并发下容易出现查询排队
+  // 这里采用“生产环境默认更大、同时可通过 env 覆盖”的策略，兼容单机与 k8s 多副本
+  const defaultMax = env.NODE_ENV === 'production' ? 20 : 10;
+  return {
+    max: env.DB_POOL_MAX ?? defaultMax,
+    idle_timeout: env.DB_POOL_IDLE_TIMEOUT ?? 20,
+    connect_timeout: env.DB_POOL_CONNECT_TIMEOUT ?? 10,
+    connection: getDbConnectionParams(),
+  };
+}
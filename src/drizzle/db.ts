@@ -3,6 +3,7 @@ import 'server-only';
 import { drizzle, type PostgresJsDatabase } from 'drizzle-orm/postgres-js';
 import postgres from 'postgres';
 import { getEnvConfig } from '@/lib/config/env.schema';
+import { getDbPoolOptions } from './connection-params';
 import * as schema from './schema';
 
 let dbInstance: PostgresJsDatabase<typeof schema> | null = null;
@@ -15,14 +16,7 @@ function createDbInstance(): PostgresJsDatabase<typeof schema> {
     throw new Error('DSN environment variable is not set');
   }
 
-  // postgres.js 默认 max=10，在高并发下容易出现查询排队
-  // 这里采用“生产环境默认更大、同时可通过 env 覆盖”的策略，兼容单机与 k8s 多副本
-  const defaultMax = env.NODE_ENV === 'production' ? 20 : 10;
-  const client = postgres(connectionString, {
-    max: env.DB_POOL_MAX ?? defaultMax,
-    idle_timeout: env.DB_POOL_IDLE_TIMEOUT ?? 20,
-    connect_timeout: env.DB_POOL_CONNECT_TIMEOUT ?? 10,
-  });
+  const client = postgres(connectionString, getDbPoolOptions());
   return drizzle(client, { schema });
 }
 
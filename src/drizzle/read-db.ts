@@ -0,0 +1,39 @@
+import 'server-only';
+
+import { drizzle, type PostgresJsDatabase } from 'drizzle-orm/postgres-js';
+import postgres from 'postgres';
+import { getEnvConfig } from '@/lib/config/env.schema';
+import { getDbPoolOptions } from './connection-params';
+import { db } from './db';
+import * as schema from './schema';
+
+let replicaInstance: PostgresJsDatabase<typeof schema> | null = null;
+
+/**
+ * 只读查询使用的数据库实例
+ *
+ * 配置 DSN_READ_REPLICA 时连接只读副本，否则回退主库。
+ * 仅用于可容忍复制延迟的读查询（统计看板、价格查询等），写入与读后写场景必须使用 db。
+ */
+export function getReadDb(): PostgresJsDatabase<typeof schema> {
+  const replicaDsn = getEnvConfig().DSN_READ_REPLICA;
+  if (!replicaDsn) {
+    return db;
+  }
+
+  if (!replicaInstance) {
+    const client = postgres(replicaDsn, getDbPoolOptions());
+    replicaInstance = drizzle(client, { schema });
+  }
+
+  return replicaInstance;
+}
+
+export const readDb = new Proxy({} as PostgresJsDatabase<typeof schema>, {
+  get(_target, prop) {
+    const instance = getReadDb();
+    const value = Reflect.get(instance, prop);
+
+    return typeof value === 'function' ? value.bind(instance) : value;
+  },
+});
@@ -21,6 +21,7 @@ import type { AllowedModelRuleInput, ProviderModelRedirectRule, ProviderType } f
 import type { FilterOperation } from "@/lib/request-filter-types";
 import type { IpExtractionConfig } from "@/types/ip-extraction";
 import type { AuditCategory } from "@/types/audit-log";
+import type { RateLimitMetadata } from "@/types/statistics";
 
 // Enums
 export const dailyResetModeEnum = pgEnum('daily_reset_mode', ['fixed', 'rolling']);
@@ -86,6 +87,9 @@ export const users = pgTable('users', {
   // Non-empty = listed patterns are denied even if allowedClients permits them
   blockedClients: jsonb('blocked_clients').$type<string[]>().notNull().default([]),
 
+  // 用户统计时区（IANA 标识符），null 表示跟随系统时区
+  timezone: varchar('timezone', { length: 64 }),
+
   createdAt: timestamp('created_at', { withTimezone: true }).defaultNow(),
   updatedAt: timestamp('updated_at', { withTimezone: true }).defaultNow(),
   deletedAt: timestamp('deleted_at', { withTimezone: true }),
@@ -528,6 +532,8 @@ export const messageRequest = pgTable('message_request', {
   errorMessage: text('error_message'),
   errorStack: text('error_stack'),  // 完整堆栈信息，用于排查 TypeError: terminated 等流错误
   errorCause: text('error_cause'),  // 嵌套错误原因（JSON 格式），如 NGHTTP2_INTERNAL_ERROR
+  // 限流上下文（RateLimitError.toJSON()），旧记录仍附加在 error_message 中
+  rateLimitMetadata: jsonb('rate_limit_metadata').$type<RateLimitMetadata>(),
 
   // 拦截原因（用于记录被敏感词等规则拦截的请求）
   blockedBy: varchar('blocked_by', { length: 50 }),
@@ -655,6 +661,19 @@ export const modelPrices = pgTable('model_prices', {
   ),
 }));
 
+// 模型价格历史（追加写入，仅用于审计价格何时变化）
+// model_prices 保持“每个模型一条”的替换语义，每次写入新价格时在同一事务内追加一条历史
+export const modelPriceHistory = pgTable('model_price_history', {
+  id: serial('id').primaryKey(),
+  modelName: varchar('model_name').notNull(),
+  priceData: jsonb('price_data').notNull(),
+  source: varchar('source', { length: 20 }).notNull().$type<'cloud' | 'litellm' | 'manual'>(),
+  createdAt: timestamp('created_at', { withTimezone: true }).defaultNow(),
+}, (table) => ({
+  // 按模型查询历史（时间倒序）
+  modelPriceHistoryModelCreatedIdx: index('idx_model_price_history_model_created').on(table.modelName, table.createdAt.desc()),
+}));
+
 // 云端价格表目录元数据（providers 字典 / vendor 汇总 / 版本指纹），单行 upsert
 export const cloudPricingCatalog = pgTable('cloud_pricing_catalog', {
   id: serial('id').primaryKey(),
@@ -760,6 +779,10 @@ export const systemSettings = pgTable('system_settings', {
 
   // 货币显示配置
   currencyDisplay: varchar('currency_display', { length: 10 }).notNull().default('USD'),
+  // 手动配置的 USD → currencyDisplay 汇率（仅用于展示，存储始终为 USD）
+  currencyDisplayRate: numeric('currency_display_rate', { precision: 18, scale: 8 })
+    .notNull()
+    .default('1'),
 
   // 计费模型来源配置: 'original' (重定向前) | 'redirected' (重定向后)
   billingModelSource: varchar('billing_model_source', { length: 20 }).notNull().default('original'),
@@ -793,6 +816,8 @@ export const systemSettings = pgTable('system_settings', {
 
   // 客户端版本检查配置
   enableClientVersionCheck: boolean('enable_client_version_check').notNull().default(false),
+  // Claude 客户端最低版本（为空时仅按 GA 版本检测）
+  minClientVersion: varchar('min_client_version', { length: 32 }),
 
   // 供应商不可用时是否返回详细错误信息
   verboseProviderError: boolean('verbose_provider_error').notNull().default(false),
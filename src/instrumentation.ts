@@ -431,7 +431,7 @@ export async function register() {
       await scheduleNotifications();
 
       // 初始化智能探测调度器（如果启用）
-      const { startProbeScheduler, isSmartProbingEnabled } = await import(
+      const { startProbeScheduler, stopProbeScheduler, isSmartProbingEnabled } = await import(
         "@/lib/circuit-breaker-probe"
       );
       if (isSmartProbingEnabled()) {
@@ -439,6 +439,14 @@ export async function register() {
         logger.info("Smart probing scheduler started");
       }
 
+      // 关闭时先停后台队列/调度器（注册逆序执行），再走内置的缓冲刷写与连接关闭
+      const { registerShutdownHook } = await import("@/lib/lifecycle/shutdown");
+      const { stopCleanupQueue } = await import("@/lib/log-cleanup/cleanup-queue");
+      const { stopNotificationQueue } = await import("@/lib/notification/notification-queue");
+      registerShutdownHook("stopCleanupQueue", stopCleanupQueue);
+      registerShutdownHook("stopNotificationQueue", stopNotificationQueue);
+      registerShutdownHook("stopProbeScheduler", stopProbeScheduler);
+
       try {
         const { startEndpointProbeScheduler } = await import(
           "@/lib/provider-endpoints/probe-scheduler"
@@ -52,6 +52,12 @@ export function getEndpointList() {
   );
 }
 
+export function getApiTypeList() {
+  return toActionResult(
+    apiGet<{ items?: string[] }>("/api/v1/usage-logs/api-types").then(unwrapItems)
+  );
+}
+
 export function getUsageLogSessionIdSuggestions(params: object) {
   return toActionResult(
     apiGet<{ items?: string[] }>(
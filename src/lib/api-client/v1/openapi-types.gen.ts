@@ -1552,6 +1552,26 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/api/v1/dashboard/statistics/export": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        /**
+         * Export dashboard statistics
+         * @description Streams the per-user statistics timeline for the selected time range as CSV.
+         */
+        get: operations["getDashboardStatisticsExport"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/api/v1/dashboard/concurrent-sessions": {
         parameters: {
             query?: never;
@@ -11871,6 +11891,8 @@ export interface operations {
                          * @enum {string}
                          */
                         currencyDisplay: "USD" | "CNY" | "EUR" | "JPY" | "GBP" | "HKD" | "TWD" | "KRW" | "SGD";
+                        /** @description Manual USD to display currency rate as a decimal string; stored costs stay in USD. */
+                        currencyDisplayRate?: string;
                         /**
                          * @description Whether billing uses the originally requested model or the redirected model.
                          * @enum {string}
@@ -11897,6 +11919,8 @@ export interface operations {
                         cleanupBatchSize?: number;
                         /** @description Whether client version checks are enabled. */
                         enableClientVersionCheck: boolean;
+                        /** @description Minimum Claude client version, or null to rely on GA version detection. */
+                        minClientVersion?: string | null;
                         /** @description Whether provider errors include extra diagnostics. */
                         verboseProviderError: boolean;
                         /** @description Whether sanitized upstream error messages are passed through. */
@@ -12133,6 +12157,8 @@ export interface operations {
                      * @enum {string}
                      */
                     currencyDisplay?: "USD" | "CNY" | "EUR" | "JPY" | "GBP" | "HKD" | "TWD" | "KRW" | "SGD";
+                    /** @description Manual USD to display currency rate; stored costs stay in USD. */
+                    currencyDisplayRate?: number;
                     /**
                      * @description Whether billing uses the originally requested model or the redirected model.
                      * @enum {string}
@@ -12159,6 +12185,8 @@ export interface operations {
                     cleanupBatchSize?: number;
                     /** @description Whether client version checks are enabled. */
                     enableClientVersionCheck?: boolean;
+                    /** @description Minimum Claude client version, or null to rely on GA version detection. */
+                    minClientVersion?: string | null;
                     /** @description Whether provider errors include extra diagnostics. */
                     verboseProviderError?: boolean;
                     /** @description Whether sanitized upstream error messages are passed through. */
@@ -12268,6 +12296,8 @@ export interface operations {
                          * @enum {string}
                          */
                         currencyDisplay: "USD" | "CNY" | "EUR" | "JPY" | "GBP" | "HKD" | "TWD" | "KRW" | "SGD";
+                        /** @description Manual USD to display currency rate as a decimal string; stored costs stay in USD. */
+                        currencyDisplayRate?: string;
                         /**
                          * @description Whether billing uses the originally requested model or the redirected model.
                          * @enum {string}
@@ -12294,6 +12324,8 @@ export interface operations {
                         cleanupBatchSize?: number;
                         /** @description Whether client version checks are enabled. */
                         enableClientVersionCheck: boolean;
+                        /** @description Minimum Claude client version, or null to rely on GA version detection. */
+                        minClientVersion?: string | null;
                         /** @description Whether provider errors include extra diagnostics. */
                         verboseProviderError: boolean;
                         /** @description Whether sanitized upstream error messages are passed through. */
@@ -12570,6 +12602,8 @@ export interface operations {
                          * @enum {string}
                          */
                         currencyDisplay: "USD" | "CNY" | "EUR" | "JPY" | "GBP" | "HKD" | "TWD" | "KRW" | "SGD";
+                        /** @description Manual USD to display currency rate as a decimal string; stored costs stay in USD. */
+                        currencyDisplayRate?: string;
                         /**
                          * @description Whether billing uses the originally requested model or the redirected model.
                          * @enum {string}
@@ -21420,6 +21454,141 @@ export interface operations {
             };
         };
     };
+    getDashboardStatisticsExport: {
+        parameters: {
+            query?: {
+                /** @description Statistics time range. */
+                timeRange?: "today" | "7days" | "30days" | "thisMonth";
+            };
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description Statistics CSV. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    /** @description CSV with header date,user_id,user_name,api_calls,total_cost; costs are in USD. */
+                    "text/csv": string;
+                };
+            };
+            /** @description Invalid request. */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/problem+json": {
+                        /** @description Stable problem type URI or URN. */
+                        type: string;
+                        /** @description Short problem title. */
+                        title: string;
+                        /** @description HTTP status code. */
+                        status: number;
+                        /** @description Human-readable error detail. */
+                        detail: string;
+                        /** @description Request path that produced the problem. */
+                        instance: string;
+                        /** @description Application error code for frontend i18n. */
+                        errorCode: string;
+                        /** @description Optional i18n parameters. */
+                        errorParams?: {
+                            [key: string]: unknown;
+                        };
+                        /** @description Optional request trace identifier. */
+                        traceId?: string;
+                        /** @description Validation failure details. */
+                        invalidParams?: {
+                            /** @description Path to the invalid input field. */
+                            path: (string | number)[];
+                            /** @description Machine-readable validation error code. */
+                            code: string;
+                            /** @description Validation error message. */
+                            message: string;
+                        }[];
+                    };
+                };
+            };
+            /** @description Authentication required. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/problem+json": {
+                        /** @description Stable problem type URI or URN. */
+                        type: string;
+                        /** @description Short problem title. */
+                        title: string;
+                        /** @description HTTP status code. */
+                        status: number;
+                        /** @description Human-readable error detail. */
+                        detail: string;
+                        /** @description Request path that produced the problem. */
+                        instance: string;
+                        /** @description Application error code for frontend i18n. */
+                        errorCode: string;
+                        /** @description Optional i18n parameters. */
+                        errorParams?: {
+                            [key: string]: unknown;
+                        };
+                        /** @description Optional request trace identifier. */
+                        traceId?: string;
+                        /** @description Validation failure details. */
+                        invalidParams?: {
+                            /** @description Path to the invalid input field. */
+                            path: (string | number)[];
+                            /** @description Machine-readable validation error code. */
+                            code: string;
+                            /** @description Validation error message. */
+                            message: string;
+                        }[];
+                    };
+                };
+            };
+            /** @description Access denied. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/problem+json": {
+                        /** @description Stable problem type URI or URN. */
+                        type: string;
+                        /** @description Short problem title. */
+                        title: string;
+                        /** @description HTTP status code. */
+                        status: number;
+                        /** @description Human-readable error detail. */
+                        detail: string;
+                        /** @description Request path that produced the problem. */
+                        instance: string;
+                        /** @description Application error code for frontend i18n. */
+                        errorCode: string;
+                        /** @description Optional i18n parameters. */
+                        errorParams?: {
+                            [key: string]: unknown;
+                        };
+                        /** @description Optional request trace identifier. */
+                        traceId?: string;
+                        /** @description Validation failure details. */
+                        invalidParams?: {
+                            /** @description Path to the invalid input field. */
+                            path: (string | number)[];
+                            /** @description Machine-readable validation error code. */
+                            code: string;
+                            /** @description Validation error message. */
+                            message: string;
+                        }[];
+                    };
+                };
+            };
+        };
+    };
     getDashboardConcurrentSessions: {
         parameters: {
             query?: never;
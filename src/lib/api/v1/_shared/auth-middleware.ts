@@ -153,15 +153,14 @@ async function classifyCredential(
   const [
     { detectSessionTokenKind, getSessionTokenMode, isSignedAdminAuthToken },
     { config },
-    { constantTimeEqual },
+    { isAdminToken },
   ] = await Promise.all([
     import("@/lib/auth"),
     import("@/lib/config/config"),
     import("@/lib/security/constant-time-compare"),
   ]);
 
-  const adminToken = config.auth.adminToken;
-  if (adminToken && constantTimeEqual(token, adminToken)) return "admin-token";
+  if (isAdminToken(token, config.auth.adminToken)) return "admin-token";
   if (getSessionTokenMode() !== "legacy" && (await isSignedAdminAuthToken(token))) {
     return "admin-token";
   }
@@ -1,5 +1,6 @@
 import { z } from "@hono/zod-openapi";
 import { PUBLIC_PROVIDER_TYPE_VALUES } from "@/lib/api/v1/_shared/constants";
+import { HHMM_PATTERN } from "@/lib/utils/time-of-day";
 
 export const ApiVersionSchema = z.literal("1.0.0").describe("Management API version.");
 
@@ -25,6 +26,11 @@ export const ProblemJsonSchema = z.object({
   invalidParams: z.array(InvalidParamSchema).optional().describe("Validation failure details."),
 });
 
+export const TimeOfDaySchema = z
+  .string()
+  .regex(HHMM_PATTERN)
+  .describe("Time of day in HH:mm format.");
+
 export const IsoDateTimeStringSchema = z
   .string()
   .datetime({ offset: true })
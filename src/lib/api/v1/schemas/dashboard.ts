@@ -52,6 +52,10 @@ export const DashboardOverviewResponseSchema = z.object({
   recentMinuteRequests: z.number().int().min(0).describe("Recent one-minute request count."),
 });
 
+export const DashboardStatisticsCsvSchema = z
+  .string()
+  .describe("CSV with header date,user_id,user_name,api_calls,total_cost; costs are in USD.");
+
 export const DashboardGenericObjectSchema = z
   .record(z.string(), z.unknown())
   .describe("Dashboard response object.");
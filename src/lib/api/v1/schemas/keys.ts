@@ -1,4 +1,5 @@
 import { z } from "@hono/zod-openapi";
+import { TimeOfDaySchema } from "./_common";
 
 const ResetModeSchema = z.enum(["fixed", "rolling"]);
 const CacheTtlPreferenceSchema = z.enum(["inherit", "5m", "1h"]);
@@ -24,11 +25,7 @@ const KeyMutationFields = {
   limit5hResetMode: ResetModeSchema.optional().describe("Five-hour reset mode."),
   limitDailyUsd: z.number().min(0).max(10_000).nullable().optional().describe("Daily USD quota."),
   dailyResetMode: ResetModeSchema.optional().describe("Daily reset mode."),
-  dailyResetTime: z
-    .string()
-    .regex(/^([01]?\d|2[0-3]):[0-5]\d$/)
-    .optional()
-    .describe("Daily reset time in HH:mm."),
+  dailyResetTime: TimeOfDaySchema.optional().describe("Daily reset time in HH:mm."),
   limitWeeklyUsd: z.number().min(0).max(50_000).nullable().optional().describe("Weekly USD quota."),
   limitMonthlyUsd: z
     .number()
@@ -1,5 +1,5 @@
 import { z } from "@hono/zod-openapi";
-import { IsoDateTimeStringSchema } from "./_common";
+import { IsoDateTimeStringSchema, TimeOfDaySchema } from "./_common";
 import { WebhookTargetSchema } from "./webhook-targets";
 
 export const NotificationTypeSchema = z
@@ -84,6 +84,9 @@ export const NotificationSettingsUpdateSchema = NotificationSettingsSchema.omit(
   createdAt: true,
   updatedAt: true,
 })
+  .extend({
+    dailyLeaderboardTime: TimeOfDaySchema.nullable().describe("Daily leaderboard schedule time."),
+  })
   .partial()
   .strict()
   .describe("Notification settings update request.");
@@ -3,8 +3,9 @@ import { HIDDEN_PROVIDER_TYPES as HIDDEN_PROVIDER_TYPE_VALUES } from "@/lib/api/
 import {
   CODEX_IMAGE_GENERATION_PREFERENCE_VALUES,
   PROVIDER_KEY_MAX_LENGTH,
+  PROVIDER_LIMITS,
 } from "@/lib/constants/provider.constants";
-import { ProviderTypeSchema } from "./_common";
+import { ProviderTypeSchema, TimeOfDaySchema } from "./_common";
 
 export const HIDDEN_PROVIDER_TYPES = new Set(HIDDEN_PROVIDER_TYPE_VALUES);
 
@@ -202,7 +203,12 @@ const ProviderBatchUpdateFieldsSchema = z
     is_enabled: z.boolean().optional().describe("Provider enabled state."),
     priority: z.number().int().optional().describe("Provider routing priority."),
     weight: z.number().min(0).optional().describe("Provider routing weight."),
-    cost_multiplier: z.number().min(0).optional().describe("Provider cost multiplier."),
+    cost_multiplier: z
+      .number()
+      .gt(0)
+      .max(PROVIDER_LIMITS.COST_MULTIPLIER.MAX)
+      .optional()
+      .describe("Provider cost multiplier."),
     group_tag: z.string().max(200).nullable().optional().describe("Provider group tag."),
     model_redirects: z.array(z.unknown()).nullable().optional().describe("Model redirect rules."),
     allowed_models: z.array(z.unknown()).nullable().optional().describe("Allowed model rules."),
@@ -212,7 +218,7 @@ const ProviderBatchUpdateFieldsSchema = z
     limit_5h_reset_mode: z.enum(["fixed", "rolling"]).optional().describe("Five-hour reset mode."),
     limit_daily_usd: z.number().min(0).nullable().optional().describe("Daily USD limit."),
     daily_reset_mode: z.enum(["fixed", "rolling"]).optional().describe("Daily reset mode."),
-    daily_reset_time: z.string().optional().describe("Daily reset time."),
+    daily_reset_time: TimeOfDaySchema.optional().describe("Daily reset time."),
     codex_image_generation_preference: CodexImageGenerationPreferenceSchema.nullable()
       .optional()
       .describe("Codex image generation tool preference."),
@@ -346,11 +352,6 @@ export const ProviderGroupsQuerySchema = z.object({
   userId: z.coerce.number().int().positive().optional().describe("Optional user id filter."),
 });
 
-const TimeOfDaySchema = z
-  .string()
-  .regex(/^([01][0-9]|2[0-3]):[0-5][0-9]$/)
-  .describe("Time of day in HH:mm format.");
-
 export const ProviderCreateSchema = z
   .object({
     name: z.string().trim().min(1).max(64).describe("Provider display name."),
@@ -359,7 +360,12 @@ export const ProviderCreateSchema = z
     is_enabled: z.boolean().optional().describe("Whether the provider is enabled."),
     weight: z.number().int().min(1).max(100).optional().describe("Provider routing weight."),
     priority: z.number().int().min(0).optional().describe("Provider routing priority."),
-    cost_multiplier: z.number().min(0).optional().describe("Provider cost multiplier."),
+    cost_multiplier: z
+      .number()
+      .gt(0)
+      .max(PROVIDER_LIMITS.COST_MULTIPLIER.MAX)
+      .optional()
+      .describe("Provider cost multiplier."),
     group_tag: z.string().max(255).nullable().optional().describe("Provider group tag."),
     group_priorities: z
       .record(z.string(), z.number().int().min(0))
@@ -8,6 +8,10 @@ const currencyValues = Object.keys(CURRENCY_CONFIG) as [
 ];
 
 const CurrencyCodeSchema = z.enum(currencyValues).describe("Currency code used for cost display.");
+const CurrencyDisplayRateSchema = z
+  .string()
+  .optional()
+  .describe("Manual USD to display currency rate as a decimal string; stored costs stay in USD.");
 
 const BillingModelSourceSchema = z
   .enum(["original", "redirected"])
@@ -86,6 +90,7 @@ export const SystemSettingsSchema = z
     siteTitle: z.string().describe("Site title shown in the dashboard."),
     allowGlobalUsageView: z.boolean().describe("Whether users can view global usage data."),
     currencyDisplay: CurrencyCodeSchema,
+    currencyDisplayRate: CurrencyDisplayRateSchema,
     billingModelSource: BillingModelSourceSchema,
     codexPriorityBillingSource: CodexPriorityBillingSourceSchema,
     billNonSuccessfulRequests: z
@@ -106,6 +111,12 @@ export const SystemSettingsSchema = z
     cleanupSchedule: z.string().optional().describe("Cleanup cron schedule."),
     cleanupBatchSize: z.number().int().optional().describe("Cleanup batch size."),
     enableClientVersionCheck: z.boolean().describe("Whether client version checks are enabled."),
+    minClientVersion: z
+      .string()
+      .max(32)
+      .nullable()
+      .optional()
+      .describe("Minimum Claude client version, or null to rely on GA version detection."),
     verboseProviderError: z
       .boolean()
       .describe("Whether provider errors include extra diagnostics."),
@@ -186,6 +197,12 @@ export const SystemSettingsUpdateSchema = SystemSettingsSchema.omit({
       .optional()
       .describe("System timezone, or null to use default."),
     responseFixerConfig: ResponseFixerConfigSchema.partial().optional(),
+    currencyDisplayRate: z
+      .number()
+      .positive()
+      .max(100000)
+      .optional()
+      .describe("Manual USD to display currency rate; stored costs stay in USD."),
   })
   .partial()
   .strict()
@@ -202,6 +219,7 @@ export const SystemSettingsUpdateResponseSchema = SystemSettingsSchema.extend({
 export const SystemDisplaySettingsSchema = z.object({
   siteTitle: z.string().describe("Site title shown in read-only dashboard surfaces."),
   currencyDisplay: CurrencyCodeSchema,
+  currencyDisplayRate: CurrencyDisplayRateSchema,
   billingModelSource: BillingModelSourceSchema,
 });
 
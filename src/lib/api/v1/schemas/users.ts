@@ -1,5 +1,5 @@
 import { z } from "@hono/zod-openapi";
-import { createCursorResponseSchema } from "./_common";
+import { createCursorResponseSchema, TimeOfDaySchema } from "./_common";
 
 const DateLikeSchema = z.string().datetime().nullable().optional();
 const IsoDateTimeSchema = z
@@ -42,11 +42,7 @@ const UserMutationFieldsSchema = {
     .optional()
     .describe("Concurrent session limit."),
   dailyResetMode: ResetModeSchema.optional().describe("Daily reset mode."),
-  dailyResetTime: z
-    .string()
-    .regex(/^([01]\d|2[0-3]):[0-5]\d$/)
-    .optional()
-    .describe("Daily reset time in HH:mm."),
+  dailyResetTime: TimeOfDaySchema.optional().describe("Daily reset time in HH:mm."),
   isEnabled: z.boolean().optional().describe("Whether the user is enabled."),
   expiresAt: DateLikeSchema.describe("Expiration timestamp, or null to clear."),
   allowedClients: z
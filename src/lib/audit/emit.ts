@@ -1,7 +1,7 @@
 import * as authModule from "@/lib/auth";
 import { logger } from "@/lib/logger";
 import { createAuditLogAsync } from "@/repository/audit-log";
-import type { AuditCategory } from "@/types/audit-log";
+import type { AuditCategory, AuditLogInput } from "@/types/audit-log";
 import { redactSensitive } from "./redact";
 import { resolveRequestContext } from "./request-context";
 
@@ -50,6 +50,40 @@ export function emitActionAudit(args: EmitActionAuditArgs): void {
   void emitAsync(args);
 }
 
+/**
+ * Build the audit log row for a server action without persisting it.
+ *
+ * Shares operator/request-context capture and redaction with
+ * `emitActionAudit`, for callers that must write the row inside their own
+ * DB transaction (see `insertAuditLogInTransaction`).
+ */
+export async function buildActionAuditEntry(args: EmitActionAuditArgs): Promise<AuditLogInput> {
+  const session = safeGetScopedAuthSession();
+  // Prefer the adapter-populated ALS; fall back to next/headers for direct
+  // Server Actions that bypass the OpenAPI adapter (system-settings form,
+  // any future "use server" form action, etc).
+  const { ip, userAgent } = await resolveRequestContext();
+
+  return {
+    actionCategory: args.category,
+    actionType: args.action,
+    targetType: args.targetType ?? null,
+    targetId: args.targetId != null ? String(args.targetId) : null,
+    targetName: args.targetName ?? null,
+    beforeValue:
+      args.before !== undefined ? redactSensitive(args.before, args.redactExtraKeys) : null,
+    afterValue: args.after !== undefined ? redactSensitive(args.after, args.redactExtraKeys) : null,
+    operatorUserId: session?.user.id ?? null,
+    operatorUserName: session?.user.name ?? null,
+    operatorKeyId: session?.key.id ?? null,
+    operatorKeyName: session?.key.name ?? null,
+    operatorIp: ip,
+    userAgent,
+    success: args.success,
+    errorMessage: args.errorMessage ?? null,
+  };
+}
+
 async function emitAsync(args: EmitActionAuditArgs): Promise<void> {
   // Defense-in-depth: swallow ANY failure inside the audit pipeline.
   //   - `resolveRequestContext()` has its own try/catch but dynamic-import
@@ -61,31 +95,7 @@ async function emitAsync(args: EmitActionAuditArgs): Promise<void> {
   //     extra outer catch keeps the contract consistent even if that
   //     implementation changes later.
   try {
-    const session = safeGetScopedAuthSession();
-    // Prefer the adapter-populated ALS; fall back to next/headers for direct
-    // Server Actions that bypass the OpenAPI adapter (system-settings form,
-    // any future "use server" form action, etc).
-    const { ip, userAgent } = await resolveRequestContext();
-
-    await createAuditLogAsync({
-      actionCategory: args.category,
-      actionType: args.action,
-      targetType: args.targetType ?? null,
-      targetId: args.targetId != null ? String(args.targetId) : null,
-      targetName: args.targetName ?? null,
-      beforeValue:
-        args.before !== undefined ? redactSensitive(args.before, args.redactExtraKeys) : null,
-      afterValue:
-        args.after !== undefined ? redactSensitive(args.after, args.redactExtraKeys) : null,
-      operatorUserId: session?.user.id ?? null,
-      operatorUserName: session?.user.name ?? null,
-      operatorKeyId: session?.key.id ?? null,
-      operatorKeyName: session?.key.name ?? null,
-      operatorIp: ip,
-      userAgent,
-      success: args.success,
-      errorMessage: args.errorMessage ?? null,
-    });
+    await createAuditLogAsync(await buildActionAuditEntry(args));
   } catch (error) {
     logger.warn("[Audit] emitActionAudit failed, suppressed", {
       category: args.category,
@@ -9,7 +9,7 @@ import { DEFAULT_AUTH_SESSION_TTL_SECONDS } from "@/lib/auth-session-store";
 import { config } from "@/lib/config/config";
 import { getEnvConfig } from "@/lib/config/env.schema";
 import { logger } from "@/lib/logger";
-import { constantTimeEqual } from "@/lib/security/constant-time-compare";
+import { constantTimeEqual, isAdminToken } from "@/lib/security/constant-time-compare";
 import { findKeyList, validateApiKeyAndGetUser } from "@/repository/key";
 import type { Key } from "@/types/key";
 import type { User } from "@/types/user";
@@ -189,8 +189,7 @@ export async function validateKey(
 ): Promise<AuthSession | null> {
   const allowReadOnlyAccess = options?.allowReadOnlyAccess ?? false;
 
-  const adminToken = config.auth.adminToken;
-  if (adminToken && constantTimeEqual(keyString, adminToken)) {
+  if (isAdminToken(keyString, config.auth.adminToken)) {
     const now = new Date();
     const adminUser: User = {
       id: -1,
@@ -327,8 +326,7 @@ export async function validateAuthToken(
 
   // Opaque mode: allow raw ADMIN_TOKEN for backward-compatible programmatic API access.
   // Safe because admin token is a server-side env secret, not a user-issued DB key.
-  const adminToken = config.auth.adminToken;
-  if (adminToken && constantTimeEqual(token, adminToken)) {
+  if (isAdminToken(token, config.auth.adminToken)) {
     return validateKey(token, options);
   }
 
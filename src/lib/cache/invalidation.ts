@@ -0,0 +1,165 @@
+/**
+ * 进程级缓存的跨实例失效总线
+ *
+ * 特性：
+ * - 单一频道 cch:cache:invalidate，消息携带缓存名与 key（key 为 null 表示清空整个缓存）
+ * - 本地立即失效，再通过 Redis Pub/Sub 通知其他实例
+ * - 降级策略：Redis 不可用时仅本地失效，其他实例依赖各自 TTL 过期
+ * - 消息带实例标识，本实例发出的消息不会被重复处理
+ */
+
+import "server-only";
+
+import { randomUUID } from "node:crypto";
+import { logger } from "@/lib/logger";
+import { publishCacheInvalidation, subscribeCacheInvalidation } from "@/lib/redis/pubsub";
+
+export const CHANNEL_CACHE_INVALIDATE = "cch:cache:invalidate";
+
+export type CacheInvalidationHandler = (key: string | null) => void;
+
+interface CacheInvalidationMessage {
+  cache: string;
+  key: string | null;
+  origin: string;
+}
+
+/**
+ * Pub/Sub 传输层（生产环境为 Redis，测试中可替换为内存实现）
+ */
+export interface CacheInvalidationTransport {
+  publish(channel: string, message: string): Promise<void>;
+  subscribe(channel: string, callback: (message: string) => void): Promise<(() => void) | null>;
+}
+
+export interface CacheInvalidationBus {
+  /** 注册本地缓存的失效处理函数；同名缓存可注册多个处理函数 */
+  register(cache: string, handler: CacheInvalidationHandler): () => void;
+  /** 失效本地缓存并广播到其他实例 */
+  invalidate(cache: string, key?: string | null): Promise<void>;
+}
+
+function parseMessage(raw: string): CacheInvalidationMessage | null {
+  try {
+    const parsed = JSON.parse(raw) as Partial<CacheInvalidationMessage>;
+    if (typeof parsed.cache !== "string" || typeof parsed.origin !== "string") {
+      return null;
+    }
+    return {
+      cache: parsed.cache,
+      key: typeof parsed.key === "string" ? parsed.key : null,
+      origin: parsed.origin,
+    };
+  } catch {
+    return null;
+  }
+}
+
+export function createCacheInvalidationBus(
+  transport: CacheInvalidationTransport,
+  options: { instanceId?: string; shouldSubscribe?: () => boolean } = {}
+): CacheInvalidationBus {
+  const instanceId = options.instanceId ?? randomUUID();
+  const handlers = new Map<string, Set<CacheInvalidationHandler>>();
+  let subscribed = false;
+  let subscribePromise: Promise<void> | null = null;
+
+  function applyLocal(cache: string, key: string | null): void {
+    const cacheHandlers = handlers.get(cache);
+    if (!cacheHandlers) return;
+    for (const handler of cacheHandlers) {
+      try {
+        handler(key);
+      } catch (error) {
+        logger.error("[CacheInvalidation] Handler error", { cache, key, error });
+      }
+    }
+  }
+
+  function ensureSubscription(): Promise<void> {
+    if (subscribed) return Promise.resolve();
+    if (subscribePromise) return subscribePromise;
+
+    subscribePromise = (async () => {
+      if (options.shouldSubscribe && !options.shouldSubscribe()) {
+        subscribed = true;
+        return;
+      }
+
+      try {
+        const cleanup = await transport.subscribe(CHANNEL_CACHE_INVALIDATE, (raw) => {
+          const message = parseMessage(raw);
+          if (!message || message.origin === instanceId) return;
+          applyLocal(message.cache, message.key);
+        });
+        // 订阅失败（Redis 未启用或连接失败）时保持未订阅状态，下次注册/失效时重试
+        if (cleanup) {
+          subscribed = true;
+        }
+      } catch (error) {
+        logger.warn("[CacheInvalidation] Failed to subscribe", { error });
+      }
+    })().finally(() => {
+      subscribePromise = null;
+    });
+
+    return subscribePromise;
+  }
+
+  return {
+    register(cache, handler) {
+      const cacheHandlers = handlers.get(cache) ?? new Set<CacheInvalidationHandler>();
+      cacheHandlers.add(handler);
+      handlers.set(cache, cacheHandlers);
+      void ensureSubscription();
+
+      return () => {
+        cacheHandlers.delete(handler);
+        if (cacheHandlers.size === 0) {
+          handlers.delete(cache);
+        }
+      };
+    },
+
+    async invalidate(cache, key = null) {
+      applyLocal(cache, key);
+      void ensureSubscription();
+
+      const message: CacheInvalidationMessage = { cache, key, origin: instanceId };
+      try {
+        await transport.publish(CHANNEL_CACHE_INVALIDATE, JSON.stringify(message));
+      } catch (error) {
+        logger.warn("[CacheInvalidation] Failed to publish", { cache, key, error });
+      }
+    },
+  };
+}
+
+const defaultBus = createCacheInvalidationBus(
+  {
+    publish: publishCacheInvalidation,
+    subscribe: subscribeCacheInvalidation,
+  },
+  {
+    // CI/build 阶段跳过订阅
+    shouldSubscribe: () =>
+      process.env.CI !== "true" && process.env.NEXT_PHASE !== "phase-production-build",
+  }
+);
+
+/**
+ * 注册进程级缓存的失效处理函数（接收跨实例失效通知）
+ */
+export function registerCacheInvalidationHandler(
+  cache: string,
+  handler: CacheInvalidationHandler
+): () => void {
+  return defaultBus.register(cache, handler);
+}
+
+/**
+ * 失效指定缓存条目（key 省略时清空整个缓存），并通知其他实例
+ */
+export function invalidateCacheEntry(cache: string, key?: string | null): Promise<void> {
+  return defaultBus.invalidate(cache, key);
+}
@@ -37,6 +37,12 @@ export const EnvSchema = z.object({
     if (val.includes("user:password@host:port")) return undefined; // 占位符模板
     return val;
   }, z.string().url("数据库URL格式无效")),
+  // 只读副本（可选）：统计看板等大查询走副本，未配置时回退主库
+  DSN_READ_REPLICA: optionalPreprocessed((val) => {
+    if (!val || typeof val !== "string") return undefined;
+    if (val.includes("user:password@host:port")) return undefined;
+    return val;
+  }, z.string().url("只读副本数据库URL格式无效")),
   // PostgreSQL 连接池配置（postgres.js）
   // - 多副本部署（k8s）需要结合数据库 max_connections 分摊配置
   // - 这些值为“每个应用进程”的连接池上限
@@ -160,6 +166,9 @@ export const EnvSchema = z.object({
   // 超时后主动断开该输家连接，仅用已收到的内容尝试计费（通常计不出 -> 跳过）。
   HEDGE_LOSER_DRAIN_TIMEOUT_MS: z.coerce.number().int().min(1000).default(120_000),
 
+  // 统计查询超时（毫秒）：用户/供应商统计等大范围聚合查询的 statement_timeout，0 表示不限制
+  STATISTICS_QUERY_TIMEOUT: z.coerce.number().int().min(0).default(10_000),
+
   DASHBOARD_LOGS_POLL_INTERVAL_MS: z.coerce.number().int().min(250).max(60000).default(5000),
 
   // Langfuse Observability (optional, auto-enabled when keys are set)
@@ -14,6 +14,8 @@ export const PROVIDER_LIMITS = {
   LIMIT_MONTHLY_USD: { MIN: 10, MAX: 30000, STEP: 1 },
   // 并发 Session 上限：降低到 150（单供应商合理上限）
   CONCURRENT_SESSIONS: { MIN: 1, MAX: 150 },
+  // 成本倍率：必须大于 0（0 或负数会导致计费失真），上限 100
+  COST_MULTIPLIER: { MAX: 100 },
 } as const;
 
 export const PROVIDER_RULE_LIMITS = {
@@ -5,7 +5,8 @@ import { isShuttingDown } from "@/lib/lifecycle/shutdown";
 import { logger } from "@/lib/logger";
 import { getRedisClient } from "@/lib/redis/client";
 import { APP_VERSION } from "@/lib/version";
-import type { ComponentHealth, HealthCheckResponse } from "./types";
+import { checkMigrationStatus } from "./migrations";
+import type { ComponentHealth, HealthCheckResponse, MigrationStatus } from "./types";
 
 // -- 版本 --
 
@@ -19,6 +20,7 @@ export function getAppVersion(): string {
 
 const DB_CHECK_TIMEOUT_MS = 3_000;
 const REDIS_CHECK_TIMEOUT_MS = 2_000;
+const MIGRATION_CHECK_TIMEOUT_MS = 3_000;
 const DATABASE_FAILURE_MESSAGE = "Database connection failed";
 const REDIS_FAILURE_MESSAGE = "Redis connection failed";
 const PROXY_FAILURE_MESSAGE = "Proxy request failed";
@@ -137,6 +139,19 @@ export async function checkProxy(): Promise<ComponentHealth> {
   }
 }
 
+// -- 迁移状态检查 --
+
+export async function checkMigrations(): Promise<MigrationStatus> {
+  try {
+    return await withTimeout(checkMigrationStatus(), MIGRATION_CHECK_TIMEOUT_MS, "migrations");
+  } catch (error) {
+    logger.warn("[Health] migrations check failed", {
+      error: error instanceof Error ? error.message : String(error),
+    });
+    return "unchecked";
+  }
+}
+
 // -- 综合判定 --
 
 export async function checkReadiness(): Promise<HealthCheckResponse> {
@@ -160,12 +175,14 @@ export async function checkReadiness(): Promise<HealthCheckResponse> {
   }
 
   const [database, redis, proxy] = await Promise.all([checkDatabase(), checkRedis(), checkProxy()]);
+  // 仅在数据库可连接时检查迁移，否则结果没有意义
+  const migrations = database.status === "up" ? await checkMigrations() : undefined;
 
-  // DB 必需，Redis/Proxy 可选（降级但不摘流量）
+  // DB 必需，Redis/Proxy 可选（降级但不摘流量）；迁移未应用同样视为降级
   let status: HealthCheckResponse["status"] = "healthy";
   if (database.status === "down") {
     status = "unhealthy";
-  } else if (redis.status === "down" || proxy.status === "down") {
+  } else if (redis.status === "down" || proxy.status === "down" || migrations === "pending") {
     status = "degraded";
   }
 
@@ -174,6 +191,7 @@ export async function checkReadiness(): Promise<HealthCheckResponse> {
     timestamp: new Date().toISOString(),
     version,
     uptime: Math.round(process.uptime()),
+    ...(migrations ? { migrations } : {}),
     components: { database, redis, proxy },
   };
 }
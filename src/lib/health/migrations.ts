@@ -0,0 +1,40 @@
+import { readFile } from "node:fs/promises";
+import path from "node:path";
+import { isMigrationUpToDate } from "@/repository/migration-status";
+import type { MigrationStatus } from "./types";
+
+type MigrationJournal = { entries?: Array<{ when?: number }> };
+
+let cachedExpectedVersion: Promise<number | null> | null = null;
+
+/**
+ * 读取随镜像发布的迁移 journal，取最新一条迁移的 when 作为期望版本
+ * journal 不可读时返回 null（跳过检查，而不是误报 pending）
+ */
+async function loadExpectedMigrationVersion(): Promise<number | null> {
+  try {
+    const journalPath = path.join(process.cwd(), "drizzle", "meta", "_journal.json");
+    const journal = JSON.parse(await readFile(journalPath, "utf8")) as MigrationJournal;
+    const versions = (journal.entries ?? [])
+      .map((entry) => entry.when)
+      .filter((when): when is number => typeof when === "number" && Number.isFinite(when));
+    return versions.length > 0 ? Math.max(...versions) : null;
+  } catch {
+    return null;
+  }
+}
+
+export function getExpectedMigrationVersion(): Promise<number | null> {
+  cachedExpectedVersion ??= loadExpectedMigrationVersion();
+  return cachedExpectedVersion;
+}
+
+/**
+ * 检查当前实例连接的数据库是否已应用全部迁移
+ */
+export async function checkMigrationStatus(): Promise<MigrationStatus> {
+  const expectedVersion = await getExpectedMigrationVersion();
+  if (expectedVersion === null) return "unchecked";
+
+  return (await isMigrationUpToDate(expectedVersion)) ? "up_to_date" : "pending";
+}
@@ -1,5 +1,7 @@
 export type ComponentStatus = "up" | "down" | "degraded" | "unchecked";
 
+export type MigrationStatus = "up_to_date" | "pending" | "unchecked";
+
 export interface ComponentHealth {
   status: ComponentStatus;
   latencyMs?: number;
@@ -11,6 +13,8 @@ export interface HealthCheckResponse {
   timestamp: string;
   version: string;
   uptime: number;
+  /** 数据库可连接时才检查；pending 表示实例已连上数据库但迁移尚未应用 */
+  migrations?: MigrationStatus;
   components?: {
     database?: ComponentHealth;
     redis?: ComponentHealth;
@@ -46,6 +46,34 @@ async function withTimeout<T>(p: Promise<T>, ms: number, label: string): Promise
   }
 }
 
+export type ShutdownHook = () => void | Promise<void>;
+
+interface RegisteredShutdownHook {
+  name: string;
+  hook: ShutdownHook;
+}
+
+// 动态注册的关闭回调（后台队列/调度器等），在内置步骤之前按注册的逆序（LIFO）执行：
+// 后启动的组件可能依赖先启动的组件，因此先关。
+const shutdownHooks: RegisteredShutdownHook[] = [];
+
+/**
+ * 注册关闭回调，返回注销函数
+ */
+export function registerShutdownHook(name: string, hook: ShutdownHook): () => void {
+  const entry: RegisteredShutdownHook = { name, hook };
+  shutdownHooks.push(entry);
+  return () => {
+    const index = shutdownHooks.indexOf(entry);
+    if (index !== -1) shutdownHooks.splice(index, 1);
+  };
+}
+
+// 仅供测试重置已注册的回调，正常代码路径不使用。
+export function __resetShutdownHooksForTests(): void {
+  shutdownHooks.length = 0;
+}
+
 const DEFAULT_STEP_TIMEOUT_MS = 3000;
 const DEFAULT_TOTAL_TIMEOUT_MS = 10000;
 
@@ -65,7 +93,19 @@ export async function runApplicationCleanup(
   const startedAt = Date.now();
   logger.info("[Shutdown] application cleanup starting", { signal, totalMs, stepMs });
 
+  // 整体超时后置位：尚未开始的注册回调不再启动
+  let timedOut = false;
+
   const work = (async () => {
+    // 0. 动态注册的回调，LIFO 顺序
+    for (const { name, hook } of [...shutdownHooks].reverse()) {
+      if (timedOut) {
+        logger.warn("[Shutdown] skipping registered hook after total timeout", { name });
+        continue;
+      }
+      await withTimeout(Promise.resolve().then(hook), stepMs, name);
+    }
+
     // 1. 停止本地周期任务（不需要做 IO，几乎是同步）
     await withTimeout(
       (async () => {
@@ -182,6 +222,7 @@ export async function runApplicationCleanup(
 
   const total = new Promise<void>((resolve) => {
     const t = setTimeout(() => {
+      timedOut = true;
       logger.warn("[Shutdown] application cleanup total timeout reached", { totalMs });
       resolve();
     }, totalMs);
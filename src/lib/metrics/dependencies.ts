@@ -0,0 +1,44 @@
+import { checkDatabase, checkRedis } from "@/lib/health/checker";
+import type { ComponentHealth } from "@/lib/health/types";
+import { getRedisClient } from "@/lib/redis/client";
+import { appMetrics } from "./index";
+
+const REDIS_CLIENT_STATUSES = [
+  "wait",
+  "connecting",
+  "connect",
+  "ready",
+  "reconnecting",
+  "close",
+  "end",
+] as const;
+
+function recordComponent(component: string, health: ComponentHealth): void {
+  // unchecked（未配置）不输出，避免误报为 down
+  if (health.status === "unchecked") return;
+
+  appMetrics.componentUp.set({ component }, health.status === "up" ? 1 : 0);
+  if (health.latencyMs !== undefined) {
+    appMetrics.componentLatencySeconds.set({ component }, health.latencyMs / 1000);
+  }
+}
+
+/**
+ * 抓取前刷新依赖相关的 Gauge（数据库 / Redis 可用性与延迟、Redis 连接状态）
+ *
+ * 复用健康检查逻辑，单项检查自带超时，失败只会记录为 down
+ */
+export async function collectDependencyMetrics(): Promise<void> {
+  const [database, redis] = await Promise.all([checkDatabase(), checkRedis()]);
+  recordComponent("database", database);
+  recordComponent("redis", redis);
+
+  if (process.env.REDIS_URL?.trim()) {
+    const status = getRedisClient({ allowWhenRateLimitDisabled: true })?.status;
+    for (const candidate of REDIS_CLIENT_STATUSES) {
+      appMetrics.redisClientStatus.set({ status: candidate }, candidate === status ? 1 : 0);
+    }
+  }
+
+  appMetrics.processUptimeSeconds.set({}, Math.round(process.uptime()));
+}
@@ -0,0 +1,136 @@
+import { Counter, Gauge, Histogram, MetricsRegistry } from "./registry";
+
+export { PROMETHEUS_CONTENT_TYPE } from "./registry";
+
+/**
+ * 代理请求耗时分桶（秒）
+ *
+ * 覆盖普通请求到长时间 SSE 流：非流式多在 1-60s，流式 / 长推理请求可达数分钟
+ */
+export const PROXY_DURATION_BUCKETS_SECONDS = [
+  0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 180, 300, 600, 900,
+] as const;
+
+/** 作为 path 标签保留的已知端点，其余路径统一归为 "other" 以控制标签基数 */
+const KNOWN_PROXY_PATHS = new Set([
+  "/v1/messages",
+  "/v1/messages/count_tokens",
+  "/v1/chat/completions",
+  "/v1/responses",
+  "/v1/responses/compact",
+  "/v1/models",
+  "/v1/responses/models",
+  "/v1/chat/completions/models",
+  "/v1/chat/models",
+]);
+
+function createAppMetrics() {
+  const registry = new MetricsRegistry();
+
+  return {
+    registry,
+    proxyRequestsTotal: registry.register(
+      new Counter("cch_proxy_requests_total", "Total proxy requests by path and status", [
+        "method",
+        "path",
+        "status",
+      ])
+    ),
+    proxyRequestDurationSeconds: registry.register(
+      new Histogram(
+        "cch_proxy_request_duration_seconds",
+        "Proxy request latency until the response body completes, in seconds",
+        ["method", "path", "status"],
+        PROXY_DURATION_BUCKETS_SECONDS
+      )
+    ),
+    componentUp: registry.register(
+      new Gauge("cch_component_up", "Whether a dependency is reachable (1 = up, 0 = down)", [
+        "component",
+      ])
+    ),
+    componentLatencySeconds: registry.register(
+      new Gauge("cch_component_check_latency_seconds", "Latency of the last dependency check", [
+        "component",
+      ])
+    ),
+    redisClientStatus: registry.register(
+      new Gauge("cch_redis_client_status", "Current Redis client connection status (1 = active)", [
+        "status",
+      ])
+    ),
+    processUptimeSeconds: registry.register(
+      new Gauge("cch_process_uptime_seconds", "Process uptime in seconds")
+    ),
+  };
+}
+
+type AppMetrics = ReturnType<typeof createAppMetrics>;
+
+// 使用 globalThis 保证单例（避免开发环境热重载重复注册）
+const g = globalThis as unknown as { __CCH_APP_METRICS__?: AppMetrics };
+if (!g.__CCH_APP_METRICS__) {
+  g.__CCH_APP_METRICS__ = createAppMetrics();
+}
+
+export const appMetrics: AppMetrics = g.__CCH_APP_METRICS__;
+
+export function normalizeProxyPath(path: string): string {
+  const withoutQuery = path.split("?")[0].replace(/\/+$/, "");
+  return KNOWN_PROXY_PATHS.has(withoutQuery) ? withoutQuery : "other";
+}
+
+/**
+ * 记录一次代理请求
+ *
+ * 流式响应需配合 onStreamComplete 在流结束时调用，耗时包含完整的流式传输时间
+ */
+export function recordProxyRequest(
+  method: string,
+  path: string,
+  status: number,
+  durationMs: number
+): void {
+  const labels = { method: method.toUpperCase(), path: normalizeProxyPath(path), status };
+  appMetrics.proxyRequestsTotal.inc(labels);
+  appMetrics.proxyRequestDurationSeconds.observe(labels, durationMs / 1000);
+}
+
+/**
+ * 包装响应体，在流读取完毕、出错或被客户端取消时调用一次 onComplete
+ *
+ * 用于 SSE 等流式响应：中间件返回时只拿到响应头，需等响应体结束后再记录耗时
+ */
+export function onStreamComplete(
+  body: ReadableStream<Uint8Array>,
+  onComplete: () => void
+): ReadableStream<Uint8Array> {
+  const reader = body.getReader();
+  let completed = false;
+  const complete = () => {
+    if (completed) return;
+    completed = true;
+    onComplete();
+  };
+
+  return new ReadableStream<Uint8Array>({
+    async pull(controller) {
+      try {
+        const { done, value } = await reader.read();
+        if (done) {
+          complete();
+          controller.close();
+          return;
+        }
+        controller.enqueue(value);
+      } catch (error) {
+        complete();
+        controller.error(error);
+      }
+    },
+    async cancel(reason) {
+      complete();
+      await reader.cancel(reason);
+    },
+  });
+}
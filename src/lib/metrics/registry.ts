@@ -0,0 +1,186 @@
+/**
+ * 轻量 Prometheus 指标注册表
+ *
+ * 仅实现本项目需要的 Counter / Histogram / Gauge，输出 Prometheus 文本格式（0.0.4），
+ * 供 /api/metrics 抓取。指标保存在进程内存中，多实例部署时由 Prometheus 分别抓取。
+ */
+
+export type MetricLabels = Record<string, string | number>;
+
+interface Metric {
+  readonly name: string;
+  render(): string[];
+}
+
+function escapeLabelValue(value: string): string {
+  return value.replace(/\\/g, "\\\\").replace(/\n/g, "\\n").replace(/"/g, '\\"');
+}
+
+function formatLabels(labels: MetricLabels): string {
+  const entries = Object.entries(labels);
+  if (entries.length === 0) return "";
+  const pairs = entries.map(([key, value]) => `${key}="${escapeLabelValue(String(value))}"`);
+  return `{${pairs.join(",")}}`;
+}
+
+function formatValue(value: number): string {
+  if (Number.isNaN(value)) return "NaN";
+  if (value === Number.POSITIVE_INFINITY) return "+Inf";
+  if (value === Number.NEGATIVE_INFINITY) return "-Inf";
+  return String(value);
+}
+
+function labelKey(labelNames: readonly string[], labels: MetricLabels): string {
+  return labelNames.map((name) => String(labels[name] ?? "")).join("\u0000");
+}
+
+function pickLabels(labelNames: readonly string[], labels: MetricLabels): MetricLabels {
+  const picked: MetricLabels = {};
+  for (const name of labelNames) {
+    picked[name] = labels[name] ?? "";
+  }
+  return picked;
+}
+
+function header(name: string, help: string, type: string): string[] {
+  const escapedHelp = help.replace(/\\/g, "\\\\").replace(/\n/g, "\\n");
+  return [`# HELP ${name} ${escapedHelp}`, `# TYPE ${name} ${type}`];
+}
+
+export class Counter implements Metric {
+  private readonly values = new Map<string, { labels: MetricLabels; value: number }>();
+
+  constructor(
+    readonly name: string,
+    private readonly help: string,
+    private readonly labelNames: readonly string[] = []
+  ) {}
+
+  inc(labels: MetricLabels = {}, value = 1): void {
+    if (value < 0) {
+      throw new Error(`Counter ${this.name} cannot be decreased`);
+    }
+    const key = labelKey(this.labelNames, labels);
+    const entry = this.values.get(key);
+    if (entry) {
+      entry.value += value;
+    } else {
+      this.values.set(key, { labels: pickLabels(this.labelNames, labels), value });
+    }
+  }
+
+  render(): string[] {
+    const lines = header(this.name, this.help, "counter");
+    for (const { labels, value } of this.values.values()) {
+      lines.push(`${this.name}${formatLabels(labels)} ${formatValue(value)}`);
+    }
+    return lines;
+  }
+}
+
+export class Gauge implements Metric {
+  private readonly values = new Map<string, { labels: MetricLabels; value: number }>();
+
+  constructor(
+    readonly name: string,
+    private readonly help: string,
+    private readonly labelNames: readonly string[] = []
+  ) {}
+
+  set(labels: MetricLabels, value: number): void {
+    this.values.set(labelKey(this.labelNames, labels), {
+      labels: pickLabels(this.labelNames, labels),
+      value,
+    });
+  }
+
+  render(): string[] {
+    const lines = header(this.name, this.help, "gauge");
+    for (const { labels, value } of this.values.values()) {
+      lines.push(`${this.name}${formatLabels(labels)} ${formatValue(value)}`);
+    }
+    return lines;
+  }
+}
+
+interface HistogramSeries {
+  labels: MetricLabels;
+  /** 与 buckets 一一对应的非累计计数 */
+  counts: number[];
+  sum: number;
+  count: number;
+}
+
+export class Histogram implements Metric {
+  private readonly series = new Map<string, HistogramSeries>();
+  private readonly buckets: number[];
+
+  constructor(
+    readonly name: string,
+    private readonly help: string,
+    private readonly labelNames: readonly string[],
+    buckets: readonly number[]
+  ) {
+    this.buckets = [...new Set(buckets)].filter(Number.isFinite).sort((a, b) => a - b);
+  }
+
+  observe(labels: MetricLabels, value: number): void {
+    if (!Number.isFinite(value)) return;
+
+    const key = labelKey(this.labelNames, labels);
+    let entry = this.series.get(key);
+    if (!entry) {
+      entry = {
+        labels: pickLabels(this.labelNames, labels),
+        counts: new Array(this.buckets.length).fill(0),
+        sum: 0,
+        count: 0,
+      };
+      this.series.set(key, entry);
+    }
+
+    const index = this.buckets.findIndex((bound) => value <= bound);
+    if (index >= 0) entry.counts[index] += 1;
+    entry.sum += value;
+    entry.count += 1;
+  }
+
+  render(): string[] {
+    const lines = header(this.name, this.help, "histogram");
+    for (const { labels, counts, sum, count } of this.series.values()) {
+      let cumulative = 0;
+      this.buckets.forEach((bound, index) => {
+        cumulative += counts[index];
+        const bucketLabels = formatLabels({ ...labels, le: formatValue(bound) });
+        lines.push(`${this.name}_bucket${bucketLabels} ${cumulative}`);
+      });
+      lines.push(`${this.name}_bucket${formatLabels({ ...labels, le: "+Inf" })} ${count}`);
+      lines.push(`${this.name}_sum${formatLabels(labels)} ${formatValue(sum)}`);
+      lines.push(`${this.name}_count${formatLabels(labels)} ${count}`);
+    }
+    return lines;
+  }
+}
+
+export class MetricsRegistry {
+  private readonly metrics = new Map<string, Metric>();
+
+  register<T extends Metric>(metric: T): T {
+    if (this.metrics.has(metric.name)) {
+      throw new Error(`Metric ${metric.name} is already registered`);
+    }
+    this.metrics.set(metric.name, metric);
+    return metric;
+  }
+
+  /** 以 Prometheus 文本格式输出全部指标 */
+  render(): string {
+    const lines: string[] = [];
+    for (const metric of this.metrics.values()) {
+      lines.push(...metric.render());
+    }
+    return `${lines.join("\n")}\n`;
+  }
+}
+
+export const PROMETHEUS_CONTENT_TYPE = "text/plain; version=0.0.4; charset=utf-8";
@@ -5,6 +5,7 @@ import { readMigrationFiles } from "drizzle-orm/migrator";
 import { drizzle } from "drizzle-orm/postgres-js";
 import { migrate } from "drizzle-orm/postgres-js/migrator";
 import postgres from "postgres";
+import { getDbConnectionParams } from "@/drizzle/connection-params";
 import { logger } from "@/lib/logger";
 
 const MIGRATION_ADVISORY_LOCK_NAME = "claude-code-hub:migrations";
@@ -19,7 +20,7 @@ export async function withAdvisoryLock<T>(
     process.exit(1);
   }
 
-  const client = postgres(process.env.DSN, { max: 1 });
+  const client = postgres(process.env.DSN, { max: 1, connection: getDbConnectionParams() });
   let acquired = false;
 
   try {
@@ -152,7 +153,10 @@ export async function runMigrations() {
 
   logger.info("Starting database migrations...");
 
-  const migrationClient = postgres(process.env.DSN, { max: 1 });
+  const migrationClient = postgres(process.env.DSN, {
+    max: 1,
+    connection: getDbConnectionParams(),
+  });
   const db = drizzle(migrationClient);
 
   try {
@@ -196,7 +200,10 @@ export async function checkDatabaseConnection(retries = 30, delay = 2000): Promi
 
   for (let i = 0; i < retries; i++) {
     try {
-      const client = postgres(process.env.DSN, { max: 1 });
+      const client = postgres(process.env.DSN, {
+        max: 1,
+        connection: getDbConnectionParams(),
+      });
       await client`SELECT 1`;
       await client.end();
       logger.info("Database connection established");
@@ -11,6 +11,7 @@ import {
 import { generateCostAlerts } from "@/lib/notification/tasks/cost-alert";
 import { generateDailyLeaderboard } from "@/lib/notification/tasks/daily-leaderboard";
 import { buildRedisQueueOptions } from "@/lib/redis/bull-queue-options";
+import { parseHHMM, type TimeOfDay } from "@/lib/utils/time-of-day";
 import { resolveSystemTimezone } from "@/lib/utils/timezone";
 import {
   buildCacheHitRateAlertMessage,
@@ -27,6 +28,9 @@ import {
 } from "@/lib/webhook";
 import { isCacheHitRateAlertSettingsWindowMode } from "@/lib/webhook/types";
 
+// 排行榜发送时间缺失或格式非法时回退到 09:00
+const DEFAULT_LEADERBOARD_TIME: TimeOfDay = { hour: 9, minute: 0 };
+
 /**
  * 通知任务数据
  */
@@ -803,7 +807,8 @@ export async function scheduleNotifications() {
         settings.dailyLeaderboardWebhook &&
         settings.dailyLeaderboardTime
       ) {
-        const [hour, minute] = settings.dailyLeaderboardTime.split(":").map(Number);
+        const { hour, minute } =
+          parseHHMM(settings.dailyLeaderboardTime) ?? DEFAULT_LEADERBOARD_TIME;
         const cron = `${minute} ${hour} * * *`; // 每天指定时间
 
         await queue.add(
@@ -875,7 +880,8 @@ export async function scheduleNotifications() {
 
       if (settings.dailyLeaderboardEnabled) {
         const bindings = await getEnabledBindingsByType("daily_leaderboard");
-        const [hour, minute] = (settings.dailyLeaderboardTime ?? "09:00").split(":").map(Number);
+        const { hour, minute } =
+          parseHHMM(settings.dailyLeaderboardTime ?? "") ?? DEFAULT_LEADERBOARD_TIME;
         const defaultCron = `${minute} ${hour} * * *`;
 
         for (const binding of bindings) {
@@ -1,8 +1,13 @@
-import { sql } from "drizzle-orm";
+import { and, isNull, sql } from "drizzle-orm";
 import { db } from "@/drizzle/db";
 import { keys, providers } from "@/drizzle/schema";
 import { logger } from "@/lib/logger";
-import { getTimeRangeForPeriod } from "@/lib/rate-limit/time-utils";
+import {
+  type DailyResetMode,
+  getTimeRangeForPeriod,
+  getTimeRangeForPeriodWithMode,
+  type TimeRange,
+} from "@/lib/rate-limit/time-utils";
 import type { CostAlertData } from "@/lib/webhook";
 import { sumKeyCostInTimeRange, sumProviderCostInTimeRange } from "@/repository/statistics";
 
@@ -43,124 +48,184 @@ export async function generateCostAlerts(threshold: number): Promise<CostAlertDa
   }
 }
 
+export type KeyCostAlertWindow = "5h" | "daily" | "weekly" | "monthly" | "total";
+
 /**
- * 检查用户配额超额情况
- *
- * 使用统一的时间窗口计算函数 (getTimeRangeForPeriod) 和
- * 带 warmup/deleted 过滤的统计函数 (sumKeyCostInTimeRange)。
+ * Key 级额度预警（单个窗口）
+ */
+export interface KeyCostAlert {
+  keyId: number;
+  keyName: string;
+  window: KeyCostAlertWindow;
+  currentCost: number;
+  quotaLimit: number;
+  /** currentCost / quotaLimit */
+  usageRatio: number;
+}
+
+const KEY_COST_ALERT_PERIOD_LABELS: Record<KeyCostAlertWindow, string> = {
+  "5h": "5小时",
+  daily: "每日",
+  weekly: "本周",
+  monthly: "本月",
+  total: "总计",
+};
+
+type KeyCostWindowCheck = {
+  window: KeyCostAlertWindow;
+  limit: number;
+  range: () => Promise<TimeRange>;
+};
+
+function parseLimit(value: string | null | undefined): number {
+  if (!value) return 0;
+  const parsed = parseFloat(value);
+  return Number.isFinite(parsed) ? parsed : 0;
+}
+
+/**
+ * 评估所有 Key 的额度使用情况，返回达到阈值的窗口
  *
- * 时间窗口语义:
+ * 时间窗口语义（与限流保持一致）:
  * - 5h: 滚动窗口（过去 5 小时）
- * - weekly: 自然周（本周一 00:00 开始，使用系统时区）
- * - monthly: 自然月（本月 1 号 00:00 开始，使用系统时区）
+ * - daily: 按 Key 的 dailyResetMode / dailyResetTime 计算（fixed 为自定义重置时间，rolling 为过去 24 小时）
+ * - weekly / monthly: 自然周 / 自然月（系统时区）
+ * - total: 全部历史
+ *
+ * 所有窗口的下限都会被 Key 的 costResetAt 截断；某个窗口限额为 0/未设置时仅跳过该窗口。
+ * 单个窗口查询失败时记录日志并跳过该窗口，其余窗口与 Key 照常评估。
+ *
+ * @param threshold 阈值 (0-1，例如 0.8 表示 80%)
  */
-async function checkUserQuotas(threshold: number): Promise<CostAlertData[]> {
-  const alerts: CostAlertData[] = [];
+export async function evaluateKeyCostAlerts(threshold: number): Promise<KeyCostAlert[]> {
+  const alerts: KeyCostAlert[] = [];
 
-  try {
-    // 查询有配额限制的密钥
-    const keysWithLimits = await db
-      .select({
-        id: keys.id,
-        key: keys.key,
-        userName: keys.name,
+  // 查询有配额限制的有效密钥
+  const keysWithLimits = await db
+    .select({
+      id: keys.id,
+      key: keys.key,
+      userName: keys.name,
 
-        // 限额配置
-        limit5h: keys.limit5hUsd,
-        limitWeek: keys.limitWeeklyUsd,
-        limitMonth: keys.limitMonthlyUsd,
-      })
-      .from(keys)
-      .where(
-        sql`${keys.limit5hUsd} > 0 OR ${keys.limitWeeklyUsd} > 0 OR ${keys.limitMonthlyUsd} > 0`
-      );
-
-    // 预计算时间范围（所有 key 共享相同的时间窗口）
-    const [range5h, rangeWeekly, rangeMonthly] = await Promise.all([
-      getTimeRangeForPeriod("5h"),
-      getTimeRangeForPeriod("weekly"),
-      getTimeRangeForPeriod("monthly"),
-    ]);
+      // 限额配置
+      limit5h: keys.limit5hUsd,
+      limitDaily: keys.limitDailyUsd,
+      dailyResetMode: keys.dailyResetMode,
+      dailyResetTime: keys.dailyResetTime,
+      limitWeek: keys.limitWeeklyUsd,
+      limitMonth: keys.limitMonthlyUsd,
+      limitTotal: keys.limitTotalUsd,
+      costResetAt: keys.costResetAt,
+    })
+    .from(keys)
+    .where(
+      and(
+        isNull(keys.deletedAt),
+        sql`(${keys.limit5hUsd} > 0 OR ${keys.limitDailyUsd} > 0 OR ${keys.limitWeeklyUsd} > 0 OR ${keys.limitMonthlyUsd} > 0 OR ${keys.limitTotalUsd} > 0)`
+      )
+    );
 
-    for (const keyData of keysWithLimits) {
-      // 检查 5 小时额度
-      if (keyData.limit5h) {
-        const limit5h = parseFloat(keyData.limit5h);
-        if (limit5h > 0) {
-          // 使用 keyId 和标准统计函数（包含 warmup/deleted 过滤）
-          const cost5h = await sumKeyCostInTimeRange(
-            keyData.id,
-            range5h.startTime,
-            range5h.endTime
-          );
-          if (cost5h >= limit5h * threshold) {
-            alerts.push({
-              targetType: "user",
-              targetName: keyData.userName,
-              targetId: keyData.id,
-              currentCost: cost5h,
-              quotaLimit: limit5h,
-              threshold,
-              period: "5小时",
-            });
-          }
-        }
-      }
+  // 预计算时间范围（所有 key 共享相同的时间窗口）
+  const [range5h, rangeWeekly, rangeMonthly] = await Promise.all([
+    getTimeRangeForPeriod("5h"),
+    getTimeRangeForPeriod("weekly"),
+    getTimeRangeForPeriod("monthly"),
+  ]);
 
-      // 检查本周额度（自然周：从周一开始）
-      if (keyData.limitWeek) {
-        const limitWeek = parseFloat(keyData.limitWeek);
-        if (limitWeek > 0) {
-          const costWeek = await sumKeyCostInTimeRange(
-            keyData.id,
-            rangeWeekly.startTime,
-            rangeWeekly.endTime
-          );
-          if (costWeek >= limitWeek * threshold) {
-            alerts.push({
-              targetType: "user",
-              targetName: keyData.userName,
-              targetId: keyData.id,
-              currentCost: costWeek,
-              quotaLimit: limitWeek,
-              threshold,
-              period: "本周",
-            });
-          }
-        }
-      }
+  // 每日窗口依赖 Key 自身的重置配置，按 (mode, resetTime) 缓存
+  const dailyRanges = new Map<string, Promise<TimeRange>>();
+  const getDailyRange = (mode: DailyResetMode, resetTime: string) => {
+    const cacheKey = `${mode}:${resetTime}`;
+    let range = dailyRanges.get(cacheKey);
+    if (!range) {
+      range = getTimeRangeForPeriodWithMode("daily", resetTime, mode);
+      dailyRanges.set(cacheKey, range);
+    }
+    return range;
+  };
 
-      // 检查本月额度（自然月：从 1 号开始）
-      if (keyData.limitMonth) {
-        const limitMonth = parseFloat(keyData.limitMonth);
-        if (limitMonth > 0) {
-          const costMonth = await sumKeyCostInTimeRange(
-            keyData.id,
-            rangeMonthly.startTime,
-            rangeMonthly.endTime
-          );
-          if (costMonth >= limitMonth * threshold) {
-            alerts.push({
-              targetType: "user",
-              targetName: keyData.userName,
-              targetId: keyData.id,
-              currentCost: costMonth,
-              quotaLimit: limitMonth,
-              threshold,
-              period: "本月",
-            });
-          }
+  for (const keyData of keysWithLimits) {
+    const checks: KeyCostWindowCheck[] = [
+      { window: "5h", limit: parseLimit(keyData.limit5h), range: async () => range5h },
+      {
+        window: "daily",
+        limit: parseLimit(keyData.limitDaily),
+        range: () =>
+          getDailyRange(
+            (keyData.dailyResetMode ?? "fixed") as DailyResetMode,
+            keyData.dailyResetTime ?? "00:00"
+          ),
+      },
+      { window: "weekly", limit: parseLimit(keyData.limitWeek), range: async () => rangeWeekly },
+      { window: "monthly", limit: parseLimit(keyData.limitMonth), range: async () => rangeMonthly },
+      {
+        window: "total",
+        limit: parseLimit(keyData.limitTotal),
+        range: async () => ({ startTime: new Date(0), endTime: new Date() }),
+      },
+    ];
+
+    for (const { window, limit, range } of checks) {
+      if (limit <= 0) continue;
+
+      // 单个 Key/窗口评估失败只跳过该窗口，不丢弃其余已计算的告警
+      try {
+        const { startTime, endTime } = await range();
+        // 使用 keyId 和标准统计函数（包含 warmup/deleted 过滤），并按 costResetAt 截断
+        const currentCost = await sumKeyCostInTimeRange(
+          keyData.id,
+          startTime,
+          endTime,
+          keyData.costResetAt ?? null
+        );
+
+        const usageRatio = currentCost / limit;
+        if (usageRatio >= threshold) {
+          alerts.push({
+            keyId: keyData.id,
+            keyName: keyData.userName,
+            window,
+            currentCost,
+            quotaLimit: limit,
+            usageRatio,
+          });
         }
+      } catch (error) {
+        logger.error({
+          action: "evaluate_key_cost_alert_error",
+          keyId: keyData.id,
+          window,
+          error: error instanceof Error ? error.message : String(error),
+        });
       }
     }
+  }
+
+  return alerts;
+}
+
+/**
+ * 检查用户配额超额情况（Key 级 5h / 每日 / 本周 / 本月 / 总计额度）
+ */
+async function checkUserQuotas(threshold: number): Promise<CostAlertData[]> {
+  try {
+    const keyAlerts = await evaluateKeyCostAlerts(threshold);
+    return keyAlerts.map((alert) => ({
+      targetType: "user" as const,
+      targetName: alert.keyName,
+      targetId: alert.keyId,
+      currentCost: alert.currentCost,
+      quotaLimit: alert.quotaLimit,
+      threshold,
+      period: KEY_COST_ALERT_PERIOD_LABELS[alert.window],
+    }));
   } catch (error) {
     logger.error({
       action: "check_user_quotas_error",
       error: error instanceof Error ? error.message : String(error),
     });
+    return [];
   }
-
-  return alerts;
 }
 
 /**
@@ -10,6 +10,7 @@
  */
 import { iconFileForVendor, type VendorIconFileEntry } from "@/lib/model-vendor/vendor-icon-files";
 import { vendorDisplayName } from "@/lib/model-vendor/vendor-inference";
+import { MODEL_CAPABILITY_FIELDS } from "@/lib/utils/model-capabilities";
 import type { ModelPriceData } from "@/types/model-price";
 import type {
   CptCharge,
@@ -319,21 +320,6 @@ export function convertCptVariant(variant: CptPricingVariant): Record<string, un
   return node;
 }
 
-const CAPABILITY_FIELD_MAP: Record<string, string[]> = {
-  assistant_prefill: ["supports_assistant_prefill"],
-  computer_use: ["supports_computer_use"],
-  function_calling: ["supports_function_calling", "supports_tool_choice"],
-  pdf_input: ["supports_pdf_input"],
-  prompt_caching: ["supports_prompt_caching"],
-  reasoning: ["supports_reasoning"],
-  structured_output: ["supports_response_schema"],
-  vision: ["supports_vision"],
-  audio_input: ["supports_audio_input"],
-  audio_output: ["supports_audio_output"],
-  video_input: ["supports_video_input"],
-  web_search: ["supports_web_search"],
-};
-
 function modeOfModelType(modelType: string | null | undefined): string {
   if (!modelType) return "chat";
   switch (modelType) {
@@ -464,7 +450,7 @@ export function convertCptModelEntry(
   }
 
   if (entry.capabilities && typeof entry.capabilities === "object") {
-    for (const [capability, fields] of Object.entries(CAPABILITY_FIELD_MAP)) {
+    for (const [capability, fields] of Object.entries(MODEL_CAPABILITY_FIELDS)) {
       if (entry.capabilities[capability] === true) {
         for (const field of fields) {
           (priceData as Record<string, unknown>)[field] = true;
@@ -0,0 +1,46 @@
+import { resolveProviderGroupsWithDefault } from "@/lib/utils/provider-group";
+import { findAllProvidersFresh } from "@/repository/provider";
+import { findAllUserProviderGroupValues } from "@/repository/user";
+
+export interface GroupUsageInfo {
+  name: string;
+  providerCount: number;
+  userCount: number;
+}
+
+interface GroupUsageInput {
+  findAllProvidersFresh?: () => Promise<Array<{ groupTag: string | null }>>;
+  findAllUserProviderGroupValues?: () => Promise<Array<{ providerGroup: string | null }>>;
+}
+
+function countGroups(values: Array<string | null>): Map<string, number> {
+  const counts = new Map<string, number>();
+  for (const value of values) {
+    // 同一条记录重复写同一分组时只计一次
+    for (const name of new Set(resolveProviderGroupsWithDefault(value))) {
+      counts.set(name, (counts.get(name) || 0) + 1);
+    }
+  }
+  return counts;
+}
+
+/**
+ * 汇总供应商 groupTag 与用户 providerGroup 中出现的全部分组（去重、按名称排序），
+ * 并标注引用该分组的供应商数与用户数。未设置分组的供应商/用户计入 default 组。
+ */
+export async function collectGroupUsage(input: GroupUsageInput = {}): Promise<GroupUsageInfo[]> {
+  const loadProviders = input.findAllProvidersFresh ?? findAllProvidersFresh;
+  const loadUserGroups = input.findAllUserProviderGroupValues ?? findAllUserProviderGroupValues;
+
+  const [providers, users] = await Promise.all([loadProviders(), loadUserGroups()]);
+
+  const providerCounts = countGroups(providers.map((provider) => provider.groupTag));
+  const userCounts = countGroups(users.map((user) => user.providerGroup));
+
+  const names = new Set([...providerCounts.keys(), ...userCounts.keys()]);
+  return [...names].sort().map((name) => ({
+    name,
+    providerCount: providerCounts.get(name) || 0,
+    userCount: userCounts.get(name) || 0,
+  }));
+}
@@ -1,6 +1,7 @@
 import safeRegex from "safe-regex";
 import { z } from "zod";
 import { PROVIDER_RULE_LIMITS } from "@/lib/constants/provider.constants";
+import { findProviderModelRedirectConflict } from "@/lib/provider-model-redirects";
 import { resolveProviderPatternRegex } from "@/lib/provider-pattern-regex";
 
 export const PROVIDER_MODEL_REDIRECT_MATCH_TYPE_SCHEMA = z.enum([
@@ -84,7 +85,21 @@ export const PROVIDER_MODEL_REDIRECT_RULE_LIST_SCHEMA = z
     {
       message: "Duplicate redirect rule for matchType+source",
     }
-  );
+  )
+  .superRefine((rules, ctx) => {
+    const conflict = findProviderModelRedirectConflict(rules);
+    if (!conflict) {
+      return;
+    }
+
+    ctx.addIssue({
+      code: z.ZodIssueCode.custom,
+      message:
+        conflict.type === "self"
+          ? `Redirect cannot point a model to itself: ${conflict.source}`
+          : `Redirect rules form a cycle: ${conflict.path.join(" -> ")}`,
+    });
+  });
 
 export const PROVIDER_MODEL_REDIRECT_RULES_SCHEMA =
   PROVIDER_MODEL_REDIRECT_RULE_LIST_SCHEMA.nullable().optional();
@@ -1,5 +1,10 @@
 import { matchesPattern } from "@/lib/model-pattern-matcher";
-import type { ProviderModelRedirectMatchType, ProviderModelRedirectRule } from "@/types/provider";
+import type {
+  Provider,
+  ProviderModelRedirectMatchType,
+  ProviderModelRedirectRule,
+} from "@/types/provider";
+import type { ProviderParameterOverrideSpecialSetting } from "@/types/special-settings";
 
 const PROVIDER_MODEL_REDIRECT_MATCH_TYPES = new Set<ProviderModelRedirectMatchType>([
   "exact",
@@ -84,6 +89,56 @@ export function normalizeProviderModelRedirectRules(
   return normalized;
 }
 
+export type ProviderModelRedirectConflict =
+  | { type: "self"; source: string }
+  | { type: "cycle"; path: string[] };
+
+/**
+ * 检测精确匹配重定向中的自重定向（a→a）与循环（a→b、b→a）
+ *
+ * 同一 source 仅第一条规则生效（与 findMatchingProviderModelRedirectRule 一致）；
+ * 其他匹配类型无法静态展开目标，不参与检测
+ */
+export function findProviderModelRedirectConflict(
+  rules: ProviderModelRedirectRule[] | null | undefined
+): ProviderModelRedirectConflict | null {
+  const exactTargets = new Map<string, string>();
+  for (const rule of rules ?? []) {
+    if (rule.matchType !== "exact") continue;
+    const source = rule.source.trim();
+    const target = rule.target.trim();
+    if (source === target) {
+      return { type: "self", source };
+    }
+    if (!exactTargets.has(source)) {
+      exactTargets.set(source, target);
+    }
+  }
+
+  // 0/缺省: 未访问；1: 当前路径上；2: 已确认无环
+  const state = new Map<string, 1 | 2>();
+  for (const start of exactTargets.keys()) {
+    if (state.has(start)) continue;
+
+    const path: string[] = [];
+    let current: string | undefined = start;
+    while (current !== undefined && !state.has(current)) {
+      state.set(current, 1);
+      path.push(current);
+      current = exactTargets.get(current);
+    }
+
+    if (current !== undefined && state.get(current) === 1) {
+      return { type: "cycle", path: [...path.slice(path.indexOf(current)), current] };
+    }
+    for (const model of path) {
+      state.set(model, 2);
+    }
+  }
+
+  return null;
+}
+
 export function hasProviderModelRedirectRules(
   rules: ProviderModelRedirectRule[] | null | undefined
 ): boolean {
@@ -120,3 +175,33 @@ export function getProviderModelRedirectTarget(
 ): string {
   return findMatchingProviderModelRedirectRule(model, rules)?.target ?? model;
 }
+
+/**
+ * 构造模型重定向审计（provider_parameter_override，path = "model"）
+ *
+ * 与其他供应商参数覆写共用同一类型，便于在请求记录中统一展示与筛选。
+ */
+export function buildModelRedirectSpecialSetting(
+  provider: Pick<Provider, "id" | "name" | "providerType">,
+  originalModel: string,
+  redirectedModel: string
+): ProviderParameterOverrideSpecialSetting {
+  const changed = originalModel !== redirectedModel;
+  return {
+    type: "provider_parameter_override",
+    scope: "provider",
+    providerId: provider.id ?? null,
+    providerName: provider.name ?? null,
+    providerType: provider.providerType ?? null,
+    hit: true,
+    changed,
+    changes: [
+      {
+        path: "model",
+        before: originalModel,
+        after: redirectedModel,
+        changed,
+      },
+    ],
+  };
+}
@@ -1,3 +1,4 @@
+import { PROVIDER_LIMITS } from "@/lib/constants/provider.constants";
 import { normalizeProviderGroupTag } from "@/lib/utils/provider-group";
 import type {
   ProviderBatchApplyUpdates,
@@ -216,9 +217,15 @@ function isValidSetValue(field: ProviderBatchPatchField, value: unknown): boolea
     case "swap_cache_ttl_billing":
     case "proxy_fallback_to_direct":
       return typeof value === "boolean";
+    case "cost_multiplier":
+      return (
+        typeof value === "number" &&
+        Number.isFinite(value) &&
+        value > 0 &&
+        value <= PROVIDER_LIMITS.COST_MULTIPLIER.MAX
+      );
     case "priority":
     case "weight":
-    case "cost_multiplier":
     case "limit_5h_usd":
     case "limit_daily_usd":
     case "limit_weekly_usd":
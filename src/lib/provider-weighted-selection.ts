@@ -0,0 +1,54 @@
+import type { Provider } from "@/types/provider";
+
+type WeightedProviderCandidate = Pick<Provider, "priority" | "weight">;
+
+export interface WeightedSelectionOptions<T> {
+  /** 返回 [0, 1) 的随机数函数，测试中可注入确定性实现 */
+  random?: () => number;
+  /** 解析有效优先级（如分组优先级覆盖），默认使用 provider.priority */
+  resolvePriority?: (provider: T) => number;
+}
+
+/**
+ * 参与加权随机的有效权重：weight <= 0 视为 1
+ * 决策链中展示的选中概率也须按此计算，与实际选择保持一致
+ */
+export function getEffectiveWeight(provider: Pick<Provider, "weight">): number {
+  return provider.weight > 0 ? provider.weight : 1;
+}
+
+/**
+ * 在最高优先级层（有效优先级数值最小）内按 weight 加权随机选择一个供应商
+ *
+ * - weight <= 0 视为 1，避免零权重供应商永远饿死
+ * - 候选顺序即累积权重顺序，调用方可预先排序（如按成本倍率）
+ *
+ * @returns 选中的供应商；输入为空时返回 null
+ */
+export function selectWeightedProvider<T extends WeightedProviderCandidate>(
+  providers: T[],
+  options: WeightedSelectionOptions<T> = {}
+): T | null {
+  if (providers.length === 0) {
+    return null;
+  }
+
+  const random = options.random ?? Math.random;
+  const resolvePriority = options.resolvePriority ?? ((p: T) => p.priority ?? 0);
+
+  const topPriority = Math.min(...providers.map(resolvePriority));
+  const tier = providers.filter((p) => resolvePriority(p) === topPriority);
+
+  const totalWeight = tier.reduce((sum, p) => sum + getEffectiveWeight(p), 0);
+
+  const target = random() * totalWeight;
+  let cumulativeWeight = 0;
+  for (const provider of tier) {
+    cumulativeWeight += getEffectiveWeight(provider);
+    if (target < cumulativeWeight) {
+      return provider;
+    }
+  }
+
+  return tier[tier.length - 1];
+}
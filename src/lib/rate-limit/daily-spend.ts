@@ -0,0 +1,65 @@
+/**
+ * Key 每日消费计算
+ *
+ * 按 DailyResetMode 确定窗口后从 usage_ledger 聚合消费：
+ * - rolling: 过去 24 小时 [now - 24h, now)
+ * - fixed: 自 resetTime（系统时区或指定时区）最近一次重置至今
+ */
+
+import { resolveSystemTimezone } from "@/lib/utils/timezone";
+import { sumKeyCostInTimeRange } from "@/repository/statistics";
+import { type DailyResetMode, getDailyTimeRange } from "./time-utils";
+
+export interface DailySpendOptions {
+  mode: DailyResetMode;
+  resetTime?: string;
+  /** 缺省时使用系统配置时区 */
+  timezone?: string;
+  now?: Date;
+  /** 消费重置时间（如 costResetAt），早于该时间的消费不计入 */
+  clipStart?: Date | null;
+}
+
+/**
+ * 计算 Key 在滚动 24 小时窗口内的消费
+ */
+export async function computeRollingDailySpend(
+  keyId: number,
+  now: Date = new Date(),
+  clipStart?: Date | null
+): Promise<number> {
+  // 滚动窗口与时区无关，显式传入 UTC 以跳过系统时区查询
+  return computeDailySpend(keyId, { mode: "rolling", timezone: "UTC", now, clipStart });
+}
+
+/**
+ * 计算 Key 自最近一次固定重置时间以来的消费
+ */
+export async function computeFixedDailySpend(
+  keyId: number,
+  resetTime: string,
+  timezone?: string,
+  now: Date = new Date(),
+  clipStart?: Date | null
+): Promise<number> {
+  return computeDailySpend(keyId, { mode: "fixed", resetTime, timezone, now, clipStart });
+}
+
+/**
+ * 按重置模式计算 Key 的每日消费
+ */
+export async function computeDailySpend(
+  keyId: number,
+  options: DailySpendOptions
+): Promise<number> {
+  const now = options.now ?? new Date();
+  const timezone = options.timezone ?? (await resolveSystemTimezone());
+  const { startTime, endTime } = getDailyTimeRange(
+    options.mode,
+    options.resetTime ?? "00:00",
+    timezone,
+    now
+  );
+
+  return sumKeyCostInTimeRange(keyId, startTime, endTime, options.clipStart);
+}
@@ -0,0 +1,95 @@
+/**
+ * 限流元数据解析
+ * 新记录直接读取 message_request.rate_limit_metadata（JSONB）；
+ * 旧记录的元数据以 " | rate_limit_metadata: {...}" 形式附加在 error_message 末尾，仅作兼容回退。
+ */
+
+import type { RateLimitMetadata, RateLimitType } from "@/types/statistics";
+
+const RATE_LIMIT_TYPES: ReadonlySet<RateLimitType> = new Set<RateLimitType>([
+  "rpm",
+  "usd_5h",
+  "usd_weekly",
+  "usd_monthly",
+  "usd_total",
+  "concurrent_sessions",
+  "daily_quota",
+]);
+
+const LEGACY_METADATA_MARKER = "rate_limit_metadata:";
+
+function toFiniteNumber(value: unknown): number | null {
+  if (typeof value === "number") {
+    return Number.isFinite(value) ? value : null;
+  }
+  if (typeof value === "string" && value.trim() !== "") {
+    const parsed = Number(value);
+    return Number.isFinite(parsed) ? parsed : null;
+  }
+  return null;
+}
+
+/**
+ * 将任意 JSON 值解析为限流元数据；limit_type 缺失或未知时返回 null
+ */
+export function parseRateLimitMetadata(value: unknown): RateLimitMetadata | null {
+  if (!value || typeof value !== "object" || Array.isArray(value)) {
+    return null;
+  }
+
+  const record = value as Record<string, unknown>;
+  const limitType = record.limit_type;
+  if (typeof limitType !== "string" || !RATE_LIMIT_TYPES.has(limitType as RateLimitType)) {
+    return null;
+  }
+
+  // 早期写入使用 current 字段，这里一并兼容
+  const currentUsage = toFiniteNumber(record.current_usage ?? record.current) ?? 0;
+
+  return {
+    type: "rate_limit_error",
+    limit_type: limitType as RateLimitType,
+    current_usage: currentUsage,
+    limit_value: toFiniteNumber(record.limit_value) ?? 0,
+    reset_time: typeof record.reset_time === "string" ? record.reset_time : null,
+    provider_id: toFiniteNumber(record.provider_id),
+    message: typeof record.message === "string" ? record.message : "",
+  };
+}
+
+/**
+ * 从旧格式的 error_message 中提取限流元数据（兼容 rate_limit_metadata 列上线前的记录）
+ */
+export function extractRateLimitMetadataFromErrorMessage(
+  errorMessage: string | null | undefined
+): RateLimitMetadata | null {
+  if (!errorMessage) {
+    return null;
+  }
+
+  const markerIndex = errorMessage.lastIndexOf(LEGACY_METADATA_MARKER);
+  if (markerIndex === -1) {
+    return null;
+  }
+
+  try {
+    return parseRateLimitMetadata(
+      JSON.parse(errorMessage.slice(markerIndex + LEGACY_METADATA_MARKER.length))
+    );
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * 读取一条记录的限流元数据：优先使用结构化列，缺失时回退解析 error_message
+ */
+export function resolveRateLimitMetadata(row: {
+  rateLimitMetadata?: unknown;
+  errorMessage?: string | null;
+}): RateLimitMetadata | null {
+  return (
+    parseRateLimitMetadata(row.rateLimitMetadata) ??
+    extractRateLimitMetadataFromErrorMessage(row.errorMessage)
+  );
+}
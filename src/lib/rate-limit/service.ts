@@ -1307,11 +1307,20 @@ export class RateLimitService {
   /**
    * 检查用户 RPM（每分钟请求数）限制
    * 使用 Redis ZSET 实现滑动窗口
+   *
+   * resetAt 为窗口内最早一条请求滑出 1 分钟窗口的时间（窗口为空时为 now + 60s），
+   * 供 RateLimitError 填充 resetTime
    */
   static async checkUserRPM(
     userId: number,
     rpmLimit: number
-  ): Promise<{ allowed: boolean; reason?: string; current?: number }> {
+  ): Promise<{
+    allowed: boolean;
+    reason?: string;
+    current?: number;
+    remaining?: number;
+    resetAt?: Date;
+  }> {
     if (!rpmLimit || rpmLimit <= 0) {
       return { allowed: true }; // 未设置限制
     }
@@ -1335,25 +1344,38 @@ export class RateLimitService {
       // 2. 统计当前请求数
       pipeline.zcard(key);
 
+      // 3. 读取窗口内最早的请求（用于计算 resetAt）
+      pipeline.zrange(key, 0, 0, "WITHSCORES");
+
       const results = await pipeline.exec();
       const count = (results?.[1]?.[1] as number) || 0;
+      const oldest = results?.[2]?.[1] as string[] | undefined;
+      const oldestScore = Number(oldest?.[1]);
+      const resetAt = new Date((Number.isFinite(oldestScore) ? oldestScore : now) + 60000);
 
       if (count >= rpmLimit) {
         return {
           allowed: false,
           reason: `用户每分钟请求数上限已达到（${count}/${rpmLimit}）`,
           current: count,
+          remaining: 0,
+          resetAt,
         };
       }
 
-      // 3. 记录本次请求
+      // 4. 记录本次请求
       await RateLimitService.redis
         .pipeline()
         .zadd(key, now, `${now}:${Math.random()}`)
         .expire(key, 120) // 2 分钟 TTL
         .exec();
 
-      return { allowed: true, current: count + 1 };
+      return {
+        allowed: true,
+        current: count + 1,
+        remaining: Math.max(0, rpmLimit - count - 1),
+        resetAt,
+      };
     } catch (error) {
       logger.error(`[RateLimit] User RPM check failed for user ${userId}:`, error);
       return { allowed: true }; // Fail Open
@@ -1368,7 +1390,13 @@ export class RateLimitService {
     id: number,
     type: "user",
     limit: number
-  ): Promise<{ allowed: boolean; reason?: string; current?: number }> {
+  ): Promise<{
+    allowed: boolean;
+    reason?: string;
+    current?: number;
+    remaining?: number;
+    resetAt?: Date;
+  }> {
     if (type === "user") {
       return RateLimitService.checkUserRPM(id, limit);
     }
@@ -121,6 +121,27 @@ export async function getTimeRangeForPeriodWithMode(
   return getTimeRangeForPeriod(period, resetTime);
 }
 
+/**
+ * 计算每日限额窗口（纯函数，便于在指定时刻与时区下复算）
+ * - rolling: [now - 24h, now)
+ * - fixed: [最近一次 resetTime（timezone 下）, now)
+ */
+export function getDailyTimeRange(
+  mode: DailyResetMode,
+  resetTime: string,
+  timezone: string,
+  now: Date = new Date()
+): TimeRange {
+  if (mode === "rolling") {
+    return { startTime: new Date(now.getTime() - 24 * 60 * 60 * 1000), endTime: now };
+  }
+
+  return {
+    startTime: getCustomDailyResetTime(now, normalizeResetTime(resetTime), timezone),
+    endTime: now,
+  };
+}
+
 /**
  * 根据周期计算 Redis Key 的 TTL（秒）
  * - 5h: 5 小时（固定）
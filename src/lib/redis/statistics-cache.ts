@@ -1,5 +1,6 @@
 import { logger } from "@/lib/logger";
 import { resolveSystemTimezone } from "@/lib/utils/timezone";
+import { StatisticsQueryTimeoutError } from "@/repository/_shared/statistics-query-timeout";
 import {
   getKeyStatisticsFromDB,
   getMixedStatisticsFromDB,
@@ -28,7 +29,8 @@ async function queryDatabase(
   timeRange: TimeRange,
   mode: "users" | "keys" | "mixed",
   timezone: string,
-  userId?: number
+  userId?: number,
+  signal?: AbortSignal
 ): Promise<StatisticsCacheData> {
   if ((mode === "keys" || mode === "mixed") && userId === undefined) {
     throw new Error(`queryDatabase: userId required for mode="${mode}"`);
@@ -39,7 +41,7 @@ async function queryDatabase(
     case "keys":
       return await getKeyStatisticsFromDB(userId!, timeRange, timezone);
     case "mixed":
-      return await getMixedStatisticsFromDB(userId!, timeRange, timezone);
+      return await getMixedStatisticsFromDB(userId!, timeRange, timezone, { signal });
   }
 }
 
@@ -51,14 +53,21 @@ async function queryDatabase(
  * 2. On cache miss, acquire distributed lock to prevent thundering herd
  * 3. Requests that fail to acquire lock wait and retry (up to 5s)
  * 4. Fail-open: Redis unavailable -> direct DB query
+ *
+ * `timezoneOverride` is the caller-resolved timezone (e.g. the viewer's own timezone);
+ * when omitted the system timezone is used. The timezone is part of the cache key.
+ * `options.signal` (e.g. the request's AbortSignal) cancels in-flight DB queries when the
+ * client disconnects; an aborted query is rethrown instead of falling back to a direct query.
  */
 export async function getStatisticsWithCache(
   timeRange: TimeRange,
   mode: "users" | "keys" | "mixed",
-  userId?: number
+  userId?: number,
+  timezoneOverride?: string,
+  options: { signal?: AbortSignal } = {}
 ): Promise<StatisticsCacheData> {
   const redis = getRedisClient();
-  const timezone = await resolveSystemTimezone();
+  const timezone = timezoneOverride ?? (await resolveSystemTimezone());
 
   if (!redis) {
     logger.warn("[StatisticsCache] Redis not available, fallback to direct query", {
@@ -66,7 +75,7 @@ export async function getStatisticsWithCache(
       mode,
       userId,
     });
-    return await queryDatabase(timeRange, mode, timezone, userId);
+    return await queryDatabase(timeRange, mode, timezone, userId, options.signal);
   }
 
   const cacheKey = buildStatisticsCacheKey(timeRange, mode, userId, timezone);
@@ -90,7 +99,7 @@ export async function getStatisticsWithCache(
     if (locked) {
       logger.debug("[StatisticsCache] Acquired lock, computing", { timeRange, mode, lockKey });
 
-      data = await queryDatabase(timeRange, mode, timezone, userId);
+      data = await queryDatabase(timeRange, mode, timezone, userId, options.signal);
 
       try {
         await redis.setex(cacheKey, CACHE_TTL, JSON.stringify(data));
@@ -128,14 +137,17 @@ export async function getStatisticsWithCache(
 
     // Retry timeout - fallback to direct DB
     logger.warn("[StatisticsCache] Retry timeout, fallback to direct query", { timeRange, mode });
-    return await queryDatabase(timeRange, mode, timezone, userId);
+    return await queryDatabase(timeRange, mode, timezone, userId, options.signal);
   } catch (error) {
+    // 查询已超时或已被调用方中止：不再回退直查，避免同一慢查询再占用一次连接
+    if (error instanceof StatisticsQueryTimeoutError || options.signal?.aborted) throw error;
+
     logger.error("[StatisticsCache] Redis error, fallback to direct query", {
       timeRange,
       mode,
       error,
     });
-    return data ?? (await queryDatabase(timeRange, mode, timezone, userId));
+    return data ?? (await queryDatabase(timeRange, mode, timezone, userId, options.signal));
   } finally {
     if (locked) {
       await redis
@@ -0,0 +1,99 @@
+import "server-only";
+
+import { AsyncLocalStorage } from "node:async_hooks";
+import { randomUUID } from "node:crypto";
+import { type LogLevel, logger } from "@/lib/logger";
+
+export const REQUEST_ID_HEADER = "x-request-id";
+
+/** 仅接受可安全回显到响应头与日志中的请求 ID */
+const VALID_REQUEST_ID = /^[A-Za-z0-9._:-]{1,128}$/;
+
+type RequestLogger = typeof logger;
+
+declare global {
+  // 挂在 globalThis 上，保证多个模块实例共享同一个请求上下文
+  // eslint-disable-next-line no-var
+  var __cchRequestIdStorage: AsyncLocalStorage<string> | undefined;
+}
+
+if (!globalThis.__cchRequestIdStorage) {
+  globalThis.__cchRequestIdStorage = new AsyncLocalStorage<string>();
+}
+
+const storage = globalThis.__cchRequestIdStorage;
+
+/**
+ * 复用客户端传入的 X-Request-Id（格式合法时），否则生成新的 UUID
+ */
+export function resolveRequestId(incoming: string | null | undefined): string {
+  const trimmed = incoming?.trim();
+  if (trimmed && VALID_REQUEST_ID.test(trimmed)) {
+    return trimmed;
+  }
+  return randomUUID();
+}
+
+/**
+ * 在请求 ID 上下文中执行回调，回调内（含异步链路）可通过 getRequestId() 读取
+ */
+export function runWithRequestId<T>(requestId: string, fn: () => T): T {
+  return storage.run(requestId, fn);
+}
+
+export function getRequestId(): string | undefined {
+  return storage.getStore();
+}
+
+/**
+ * 为日志附加固定字段
+ *
+ * 对象参数会与 bindings 合并（调用方字段优先），Error 参数以 { ...bindings, error } 形式携带，
+ * 纯字符串消息则以 bindings 作为上下文对象
+ */
+function withBindings(base: RequestLogger, bindings: Record<string, unknown>): RequestLogger {
+  const bind = (level: LogLevel) => {
+    return (arg1: unknown, arg2?: unknown, ...args: unknown[]) => {
+      const isObject = (value: unknown): value is Record<string, unknown> =>
+        !!value && typeof value === "object" && !Array.isArray(value) && !(value instanceof Error);
+
+      if (isObject(arg1)) {
+        base[level]({ ...bindings, ...arg1 }, arg2, ...args);
+      } else if (arg1 instanceof Error) {
+        base[level]({ ...bindings, error: arg1 }, arg2, ...args);
+      } else if (typeof arg1 === "string" && isObject(arg2)) {
+        base[level](arg1, { ...bindings, ...arg2 }, ...args);
+      } else if (typeof arg1 === "string" && arg2 instanceof Error) {
+        base[level](arg1, { ...bindings, error: arg2 }, ...args);
+      } else if (typeof arg1 === "string" && arg2 === undefined) {
+        base[level](bindings, arg1, ...args);
+      } else {
+        base[level](arg1, arg2, ...args);
+      }
+    };
+  };
+
+  return {
+    fatal: bind("fatal"),
+    error: bind("error"),
+    warn: bind("warn"),
+    info: bind("info"),
+    debug: bind("debug"),
+    trace: bind("trace"),
+    get level() {
+      return base.level;
+    },
+    set level(newLevel: string) {
+      base.level = newLevel;
+    },
+  };
+}
+
+/**
+ * 获取请求级 logger：日志自动携带当前请求的 requestId
+ *
+ * 不在请求上下文中（或未传入 requestId）时返回全局 logger
+ */
+export function getRequestLogger(requestId: string | undefined = getRequestId()): RequestLogger {
+  return requestId ? withBindings(logger, { requestId }) : logger;
+}
@@ -36,3 +36,16 @@ export function constantTimeEqual(a: string, b: string): boolean {
   }
   return result === 0;
 }
+
+/**
+ * Constant-time check of a provided credential against the configured ADMIN_TOKEN.
+ *
+ * An empty or missing expected token never matches, so a deployment without
+ * ADMIN_TOKEN cannot be accessed with an empty credential.
+ */
+export function isAdminToken(provided: string, adminToken: string | null | undefined): boolean {
+  if (!adminToken) {
+    return false;
+  }
+  return constantTimeEqual(provided, adminToken);
+}
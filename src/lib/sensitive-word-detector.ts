@@ -6,10 +6,11 @@
  * - 性能优先的检测顺序（包含 → 精确 → 正则）
  * - 单例模式，全局复用
  * - 支持热重载
+ * - 规则可预编译为独立匹配器（compileSensitiveWords），避免每次请求重复构造正则
  */
 
 import { logger } from "@/lib/logger";
-import { getActiveSensitiveWords } from "@/repository/sensitive-words";
+import { getActiveSensitiveWords, type SensitiveWord } from "@/repository/sensitive-words";
 
 export interface DetectionResult {
   matched: boolean;
@@ -23,10 +24,141 @@ interface RegexPattern {
   word: string;
 }
 
+export type SensitiveWordRule = Pick<SensitiveWord, "word" | "matchType" | "isEnabled">;
+
+/**
+ * 预编译的敏感词匹配器
+ *
+ * 由 compileSensitiveWords 构建，正则只在编译时构造一次，可在多次请求间复用。
+ */
+export class SensitiveWordMatcher {
+  private readonly contains: string[] = [];
+  private readonly exact: Set<string> = new Set();
+  private readonly regex: RegexPattern[] = [];
+
+  constructor(words: SensitiveWordRule[]) {
+    // 按类型分组
+    for (const word of words) {
+      if (!word.isEnabled) continue;
+
+      const lowerWord = word.word.toLowerCase();
+
+      switch (word.matchType) {
+        case "contains":
+          this.contains.push(lowerWord);
+          break;
+
+        case "exact":
+          this.exact.add(lowerWord);
+          break;
+
+        case "regex":
+          try {
+            const pattern = new RegExp(word.word, "i");
+            this.regex.push({ pattern, word: word.word });
+          } catch (error) {
+            logger.error(`[SensitiveWordMatcher] Invalid regex pattern: ${word.word}`, error);
+          }
+          break;
+
+        default:
+          logger.warn(`[SensitiveWordMatcher] Unknown match type: ${word.matchType}`);
+      }
+    }
+  }
+
+  /**
+   * 检测文本中是否包含敏感词（命中第一个即返回）
+   *
+   * @param text - 需要检测的文本
+   * @returns 检测结果
+   */
+  detect(text: string): DetectionResult {
+    if (!text || text.length === 0) {
+      return { matched: false };
+    }
+
+    const lowerText = text.toLowerCase();
+    const trimmedText = lowerText.trim();
+
+    // 1. 包含匹配（最快，O(n*m)）
+    for (const word of this.contains) {
+      if (lowerText.includes(word)) {
+        return {
+          matched: true,
+          word,
+          matchType: "contains",
+          matchedText: this.extractMatchedText(text, word),
+        };
+      }
+    }
+
+    // 2. 精确匹配（使用 Set，O(1)）
+    if (this.exact.has(trimmedText)) {
+      return {
+        matched: true,
+        word: trimmedText,
+        matchType: "exact",
+        matchedText: text.trim(),
+      };
+    }
+
+    // 3. 正则匹配（最慢，但最灵活）
+    for (const { pattern, word } of this.regex) {
+      const match = pattern.exec(text);
+      if (match) {
+        return {
+          matched: true,
+          word,
+          matchType: "regex",
+          matchedText: match[0],
+        };
+      }
+    }
+
+    return { matched: false };
+  }
+
+  /**
+   * 提取匹配到的文本片段（带上下文）
+   */
+  private extractMatchedText(text: string, word: string): string {
+    const lowerText = text.toLowerCase();
+    const index = lowerText.indexOf(word.toLowerCase());
+
+    if (index === -1) {
+      return text.substring(0, 50); // 降级：返回前50字符
+    }
+
+    // 提取前后各20个字符作为上下文
+    const start = Math.max(0, index - 20);
+    const end = Math.min(text.length, index + word.length + 20);
+    const snippet = text.substring(start, end);
+
+    return start > 0 ? `...${snippet}` : snippet;
+  }
+
+  /**
+   * 各匹配类型的规则数量
+   */
+  get counts() {
+    return {
+      containsCount: this.contains.length,
+      exactCount: this.exact.size,
+      regexCount: this.regex.length,
+    };
+  }
+}
+
+/**
+ * 编译敏感词规则为可复用的匹配器（忽略禁用的规则）
+ */
+export function compileSensitiveWords(words: SensitiveWordRule[]): SensitiveWordMatcher {
+  return new SensitiveWordMatcher(words);
+}
+
 class SensitiveWordCache {
-  private contains: string[] = [];
-  private exact: Set<string> = new Set();
-  private regex: RegexPattern[] = [];
+  private matcher: SensitiveWordMatcher = compileSensitiveWords([]);
   private lastReloadTime: number = 0;
   private isLoading: boolean = false;
 
@@ -97,43 +229,15 @@ class SensitiveWordCache {
 
       const words = await getActiveSensitiveWords();
 
-      // 清空旧缓存
-      this.contains = [];
-      this.exact.clear();
-      this.regex = [];
-
-      // 按类型分组
-      for (const word of words) {
-        const lowerWord = word.word.toLowerCase();
-
-        switch (word.matchType) {
-          case "contains":
-            this.contains.push(lowerWord);
-            break;
-
-          case "exact":
-            this.exact.add(lowerWord);
-            break;
-
-          case "regex":
-            try {
-              const pattern = new RegExp(word.word, "i");
-              this.regex.push({ pattern, word: word.word });
-            } catch (error) {
-              logger.error(`[SensitiveWordCache] Invalid regex pattern: ${word.word}`, error);
-            }
-            break;
-
-          default:
-            logger.warn(`[SensitiveWordCache] Unknown match type: ${word.matchType}`);
-        }
-      }
+      // 整体替换匹配器，重载期间仍使用旧规则
+      this.matcher = compileSensitiveWords(words);
 
       this.lastReloadTime = Date.now();
 
+      const { containsCount, exactCount, regexCount } = this.matcher.counts;
       logger.info(
         `[SensitiveWordCache] Loaded ${words.length} sensitive words: ` +
-          `contains=${this.contains.length}, exact=${this.exact.size}, regex=${this.regex.length}`
+          `contains=${containsCount}, exact=${exactCount}, regex=${regexCount}`
       );
     } catch (error) {
       logger.error("[SensitiveWordCache] Failed to reload sensitive words:", error);
@@ -150,79 +254,19 @@ class SensitiveWordCache {
    * @returns 检测结果
    */
   detect(text: string): DetectionResult {
-    if (!text || text.length === 0) {
-      return { matched: false };
-    }
-
-    const lowerText = text.toLowerCase();
-    const trimmedText = lowerText.trim();
-
-    // 1. 包含匹配（最快，O(n*m)）
-    for (const word of this.contains) {
-      if (lowerText.includes(word)) {
-        return {
-          matched: true,
-          word,
-          matchType: "contains",
-          matchedText: this.extractMatchedText(text, word),
-        };
-      }
-    }
-
-    // 2. 精确匹配（使用 Set，O(1)）
-    if (this.exact.has(trimmedText)) {
-      return {
-        matched: true,
-        word: trimmedText,
-        matchType: "exact",
-        matchedText: text.trim(),
-      };
-    }
-
-    // 3. 正则匹配（最慢，但最灵活）
-    for (const { pattern, word } of this.regex) {
-      const match = pattern.exec(text);
-      if (match) {
-        return {
-          matched: true,
-          word,
-          matchType: "regex",
-          matchedText: match[0],
-        };
-      }
-    }
-
-    return { matched: false };
-  }
-
-  /**
-   * 提取匹配到的文本片段（带上下文）
-   */
-  private extractMatchedText(text: string, word: string): string {
-    const lowerText = text.toLowerCase();
-    const index = lowerText.indexOf(word.toLowerCase());
-
-    if (index === -1) {
-      return text.substring(0, 50); // 降级：返回前50字符
-    }
-
-    // 提取前后各20个字符作为上下文
-    const start = Math.max(0, index - 20);
-    const end = Math.min(text.length, index + word.length + 20);
-    const snippet = text.substring(start, end);
-
-    return start > 0 ? `...${snippet}` : snippet;
+    return this.matcher.detect(text);
   }
 
   /**
    * 获取缓存统计信息
    */
   getStats() {
+    const { containsCount, exactCount, regexCount } = this.matcher.counts;
     return {
-      containsCount: this.contains.length,
-      exactCount: this.exact.size,
-      regexCount: this.regex.length,
-      totalCount: this.contains.length + this.exact.size + this.regex.length,
+      containsCount,
+      exactCount,
+      regexCount,
+      totalCount: containsCount + exactCount + regexCount,
       lastReloadTime: this.lastReloadTime,
       isLoading: this.isLoading,
     };
@@ -232,7 +276,7 @@ class SensitiveWordCache {
    * 检查缓存是否为空
    */
   isEmpty(): boolean {
-    return this.contains.length === 0 && this.exact.size === 0 && this.regex.length === 0;
+    return this.getStats().totalCount === 0;
   }
 }
 
@@ -0,0 +1,85 @@
+/**
+ * CSV export for the user statistics timeline (external BI).
+ *
+ * Rows are read from the database in keyset-paginated batches of users and
+ * rendered lazily one line at a time; costs are formatted from the decimal
+ * representation with fixed 6-digit precision (never via float).
+ */
+
+import { escapeCsvField } from "@/lib/usage-logs/export/csv";
+import { Decimal, toDecimal } from "@/lib/utils/currency";
+import { getUserStatisticsPageFromDB } from "@/repository/statistics";
+import type { DatabaseStatRow, TimeRange } from "@/types/statistics";
+
+export const STATISTICS_CSV_HEADER = "date,user_id,user_name,api_calls,total_cost";
+
+const STATISTICS_CSV_COST_DIGITS = 6;
+
+/** Users fetched per database round trip; each user expands to one row per time bucket. */
+export const STATISTICS_CSV_PAGE_SIZE = 200;
+
+function formatStatisticsDate(value: string | Date): string {
+  const date = value instanceof Date ? value : new Date(value);
+  return Number.isNaN(date.getTime()) ? String(value) : date.toISOString();
+}
+
+function formatStatisticsCost(value: DatabaseStatRow["total_cost"]): string {
+  return (toDecimal(value) ?? new Decimal(0)).toFixed(STATISTICS_CSV_COST_DIGITS);
+}
+
+/** Render a single statistics row as a CSV data line (no trailing newline). */
+export function buildStatisticsCsvLine(row: DatabaseStatRow): string {
+  return [
+    formatStatisticsDate(row.date as string | Date),
+    String(row.user_id),
+    escapeCsvField(row.user_name),
+    String(Number(row.api_calls) || 0),
+    formatStatisticsCost(row.total_cost),
+  ].join(",");
+}
+
+/**
+ * Export the user statistics timeline as a CSV stream, emitting one line per pull.
+ *
+ * Only one page of users is held in memory at a time: the next page is queried
+ * from inside pull() once the buffered lines of the previous page are drained.
+ * Rows are ordered by user id, then by time bucket.
+ */
+export async function createUserStatisticsCsvStream(
+  timeRange: TimeRange,
+  timezoneOverride?: string,
+  pageSize = STATISTICS_CSV_PAGE_SIZE
+): Promise<ReadableStream<string>> {
+  let headerSent = false;
+  let buffered: string[] = [];
+  let cursor: number | null = null;
+  let exhausted = false;
+
+  return new ReadableStream<string>({
+    async pull(controller) {
+      if (!headerSent) {
+        headerSent = true;
+        controller.enqueue(`${STATISTICS_CSV_HEADER}\n`);
+        return;
+      }
+
+      while (buffered.length === 0 && !exhausted) {
+        const page = await getUserStatisticsPageFromDB(
+          timeRange,
+          { afterUserId: cursor, limit: pageSize },
+          timezoneOverride
+        );
+        buffered = page.rows.map((row) => `${buildStatisticsCsvLine(row)}\n`).reverse();
+        cursor = page.nextAfterUserId;
+        exhausted = cursor === null;
+      }
+
+      const line = buffered.pop();
+      if (line === undefined) {
+        controller.close();
+      } else {
+        controller.enqueue(line);
+      }
+    },
+  });
+}
@@ -76,6 +76,31 @@ export function parseUserAgent(ua: string | null | undefined): ClientInfo | null
   };
 }
 
+/**
+ * 判断是否为 Claude Code 客户端（claude-cli/x.y.z 派生的 CLI / VSCode 插件 / 未识别旧版本）
+ */
+export function isClaudeCodeClientType(clientType: string): boolean {
+  return (
+    clientType === "claude-cli" ||
+    clientType === "claude-vscode" ||
+    clientType === "claude-cli-unknown"
+  );
+}
+
+/**
+ * 从 User-Agent 中提取 Claude Code 客户端版本号
+ *
+ * @param ua - User-Agent 字符串
+ * @returns 版本号（如 "2.0.31"）；非 Claude Code 客户端或 UA 格式异常时返回 null
+ */
+export function parseClaudeCodeVersion(ua: string | null | undefined): string | null {
+  const clientInfo = parseUserAgent(ua);
+  if (!clientInfo || !isClaudeCodeClientType(clientInfo.clientType)) {
+    return null;
+  }
+  return clientInfo.version;
+}
+
 /**
  * 根据 User-Agent 中的标记，确定 Claude 客户端的具体类型
  *
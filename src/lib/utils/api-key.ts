@@ -0,0 +1,33 @@
+/**
+ * API Key Utilities
+ *
+ * Single source of truth for the format of user-issued API keys:
+ * `sk-` followed by 32 hex characters. The lookup prefix is what key search
+ * (searchKeys) compares against, so generation and lookup always agree on
+ * how many leading characters identify a key.
+ */
+
+import { randomBytes } from "node:crypto";
+
+export const API_KEY_PREFIX = "sk-";
+
+/** Number of leading characters used to identify a key in searches and logs */
+export const API_KEY_LOOKUP_PREFIX_LENGTH = 12;
+
+const API_KEY_RANDOM_BYTES = 16;
+
+/**
+ * Generate a new API key.
+ *
+ * @param prefix - Key prefix (defaults to `sk-`)
+ */
+export function generateApiKey(prefix: string = API_KEY_PREFIX): string {
+  return `${prefix}${randomBytes(API_KEY_RANDOM_BYTES).toString("hex")}`;
+}
+
+/**
+ * Extract the lookup prefix of a key.
+ */
+export function getApiKeyPrefix(key: string): string {
+  return key.slice(0, API_KEY_LOOKUP_PREFIX_LENGTH);
+}
@@ -1,4 +1,5 @@
 import { CONTEXT_1M_TOKEN_THRESHOLD } from "@/lib/special-attributes";
+import type { MessageRequest } from "@/types/message";
 import type { ModelPriceData } from "@/types/model-price";
 import { COST_SCALE, Decimal, toDecimal } from "./currency";
 
@@ -15,8 +16,18 @@ type UsageMetrics = {
   // 图片 modality tokens（从 candidatesTokensDetails/promptTokensDetails 提取）
   input_image_tokens?: number;
   output_image_tokens?: number;
+  // 服务端搜索次数（Claude server_tool_use.web_search_requests）
+  web_search_requests?: number;
+  // 搜索上下文档位（未提供时按 medium 计价）
+  search_context_size?: SearchContextSize;
+  // 生成图片张数（OpenAI Images API 响应 data[] 长度）
+  output_images?: number;
 };
 
+export type SearchContextSize = "low" | "medium" | "high";
+
+const DEFAULT_SEARCH_CONTEXT_SIZE: SearchContextSize = "medium";
+
 export interface ResolvedLongContextPricing {
   thresholdTokens: number;
   scope: "request" | "session";
@@ -111,6 +122,87 @@ function multiplyCost(quantity: number | undefined, unitCost: number | undefined
   return qtyDecimal.mul(costDecimal);
 }
 
+/**
+ * 计算搜索上下文费用：按档位取 search_context_cost_per_query 单价 × 查询次数
+ *
+ * 档位未知、单价未配置或查询次数非正时返回 0。
+ */
+export function calculateSearchContextCost(
+  priceData: ModelPriceData,
+  contextSize: string | null | undefined,
+  queries: number | null | undefined
+): Decimal {
+  if (queries == null || !Number.isFinite(queries) || queries <= 0) {
+    return new Decimal(0);
+  }
+
+  const searchCosts = priceData.search_context_cost_per_query;
+  let costPerQuery: number | undefined;
+  switch (contextSize) {
+    case "high":
+      costPerQuery = searchCosts?.search_context_size_high;
+      break;
+    case "medium":
+      costPerQuery = searchCosts?.search_context_size_medium;
+      break;
+    case "low":
+      costPerQuery = searchCosts?.search_context_size_low;
+      break;
+    default:
+      costPerQuery = undefined;
+  }
+
+  if (!isFiniteNonNegativeNumber(costPerQuery)) {
+    return new Decimal(0);
+  }
+
+  return multiplyCost(queries, costPerQuery);
+}
+
+/**
+ * 计算图片生成费用：output_cost_per_image × 生成图片张数
+ *
+ * 单价未配置或张数非正时返回 0。
+ */
+export function calculateImageGenerationCost(
+  priceData: ModelPriceData,
+  imageCount: number | null | undefined
+): Decimal {
+  if (imageCount == null || !Number.isFinite(imageCount) || imageCount <= 0) {
+    return new Decimal(0);
+  }
+
+  if (!isFiniteNonNegativeNumber(priceData.output_cost_per_image)) {
+    return new Decimal(0);
+  }
+
+  return multiplyCost(imageCount, priceData.output_cost_per_image);
+}
+
+/**
+ * 图片生成模型（mode=image_generation）配置了按张单价且上游返回了图片张数时，
+ * 输出侧改为按张计费，输出 token 不再计价，避免重复计费。
+ */
+function resolveImageGenerationUsage(
+  usage: UsageMetrics,
+  priceData: ModelPriceData
+): { usage: UsageMetrics; imageCost: Decimal } {
+  const perImagePricing =
+    priceData.mode === "image_generation" &&
+    usage.output_images != null &&
+    usage.output_images > 0 &&
+    isFiniteNonNegativeNumber(priceData.output_cost_per_image);
+
+  if (!perImagePricing) {
+    return { usage, imageCost: new Decimal(0) };
+  }
+
+  return {
+    usage: { ...usage, output_tokens: 0, output_image_tokens: 0 },
+    imageCost: calculateImageGenerationCost(priceData, usage.output_images),
+  };
+}
+
 function resolveLongContextThreshold(priceData: ModelPriceData): number {
   const has272kFields =
     typeof priceData.input_cost_per_token_above_272k_tokens === "number" ||
@@ -370,11 +462,12 @@ export interface CostBreakdown {
  * Returns per-category costs as plain numbers.
  */
 export function calculateRequestCostBreakdown(
-  usage: UsageMetrics,
+  rawUsage: UsageMetrics,
   priceData: ModelPriceData,
   context1mAppliedOrOptions: boolean | RequestCostBreakdownOptions = false,
   priorityServiceTierApplied: boolean = false
 ): CostBreakdown {
+  const { usage, imageCost } = resolveImageGenerationUsage(rawUsage, priceData);
   const options = normalizeRequestCostBreakdownOptions(
     context1mAppliedOrOptions,
     priorityServiceTierApplied
@@ -618,6 +711,18 @@ export function calculateRequestCostBreakdown(
     inputBucket = inputBucket.add(multiplyCost(usage.input_image_tokens, imageCostPerToken));
   }
 
+  // Generated images -> output bucket（按张计费）
+  outputBucket = outputBucket.add(imageCost);
+
+  // Search queries -> input bucket（与按次调用费用同桶）
+  inputBucket = inputBucket.add(
+    calculateSearchContextCost(
+      priceData,
+      usage.search_context_size ?? DEFAULT_SEARCH_CONTEXT_SIZE,
+      usage.web_search_requests
+    )
+  );
+
   const cacheCreationBucket = cacheCreation5mBucket.add(cacheCreation1hBucket);
   const total = inputBucket.add(outputBucket).add(cacheCreationBucket).add(cacheReadBucket);
 
@@ -641,12 +746,13 @@ export function calculateRequestCostBreakdown(
  * @returns 费用（美元），保留 15 位小数
  */
 export function calculateRequestCost(
-  usage: UsageMetrics,
+  rawUsage: UsageMetrics,
   priceData: ModelPriceData,
   multiplierOrOptions: number | RequestCostCalculationOptions = 1.0,
   context1mApplied: boolean = false,
   priorityServiceTierApplied: boolean = false
 ): Decimal {
+  const { usage, imageCost } = resolveImageGenerationUsage(rawUsage, priceData);
   const options = normalizeRequestCostOptions(
     multiplierOrOptions,
     context1mApplied,
@@ -870,6 +976,18 @@ export function calculateRequestCost(
     segments.push(multiplyCost(usage.input_image_tokens, imageCostPerToken));
   }
 
+  // 图片生成按张费用（mode=image_generation，output_cost_per_image × 张数）
+  segments.push(imageCost);
+
+  // 服务端搜索费用（search_context_cost_per_query，按档位 × 查询次数）
+  segments.push(
+    calculateSearchContextCost(
+      priceData,
+      usage.search_context_size ?? DEFAULT_SEARCH_CONTEXT_SIZE,
+      usage.web_search_requests
+    )
+  );
+
   const total = segments.reduce((acc, segment) => acc.plus(segment), new Decimal(0));
 
   // Apply provider and group multipliers
@@ -877,3 +995,46 @@ export function calculateRequestCost(
   const groupMultiplierDecimal = new Decimal(options.groupMultiplier);
   return total.mul(multiplierDecimal).mul(groupMultiplierDecimal).toDecimalPlaces(COST_SCALE);
 }
+
+type MessageRequestCostFields = Pick<
+  MessageRequest,
+  | "inputTokens"
+  | "outputTokens"
+  | "cacheCreationInputTokens"
+  | "cacheCreation5mInputTokens"
+  | "cacheCreation1hInputTokens"
+  | "cacheReadInputTokens"
+  | "cacheTtlApplied"
+  | "costMultiplier"
+  | "context1mApplied"
+>;
+
+/**
+ * 基于已落库的请求记录重新计算费用（用于费用重算等离线任务）
+ *
+ * 将请求的 token 字段映射为 UsageMetrics：5m/1h 缓存创建分档计价，
+ * 未拆分的缓存创建 token 按 cacheTtlApplied 归档；超过长上下文阈值时自动应用长上下文价格。
+ * 供应商倍率取自请求记录本身的 costMultiplier，分组倍率等需由调用方传入。
+ */
+export function calculateRequestCostFromMessageRequest(
+  request: MessageRequestCostFields,
+  priceData: ModelPriceData,
+  options: Omit<RequestCostCalculationOptions, "multiplier" | "longContextPricing"> = {}
+): Decimal {
+  const usage: UsageMetrics = {
+    input_tokens: request.inputTokens,
+    output_tokens: request.outputTokens,
+    cache_creation_input_tokens: request.cacheCreationInputTokens,
+    cache_creation_5m_input_tokens: request.cacheCreation5mInputTokens,
+    cache_creation_1h_input_tokens: request.cacheCreation1hInputTokens,
+    cache_read_input_tokens: request.cacheReadInputTokens,
+    cache_ttl: request.cacheTtlApplied ?? undefined,
+  };
+
+  return calculateRequestCost(usage, priceData, {
+    ...options,
+    multiplier: request.costMultiplier,
+    context1mApplied: options.context1mApplied ?? request.context1mApplied ?? false,
+    longContextPricing: matchLongContextPricing(usage, priceData)?.pricing ?? null,
+  });
+}
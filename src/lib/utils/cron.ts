@@ -0,0 +1,37 @@
+/**
+ * Cron Expression Utilities
+ *
+ * Thin wrapper around cron-parser, the same parser Bull uses to run repeatable jobs,
+ * so a schedule that passes validation here is exactly a schedule the queue accepts.
+ */
+
+import { parseExpression } from "cron-parser";
+
+/**
+ * Check whether a string is a cron expression the scheduler can run.
+ */
+export function isValidCronExpression(expression: string): boolean {
+  if (!expression || typeof expression !== "string" || !expression.trim()) {
+    return false;
+  }
+
+  try {
+    parseExpression(expression.trim());
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+/**
+ * Compute the first run strictly after `from`.
+ *
+ * @param expression - cron expression (5 fields, or 6 with leading seconds)
+ * @param from - Reference instant (exclusive)
+ * @param timezone - IANA timezone identifier (e.g., "Asia/Shanghai")
+ * @throws Error when the expression is invalid or never fires
+ */
+export function getNextCronRun(expression: string, from: Date, timezone: string = "UTC"): Date {
+  const interval = parseExpression(expression.trim(), { currentDate: from, tz: timezone });
+  return interval.next().toDate();
+}
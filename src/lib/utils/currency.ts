@@ -86,6 +86,29 @@ export function sumCosts(values: DecimalInput[]): Decimal {
   }, new Decimal(0));
 }
 
+/**
+ * 将 USD 金额按展示汇率换算为目标货币（仅用于展示，存储值始终为 USD）
+ * - USD 固定按 1 换算，忽略传入汇率
+ * - 汇率缺失或非正数时视为 1，避免展示为 0 或负数
+ */
+export function convertCurrency(
+  amountUsd: DecimalInput,
+  to: CurrencyCode = "USD",
+  rate?: DecimalInput
+): Decimal {
+  const amount = toDecimal(amountUsd) ?? new Decimal(0);
+  if (to === "USD") {
+    return amount;
+  }
+
+  const decimalRate = toDecimal(rate);
+  if (!decimalRate || decimalRate.lte(0)) {
+    return amount;
+  }
+
+  return amount.times(decimalRate);
+}
+
 /**
  * 格式化货币显示（浏览器环境 - 推荐使用 useFormatCurrency）
  * @param value - 金额数值
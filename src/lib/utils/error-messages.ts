@@ -147,7 +147,7 @@ export type ErrorParams = {
 
   // General parameters
   INVALID_RANGE: { field?: string; min: number; max: number };
-  DUPLICATE_NAME: { name: string };
+  DUPLICATE_NAME: { name: string; existingId?: number };
 
   // Default (no parameters)
   [key: string]: Record<string, string | number> | undefined;
@@ -11,6 +11,7 @@ export type { CurrencyCode } from "./currency";
 export {
   COST_SCALE,
   CURRENCY_CONFIG,
+  convertCurrency,
   costToNumber,
   Decimal,
   formatCostForStorage,
@@ -0,0 +1,36 @@
+import type { ModelPriceData } from "@/types/model-price";
+
+/**
+ * 模型能力 -> 价格数据中对应的 supports_* 字段
+ * 云端价格表同步（capabilities）与按能力筛选模型共用同一份映射
+ */
+export const MODEL_CAPABILITY_FIELDS = {
+  assistant_prefill: ["supports_assistant_prefill"],
+  computer_use: ["supports_computer_use"],
+  function_calling: ["supports_function_calling", "supports_tool_choice"],
+  pdf_input: ["supports_pdf_input"],
+  prompt_caching: ["supports_prompt_caching"],
+  reasoning: ["supports_reasoning"],
+  structured_output: ["supports_response_schema"],
+  vision: ["supports_vision"],
+  audio_input: ["supports_audio_input"],
+  audio_output: ["supports_audio_output"],
+  video_input: ["supports_video_input"],
+  web_search: ["supports_web_search"],
+} as const satisfies Record<string, readonly string[]>;
+
+export type ModelCapability = keyof typeof MODEL_CAPABILITY_FIELDS;
+
+export function isModelCapability(value: unknown): value is ModelCapability {
+  return typeof value === "string" && Object.hasOwn(MODEL_CAPABILITY_FIELDS, value);
+}
+
+/**
+ * 判断价格数据是否声明支持指定能力（任一对应 supports_* 字段为 true 即视为支持）
+ */
+export function modelSupportsCapability(
+  priceData: ModelPriceData,
+  capability: ModelCapability
+): boolean {
+  return MODEL_CAPABILITY_FIELDS[capability].some((field) => priceData[field] === true);
+}
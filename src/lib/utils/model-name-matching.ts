@@ -54,3 +54,53 @@ export function buildModelNameFallbackCandidates(modelName: string): string[] {
 
   return candidates;
 }
+
+/** 末尾日期版本号:"-20241022" 或 "-2024-10-22"(分隔符已统一为 "-") */
+const DATE_SUFFIX_RE = /-(\d{8}|\d{4}-\d{2}-\d{2})$/;
+
+/**
+ * 模型名归一化(纯函数,用于模糊价格匹配)。
+ * - 转小写
+ * - 空白 / "." / "_" / "@" 统一为 "-",并合并连续分隔符
+ * - 剥除末尾日期版本号
+ *
+ * 例:"claude-3-5-sonnet-20241022" 与 "Claude-3.5-Sonnet" 均归一为 "claude-3-5-sonnet"。
+ */
+export function normalizeModelName(modelName: string): string {
+  return modelName
+    .trim()
+    .toLowerCase()
+    .replace(/[\s._@]+/g, "-")
+    .replace(/-{2,}/g, "-")
+    .replace(DATE_SUFFIX_RE, "")
+    .replace(/^-+|-+$/g, "");
+}
+
+/**
+ * 从候选价格模型名中选出与调用名最匹配的一个(纯函数,候选顺序即优先级)。
+ * 1. 归一化后完全相等
+ * 2. 归一化后候选名是调用名在 "-" 边界上的前缀,取最长者
+ */
+export function pickFuzzyModelNameMatch(
+  modelName: string,
+  candidateNames: readonly string[]
+): string | null {
+  const target = normalizeModelName(modelName);
+  if (!target) return null;
+
+  let prefixMatch: { name: string; length: number } | null = null;
+  for (const name of candidateNames) {
+    const normalized = normalizeModelName(name);
+    if (!normalized) continue;
+    if (normalized === target) return name;
+
+    if (
+      target.startsWith(`${normalized}-`) &&
+      (!prefixMatch || normalized.length > prefixMatch.length)
+    ) {
+      prefixMatch = { name, length: normalized.length };
+    }
+  }
+
+  return prefixMatch?.name ?? null;
+}
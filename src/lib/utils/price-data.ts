@@ -1,4 +1,4 @@
-import type { ModelPriceData } from "@/types/model-price";
+import type { ModelPriceData, ModelPriceFieldChange } from "@/types/model-price";
 import { collectAdditionalPriceLikeNumbers } from "./model-price-fields";
 
 function hasValidNumericPrice(values: unknown[]): boolean {
@@ -84,3 +84,51 @@ export function hasValidPriceData(priceData: ModelPriceData): boolean {
 
   return false;
 }
+
+function isPlainObject(value: unknown): value is Record<string, unknown> {
+  return !!value && typeof value === "object" && !Array.isArray(value);
+}
+
+function isSameValue(a: unknown, b: unknown): boolean {
+  if (Object.is(a, b)) return true;
+  if (Array.isArray(a) && Array.isArray(b)) {
+    return JSON.stringify(a) === JSON.stringify(b);
+  }
+  return false;
+}
+
+function collectPriceDataChanges(
+  before: Record<string, unknown>,
+  after: Record<string, unknown>,
+  prefix: string,
+  changes: ModelPriceFieldChange[]
+): void {
+  const keys = new Set([...Object.keys(before), ...Object.keys(after)]);
+  for (const key of keys) {
+    const field = prefix ? `${prefix}.${key}` : key;
+    const beforeValue = before[key];
+    const afterValue = after[key];
+
+    if (isPlainObject(beforeValue) && isPlainObject(afterValue)) {
+      collectPriceDataChanges(beforeValue, afterValue, field, changes);
+      continue;
+    }
+
+    if (!isSameValue(beforeValue, afterValue)) {
+      changes.push({ field, before: beforeValue, after: afterValue });
+    }
+  }
+}
+
+/**
+ * 比较两个价格版本，返回发生变化的字段列表（按字段路径排序）。
+ * 嵌套对象（long_context_pricing、pricing 等）逐字段展开比较，数组按序列化结果整体比较。
+ */
+export function diffPriceData(
+  before: ModelPriceData | null | undefined,
+  after: ModelPriceData | null | undefined
+): ModelPriceFieldChange[] {
+  const changes: ModelPriceFieldChange[] = [];
+  collectPriceDataChanges(before ?? {}, after ?? {}, "", changes);
+  return changes.sort((a, b) => a.field.localeCompare(b.field));
+}
@@ -3,6 +3,7 @@ import {
   normalizeProviderGroup,
   normalizeProviderGroupTag,
   parseProviderGroups,
+  replaceProviderGroupInList,
   resolveProviderGroupsWithDefault,
 } from "./provider-group";
 
@@ -33,4 +34,29 @@ describe("provider-group utils", () => {
     expect(parseProviderGroups(null)).toEqual([]);
     expect(parseProviderGroups("   ")).toEqual([]);
   });
+
+  test("replaceProviderGroupInList 只替换完整匹配的分组并保留其他分组顺序", () => {
+    expect(replaceProviderGroupInList("a,legacy,b", "legacy", "new")).toBe("a,new,b");
+    expect(replaceProviderGroupInList("legacy", "legacy", "new")).toBe("new");
+    expect(replaceProviderGroupInList("legacy-2,legacy2", "legacy", "new")).toBe(
+      "legacy-2,legacy2"
+    );
+  });
+
+  test("replaceProviderGroupInList 目标分组已存在时去重", () => {
+    expect(replaceProviderGroupInList("new,legacy,b", "legacy", "new")).toBe("new,b");
+    expect(replaceProviderGroupInList("a,legacy,legacy", "legacy", "new")).toBe("a,new");
+  });
+
+  test("replaceProviderGroupInList 在 to 为空时移除分组，列表为空返回 null", () => {
+    expect(replaceProviderGroupInList("a,legacy,b", "legacy", "")).toBe("a,b");
+    expect(replaceProviderGroupInList("legacy", "legacy", "  ")).toBeNull();
+    expect(replaceProviderGroupInList(null, "legacy", "new")).toBeNull();
+  });
+
+  test("replaceProviderGroupInList 支持中文逗号、换行与空白", () => {
+    expect(replaceProviderGroupInList(" 研发 ，legacy\n直营 ", " legacy ", "渠道")).toBe(
+      "研发,渠道,直营"
+    );
+  });
 });
@@ -59,3 +59,25 @@ export function resolveProviderGroupsWithDefault(value: unknown): string[] {
 
   return groups;
 }
+
+/**
+ * 在分组列表字符串中将 from 替换为 to，保留其他分组及原有顺序。
+ * - to 为空时直接移除 from
+ * - to 已存在时去重，只保留首次出现的位置
+ * - 结果为空时返回 null，由调用方决定回退值（users/keys 回退 default，providers 置空）
+ */
+export function replaceProviderGroupInList(
+  value: unknown,
+  from: string,
+  to: string
+): string | null {
+  const source = from.trim();
+  const target = to.trim();
+  const groups = splitProviderGroupValue(value).flatMap((group) => {
+    if (group !== source) return [group];
+    return target ? [target] : [];
+  });
+  if (groups.length === 0) return null;
+
+  return Array.from(new Set(groups)).join(",");
+}
@@ -0,0 +1,24 @@
+import { describe, expect, it } from "vitest";
+import { isValidHHMM, parseHHMM } from "./time-of-day";
+
+describe("parseHHMM", () => {
+  it("parses boundary values", () => {
+    expect(parseHHMM("00:00")).toEqual({ hour: 0, minute: 0 });
+    expect(parseHHMM("09:05")).toEqual({ hour: 9, minute: 5 });
+    expect(parseHHMM("23:59")).toEqual({ hour: 23, minute: 59 });
+  });
+
+  it("returns null for malformed values", () => {
+    for (const value of ["", "9:00", "24:00", "12:60", "12-30", "12:3", " 12:30", "12:30:00"]) {
+      expect(parseHHMM(value)).toBeNull();
+    }
+  });
+});
+
+describe("isValidHHMM", () => {
+  it("accepts 00:00-23:59 only", () => {
+    expect(isValidHHMM("18:30")).toBe(true);
+    expect(isValidHHMM("25:00")).toBe(false);
+    expect(isValidHHMM("ab:cd")).toBe(false);
+  });
+});
@@ -0,0 +1,33 @@
+/**
+ * HH:mm 时间字符串工具
+ *
+ * 用于每日重置时间（用户/密钥/供应商）与每日排行榜发送时间等字段，
+ * 仅接受两位小时与两位分钟（00:00 - 23:59）。
+ */
+
+export const HHMM_PATTERN = /^([01]\d|2[0-3]):[0-5]\d$/;
+
+export interface TimeOfDay {
+  hour: number;
+  minute: number;
+}
+
+/**
+ * 判断字符串是否为合法的 HH:mm 时间
+ */
+export function isValidHHMM(value: string): boolean {
+  return HHMM_PATTERN.test(value);
+}
+
+/**
+ * 解析 HH:mm 时间字符串
+ *
+ * @returns 小时与分钟；格式非法时返回 null
+ */
+export function parseHHMM(value: string): TimeOfDay | null {
+  const matches = HHMM_PATTERN.exec(value);
+  if (!matches) {
+    return null;
+  }
+  return { hour: Number(matches[1]), minute: Number(value.slice(3)) };
+}
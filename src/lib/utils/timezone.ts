@@ -168,3 +168,19 @@ export async function resolveSystemTimezone(): Promise<string> {
   // Step 3: Ultimate fallback
   return "UTC";
 }
+
+/**
+ * Resolves the timezone used for a user's statistics.
+ *
+ * A valid per-user timezone wins; null, empty or invalid values fall back to
+ * resolveSystemTimezone().
+ *
+ * @param userTimezone - The user's stored timezone (users.timezone)
+ * @returns Resolved IANA timezone identifier (always valid)
+ */
+export async function resolveUserTimezone(userTimezone?: string | null): Promise<string> {
+  if (userTimezone && isValidIANATimezone(userTimezone)) {
+    return userTimezone;
+  }
+  return resolveSystemTimezone();
+}
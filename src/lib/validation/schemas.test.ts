@@ -25,15 +25,17 @@ describe("Provider schemas - priority/weight/costMultiplier 规则对齐", () =>
       expect(UpdateProviderSchema.safeParse({ weight: -1 }).success).toBe(false);
     });
 
-    test("costMultiplier 接受 0 和正数（含小数），使用 coerce 支持字符串转换", () => {
-      expect(UpdateProviderSchema.safeParse({ cost_multiplier: 0 }).success).toBe(true);
+    test("costMultiplier 接受 (0, 100] 内的正数（含小数），使用 coerce 支持字符串转换", () => {
       expect(UpdateProviderSchema.safeParse({ cost_multiplier: 0.5 }).success).toBe(true);
+      expect(UpdateProviderSchema.safeParse({ cost_multiplier: 100 }).success).toBe(true);
       expect(UpdateProviderSchema.safeParse({ cost_multiplier: 1.5 }).success).toBe(true);
       // coerce 会将字符串转为数字
       expect(UpdateProviderSchema.safeParse({ cost_multiplier: "0.5" }).success).toBe(true);
       expect(UpdateProviderSchema.safeParse({ cost_multiplier: "1.5" }).success).toBe(true);
-      // 负数被拒绝
+      // 0、负数和超过上限的值被拒绝
+      expect(UpdateProviderSchema.safeParse({ cost_multiplier: 0 }).success).toBe(false);
       expect(UpdateProviderSchema.safeParse({ cost_multiplier: -1 }).success).toBe(false);
+      expect(UpdateProviderSchema.safeParse({ cost_multiplier: 100.01 }).success).toBe(false);
     });
 
     test("非法值被拒绝", () => {
@@ -48,7 +50,8 @@ describe("Provider schemas - priority/weight/costMultiplier 规则对齐", () =>
 
       // cost_multiplier: 非数字字符串被拒绝
       expect(UpdateProviderSchema.safeParse({ cost_multiplier: "abc" }).success).toBe(false);
-      // 注意: null 会被 coerce 转为 0 (Number(null) === 0)，所以会通过
+      // 注意: null 会被 coerce 转为 0 (Number(null) === 0)，因此同样被拒绝
+      expect(UpdateProviderSchema.safeParse({ cost_multiplier: null }).success).toBe(false);
     });
   });
 
@@ -74,9 +77,9 @@ describe("Provider schemas - priority/weight/costMultiplier 规则对齐", () =>
       expect(CreateProviderSchema.safeParse({ ...base, weight: -1 }).success).toBe(false);
     });
 
-    test("costMultiplier 接受 0 和正数（含小数），使用 coerce 支持字符串转换", () => {
-      expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: 0 }).success).toBe(true);
+    test("costMultiplier 接受 (0, 100] 内的正数（含小数），使用 coerce 支持字符串转换", () => {
       expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: 0.5 }).success).toBe(true);
+      expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: 100 }).success).toBe(true);
       expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: 1.5 }).success).toBe(true);
       // coerce 会将字符串转为数字
       expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: "0.5" }).success).toBe(
@@ -85,8 +88,12 @@ describe("Provider schemas - priority/weight/costMultiplier 规则对齐", () =>
       expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: "1.5" }).success).toBe(
         true
       );
-      // 负数被拒绝
+      // 0、负数和超过上限的值被拒绝
+      expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: 0 }).success).toBe(false);
       expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: -1 }).success).toBe(false);
+      expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: 101 }).success).toBe(
+        false
+      );
     });
 
     test("非法值被拒绝", () => {
@@ -103,7 +110,10 @@ describe("Provider schemas - priority/weight/costMultiplier 规则对齐", () =>
       expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: "abc" }).success).toBe(
         false
       );
-      // 注意: null 会被 coerce 转为 0 (Number(null) === 0)，所以会通过
+      // 注意: null 会被 coerce 转为 0 (Number(null) === 0)，因此同样被拒绝
+      expect(CreateProviderSchema.safeParse({ ...base, cost_multiplier: null }).success).toBe(
+        false
+      );
     });
 
     test("allowed_clients/blocked_clients 支持 null 并归一化为空数组", () => {
@@ -14,9 +14,19 @@ import {
   MAX_PUBLIC_STATUS_RANGE_HOURS,
   PUBLIC_STATUS_INTERVAL_OPTIONS,
 } from "@/lib/public-status/constants";
+import { isValidCronExpression } from "@/lib/utils/cron";
 import { CURRENCY_CONFIG } from "@/lib/utils/currency";
+import { HHMM_PATTERN } from "@/lib/utils/time-of-day";
 import { isValidIANATimezone } from "@/lib/utils/timezone";
 
+/**
+ * 供应商成本倍率：必须大于 0 且不超过上限（支持字符串转换）
+ */
+export const ProviderCostMultiplierSchema = z.coerce
+  .number()
+  .gt(0, "成本倍率必须大于 0")
+  .max(PROVIDER_LIMITS.COST_MULTIPLIER.MAX, "成本倍率不能超过 100");
+
 const CACHE_TTL_PREFERENCE = z.enum(["inherit", "5m", "1h"]);
 const CONTEXT_1M_PREFERENCE = z.enum(["inherit", "force_enable", "disabled"]);
 
@@ -226,7 +236,7 @@ export const CreateUserSchema = z.object({
   dailyResetMode: z.enum(["fixed", "rolling"]).optional().default("fixed"),
   dailyResetTime: z
     .string()
-    .regex(/^([01]\d|2[0-3]):[0-5]\d$/, "重置时间格式必须为 HH:mm")
+    .regex(HHMM_PATTERN, "重置时间格式必须为 HH:mm")
     .optional()
     .default("00:00"),
   // Allowed clients (CLI/IDE restrictions)
@@ -350,7 +360,7 @@ export const UpdateUserSchema = z.object({
   dailyResetMode: z.enum(["fixed", "rolling"]).optional(),
   dailyResetTime: z
     .string()
-    .regex(/^([01]\d|2[0-3]):[0-5]\d$/, "重置时间格式必须为 HH:mm")
+    .regex(HHMM_PATTERN, "重置时间格式必须为 HH:mm")
     .optional(),
   // Allowed clients (CLI/IDE restrictions)
   allowedClients: OPTIONAL_CLIENT_PATTERN_ARRAY_SCHEMA,
@@ -361,6 +371,14 @@ export const UpdateUserSchema = z.object({
     .array(z.string().max(64, "模型名称长度不能超过64个字符"))
     .max(50, "模型数量不能超过50个")
     .optional(),
+  // 用户统计时区（null 表示跟随系统时区）
+  timezone: z
+    .string()
+    .refine((val) => isValidIANATimezone(val), {
+      message: "无效的时区标识符，请使用 IANA 时区格式（如 Asia/Shanghai）",
+    })
+    .nullable()
+    .optional(),
 });
 
 /**
@@ -392,7 +410,7 @@ export const KeyFormSchema = z.object({
   dailyResetMode: z.enum(["fixed", "rolling"]).optional().default("fixed"),
   dailyResetTime: z
     .string()
-    .regex(/^([01]?[0-9]|2[0-3]):[0-5][0-9]$/, "重置时间格式必须为 HH:mm")
+    .regex(HHMM_PATTERN, "重置时间格式必须为 HH:mm")
     .optional()
     .default("00:00"),
   limitWeeklyUsd: z.coerce
@@ -482,7 +500,7 @@ export const CreateProviderSchema = z
       .nullable()
       .optional()
       .default(null),
-    cost_multiplier: z.coerce.number().min(0, "成本倍率不能为负数").optional().default(1.0),
+    cost_multiplier: ProviderCostMultiplierSchema.optional().default(1.0),
     group_tag: z.string().max(255, "分组标签不能超过255个字符").nullable().optional(),
     // Codex 支持:供应商类型和模型重定向
     provider_type: z
@@ -531,7 +549,7 @@ export const CreateProviderSchema = z
     daily_reset_mode: z.enum(["fixed", "rolling"]).optional().default("fixed"),
     daily_reset_time: z
       .string()
-      .regex(/^([01]?[0-9]|2[0-3]):[0-5][0-9]$/, "重置时间格式必须为 HH:mm")
+      .regex(HHMM_PATTERN, "重置时间格式必须为 HH:mm")
       .optional()
       .default("00:00"),
     limit_weekly_usd: z.coerce
@@ -731,7 +749,7 @@ export const UpdateProviderSchema = z
       .record(z.string(), z.number().int().min(0).max(2147483647))
       .nullable()
       .optional(),
-    cost_multiplier: z.coerce.number().min(0, "成本倍率不能为负数").optional(),
+    cost_multiplier: ProviderCostMultiplierSchema.optional(),
     group_tag: z.string().max(255, "分组标签不能超过255个字符").nullable().optional(),
     // Codex 支持:供应商类型和模型重定向
     provider_type: z
@@ -778,7 +796,7 @@ export const UpdateProviderSchema = z
     daily_reset_mode: z.enum(["fixed", "rolling"]).optional(),
     daily_reset_time: z
       .string()
-      .regex(/^([01]?[0-9]|2[0-3]):[0-5][0-9]$/, "重置时间格式必须为 HH:mm")
+      .regex(HHMM_PATTERN, "重置时间格式必须为 HH:mm")
       .optional(),
     limit_weekly_usd: z.coerce
       .number()
@@ -958,6 +976,12 @@ export const UpdateSystemSettingsSchema = z.object({
       { message: "不支持的货币类型" }
     )
     .optional(),
+  // USD → 展示货币汇率（可选，仅影响展示）
+  currencyDisplayRate: z.coerce
+    .number()
+    .positive("展示汇率必须大于0")
+    .max(100000, "展示汇率不能超过100000")
+    .optional(),
   // 计费模型来源配置（可选）
   billingModelSource: z
     .enum(["original", "redirected"], { message: "不支持的计费模型来源" })
@@ -982,7 +1006,13 @@ export const UpdateSystemSettingsSchema = z.object({
     .min(1, "保留天数不能少于1天")
     .max(365, "保留天数不能超过365天")
     .optional(),
-  cleanupSchedule: z.string().min(1, "执行时间不能为空").optional(),
+  cleanupSchedule: z
+    .string()
+    .min(1, "执行时间不能为空")
+    .refine((val) => isValidCronExpression(val), {
+      message: "无效的 Cron 表达式，请使用 5 段或带秒的 6 段格式（如 0 2 * * *）",
+    })
+    .optional(),
   cleanupBatchSize: z.coerce
     .number()
     .int("批量大小必须是整数")
@@ -991,6 +1021,14 @@ export const UpdateSystemSettingsSchema = z.object({
     .optional(),
   // 客户端版本检查配置（可选）
   enableClientVersionCheck: z.boolean().optional(),
+  // Claude 客户端最低版本（可选，null 表示仅按 GA 版本检测）
+  minClientVersion: z
+    .string()
+    .trim()
+    .max(32, "最低客户端版本不能超过32个字符")
+    .regex(/^v?\d+\.\d+\.\d+(?:-[0-9A-Za-z.-]+)?$/, "最低客户端版本格式无效，应为 x.y.z")
+    .nullable()
+    .optional(),
   // 供应商不可用时是否返回详细错误信息（可选）
   verboseProviderError: z.boolean().optional(),
   // 标准代理错误响应是否透传安全脱敏后的上游错误 message（可选）
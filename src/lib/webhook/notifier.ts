@@ -181,6 +181,12 @@ export class WebhookNotifier {
 
     if (!response.ok) {
       const errorBody = await response.text().catch(() => "");
+      // Telegram 的 4xx 响应体为 { ok: false, description }，直接展示描述便于排查（如 chat not found）
+      const telegramDescription =
+        this.providerType === "telegram" ? parseTelegramErrorDescription(errorBody) : null;
+      if (telegramDescription) {
+        throw new Error(`Telegram API Error (HTTP ${response.status}): ${telegramDescription}`);
+      }
       throw new Error(
         `HTTP ${response.status}: ${response.statusText}${errorBody ? ` - ${errorBody}` : ""}`
       );
@@ -228,6 +234,15 @@ export class WebhookNotifier {
   }
 }
 
+function parseTelegramErrorDescription(body: string): string | null {
+  try {
+    const parsed = JSON.parse(body) as { description?: unknown };
+    return typeof parsed.description === "string" && parsed.description ? parsed.description : null;
+  } catch {
+    return null;
+  }
+}
+
 /**
  * 便捷函数：发送结构化消息到 webhook
  */
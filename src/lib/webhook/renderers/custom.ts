@@ -2,6 +2,9 @@ import { buildTemplateVariables } from "../templates/placeholders";
 import type { StructuredMessage, WebhookPayload, WebhookSendOptions } from "../types";
 import type { Renderer } from "./index";
 
+/** 匹配 {{name}} 形式的占位符（允许两侧空白） */
+const PLACEHOLDER_PATTERN = /\{\{\s*([\w.]+)\s*\}\}/g;
+
 export class CustomRenderer implements Renderer {
   constructor(
     private readonly template: Record<string, unknown>,
@@ -25,7 +28,7 @@ export class CustomRenderer implements Renderer {
 
     return {
       body: JSON.stringify(bodyObject),
-      ...(this.headers ? { headers: this.headers } : {}),
+      ...(this.headers ? { headers: this.renderHeaders(this.headers, variables) } : {}),
     };
   }
 
@@ -50,11 +53,25 @@ export class CustomRenderer implements Renderer {
     return value;
   }
 
-  private interpolateString(template: string, variables: Record<string, string>): string {
-    let result = template;
-    for (const [key, value] of Object.entries(variables)) {
-      result = result.replaceAll(key, value);
+  private renderHeaders(
+    headers: Record<string, string>,
+    variables: Record<string, string>
+  ): Record<string, string> {
+    const result: Record<string, string> = {};
+    for (const [key, value] of Object.entries(headers)) {
+      result[key] = this.interpolateString(value, variables);
     }
     return result;
   }
+
+  /**
+   * 单次扫描替换占位符：未知占位符渲染为空字符串，
+   * 且替换后的值不会再次被解析（避免错误信息中的 {{...}} 被误替换）
+   */
+  private interpolateString(template: string, variables: Record<string, string>): string {
+    return template.replace(
+      PLACEHOLDER_PATTERN,
+      (_match, name: string) => variables[`{{${name}}}`] ?? ""
+    );
+  }
 }
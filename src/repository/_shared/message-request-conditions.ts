@@ -7,3 +7,9 @@ import { messageRequest } from "@/drizzle/schema";
  * 统一的过滤条件：排除 blocked_by='warmup' 的记录。
  */
 export const EXCLUDE_WARMUP_CONDITION = sql`(${messageRequest.blockedBy} IS NULL OR ${messageRequest.blockedBy} <> 'warmup')`;
+
+/**
+ * 仅匹配 warmup 请求。usage_ledger 在触发器层面不写入 warmup 行，
+ * 调用次数视图需要包含 warmup 时从 message_request 按此条件补回（费用始终不计）。
+ */
+export const WARMUP_ONLY_CONDITION = sql`${messageRequest.blockedBy} = 'warmup'`;
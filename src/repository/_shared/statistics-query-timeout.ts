@@ -0,0 +1,111 @@
+import type { SQL } from "drizzle-orm";
+import { sql } from "drizzle-orm";
+import { readDb } from "@/drizzle/read-db";
+import { getEnvConfig } from "@/lib/config/env.schema";
+import { ERROR_CODES } from "@/lib/utils/error-messages";
+
+/** PostgreSQL query_canceled（statement_timeout 触发时的 SQLSTATE） */
+const PG_QUERY_CANCELED = "57014";
+
+/**
+ * 统计查询超过 STATISTICS_QUERY_TIMEOUT 被数据库取消时抛出
+ */
+export class StatisticsQueryTimeoutError extends Error {
+  readonly code = ERROR_CODES.TIMEOUT;
+
+  constructor(readonly timeoutMs: number) {
+    super(`Statistics query timed out after ${timeoutMs}ms`);
+    this.name = "StatisticsQueryTimeoutError";
+  }
+}
+
+function isQueryCanceledError(error: unknown): boolean {
+  let current: unknown = error;
+  // drizzle 可能将驱动错误包装在 cause 中
+  for (let depth = 0; current && typeof current === "object" && depth < 3; depth++) {
+    if ((current as { code?: unknown }).code === PG_QUERY_CANCELED) return true;
+    current = (current as { cause?: unknown }).cause;
+  }
+  return false;
+}
+
+/**
+ * 在带 statement_timeout 的事务中执行统计查询
+ *
+ * set_config(..., true) 仅对当前事务生效，超时由数据库侧取消查询并释放连接，
+ * 而不是仅在应用侧放弃等待。STATISTICS_QUERY_TIMEOUT=0 时不设超时。
+ * 统计查询为只读，配置 DSN_READ_REPLICA 时在只读副本上执行。
+ * 传入 signal 时，已中止的查询不会再发出（已发出的 SQL 仍由 statement_timeout 兜底）。
+ */
+export async function executeStatisticsQuery(query: SQL, signal?: AbortSignal) {
+  signal?.throwIfAborted();
+
+  const timeoutMs = getEnvConfig().STATISTICS_QUERY_TIMEOUT;
+  if (timeoutMs <= 0) {
+    return readDb.execute(query);
+  }
+
+  try {
+    return await readDb.transaction(async (tx) => {
+      await tx.execute(sql`SELECT set_config('statement_timeout', ${String(timeoutMs)}, true)`);
+      // 设置超时期间若已被中止，不再发出主查询
+      signal?.throwIfAborted();
+      return tx.execute(query);
+    });
+  } catch (error) {
+    if (isQueryCanceledError(error)) {
+      throw new StatisticsQueryTimeoutError(timeoutMs);
+    }
+    throw error;
+  }
+}
+
+type StatisticsQueryTasks<T extends readonly unknown[]> = {
+  [K in keyof T]: (signal: AbortSignal) => Promise<T[K]>;
+};
+
+/**
+ * 并行执行一组统计子查询
+ *
+ * - 任一子查询失败时立即中止组内 signal，尚未发出的查询不再执行，并返回该错误
+ * - 外部 signal 中止（如客户端断开）时立即以其 reason（AbortError）返回，不等待在途查询
+ * - 提前返回后仍在途的子查询结果会被吞掉，避免 unhandled rejection
+ */
+export function runStatisticsQueryGroup<T extends readonly unknown[]>(
+  tasks: StatisticsQueryTasks<T>,
+  signal?: AbortSignal
+): Promise<T> {
+  if (signal?.aborted) {
+    return Promise.reject(signal.reason);
+  }
+
+  const controller = new AbortController();
+  const onParentAbort = () => controller.abort(signal?.reason);
+  signal?.addEventListener("abort", onParentAbort, { once: true });
+
+  const promises = (tasks as readonly ((signal: AbortSignal) => Promise<unknown>)[]).map(
+    (task) => {
+      const promise = Promise.resolve().then(() => task(controller.signal));
+      promise.catch((error) => {
+        if (!controller.signal.aborted) controller.abort(error);
+      });
+      return promise;
+    }
+  );
+
+  return new Promise<T>((resolve, reject) => {
+    const onAbort = () => reject(controller.signal.reason);
+    if (controller.signal.aborted) {
+      onAbort();
+    } else {
+      controller.signal.addEventListener("abort", onAbort, { once: true });
+    }
+
+    Promise.all(promises)
+      .then((results) => resolve(results as unknown as T), reject)
+      .finally(() => {
+        controller.signal.removeEventListener("abort", onAbort);
+        signal?.removeEventListener("abort", onParentAbort);
+      });
+  });
+}
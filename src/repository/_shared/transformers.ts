@@ -60,6 +60,7 @@ export function toUser(dbUser: any): User {
     allowedClients: dbUser?.allowedClients ?? [],
     blockedClients: dbUser?.blockedClients ?? [],
     allowedModels: dbUser?.allowedModels ?? [],
+    timezone: dbUser?.timezone ?? null,
     createdAt: dbUser?.createdAt ? new Date(dbUser.createdAt) : new Date(),
     updatedAt: dbUser?.updatedAt ? new Date(dbUser.updatedAt) : new Date(),
   };
@@ -245,6 +246,7 @@ export function toSystemSettings(dbSettings: any): SystemSettings {
     siteTitle: dbSettings?.siteTitle ?? "Claude Code Hub",
     allowGlobalUsageView: dbSettings?.allowGlobalUsageView ?? true,
     currencyDisplay: dbSettings?.currencyDisplay ?? "USD",
+    currencyDisplayRate: dbSettings?.currencyDisplayRate ?? "1",
     billingModelSource: dbSettings?.billingModelSource ?? "original",
     codexPriorityBillingSource:
       dbSettings?.codexPriorityBillingSource === "requested" ||
@@ -259,6 +261,7 @@ export function toSystemSettings(dbSettings: any): SystemSettings {
     cleanupSchedule: dbSettings?.cleanupSchedule ?? "0 2 * * *",
     cleanupBatchSize: dbSettings?.cleanupBatchSize ?? 10000,
     enableClientVersionCheck: dbSettings?.enableClientVersionCheck ?? false,
+    minClientVersion: dbSettings?.minClientVersion ?? null,
     verboseProviderError: dbSettings?.verboseProviderError ?? false,
     passThroughUpstreamErrorMessage: dbSettings?.passThroughUpstreamErrorMessage ?? true,
     enableHttp2: dbSettings?.enableHttp2 ?? false,
@@ -3,7 +3,7 @@
 import { and, desc, eq, inArray, isNull, notInArray, sql } from "drizzle-orm";
 import { db } from "@/drizzle/db";
 import { keys as keysTable, messageRequest, providers, users } from "@/drizzle/schema";
-import { logger } from "@/lib/logger";
+import { getRequestLogger } from "@/lib/request-id";
 
 /**
  * 活动流条目（单个请求记录）
@@ -60,6 +60,8 @@ export interface ActivityStreamItem {
  * @param limit 最大返回条数（默认 20）
  */
 export async function findRecentActivityStream(limit = 20): Promise<ActivityStreamItem[]> {
+  const requestLogger = getRequestLogger();
+
   try {
     // 1. 从 Redis 获取活跃 session ID
     const { SessionTracker } = await import("@/lib/session-tracker");
@@ -128,7 +130,7 @@ export async function findRecentActivityStream(limit = 20): Promise<ActivityStre
 
       activityItems = latestPerSession;
 
-      logger.debug("[ActivityStream] Got active session requests", {
+      requestLogger.debug("[ActivityStream] Got active session requests", {
         activeSessionCount: activeSessionIds.length,
         latestRequestCount: latestPerSession.length,
       });
@@ -198,7 +200,7 @@ export async function findRecentActivityStream(limit = 20): Promise<ActivityStre
 
       activityItems = [...activityItems, ...additionalItems];
 
-      logger.debug("[ActivityStream] Added recent requests", {
+      requestLogger.debug("[ActivityStream] Added recent requests", {
         additionalCount: additionalItems.length,
         totalCount: activityItems.length,
       });
@@ -216,14 +218,14 @@ export async function findRecentActivityStream(limit = 20): Promise<ActivityStre
       .sort((a, b) => b.startTime - a.startTime)
       .slice(0, limit);
 
-    logger.debug("[ActivityStream] Final activity stream", {
+    requestLogger.debug("[ActivityStream] Final activity stream", {
       totalUnique: uniqueItems.size,
       returned: sortedItems.length,
     });
 
     return sortedItems;
   } catch (error) {
-    logger.error("Failed to get recent activity stream:", error);
+    requestLogger.error("Failed to get recent activity stream:", error);
     return [];
   }
 }
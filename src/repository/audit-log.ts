@@ -49,12 +49,17 @@ function buildAuditLogFilterConditions(filter: AuditLogFilter): ReturnType<typeo
   return conditions;
 }
 
-async function insertAuditLog(entry: AuditLogInput): Promise<void> {
+type AuditLogTransaction = Parameters<Parameters<typeof db.transaction>[0]>[0];
+
+async function insertAuditLog(
+  entry: AuditLogInput,
+  executor: typeof db | AuditLogTransaction = db
+): Promise<void> {
   const userAgent =
     entry.userAgent && entry.userAgent.length > 512
       ? entry.userAgent.slice(0, 512)
       : entry.userAgent;
-  await db.insert(auditLog).values({
+  await executor.insert(auditLog).values({
     actionCategory: entry.actionCategory,
     actionType: entry.actionType,
     targetType: entry.targetType ?? null,
@@ -97,6 +102,20 @@ export async function createAuditLogAsync(entry: AuditLogInput): Promise<void> {
   }
 }
 
+/**
+ * Transactional audit log insert.
+ *
+ * Unlike `createAuditLogAsync`, errors propagate so the surrounding
+ * transaction rolls back together with the change being audited. Used where
+ * the audit row must be atomic with the mutation (e.g. manual cost resets).
+ */
+export async function insertAuditLogInTransaction(
+  tx: AuditLogTransaction,
+  entry: AuditLogInput
+): Promise<void> {
+  await insertAuditLog(entry, tx);
+}
+
 export interface AuditLogCursor {
   createdAt: string; // ISO
   id: number;
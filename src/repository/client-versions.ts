@@ -3,7 +3,7 @@
 import { and, gte, isNull, sql } from "drizzle-orm";
 import { db } from "@/drizzle/db";
 import { messageRequest, users } from "@/drizzle/schema";
-import { logger } from "@/lib/logger";
+import { getRequestLogger } from "@/lib/request-id";
 
 /**
  * 原始用户版本数据（从数据库查询）
@@ -55,7 +55,7 @@ export async function getActiveUserVersions(days = 7): Promise<RawUserVersion[]>
     }));
   } catch (error) {
     // Fail Open: 查询失败返回空数组
-    logger.error({ error }, "[ClientVersions] 查询活跃用户失败");
+    getRequestLogger().error({ error }, "[ClientVersions] 查询活跃用户失败");
     return [];
   }
 }
@@ -20,6 +20,7 @@ export {
   findKeyUsageToday,
   findKeyUsageTodayBatch,
   resolveApiKeyAuthOutcome,
+  restoreKey,
   updateKey,
   validateApiKeyAndGetUser,
 } from "./key";
@@ -39,6 +40,7 @@ export {
 } from "./model-price";
 // Provider related exports
 export {
+  countActiveProviders,
   createProvider,
   deleteProvider,
   findProviderById,
@@ -75,6 +77,7 @@ export {
   getActiveUsersFromDB,
   getKeyStatisticsFromDB,
   getUserStatisticsFromDB,
+  getUserTotalsFromDB,
 } from "./statistics";
 // System settings related exports
 export { getSystemSettings, updateSystemSettings } from "./system-config";
@@ -87,4 +90,14 @@ export {
   sumLedgerTotalCostBatch,
 } from "./usage-ledger";
 // User related exports
-export { createUser, deleteUser, findUserById, findUserList, updateUser } from "./user";
+export {
+  addUserTag,
+  countActiveUsers,
+  createUser,
+  deleteUser,
+  findUserById,
+  findUserList,
+  removeUserTag,
+  restoreUser,
+  updateUser,
+} from "./user";
@@ -1,8 +1,25 @@
 "use server";
 
-import { and, count, desc, eq, gt, gte, inArray, isNull, lt, or, sql, sum } from "drizzle-orm";
+import {
+  and,
+  count,
+  desc,
+  eq,
+  exists,
+  gt,
+  gte,
+  inArray,
+  isNotNull,
+  isNull,
+  lt,
+  lte,
+  ne,
+  or,
+  sql,
+  sum,
+} from "drizzle-orm";
 import { db } from "@/drizzle/db";
-import { keys, providers, usageLedger, users } from "@/drizzle/schema";
+import { keys, messageRequest, providers, usageLedger, users } from "@/drizzle/schema";
 import { CHANNEL_API_KEYS_UPDATED, publishCacheInvalidation } from "@/lib/redis/pubsub";
 import {
   cacheActiveKey,
@@ -13,11 +30,16 @@ import {
   invalidateCachedKey,
 } from "@/lib/security/api-key-auth-cache";
 import { apiKeyVacuumFilter } from "@/lib/security/api-key-vacuum-filter";
+import { API_KEY_LOOKUP_PREFIX_LENGTH } from "@/lib/utils/api-key";
 import { Decimal, toCostDecimal } from "@/lib/utils/currency";
+import type { AuditLogInput } from "@/types/audit-log";
 import type { CreateKeyData, Key, UpdateKeyData } from "@/types/key";
 import type { User } from "@/types/user";
 import { LEDGER_BILLING_CONDITION } from "./_shared/ledger-conditions";
+import { escapeLike } from "./_shared/like";
+import { WARMUP_ONLY_CONDITION } from "./_shared/message-request-conditions";
 import { toKey, toUser } from "./_shared/transformers";
+import { insertAuditLogInTransaction } from "./audit-log";
 
 export async function findKeyById(id: number): Promise<Key | null> {
   const [key] = await db
@@ -139,6 +161,128 @@ export async function findKeyListBatch(userIds: number[]): Promise<Map<number, K
   return keyMap;
 }
 
+/**
+ * 查找属于指定供应商分组的所有有效 Key
+ *
+ * provider_group 以逗号分隔存储多个分组（如 "cli,chat"），与用户分组筛选一致：
+ * 拆分后逐项精确匹配，"a, b ,c" 查询 "b" 也能命中
+ */
+export async function findKeysByProviderGroup(group: string): Promise<Key[]> {
+  const trimmedGroup = group.trim();
+  if (!trimmedGroup) return [];
+
+  const result = await db
+    .select({
+      id: keys.id,
+      userId: keys.userId,
+      key: keys.key,
+      name: keys.name,
+      isEnabled: keys.isEnabled,
+      expiresAt: keys.expiresAt,
+      canLoginWebUi: keys.canLoginWebUi,
+      limit5hUsd: keys.limit5hUsd,
+      limit5hResetMode: keys.limit5hResetMode,
+      limitDailyUsd: keys.limitDailyUsd,
+      dailyResetMode: keys.dailyResetMode,
+      dailyResetTime: keys.dailyResetTime,
+      limitWeeklyUsd: keys.limitWeeklyUsd,
+      limitMonthlyUsd: keys.limitMonthlyUsd,
+      limitTotalUsd: keys.limitTotalUsd,
+      costResetAt: keys.costResetAt,
+      limitConcurrentSessions: keys.limitConcurrentSessions,
+      providerGroup: keys.providerGroup,
+      cacheTtlPreference: keys.cacheTtlPreference,
+      createdAt: keys.createdAt,
+      updatedAt: keys.updatedAt,
+      deletedAt: keys.deletedAt,
+    })
+    .from(keys)
+    .where(
+      and(
+        isNull(keys.deletedAt),
+        sql`${trimmedGroup} = ANY(regexp_split_to_array(coalesce(${keys.providerGroup}, ''), '\\s*[,，\n\r]+\\s*'))`
+      )
+    )
+    .orderBy(keys.userId, keys.createdAt);
+
+  return result.map(toKey);
+}
+
+/**
+ * Key 前缀检索允许的最大长度：仅比对密钥开头的前缀，
+ * 超过该长度的搜索词不参与密钥匹配，避免完整密钥出现在查询条件中
+ */
+const KEY_SEARCH_PREFIX_LENGTH = API_KEY_LOOKUP_PREFIX_LENGTH;
+
+export interface SearchKeysOptions {
+  /** Page size */
+  limit?: number;
+  /** Row offset */
+  offset?: number;
+}
+
+/**
+ * 管理后台按关键字检索 Key
+ *
+ * 匹配 name / provider_group（包含匹配）以及密钥前缀（仅前 KEY_SEARCH_PREFIX_LENGTH 位），
+ * 排除已软删除的 Key，按 created_at 倒序分页返回
+ */
+export async function searchKeys(
+  searchTerm: string,
+  options: SearchKeysOptions = {}
+): Promise<Key[]> {
+  const trimmedSearch = searchTerm.trim();
+  if (!trimmedSearch) return [];
+
+  const limit = Math.min(Math.max(1, options.limit ?? 50), 200);
+  const offset = Math.max(0, options.offset ?? 0);
+  const escapedSearch = escapeLike(trimmedSearch);
+  const pattern = `%${escapedSearch}%`;
+
+  const matchConditions = [
+    sql`${keys.name} ILIKE ${pattern} ESCAPE '\\'`,
+    sql`${keys.providerGroup} ILIKE ${pattern} ESCAPE '\\'`,
+  ];
+  if (trimmedSearch.length <= KEY_SEARCH_PREFIX_LENGTH) {
+    matchConditions.push(
+      sql`left(${keys.key}, ${KEY_SEARCH_PREFIX_LENGTH}) ILIKE ${`${escapedSearch}%`} ESCAPE '\\'`
+    );
+  }
+
+  const result = await db
+    .select({
+      id: keys.id,
+      userId: keys.userId,
+      key: keys.key,
+      name: keys.name,
+      isEnabled: keys.isEnabled,
+      expiresAt: keys.expiresAt,
+      canLoginWebUi: keys.canLoginWebUi,
+      limit5hUsd: keys.limit5hUsd,
+      limit5hResetMode: keys.limit5hResetMode,
+      limitDailyUsd: keys.limitDailyUsd,
+      dailyResetMode: keys.dailyResetMode,
+      dailyResetTime: keys.dailyResetTime,
+      limitWeeklyUsd: keys.limitWeeklyUsd,
+      limitMonthlyUsd: keys.limitMonthlyUsd,
+      limitTotalUsd: keys.limitTotalUsd,
+      costResetAt: keys.costResetAt,
+      limitConcurrentSessions: keys.limitConcurrentSessions,
+      providerGroup: keys.providerGroup,
+      cacheTtlPreference: keys.cacheTtlPreference,
+      createdAt: keys.createdAt,
+      updatedAt: keys.updatedAt,
+      deletedAt: keys.deletedAt,
+    })
+    .from(keys)
+    .where(and(isNull(keys.deletedAt), or(...matchConditions)))
+    .orderBy(desc(keys.createdAt), desc(keys.id))
+    .limit(limit)
+    .offset(offset);
+
+  return result.map(toKey);
+}
+
 export async function createKey(keyData: CreateKeyData): Promise<Key> {
   const dbData = {
     userId: keyData.user_id,
@@ -299,9 +443,15 @@ export async function updateKey(id: number, keyData: UpdateKeyData): Promise<Key
   return updated;
 }
 
+/**
+ * 查找用户名下同名的生效 Key（用于重名检测）
+ *
+ * @param excludeKeyId - 编辑时排除 Key 自身
+ */
 export async function findActiveKeyByUserIdAndName(
   userId: number,
-  name: string
+  name: string,
+  excludeKeyId?: number
 ): Promise<Key | null> {
   const [key] = await db
     .select({
@@ -335,7 +485,8 @@ export async function findActiveKeyByUserIdAndName(
         eq(keys.name, name),
         isNull(keys.deletedAt),
         eq(keys.isEnabled, true),
-        or(isNull(keys.expiresAt), gt(keys.expiresAt, new Date()))
+        or(isNull(keys.expiresAt), gt(keys.expiresAt, new Date())),
+        excludeKeyId !== undefined ? ne(keys.id, excludeKeyId) : undefined
       )
     );
 
@@ -453,6 +604,95 @@ export async function countActiveKeysByUser(userId: number): Promise<number> {
   return Number(row?.count || 0);
 }
 
+export interface ActiveKeysPage {
+  keys: Key[];
+  /** 下一页游标（本页最后一个 Key 的 id），null 表示已无更多数据 */
+  nextCursor: number | null;
+}
+
+/**
+ * 按 id 游标分批读取有效 Key（未删除、已启用、未过期）
+ *
+ * 供后台任务（过期扫描、缓存预热等）分批处理，避免一次性加载全部 Key：
+ * 以返回的 nextCursor 作为下一次的 cursor 循环调用，直到 nextCursor 为 null。
+ *
+ * @param cursor 上一页返回的 nextCursor，首次调用传 null
+ * @param limit 每批数量（1-1000）
+ */
+export async function findActiveKeysPaginated(
+  cursor: number | null,
+  limit: number = 500
+): Promise<ActiveKeysPage> {
+  const pageSize = Math.min(Math.max(1, Math.trunc(limit) || 1), 1000);
+
+  const conditions = [
+    isNull(keys.deletedAt),
+    eq(keys.isEnabled, true),
+    or(isNull(keys.expiresAt), gt(keys.expiresAt, new Date())),
+  ];
+  if (cursor !== null) {
+    conditions.push(gt(keys.id, cursor));
+  }
+
+  // 多取一条用于判断是否还有下一页
+  const rows = await db
+    .select({
+      id: keys.id,
+      userId: keys.userId,
+      key: keys.key,
+      name: keys.name,
+      isEnabled: keys.isEnabled,
+      expiresAt: keys.expiresAt,
+      canLoginWebUi: keys.canLoginWebUi,
+      limit5hUsd: keys.limit5hUsd,
+      limit5hResetMode: keys.limit5hResetMode,
+      limitDailyUsd: keys.limitDailyUsd,
+      dailyResetMode: keys.dailyResetMode,
+      dailyResetTime: keys.dailyResetTime,
+      limitWeeklyUsd: keys.limitWeeklyUsd,
+      limitMonthlyUsd: keys.limitMonthlyUsd,
+      limitTotalUsd: keys.limitTotalUsd,
+      costResetAt: keys.costResetAt,
+      limitConcurrentSessions: keys.limitConcurrentSessions,
+      providerGroup: keys.providerGroup,
+      cacheTtlPreference: keys.cacheTtlPreference,
+      createdAt: keys.createdAt,
+      updatedAt: keys.updatedAt,
+      deletedAt: keys.deletedAt,
+    })
+    .from(keys)
+    .where(and(...conditions))
+    .orderBy(keys.id)
+    .limit(pageSize + 1);
+
+  const hasMore = rows.length > pageSize;
+  const pageRows = hasMore ? rows.slice(0, pageSize) : rows;
+
+  return {
+    keys: pageRows.map(toKey),
+    nextCursor: hasMore ? pageRows[pageRows.length - 1].id : null,
+  };
+}
+
+/**
+ * 批量禁用已过期的 Key（单条 UPDATE），供过期扫描任务使用
+ *
+ * 仅更新已启用且未删除的 Key；已禁用或尚未过期的 Key 不受影响
+ *
+ * @returns 被禁用的 Key 数量
+ */
+export async function markExpiredKeysBatch(): Promise<number> {
+  const now = new Date();
+  const result = await db
+    .update(keys)
+    .set({ isEnabled: false, updatedAt: now })
+    .where(and(lte(keys.expiresAt, now), eq(keys.isEnabled, true), isNull(keys.deletedAt)))
+    .returning({ key: keys.key });
+
+  await Promise.all(result.map((row) => invalidateCachedKey(row.key).catch(() => {})));
+  return result.length;
+}
+
 export async function deleteKey(id: number): Promise<boolean> {
   const result = await db
     .update(keys)
@@ -466,13 +706,58 @@ export async function deleteKey(id: number): Promise<boolean> {
   return result.length > 0;
 }
 
-export async function resetKeyCostResetAt(keyId: number, resetAt: Date | null): Promise<boolean> {
+/**
+ * 恢复软删除的 Key（所属用户必须未被删除）
+ *
+ * @returns Key 不存在、未被删除或所属用户已删除时返回 false
+ */
+export async function restoreKey(id: number): Promise<boolean> {
+  const activeOwner = db
+    .select({ id: users.id })
+    .from(users)
+    .where(and(eq(users.id, keys.userId), isNull(users.deletedAt)));
+
   const result = await db
     .update(keys)
-    .set({ costResetAt: resetAt, updatedAt: new Date() })
-    .where(and(eq(keys.id, keyId), isNull(keys.deletedAt)))
+    .set({ deletedAt: null, updatedAt: new Date() })
+    .where(and(eq(keys.id, id), isNotNull(keys.deletedAt), exists(activeOwner)))
     .returning({ id: keys.id, key: keys.key });
 
+  if (result.length === 0) {
+    return false;
+  }
+
+  // 与新建 Key 一致：写入本机 Vacuum Filter，并广播让其它实例重建
+  try {
+    apiKeyVacuumFilter.noteExistingKey(result[0].key);
+  } catch {
+    // ignore
+  }
+  await publishCacheInvalidation(CHANNEL_API_KEYS_UPDATED).catch(() => {});
+  return true;
+}
+
+/**
+ * 重置 Key 的消费统计起点；传入 audit 时，审计记录与重置标记在同一事务内写入。
+ */
+export async function resetKeyCostResetAt(
+  keyId: number,
+  resetAt: Date | null,
+  audit?: AuditLogInput
+): Promise<boolean> {
+  const result = await db.transaction(async (tx) => {
+    const rows = await tx
+      .update(keys)
+      .set({ costResetAt: resetAt, updatedAt: new Date() })
+      .where(and(eq(keys.id, keyId), isNull(keys.deletedAt)))
+      .returning({ id: keys.id, key: keys.key });
+
+    if (rows.length > 0 && audit) {
+      await insertAuditLogInTransaction(tx, audit);
+    }
+    return rows;
+  });
+
   if (result.length > 0) {
     await invalidateCachedKey(result[0].key).catch(() => {});
   }
@@ -611,6 +896,7 @@ export async function resolveApiKeyAuthOutcome(keyString: string): Promise<ApiKe
         expiresAt: users.expiresAt,
         allowedClients: users.allowedClients,
         allowedModels: users.allowedModels,
+        timezone: users.timezone,
       })
       .from(users)
       .where(and(eq(users.id, cachedKey.userId), isNull(users.deletedAt)));
@@ -681,6 +967,7 @@ export async function resolveApiKeyAuthOutcome(keyString: string): Promise<ApiKe
       userExpiresAt: users.expiresAt,
       userAllowedClients: users.allowedClients,
       userAllowedModels: users.allowedModels,
+      userTimezone: users.timezone,
       userCreatedAt: users.createdAt,
       userUpdatedAt: users.updatedAt,
       userDeletedAt: users.deletedAt,
@@ -736,6 +1023,7 @@ export async function resolveApiKeyAuthOutcome(keyString: string): Promise<ApiKe
     expiresAt: row.userExpiresAt,
     allowedClients: row.userAllowedClients,
     allowedModels: row.userAllowedModels,
+    timezone: row.userTimezone,
     createdAt: row.userCreatedAt,
     updatedAt: row.userUpdatedAt,
     deletedAt: row.userDeletedAt,
@@ -790,6 +1078,10 @@ export async function validateApiKeyAndGetUser(
 export interface KeyStatistics {
   keyId: number;
   todayCallCount: number;
+  /** 今日总消费（美元），与 todayCallCount 使用同一统计窗口 */
+  todayTotalCost: number;
+  /** 今日平均单次调用消费（美元），无调用时为 0 */
+  avgCostPerCall: number;
   lastUsedAt: Date | null;
   lastProviderName: string | null;
   modelStats: Array<{
@@ -803,7 +1095,30 @@ export interface KeyStatistics {
   }>;
 }
 
-export async function findKeysWithStatistics(userId: number): Promise<KeyStatistics[]> {
+/**
+ * 基于 Decimal 计算今日总消费与平均单次消费，避免浮点误差；无调用时平均值为 0
+ */
+function summarizeTodayCost(
+  totalCost: string | null | undefined,
+  callCount: number
+): Pick<KeyStatistics, "todayTotalCost" | "avgCostPerCall"> {
+  const costDecimal = toCostDecimal(totalCost) ?? new Decimal(0);
+  return {
+    todayTotalCost: costDecimal.toDecimalPlaces(6).toNumber(),
+    avgCostPerCall: callCount > 0 ? costDecimal.div(callCount).toDecimalPlaces(6).toNumber() : 0,
+  };
+}
+
+/**
+ * 获取用户各密钥的今日统计
+ *
+ * includeWarmup=true 时 todayCallCount 额外计入今日 warmup 请求（从 message_request 读取，
+ * warmup 不进入 usage_ledger）；消费、平均单次消费与分模型统计仍只基于计费请求。默认排除。
+ */
+export async function findKeysWithStatistics(
+  userId: number,
+  options: { includeWarmup?: boolean } = {}
+): Promise<KeyStatistics[]> {
   const userKeys = await findKeyList(userId);
 
   const today = new Date();
@@ -814,9 +1129,9 @@ export async function findKeysWithStatistics(userId: number): Promise<KeyStatist
   const stats: KeyStatistics[] = [];
 
   for (const key of userKeys) {
-    // 查询今日调用次数
+    // 查询今日调用次数与总消费
     const [todayCount] = await db
-      .select({ count: count() })
+      .select({ count: count(), totalCost: sum(usageLedger.costUsd) })
       .from(usageLedger)
       .where(
         and(
@@ -876,9 +1191,28 @@ export async function findKeysWithStatistics(userId: number): Promise<KeyStatist
       cacheReadTokens: row.cacheReadTokens,
     }));
 
+    let warmupCallCount = 0;
+    if (options.includeWarmup) {
+      const [warmupCount] = await db
+        .select({ count: count() })
+        .from(messageRequest)
+        .where(
+          and(
+            eq(messageRequest.key, key.key),
+            isNull(messageRequest.deletedAt),
+            WARMUP_ONLY_CONDITION,
+            gte(messageRequest.createdAt, today),
+            lt(messageRequest.createdAt, tomorrow)
+          )
+        );
+      warmupCallCount = Number(warmupCount?.count || 0);
+    }
+
+    const billedCallCount = Number(todayCount?.count || 0);
     stats.push({
       keyId: key.id,
-      todayCallCount: Number(todayCount?.count || 0),
+      todayCallCount: billedCallCount + warmupCallCount,
+      ...summarizeTodayCost(todayCount?.totalCost, billedCallCount),
       lastUsedAt: lastUsage?.createdAt || null,
       lastProviderName: lastUsage?.providerName || null,
       modelStats,
@@ -959,11 +1293,12 @@ async function _findKeysStatisticsBatchInternal(
   const tomorrow = new Date(today);
   tomorrow.setDate(tomorrow.getDate() + 1);
 
-  // Step 2: Query today's call counts for all keys at once
+  // Step 2: Query today's call counts and total cost for all keys at once
   const todayCountRows = await db
     .select({
       key: usageLedger.key,
       count: count(),
+      totalCost: sum(usageLedger.costUsd),
     })
     .from(usageLedger)
     .where(
@@ -976,10 +1311,10 @@ async function _findKeysStatisticsBatchInternal(
     )
     .groupBy(usageLedger.key);
 
-  const todayCountMap = new Map<string, number>();
+  const todayCountMap = new Map<string, { count: number; totalCost: string | null }>();
   for (const row of todayCountRows) {
     if (row.key) {
-      todayCountMap.set(row.key, Number(row.count));
+      todayCountMap.set(row.key, { count: Number(row.count), totalCost: row.totalCost });
     }
   }
 
@@ -1087,9 +1422,12 @@ async function _findKeysStatisticsBatchInternal(
     if (!info) continue;
 
     const lastUsage = lastUsageMap.get(key.key);
+    const todayUsage = todayCountMap.get(key.key);
+    const todayCallCount = todayUsage?.count || 0;
     const stats: KeyStatistics = {
       keyId: key.id,
-      todayCallCount: todayCountMap.get(key.key) || 0,
+      todayCallCount,
+      ...summarizeTodayCost(todayUsage?.totalCost, todayCallCount),
       lastUsedAt: lastUsage?.createdAt || null,
       lastProviderName: lastUsage?.providerName || null,
       modelStats: modelStatsMap.get(key.key) || [],
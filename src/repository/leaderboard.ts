@@ -1,5 +1,6 @@
 "use server";
 
+import { formatInTimeZone } from "date-fns-tz";
 import { and, asc, desc, eq, isNull, sql } from "drizzle-orm";
 import { db } from "@/drizzle/db";
 import { providers, usageLedger, users } from "@/drizzle/schema";
@@ -180,6 +181,68 @@ export async function findDailyLeaderboard(
   return findLeaderboardWithTimezone("daily", timezone, undefined, userFilters, includeModelStats);
 }
 
+/**
+ * 今日消耗 TopN 排行榜（用于每日排行榜通知）
+ */
+export interface DailyLeaderboardTopN {
+  /** 指定时区下的今日日期（YYYY-MM-DD） */
+  date: string;
+  entries: LeaderboardEntry[];
+}
+
+/**
+ * 查询指定时区"今日"消耗前 N 名用户
+ *
+ * 与 findDailyLeaderboard 使用相同的计费过滤（排除 warmup/拦截请求与已删除用户），
+ * 但在 SQL 中直接按消耗降序、用户名升序截取前 N 名。
+ */
+export async function findDailyLeaderboardTopN(
+  timezone: string,
+  topN: number
+): Promise<DailyLeaderboardTopN> {
+  const date = formatInTimeZone(new Date(), timezone, "yyyy-MM-dd");
+  const limit = Math.floor(topN);
+  if (!Number.isFinite(limit) || limit <= 0) {
+    return { date, entries: [] };
+  }
+
+  const totalCostExpr = sql`COALESCE(sum(${usageLedger.costUsd}), 0)`;
+
+  const rankings = await db
+    .select({
+      userId: usageLedger.userId,
+      userName: users.name,
+      totalRequests: sql<number>`count(*)::double precision`,
+      totalCost: sql<string>`COALESCE(sum(${usageLedger.costUsd}), 0)`,
+      totalTokens: sql<number>`COALESCE(
+        sum(
+          ${usageLedger.inputTokens} +
+          ${usageLedger.outputTokens} +
+          COALESCE(${usageLedger.cacheCreationInputTokens}, 0) +
+          COALESCE(${usageLedger.cacheReadInputTokens}, 0)
+        )::double precision,
+        0::double precision
+      )`,
+    })
+    .from(usageLedger)
+    .innerJoin(users, and(sql`${usageLedger.userId} = ${users.id}`, isNull(users.deletedAt)))
+    .where(and(LEDGER_BILLING_CONDITION, buildDateCondition("daily", timezone)))
+    .groupBy(usageLedger.userId, users.name)
+    .orderBy(desc(totalCostExpr), asc(users.name), asc(usageLedger.userId))
+    .limit(limit);
+
+  return {
+    date,
+    entries: rankings.map((entry) => ({
+      userId: entry.userId,
+      userName: entry.userName,
+      totalRequests: entry.totalRequests,
+      totalCost: parseFloat(entry.totalCost),
+      totalTokens: entry.totalTokens,
+    })),
+  };
+}
+
 /**
  * 查询本月消耗排行榜（不限制数量）
  * 使用 SQL AT TIME ZONE 进行时区转换，确保"本月"基于系统时区
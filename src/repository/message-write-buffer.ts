@@ -7,6 +7,7 @@ import { getEnvConfig } from "@/lib/config/env.schema";
 import { logger } from "@/lib/logger";
 import type { StoredCostBreakdown } from "@/types/cost-breakdown";
 import type { CreateMessageRequestData } from "@/types/message";
+import type { RateLimitMetadata } from "@/types/statistics";
 
 export type MessageRequestUpdatePatch = {
   durationMs?: number;
@@ -24,6 +25,7 @@ export type MessageRequestUpdatePatch = {
   errorMessage?: string;
   errorStack?: string;
   errorCause?: string;
+  rateLimitMetadata?: RateLimitMetadata | null;
   model?: string;
   /** 上游响应中实际返回的模型名(audit);null 显式清空,undefined 不动 */
   actualResponseModel?: string | null;
@@ -65,6 +67,7 @@ const COLUMN_MAP: Record<keyof MessageRequestUpdatePatch, string> = {
   errorMessage: "error_message",
   errorStack: "error_stack",
   errorCause: "error_cause",
+  rateLimitMetadata: "rate_limit_metadata",
   model: "model",
   actualResponseModel: "actual_response_model",
   providerId: "provider_id",
@@ -113,7 +116,12 @@ export function buildBatchUpdateSql(updates: MessageRequestUpdateRecord[]): SQL
         continue;
       }
 
-      if (key === "providerChain" || key === "specialSettings" || key === "costBreakdown") {
+      if (
+        key === "providerChain" ||
+        key === "specialSettings" ||
+        key === "costBreakdown" ||
+        key === "rateLimitMetadata"
+      ) {
         if (value === null) {
           cases.push(sql`WHEN ${update.id} THEN NULL`);
           continue;
@@ -1,6 +1,6 @@
 "use server";
 
-import { and, asc, desc, eq, gt, inArray, isNull, lt, sql } from "drizzle-orm";
+import { and, asc, desc, eq, gt, gte, inArray, isNotNull, isNull, lt, sql } from "drizzle-orm";
 import { db } from "@/drizzle/db";
 import { keys as keysTable, messageRequest, providers, usageLedger, users } from "@/drizzle/schema";
 import { getEnvConfig } from "@/lib/config/env.schema";
@@ -11,9 +11,11 @@ import {
   queuePublicStatusRollupWrite,
 } from "@/lib/public-status/rollup-store";
 import { formatCostForStorage } from "@/lib/utils/currency";
+import { ProviderCostMultiplierSchema } from "@/lib/validation/schemas";
 import type { HedgeLoserBilling, StoredCostBreakdown } from "@/types/cost-breakdown";
 import type { CreateMessageRequestData, MessageRequest, ProviderChainItem } from "@/types/message";
 import type { SpecialSetting } from "@/types/special-settings";
+import type { RateLimitMetadata } from "@/types/statistics";
 import { LEDGER_BILLING_CONDITION } from "./_shared/ledger-conditions";
 import { EXCLUDE_WARMUP_CONDITION } from "./_shared/message-request-conditions";
 import { toMessageRequest } from "./_shared/transformers";
@@ -458,6 +460,50 @@ export async function addMessageRequestHedgeLoserCost(
   throw lastError;
 }
 
+/**
+ * 按新的供应商倍率重算历史请求费用（管理员修正定价错误的供应商时使用）
+ *
+ * 单条 UPDATE：cost_usd = cost_usd / cost_multiplier * newMultiplier，并同步写入新的 cost_multiplier。
+ * - 仅处理 created_at >= since 且未软删除、最终供应商为 providerId 的记录
+ * - cost_multiplier 为 NULL 或 0 的记录跳过（无法反推基础费用，避免除零）
+ * - usage_ledger 由 trg_upsert_usage_ledger 触发器随之同步
+ * - cost_breakdown 明细不做重算，仍保留原始计算时的快照
+ *
+ * @returns 受影响的记录数
+ */
+export async function recomputeProviderCosts(
+  providerId: number,
+  newMultiplier: number,
+  since: Date
+): Promise<number> {
+  // 与供应商表单共用同一边界（> 0 且不超过上限），倍率为 0 会不可逆地清零历史费用
+  if (!ProviderCostMultiplierSchema.safeParse(newMultiplier).success) {
+    throw new Error(`Invalid cost multiplier: ${newMultiplier}`);
+  }
+
+  const multiplier = newMultiplier.toString();
+  const result = await db
+    .update(messageRequest)
+    .set({
+      costUsd: sql`${messageRequest.costUsd} / ${messageRequest.costMultiplier} * ${multiplier}::numeric`,
+      costMultiplier: multiplier,
+      updatedAt: new Date(),
+    })
+    .where(
+      and(
+        eq(messageRequest.providerId, providerId),
+        gte(messageRequest.createdAt, since),
+        isNull(messageRequest.deletedAt),
+        isNotNull(messageRequest.costUsd),
+        isNotNull(messageRequest.costMultiplier),
+        sql`${messageRequest.costMultiplier} <> 0`,
+        sql`${messageRequest.costMultiplier} <> ${multiplier}::numeric`
+      )
+    );
+
+  return Number((result as { count?: unknown }).count) || 0;
+}
+
 /**
  * 更新消息请求的扩展信息（status code, tokens, provider chain, error）
  */
@@ -477,6 +523,7 @@ export async function updateMessageRequestDetails(
     errorMessage?: string;
     errorStack?: string; // 完整堆栈信息
     errorCause?: string; // 嵌套错误原因（JSON 格式）
+    rateLimitMetadata?: RateLimitMetadata | null; // 限流上下文（结构化）
     model?: string; // ⭐ 新增：支持更新重定向后的模型名称
     actualResponseModel?: string | null; // 上游响应实际返回的模型名(audit 用途，不影响计费)
     providerId?: number; // ⭐ 新增：支持更新最终供应商ID（重试切换后）
@@ -539,6 +586,9 @@ export async function updateMessageRequestDetails(
   if (details.errorCause !== undefined) {
     updateData.errorCause = details.errorCause;
   }
+  if (details.rateLimitMetadata !== undefined) {
+    updateData.rateLimitMetadata = details.rateLimitMetadata;
+  }
   if (details.model !== undefined) {
     updateData.model = details.model;
   }
@@ -1457,7 +1507,10 @@ export async function findUsageLogs(params: {
 }
 
 /**
- * 查询指定 Session 的所有请求记录（用于 Session 详情页的请求列表）
+ * 查询指定 Session 的所有请求记录（用于 Session 详情页的请求列表与完整会话回放）
+ *
+ * 按 requestSequence → createdAt → id 排序，序号重复（并发/重试）时仍保持稳定的时间线；
+ * 同时带出供应商与用户名称。未知 Session 返回空列表。
  *
  * @param sessionId - Session ID
  * @param options - 分页参数
@@ -1477,10 +1530,15 @@ export async function findRequestsBySessionId(
     inputTokens: number | null;
     outputTokens: number | null;
     errorMessage: string | null;
+    providerId: number;
+    providerName: string | null;
+    userId: number;
+    userName: string | null;
   }>;
   total: number;
 }> {
   const { limit = 20, offset = 0, order = "asc" } = options || {};
+  const direction = order === "asc" ? asc : desc;
 
   // 查询总数
   const [countResult] = await db
@@ -1502,11 +1560,19 @@ export async function findRequestsBySessionId(
       inputTokens: messageRequest.inputTokens,
       outputTokens: messageRequest.outputTokens,
       errorMessage: messageRequest.errorMessage,
+      providerId: messageRequest.providerId,
+      providerName: providers.name,
+      userId: messageRequest.userId,
+      userName: users.name,
     })
     .from(messageRequest)
+    .leftJoin(providers, eq(messageRequest.providerId, providers.id))
+    .leftJoin(users, eq(messageRequest.userId, users.id))
     .where(and(eq(messageRequest.sessionId, sessionId), isNull(messageRequest.deletedAt)))
     .orderBy(
-      order === "asc" ? asc(messageRequest.requestSequence) : desc(messageRequest.requestSequence)
+      direction(messageRequest.requestSequence),
+      direction(messageRequest.createdAt),
+      direction(messageRequest.id)
     )
     .limit(limit)
     .offset(offset);
@@ -1522,6 +1588,10 @@ export async function findRequestsBySessionId(
       inputTokens: r.inputTokens,
       outputTokens: r.outputTokens,
       errorMessage: r.errorMessage,
+      providerId: r.providerId,
+      providerName: r.providerName ?? null,
+      userId: r.userId,
+      userName: r.userName ?? null,
     })),
     total,
   };
@@ -0,0 +1,33 @@
+import "server-only";
+
+import { sql } from "drizzle-orm";
+import { db } from "@/drizzle/db";
+
+/**
+ * 当前已应用的最新迁移版本
+ *
+ * drizzle 迁移器以 __drizzle_migrations.created_at（即 journal 中的 when）判定是否需要执行迁移，
+ * 这里同样以其最大值作为版本号。迁移表不存在（从未迁移）时返回 0。
+ */
+export async function getCurrentMigrationVersion(): Promise<number> {
+  const [table] = Array.from(
+    await db.execute(
+      sql`SELECT to_regclass('drizzle.__drizzle_migrations') IS NOT NULL AS exists`
+    )
+  ) as Array<{ exists?: boolean }>;
+  if (!table?.exists) return 0;
+
+  const [row] = Array.from(
+    await db.execute(sql`SELECT MAX(created_at) AS version FROM "drizzle"."__drizzle_migrations"`)
+  ) as Array<{ version?: string | number | null }>;
+
+  const version = Number(row?.version ?? 0);
+  return Number.isFinite(version) ? version : 0;
+}
+
+/**
+ * 数据库是否已应用到期望的迁移版本
+ */
+export async function isMigrationUpToDate(expectedVersion: number): Promise<boolean> {
+  return (await getCurrentMigrationVersion()) >= expectedVersion;
+}
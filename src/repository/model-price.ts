@@ -2,10 +2,16 @@
 
 import { desc, eq, inArray, sql } from "drizzle-orm";
 import { db } from "@/drizzle/db";
-import { modelPrices } from "@/drizzle/schema";
-import { logger } from "@/lib/logger";
-import { buildModelNameFallbackCandidates } from "@/lib/utils/model-name-matching";
+import { readDb } from "@/drizzle/read-db";
+import { modelPriceHistory, modelPrices } from "@/drizzle/schema";
+import { getRequestLogger } from "@/lib/request-id";
+import {
+  buildModelNameFallbackCandidates,
+  normalizeModelName,
+  pickFuzzyModelNameMatch,
+} from "@/lib/utils/model-name-matching";
 import type { ModelPrice, ModelPriceData, ModelPriceSource } from "@/types/model-price";
+import { escapeLike } from "./_shared/like";
 import { toModelPrice } from "./_shared/transformers";
 
 /**
@@ -38,6 +44,8 @@ export interface PaginatedResult<T> {
  * 1. 归一化候选名(去托管商前缀、取最后一段、剥区域前缀等)的精确匹配
  * 2. 云端价格表 aliases 数组命中原名
  * 3. aliases 命中候选名
+ *
+ * 计费依赖此查询，因此不做家族/前缀模糊匹配；需要模糊匹配时使用 findPriceByModelFuzzy。
  */
 export async function findLatestPriceByModel(modelName: string): Promise<ModelPrice | null> {
   try {
@@ -50,7 +58,7 @@ export async function findLatestPriceByModel(modelName: string): Promise<ModelPr
       updatedAt: modelPrices.updatedAt,
     };
 
-    const [price] = await db
+    const [price] = await readDb
       .select(selection)
       .from(modelPrices)
       .where(eq(modelPrices.modelName, modelName))
@@ -64,7 +72,7 @@ export async function findLatestPriceByModel(modelName: string): Promise<ModelPr
     if (price) return toModelPrice(price);
     return await findLatestPriceByModelFallback(modelName);
   } catch (error) {
-    logger.error("[ModelPrice] Failed to query latest price by model", {
+    getRequestLogger().error("[ModelPrice] Failed to query latest price by model", {
       modelName,
       error: error instanceof Error ? error.message : String(error),
     });
@@ -110,12 +118,66 @@ async function findLatestPriceByModelFallback(modelName: string): Promise<ModelP
     LIMIT 1
   `;
 
-  const result = await db.execute(query);
+  const result = await readDb.execute(query);
   const rows = Array.from(result);
   if (rows.length === 0) return null;
   return toModelPrice(rows[0]);
 }
 
+/**
+ * 模糊匹配模型价格（不用于计费）
+ *
+ * 依次尝试:精确名及候选名/别名回退(findLatestPriceByModel) -> 归一化相等 -> 归一化前缀
+ * (如 "claude-3-5-sonnet-20241022" → "claude-3.5-sonnet")。
+ * 归一化规则见 normalizeModelName;候选按 manual 优先、时间倒序参与比较,结果可预测。
+ */
+export async function findPriceByModelFuzzy(modelName: string): Promise<ModelPrice | null> {
+  const direct = await findLatestPriceByModel(modelName);
+  if (direct) return direct;
+
+  try {
+    return await findLatestPriceByModelFamily(modelName.trim());
+  } catch (error) {
+    getRequestLogger().error("[ModelPrice] Failed to query fuzzy price by model", {
+      modelName,
+      error: error instanceof Error ? error.message : String(error),
+    });
+    return null;
+  }
+}
+
+/** 以归一化后首段(模型家族,如 "claude" / "gpt")缩小候选范围,再选出模糊匹配结果 */
+async function findLatestPriceByModelFamily(modelName: string): Promise<ModelPrice | null> {
+  const family = normalizeModelName(modelName).split("-")[0];
+  if (!family) return null;
+
+  const familyPattern = `${escapeLike(family)}%`;
+  const query = sql`
+    SELECT * FROM (
+      SELECT DISTINCT ON (model_name)
+        id,
+        model_name as "modelName",
+        price_data as "priceData",
+        source,
+        created_at as "createdAt",
+        updated_at as "updatedAt"
+      FROM model_prices
+      WHERE LOWER(model_name) LIKE ${familyPattern} ESCAPE '\\'
+      ORDER BY
+        model_name,
+        (source = 'manual') DESC,
+        created_at DESC NULLS LAST,
+        id DESC
+    ) latest
+    ORDER BY ("source" = 'manual') DESC, "createdAt" DESC NULLS LAST, id DESC
+  `;
+
+  const result = await readDb.execute(query);
+  const prices = Array.from(result).map(toModelPrice);
+  const matchedName = pickFuzzyModelNameMatch(modelName, prices.map((p) => p.modelName));
+  return prices.find((price) => price.modelName === matchedName) ?? null;
+}
+
 export async function findLatestPriceByModelAndSource(
   modelName: string,
   source: ModelPriceSource
@@ -140,7 +202,7 @@ export async function findLatestPriceByModelAndSource(
     if (!price) return null;
     return toModelPrice(price);
   } catch (error) {
-    logger.error("[ModelPrice] Failed to query latest price by model and source", {
+    getRequestLogger().error("[ModelPrice] Failed to query latest price by model and source", {
       modelName,
       source,
       error: error instanceof Error ? error.message : String(error),
@@ -149,6 +211,51 @@ export async function findLatestPriceByModelAndSource(
   }
 }
 
+const PRICE_HISTORY_DEFAULT_LIMIT = 20;
+const PRICE_HISTORY_MAX_LIMIT = 100;
+
+/**
+ * 获取指定模型的价格历史（按创建时间倒序），用于审计价格何时发生变化。
+ * 读取追加写入的 model_price_history（model_prices 为替换语义，只保留当前价格）；
+ * 返回记录的 id 为历史记录 id，updatedAt 与 createdAt 相同。
+ * 配合 diffPriceData 比较相邻两条记录即可得到每次变更的字段。
+ */
+export async function findPriceHistoryByModel(
+  modelName: string,
+  limit: number = PRICE_HISTORY_DEFAULT_LIMIT
+): Promise<ModelPrice[]> {
+  const name = modelName.trim();
+  if (!name) return [];
+
+  const safeLimit = Number.isFinite(limit)
+    ? Math.min(Math.max(Math.trunc(limit), 1), PRICE_HISTORY_MAX_LIMIT)
+    : PRICE_HISTORY_DEFAULT_LIMIT;
+
+  try {
+    const rows = await readDb
+      .select({
+        id: modelPriceHistory.id,
+        modelName: modelPriceHistory.modelName,
+        priceData: modelPriceHistory.priceData,
+        source: modelPriceHistory.source,
+        createdAt: modelPriceHistory.createdAt,
+        updatedAt: modelPriceHistory.createdAt,
+      })
+      .from(modelPriceHistory)
+      .where(eq(modelPriceHistory.modelName, name))
+      .orderBy(sql`${modelPriceHistory.createdAt} DESC NULLS LAST`, desc(modelPriceHistory.id))
+      .limit(safeLimit);
+
+    return rows.map(toModelPrice);
+  } catch (error) {
+    getRequestLogger().error("[ModelPrice] Failed to query price history by model", {
+      modelName: name,
+      error: error instanceof Error ? error.message : String(error),
+    });
+    return [];
+  }
+}
+
 export async function findLatestPricesByModels(
   modelNames: string[]
 ): Promise<Map<string, ModelPrice>> {
@@ -174,7 +281,7 @@ export async function findLatestPricesByModels(
       id DESC
   `;
 
-  const result = await db.execute(query);
+  const result = await readDb.execute(query);
   const rows = Array.from(result).map(toModelPrice);
   return new Map(rows.map((row) => [row.modelName, row]));
 }
@@ -247,7 +354,7 @@ export async function findAllLatestPricesPaginated(
     ${whereCondition}
   `;
 
-  const [countResult] = await db.execute(countQuery);
+  const [countResult] = await readDb.execute(countQuery);
   const total = Number(countResult.total);
 
   // 获取分页数据
@@ -274,7 +381,7 @@ export async function findAllLatestPricesPaginated(
     LIMIT ${pageSize} OFFSET ${offset}
   `;
 
-  const result = await db.execute(dataQuery);
+  const result = await readDb.execute(dataQuery);
   const data = Array.from(result).map(toModelPrice);
 
   return {
@@ -304,23 +411,26 @@ export async function createModelPrice(
   priceData: ModelPriceData,
   source: ModelPriceSource = "litellm"
 ): Promise<ModelPrice> {
-  const [price] = await db
-    .insert(modelPrices)
-    .values({
-      modelName: modelName,
-      priceData: priceData,
-      source: source,
-    })
-    .returning({
-      id: modelPrices.id,
-      modelName: modelPrices.modelName,
-      priceData: modelPrices.priceData,
-      source: modelPrices.source,
-      createdAt: modelPrices.createdAt,
-      updatedAt: modelPrices.updatedAt,
-    });
+  return await db.transaction(async (tx) => {
+    const [price] = await tx
+      .insert(modelPrices)
+      .values({
+        modelName: modelName,
+        priceData: priceData,
+        source: source,
+      })
+      .returning({
+        id: modelPrices.id,
+        modelName: modelPrices.modelName,
+        priceData: modelPrices.priceData,
+        source: modelPrices.source,
+        createdAt: modelPrices.createdAt,
+        updatedAt: modelPrices.updatedAt,
+      });
+    await tx.insert(modelPriceHistory).values({ modelName, priceData, source });
 
-  return toModelPrice(price);
+    return toModelPrice(price);
+  });
 }
 
 /**
@@ -345,6 +455,8 @@ export async function upsertModelPrice(
         source: source,
       })
       .returning();
+    // 同一事务内追加价格历史（model_prices 只保留当前价格）
+    await tx.insert(modelPriceHistory).values({ modelName, priceData, source });
     return toModelPrice(price);
   });
 }
@@ -416,6 +528,57 @@ export async function countCloudModelPrices(): Promise<number> {
   return Number((row as { total?: unknown })?.total ?? 0);
 }
 
+/** 批量写入时每条 INSERT 的最大行数（每行 3 个参数，远低于 PG 65535 参数上限） */
+const BULK_UPSERT_CHUNK_SIZE = 1000;
+
 /**
- * 批量创建价格记录
+ * 批量更新或插入模型价格（价格表导入）
+ *
+ * 语义与 upsertModelPrice 一致：同名模型的旧记录先删除再插入新记录。
+ * model_name 无唯一约束，无法使用 ON CONFLICT，因此在单个事务内按块执行
+ * "查询已存在名称 → 批量删除 → 多行插入"。同一批次内重复的模型名以最后一条为准。
+ * @returns inserted 为新增模型数，updated 为替换了已有记录的模型数
  */
+export async function upsertModelPricesBatch(
+  prices: Array<{ modelName: string; priceData: ModelPriceData }>,
+  source: ModelPriceSource = "manual"
+): Promise<{ inserted: number; updated: number }> {
+  const byModelName = new Map<string, ModelPriceData>();
+  for (const price of prices) {
+    byModelName.set(price.modelName, price.priceData);
+  }
+  if (byModelName.size === 0) {
+    return { inserted: 0, updated: 0 };
+  }
+
+  const entries = Array.from(byModelName.entries());
+
+  return await db.transaction(async (tx) => {
+    let inserted = 0;
+    let updated = 0;
+
+    for (let offset = 0; offset < entries.length; offset += BULK_UPSERT_CHUNK_SIZE) {
+      const chunk = entries.slice(offset, offset + BULK_UPSERT_CHUNK_SIZE);
+      const modelNames = chunk.map(([modelName]) => modelName);
+
+      const existing = await tx
+        .selectDistinct({ modelName: modelPrices.modelName })
+        .from(modelPrices)
+        .where(inArray(modelPrices.modelName, modelNames));
+
+      if (existing.length > 0) {
+        const existingNames = existing.map((row) => row.modelName);
+        await tx.delete(modelPrices).where(inArray(modelPrices.modelName, existingNames));
+      }
+
+      const rows = chunk.map(([modelName, priceData]) => ({ modelName, priceData, source }));
+      await tx.insert(modelPrices).values(rows);
+      await tx.insert(modelPriceHistory).values(rows);
+
+      updated += existing.length;
+      inserted += chunk.length - existing.length;
+    }
+
+    return { inserted, updated };
+  });
+}
@@ -1,6 +1,6 @@
 "use server";
 
-import { eq } from "drizzle-orm";
+import { asc, eq } from "drizzle-orm";
 import { db } from "@/drizzle/db";
 import { notificationSettings } from "@/drizzle/schema";
 import { logger } from "@/lib/logger";
@@ -258,12 +258,19 @@ function createFallbackSettings(): NotificationSettings {
   };
 }
 
+/** 单例设置行的固定主键 */
+const SETTINGS_SINGLETON_ID = 1;
+
 /**
  * 获取通知设置，如果不存在则创建默认记录
  */
 export async function getNotificationSettings(): Promise<NotificationSettings> {
   try {
-    const [settings] = await db.select().from(notificationSettings).limit(1);
+    const [settings] = await db
+      .select()
+      .from(notificationSettings)
+      .orderBy(asc(notificationSettings.id))
+      .limit(1);
 
     if (settings) {
       return {
@@ -278,10 +285,11 @@ export async function getNotificationSettings(): Promise<NotificationSettings> {
       };
     }
 
-    // 创建默认设置
+    // 创建默认设置：固定主键，并发初始化时由主键冲突保证只有一行
     const [created] = await db
       .insert(notificationSettings)
       .values({
+        id: SETTINGS_SINGLETON_ID,
         enabled: false,
         circuitBreakerEnabled: false,
         dailyLeaderboardEnabled: false,
@@ -319,7 +327,11 @@ export async function getNotificationSettings(): Promise<NotificationSettings> {
     }
 
     // 如果并发导致没有返回，重新查询一次
-    const [fallback] = await db.select().from(notificationSettings).limit(1);
+    const [fallback] = await db
+      .select()
+      .from(notificationSettings)
+      .orderBy(asc(notificationSettings.id))
+      .limit(1);
 
     if (!fallback) {
       throw new Error("Failed to initialize notification settings");
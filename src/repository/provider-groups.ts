@@ -1,13 +1,20 @@
 import "server-only";
 
-import { asc, eq, inArray, isNull, sql } from "drizzle-orm";
+import { and, asc, eq, inArray, isNull, sql } from "drizzle-orm";
 import { db } from "@/drizzle/db";
-import { providerGroups, providers } from "@/drizzle/schema";
+import { keys, providerGroups, providers, users } from "@/drizzle/schema";
+import { invalidateCacheEntry, registerCacheInvalidationHandler } from "@/lib/cache/invalidation";
 import { PROVIDER_GROUP } from "@/lib/constants/provider.constants";
-import { parseProviderGroups } from "@/lib/utils/provider-group";
+import { invalidateCachedKey, invalidateCachedUser } from "@/lib/security/api-key-auth-cache";
+import {
+  normalizeProviderGroup,
+  parseProviderGroups,
+  replaceProviderGroupInList,
+} from "@/lib/utils/provider-group";
 import type {
   CreateProviderGroupInput,
   ProviderGroup,
+  ReassignProviderGroupResult,
   UpdateProviderGroupInput,
 } from "@/types/provider-group";
 
@@ -44,12 +51,18 @@ interface CacheEntry {
 
 const multiplierCache = new Map<string, CacheEntry>();
 
+const GROUP_MULTIPLIER_CACHE_NAME = "provider_group_multiplier";
+
+registerCacheInvalidationHandler(GROUP_MULTIPLIER_CACHE_NAME, () => multiplierCache.clear());
+
 /**
- * Invalidate the in-memory cost multiplier cache.
+ * Invalidate the in-memory cost multiplier cache on this process and, when
+ * Redis is available, on every other instance via the shared invalidation bus.
  * Call this after any mutation (create / update / delete) to provider groups.
  */
 export function invalidateGroupMultiplierCache(): void {
   multiplierCache.clear();
+  void invalidateCacheEntry(GROUP_MULTIPLIER_CACHE_NAME);
 }
 
 // ---------------------------------------------------------------------------
@@ -198,6 +211,97 @@ export async function ensureProviderGroupsExist(names: string[]): Promise<void>
   invalidateGroupMultiplierCache();
 }
 
+/**
+ * 将 users.providerGroup / keys.providerGroup / providers.groupTag 中的 from 分组
+ * 整体替换为 to（to 为空表示直接移除），用于下线分组前迁移引用。
+ *
+ * 三张表在同一事务内更新，保留每行中的其他分组；未删除的行才会被处理。
+ * users/keys 替换后按 normalizeProviderGroup 规范化（为空回退 default），
+ * providers 保留原有顺序（为空置 null）。
+ */
+export async function reassignProviderGroup(
+  from: string,
+  to: string
+): Promise<ReassignProviderGroupResult> {
+  const source = from.trim();
+  const target = to.trim();
+  if (!source || source === target) {
+    return { usersUpdated: 0, keysUpdated: 0, providersUpdated: 0 };
+  }
+
+  const now = new Date();
+  const { userIds, keyStrings, providersUpdated } = await db.transaction(async (tx) => {
+    // strpos 只做粗筛，精确匹配由 parseProviderGroups 保证（避免 "a" 命中 "ab"）
+    const userRows = await tx
+      .select({ id: users.id, providerGroup: users.providerGroup })
+      .from(users)
+      .where(and(isNull(users.deletedAt), sql`strpos(${users.providerGroup}, ${source}) > 0`));
+    const updatedUserIds: number[] = [];
+    for (const row of userRows) {
+      if (!parseProviderGroups(row.providerGroup).includes(source)) continue;
+      await tx
+        .update(users)
+        .set({
+          providerGroup: normalizeProviderGroup(
+            replaceProviderGroupInList(row.providerGroup, source, target)
+          ),
+          updatedAt: now,
+        })
+        .where(eq(users.id, row.id));
+      updatedUserIds.push(row.id);
+    }
+
+    const keyRows = await tx
+      .select({ id: keys.id, key: keys.key, providerGroup: keys.providerGroup })
+      .from(keys)
+      .where(and(isNull(keys.deletedAt), sql`strpos(${keys.providerGroup}, ${source}) > 0`));
+    const updatedKeyStrings: string[] = [];
+    for (const row of keyRows) {
+      if (!parseProviderGroups(row.providerGroup).includes(source)) continue;
+      await tx
+        .update(keys)
+        .set({
+          providerGroup: normalizeProviderGroup(
+            replaceProviderGroupInList(row.providerGroup, source, target)
+          ),
+          updatedAt: now,
+        })
+        .where(eq(keys.id, row.id));
+      updatedKeyStrings.push(row.key);
+    }
+
+    const providerRows = await tx
+      .select({ id: providers.id, groupTag: providers.groupTag })
+      .from(providers)
+      .where(and(isNull(providers.deletedAt), sql`strpos(${providers.groupTag}, ${source}) > 0`));
+    let updatedProviders = 0;
+    for (const row of providerRows) {
+      if (!parseProviderGroups(row.groupTag).includes(source)) continue;
+      await tx
+        .update(providers)
+        .set({
+          groupTag: replaceProviderGroupInList(row.groupTag, source, target),
+          updatedAt: now,
+        })
+        .where(eq(providers.id, row.id));
+      updatedProviders++;
+    }
+
+    return {
+      userIds: updatedUserIds,
+      keyStrings: updatedKeyStrings,
+      providersUpdated: updatedProviders,
+    };
+  });
+
+  await Promise.all([
+    ...userIds.map((id) => invalidateCachedUser(id).catch(() => {})),
+    ...keyStrings.map((key) => invalidateCachedKey(key).catch(() => {})),
+  ]);
+
+  return { usersUpdated: userIds.length, keysUpdated: keyStrings.length, providersUpdated };
+}
+
 /**
  * Delete a provider group by id.
  * Throws an error when attempting to delete the "default" group.
@@ -239,8 +343,8 @@ export async function deleteProviderGroup(id: number): Promise<void> {
  * Cache misses (value === 1.0 because no matching row was found) are NOT
  * cached, so newly-created groups propagate on the next request.
  *
- * Note: this cache is per-process. In multi-instance deployments, a mutation
- * on one node will not invalidate other nodes' caches; worst-case staleness
+ * Note: this cache is per-process. Mutations broadcast an invalidation over
+ * Redis Pub/Sub; when Redis is unavailable, other nodes' worst-case staleness
  * is bounded by CACHE_TTL_MS.
  */
 export async function getGroupCostMultiplier(rawGroupString: string): Promise<number> {
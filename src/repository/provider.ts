@@ -1,25 +1,30 @@
 import "server-only";
 
-import { and, desc, eq, inArray, isNotNull, isNull, ne, sql } from "drizzle-orm";
+import { and, count, desc, eq, inArray, isNotNull, isNull, ne, sql } from "drizzle-orm";
 import { db } from "@/drizzle/db";
 import { providerEndpoints, providers } from "@/drizzle/schema";
 import { normalizeAllowedModelRules } from "@/lib/allowed-model-rules";
 import { getCachedProviders } from "@/lib/cache/provider-cache";
+import { TTLMap } from "@/lib/cache/ttl-map";
 import { PROVIDER_TIMEOUT_DEFAULTS } from "@/lib/constants/provider.constants";
 import { resetEndpointCircuit } from "@/lib/endpoint-circuit-breaker";
 import { logger } from "@/lib/logger";
 import { normalizeProviderModelRedirectRules } from "@/lib/provider-model-redirects";
 import { parseProviderGroups } from "@/lib/utils/provider-group";
 import { resolveSystemTimezone } from "@/lib/utils/timezone";
+import type { AuditLogInput } from "@/types/audit-log";
 import type {
   AllowedModelRuleInput,
   AnthropicAdaptiveThinkingConfig,
   CreateProviderData,
   Provider,
   ProviderModelRedirectRule,
+  ProviderType,
   UpdateProviderData,
 } from "@/types/provider";
+import { executeStatisticsQuery } from "./_shared/statistics-query-timeout";
 import { toProvider } from "./_shared/transformers";
+import { insertAuditLogInTransaction } from "./audit-log";
 import {
   ensureProviderEndpointExistsForUrl,
   getOrCreateProviderVendorIdFromUrls,
@@ -549,6 +554,155 @@ export async function findAllProviders(): Promise<Provider[]> {
   return getCachedProviders(findAllProvidersFresh);
 }
 
+/**
+ * 按分组标签查找供应商
+ *
+ * groupTag 以逗号分隔存储多个标签（如 "pool-a,pool-b"），拆分后逐项精确匹配，
+ * 与 getDistinctProviderGroups 的拆分语义一致。排序与供应商选择一致：
+ * priority 数值越小越优先，同优先级按 weight 降序。
+ *
+ * 同一分组可能混合多种供应商类型，传入 providerType 时仅返回该类型的供应商，
+ * 以便只考虑能服务当前入口格式的供应商。
+ */
+export async function findProvidersByGroupTag(
+  tag: string,
+  options: { enabledOnly?: boolean; providerType?: ProviderType } = {}
+): Promise<Provider[]> {
+  const trimmedTag = tag.trim();
+  if (!trimmedTag) return [];
+
+  const result = await db
+    .select({
+      id: providers.id,
+      name: providers.name,
+      url: providers.url,
+      key: providers.key,
+      providerVendorId: providers.providerVendorId,
+      isEnabled: providers.isEnabled,
+      weight: providers.weight,
+      priority: providers.priority,
+      groupPriorities: providers.groupPriorities,
+      costMultiplier: providers.costMultiplier,
+      groupTag: providers.groupTag,
+      providerType: providers.providerType,
+      preserveClientIp: providers.preserveClientIp,
+      disableSessionReuse: providers.disableSessionReuse,
+      modelRedirects: providers.modelRedirects,
+      allowedModels: providers.allowedModels,
+      allowedClients: providers.allowedClients,
+      blockedClients: providers.blockedClients,
+      activeTimeStart: providers.activeTimeStart,
+      activeTimeEnd: providers.activeTimeEnd,
+      mcpPassthroughType: providers.mcpPassthroughType,
+      mcpPassthroughUrl: providers.mcpPassthroughUrl,
+      limit5hUsd: providers.limit5hUsd,
+      limit5hResetMode: providers.limit5hResetMode,
+      limitDailyUsd: providers.limitDailyUsd,
+      dailyResetMode: providers.dailyResetMode,
+      dailyResetTime: providers.dailyResetTime,
+      limitWeeklyUsd: providers.limitWeeklyUsd,
+      limitMonthlyUsd: providers.limitMonthlyUsd,
+      limitTotalUsd: providers.limitTotalUsd,
+      totalCostResetAt: providers.totalCostResetAt,
+      limitConcurrentSessions: providers.limitConcurrentSessions,
+      maxRetryAttempts: providers.maxRetryAttempts,
+      circuitBreakerFailureThreshold: providers.circuitBreakerFailureThreshold,
+      circuitBreakerOpenDuration: providers.circuitBreakerOpenDuration,
+      circuitBreakerHalfOpenSuccessThreshold: providers.circuitBreakerHalfOpenSuccessThreshold,
+      proxyUrl: providers.proxyUrl,
+      proxyFallbackToDirect: providers.proxyFallbackToDirect,
+      customHeaders: providers.customHeaders,
+      firstByteTimeoutStreamingMs: providers.firstByteTimeoutStreamingMs,
+      streamingIdleTimeoutMs: providers.streamingIdleTimeoutMs,
+      requestTimeoutNonStreamingMs: providers.requestTimeoutNonStreamingMs,
+      websiteUrl: providers.websiteUrl,
+      faviconUrl: providers.faviconUrl,
+      cacheTtlPreference: providers.cacheTtlPreference,
+      swapCacheTtlBilling: providers.swapCacheTtlBilling,
+      context1mPreference: providers.context1mPreference,
+      codexReasoningEffortPreference: providers.codexReasoningEffortPreference,
+      codexReasoningSummaryPreference: providers.codexReasoningSummaryPreference,
+      codexTextVerbosityPreference: providers.codexTextVerbosityPreference,
+      codexParallelToolCallsPreference: providers.codexParallelToolCallsPreference,
+      codexImageGenerationPreference: providers.codexImageGenerationPreference,
+      codexServiceTierPreference: providers.codexServiceTierPreference,
+      anthropicMaxTokensPreference: providers.anthropicMaxTokensPreference,
+      anthropicThinkingBudgetPreference: providers.anthropicThinkingBudgetPreference,
+      anthropicAdaptiveThinking: providers.anthropicAdaptiveThinking,
+      geminiGoogleSearchPreference: providers.geminiGoogleSearchPreference,
+      tpm: providers.tpm,
+      rpm: providers.rpm,
+      rpd: providers.rpd,
+      cc: providers.cc,
+      createdAt: providers.createdAt,
+      updatedAt: providers.updatedAt,
+      deletedAt: providers.deletedAt,
+    })
+    .from(providers)
+    .where(
+      and(
+        isNull(providers.deletedAt),
+        options.enabledOnly ? eq(providers.isEnabled, true) : undefined,
+        options.providerType ? eq(providers.providerType, options.providerType) : undefined,
+        sql`${trimmedTag} = ANY(regexp_split_to_array(coalesce(${providers.groupTag}, ''), '\\s*[,，\n\r]+\\s*'))`
+      )
+    )
+    .orderBy(providers.priority, desc(providers.weight), providers.id);
+
+  return result.map((provider) => normalizeProviderRuntimeFields(toProvider(provider)));
+}
+
+/**
+ * 按分组标签查找已启用供应商（走进程级缓存）
+ *
+ * 直接在 findAllProviders 的全量缓存上按标签过滤，不再单独按标签缓存，
+ * 避免调用方传入的任意标签撑大缓存；拆分与排序规则与 findProvidersByGroupTag 一致。
+ */
+export async function findEnabledProvidersByGroupTag(
+  tag: string,
+  providerType?: ProviderType
+): Promise<Provider[]> {
+  const trimmedTag = tag.trim();
+  if (!trimmedTag) return [];
+
+  const allProviders = await findAllProviders();
+
+  return allProviders
+    .filter(
+      (p) =>
+        p.isEnabled &&
+        (!providerType || p.providerType === providerType) &&
+        parseProviderGroups(p.groupTag).includes(trimmedTag)
+    )
+    .sort((a, b) => a.priority - b.priority || b.weight - a.weight || a.id - b.id)
+    .map((p) => normalizeProviderRuntimeFields(p));
+}
+
+/**
+ * 按名称查找未删除的供应商（用于重名检测，返回冲突记录以便定位）
+ *
+ * @param excludeId - 编辑时排除供应商自身
+ */
+export async function findProviderByNameExcluding(
+  name: string,
+  excludeId?: number
+): Promise<Provider | null> {
+  const [row] = await db
+    .select({ id: providers.id })
+    .from(providers)
+    .where(
+      and(
+        eq(providers.name, name),
+        isNull(providers.deletedAt),
+        excludeId !== undefined ? ne(providers.id, excludeId) : undefined
+      )
+    )
+    .orderBy(providers.id)
+    .limit(1);
+
+  return row ? findProviderById(row.id) : null;
+}
+
 export async function findProviderById(id: number): Promise<Provider | null> {
   const [provider] = await db
     .select({
@@ -624,6 +778,100 @@ export async function findProviderById(id: number): Promise<Provider | null> {
   return normalizeProviderRuntimeFields(toProvider(provider));
 }
 
+/**
+ * 批量按 ID 查询供应商，返回以 id 为键的 Map
+ *
+ * @param includeDeleted - 是否包含已软删除的供应商（历史日志仍需解析供应商名称）
+ */
+export async function findProvidersByIds(
+  ids: number[],
+  includeDeleted = false
+): Promise<Map<number, Provider>> {
+  const uniqueIds = Array.from(new Set(ids));
+  if (uniqueIds.length === 0) return new Map();
+
+  const result = await db
+    .select({
+      id: providers.id,
+      name: providers.name,
+      url: providers.url,
+      key: providers.key,
+      providerVendorId: providers.providerVendorId,
+      isEnabled: providers.isEnabled,
+      weight: providers.weight,
+      priority: providers.priority,
+      groupPriorities: providers.groupPriorities,
+      costMultiplier: providers.costMultiplier,
+      groupTag: providers.groupTag,
+      providerType: providers.providerType,
+      preserveClientIp: providers.preserveClientIp,
+      disableSessionReuse: providers.disableSessionReuse,
+      modelRedirects: providers.modelRedirects,
+      allowedModels: providers.allowedModels,
+      allowedClients: providers.allowedClients,
+      blockedClients: providers.blockedClients,
+      activeTimeStart: providers.activeTimeStart,
+      activeTimeEnd: providers.activeTimeEnd,
+      mcpPassthroughType: providers.mcpPassthroughType,
+      mcpPassthroughUrl: providers.mcpPassthroughUrl,
+      limit5hUsd: providers.limit5hUsd,
+      limit5hResetMode: providers.limit5hResetMode,
+      limitDailyUsd: providers.limitDailyUsd,
+      dailyResetMode: providers.dailyResetMode,
+      dailyResetTime: providers.dailyResetTime,
+      limitWeeklyUsd: providers.limitWeeklyUsd,
+      limitMonthlyUsd: providers.limitMonthlyUsd,
+      limitTotalUsd: providers.limitTotalUsd,
+      totalCostResetAt: providers.totalCostResetAt,
+      limitConcurrentSessions: providers.limitConcurrentSessions,
+      maxRetryAttempts: providers.maxRetryAttempts,
+      circuitBreakerFailureThreshold: providers.circuitBreakerFailureThreshold,
+      circuitBreakerOpenDuration: providers.circuitBreakerOpenDuration,
+      circuitBreakerHalfOpenSuccessThreshold: providers.circuitBreakerHalfOpenSuccessThreshold,
+      proxyUrl: providers.proxyUrl,
+      proxyFallbackToDirect: providers.proxyFallbackToDirect,
+      customHeaders: providers.customHeaders,
+      firstByteTimeoutStreamingMs: providers.firstByteTimeoutStreamingMs,
+      streamingIdleTimeoutMs: providers.streamingIdleTimeoutMs,
+      requestTimeoutNonStreamingMs: providers.requestTimeoutNonStreamingMs,
+      websiteUrl: providers.websiteUrl,
+      faviconUrl: providers.faviconUrl,
+      cacheTtlPreference: providers.cacheTtlPreference,
+      swapCacheTtlBilling: providers.swapCacheTtlBilling,
+      context1mPreference: providers.context1mPreference,
+      codexReasoningEffortPreference: providers.codexReasoningEffortPreference,
+      codexReasoningSummaryPreference: providers.codexReasoningSummaryPreference,
+      codexTextVerbosityPreference: providers.codexTextVerbosityPreference,
+      codexParallelToolCallsPreference: providers.codexParallelToolCallsPreference,
+      codexImageGenerationPreference: providers.codexImageGenerationPreference,
+      codexServiceTierPreference: providers.codexServiceTierPreference,
+      anthropicMaxTokensPreference: providers.anthropicMaxTokensPreference,
+      anthropicThinkingBudgetPreference: providers.anthropicThinkingBudgetPreference,
+      anthropicAdaptiveThinking: providers.anthropicAdaptiveThinking,
+      geminiGoogleSearchPreference: providers.geminiGoogleSearchPreference,
+      tpm: providers.tpm,
+      rpm: providers.rpm,
+      rpd: providers.rpd,
+      cc: providers.cc,
+      createdAt: providers.createdAt,
+      updatedAt: providers.updatedAt,
+      deletedAt: providers.deletedAt,
+    })
+    .from(providers)
+    .where(
+      includeDeleted
+        ? inArray(providers.id, uniqueIds)
+        : and(inArray(providers.id, uniqueIds), isNull(providers.deletedAt))
+    );
+
+  return new Map(
+    result.map((row) => {
+      const provider = normalizeProviderRuntimeFields(toProvider(row));
+      return [provider.id, provider] as const;
+    })
+  );
+}
+
 export async function updateProvider(
   id: number,
   providerData: UpdateProviderData
@@ -1515,23 +1763,43 @@ export async function restoreProvidersBatch(ids: number[]): Promise<number> {
   return restoredCount;
 }
 
+/**
+ * 统计已启用且未删除的供应商数量（与 enabledOnly 查询条件一致）
+ */
+export async function countActiveProviders(): Promise<number> {
+  const [row] = await db
+    .select({ count: count() })
+    .from(providers)
+    .where(and(isNull(providers.deletedAt), eq(providers.isEnabled, true)));
+
+  return Number(row?.count || 0);
+}
+
 /**
  * 手动重置供应商"总消费"统计起点
  *
  * 说明：
  * - 不删除 message_request 历史记录，仅通过 resetAt 作为聚合下限实现“从 0 重新累计”。
+ * - 传入 audit 时，审计记录在同一事务内写入。
  */
 export async function resetProviderTotalCostResetAt(
   providerId: number,
-  resetAt: Date
+  resetAt: Date,
+  audit?: AuditLogInput
 ): Promise<boolean> {
-  const result = await db
-    .update(providers)
-    .set({ totalCostResetAt: resetAt, updatedAt: new Date() })
-    .where(and(eq(providers.id, providerId), isNull(providers.deletedAt)))
-    .returning({ id: providers.id });
+  return db.transaction(async (tx) => {
+    const result = await tx
+      .update(providers)
+      .set({ totalCostResetAt: resetAt, updatedAt: new Date() })
+      .where(and(eq(providers.id, providerId), isNull(providers.deletedAt)))
+      .returning({ id: providers.id });
 
-  return result.length > 0;
+    // 审计记录与重置标记同事务写入：任一失败则整体回滚
+    if (result.length > 0 && audit) {
+      await insertAuditLogInTransaction(tx, audit);
+    }
+    return result.length > 0;
+  });
 }
 
 /**
@@ -1564,55 +1832,75 @@ export async function getDistinctProviderGroups(): Promise<string[]> {
 
 /**
  * 获取所有供应商的统计信息
- * 包括：今天的总金额、今天的调用次数、最近一次调用时间和模型
+ * 包括：今天的总金额、今天的调用次数、今天的 P50/P95/P99 耗时、最近一次调用时间和模型
  *
  * 性能优化：
  * - provider_stats: 先按最终供应商聚合，再与 providers 做 LEFT JOIN，避免 providers × usage_ledger 的笛卡尔积
  * - bounds: 用“按时区计算的时间范围”过滤 created_at，便于命中 created_at 索引
  * - DST 兼容：对“本地日界/近 7 日”先在 timestamp 上做 +interval，再 AT TIME ZONE 回到 timestamptz，避免夏令时跨日偏移
  * - latest_call: 限制近 7 天范围，避免扫描历史数据
+ * - 耗时分位数：按 final_provider_id 归属（即 provider_chain 中最终实际服务的供应商），
+ *   duration_ms 为空的记录（报错/拦截）不参与计算，而非按 0 处理；当日无有效耗时则为 null
+ * - groupTag：非空时仅统计该分组下的供应商（逗号分隔拆分后精确匹配，与 findProvidersByGroupTag 一致），
+ *   并在聚合前按 final_provider_id 过滤，缩小大规模供应商场景下的聚合范围
  */
 export type ProviderStatisticsRow = {
   id: number;
   today_cost: string;
   today_calls: number;
+  p50_duration_ms: number | null;
+  p95_duration_ms: number | null;
+  p99_duration_ms: number | null;
   last_call_time: Date | null;
   last_call_model: string | null;
 };
 
 // 轻量内存缓存：降低后台轮询/重复加载导致的重复扫描
+// 按“时区 + 分组标签”分别缓存，避免不同分组交替查询时互相驱逐；条目数有上限
 const PROVIDER_STATISTICS_CACHE_TTL_MS = 10 * 1000; // 10 秒
-let providerStatisticsCache: {
-  timezone: string;
-  expiresAt: number;
-  data: ProviderStatisticsRow[];
-} | null = null;
+const providerStatisticsCache = new TTLMap<string, ProviderStatisticsRow[]>({
+  ttlMs: PROVIDER_STATISTICS_CACHE_TTL_MS,
+  maxSize: 100,
+});
 
 // in-flight 去重：避免缓存过期瞬间并发触发多次相同查询（thundering herd）
-let providerStatisticsInFlight: {
-  timezone: string;
-  promise: Promise<ProviderStatisticsRow[]>;
-} | null = null;
+const providerStatisticsInFlight = new Map<string, Promise<ProviderStatisticsRow[]>>();
+
+// percentile_cont 返回 double precision，驱动可能给出字符串；统一为毫秒整数或 null
+function normalizeDurationPercentile(value: unknown): number | null {
+  if (value === null || value === undefined) return null;
+  const parsed = Number(value);
+  return Number.isFinite(parsed) ? Math.round(parsed) : null;
+}
 
-export async function getProviderStatistics(): Promise<ProviderStatisticsRow[]> {
+export async function getProviderStatistics(groupTag = ""): Promise<ProviderStatisticsRow[]> {
   try {
     // 统一的时区处理：使用 PostgreSQL AT TIME ZONE + 系统时区配置
     // 参考 getUserStatisticsFromDB 的实现，避免 Node.js Date 带来的时区偏移
     const timezone = await resolveSystemTimezone();
-    const now = Date.now();
-    if (
-      providerStatisticsCache &&
-      providerStatisticsCache.expiresAt > now &&
-      providerStatisticsCache.timezone === timezone
-    ) {
-      return providerStatisticsCache.data;
+    const trimmedTag = groupTag.trim();
+    const cacheKey = `${timezone}\u0000${trimmedTag}`;
+    const cached = providerStatisticsCache.get(cacheKey);
+    if (cached) {
+      return cached;
     }
 
-    if (providerStatisticsInFlight && providerStatisticsInFlight.timezone === timezone) {
-      return await providerStatisticsInFlight.promise;
+    const inFlight = providerStatisticsInFlight.get(cacheKey);
+    if (inFlight) {
+      return await inFlight;
     }
 
     const promise: Promise<ProviderStatisticsRow[]> = (async () => {
+      const groupTagCondition = trimmedTag
+        ? sql`AND ${trimmedTag} = ANY(regexp_split_to_array(coalesce(p.group_tag, ''), '\\s*[,，\n\r]+\\s*'))`
+        : sql``;
+      // 分组过滤时先限定供应商集合，再聚合 usage_ledger
+      const ledgerProviderCondition = trimmedTag
+        ? sql`AND final_provider_id IN (
+            SELECT p.id FROM providers p WHERE p.deleted_at IS NULL ${groupTagCondition}
+          )`
+        : sql``;
+
       const query = sql`
          WITH bounds AS (
            SELECT
@@ -1625,11 +1913,18 @@ export async function getProviderStatistics(): Promise<ProviderStatisticsRow[]>
            SELECT
             final_provider_id,
             COALESCE(SUM(cost_usd), 0) AS today_cost,
-            COUNT(*)::integer AS today_calls
+            COUNT(*)::integer AS today_calls,
+            percentile_cont(0.5) WITHIN GROUP (ORDER BY duration_ms)
+              FILTER (WHERE duration_ms IS NOT NULL) AS p50_duration_ms,
+            percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_ms)
+              FILTER (WHERE duration_ms IS NOT NULL) AS p95_duration_ms,
+            percentile_cont(0.99) WITHIN GROUP (ORDER BY duration_ms)
+              FILTER (WHERE duration_ms IS NOT NULL) AS p99_duration_ms
           FROM usage_ledger
           WHERE blocked_by IS NULL
             AND created_at >= (SELECT today_start FROM bounds)
             AND created_at < (SELECT tomorrow_start FROM bounds)
+            ${ledgerProviderCondition}
           GROUP BY final_provider_id
         ),
         latest_call AS (
@@ -1640,6 +1935,7 @@ export async function getProviderStatistics(): Promise<ProviderStatisticsRow[]>
           FROM usage_ledger
           WHERE blocked_by IS NULL
             AND created_at >= (SELECT last7_start FROM bounds)
+            ${ledgerProviderCondition}
           -- 性能优化：添加 7 天时间范围限制（避免扫描历史数据）
           ORDER BY final_provider_id, created_at DESC, id DESC
         )
@@ -1647,19 +1943,28 @@ export async function getProviderStatistics(): Promise<ProviderStatisticsRow[]>
           p.id,
           COALESCE(ps.today_cost, 0) AS today_cost,
           COALESCE(ps.today_calls, 0) AS today_calls,
+          ps.p50_duration_ms,
+          ps.p95_duration_ms,
+          ps.p99_duration_ms,
           lc.last_call_time,
           lc.last_call_model
         FROM providers p
         LEFT JOIN provider_stats ps ON p.id = ps.final_provider_id
         LEFT JOIN latest_call lc ON p.id = lc.final_provider_id
         WHERE p.deleted_at IS NULL
+          ${groupTagCondition}
         ORDER BY p.id ASC
       `;
 
-      logger.trace("getProviderStatistics:executing_query");
+      logger.trace("getProviderStatistics:executing_query", { groupTag: trimmedTag || null });
 
-      const result = await db.execute(query);
-      const data = Array.from(result) as ProviderStatisticsRow[];
+      const result = await executeStatisticsQuery(query);
+      const data = (Array.from(result) as ProviderStatisticsRow[]).map((row) => ({
+        ...row,
+        p50_duration_ms: normalizeDurationPercentile(row.p50_duration_ms),
+        p95_duration_ms: normalizeDurationPercentile(row.p95_duration_ms),
+        p99_duration_ms: normalizeDurationPercentile(row.p99_duration_ms),
+      }));
 
       logger.trace("getProviderStatistics:result", {
         count: data.length,
@@ -1668,23 +1973,19 @@ export async function getProviderStatistics(): Promise<ProviderStatisticsRow[]>
       // 注意：返回结果中的 today_cost 为 numeric，使用字符串表示；
       // last_call_time 由数据库返回为时间戳（UTC）。
       // 这里保持原样，交由上层进行展示格式化。
-      providerStatisticsCache = {
-        timezone,
-        expiresAt: Date.now() + PROVIDER_STATISTICS_CACHE_TTL_MS,
-        data,
-      };
+      providerStatisticsCache.set(cacheKey, data);
 
       return data;
     })();
 
     // Set in-flight BEFORE awaiting to prevent concurrent callers from starting duplicate queries
-    providerStatisticsInFlight = { timezone, promise };
+    providerStatisticsInFlight.set(cacheKey, promise);
 
     try {
       return await promise;
     } finally {
-      if (providerStatisticsInFlight?.promise === promise) {
-        providerStatisticsInFlight = null;
+      if (providerStatisticsInFlight.get(cacheKey) === promise) {
+        providerStatisticsInFlight.delete(cacheKey);
       }
     }
   } catch (error) {
@@ -1695,3 +1996,77 @@ export async function getProviderStatistics(): Promise<ProviderStatisticsRow[]>
     throw error;
   }
 }
+
+/**
+ * 供应商健康快照：供管理后台轮询的单次查询
+ *
+ * - recent*: 最近 1 小时按最终供应商（final_provider_id）归属的请求，排除 warmup/被拦截请求
+ * - errorRate: (4xx + 5xx) / 总请求数；最近 1 小时无请求时为 null
+ * - lastSuccessTime: 近 7 天内最近一次成功请求时间（限制范围避免扫描历史数据）
+ */
+export interface ProviderHealthSnapshot {
+  id: number;
+  isEnabled: boolean;
+  recentCalls: number;
+  recentErrors: number;
+  errorRate: number | null;
+  lastSuccessTime: Date | null;
+}
+
+type ProviderHealthSnapshotRow = {
+  id: number;
+  is_enabled: boolean | null;
+  recent_calls: number | string | null;
+  recent_errors: number | string | null;
+  last_success_time: Date | string | null;
+};
+
+export async function getProviderHealthSnapshot(): Promise<ProviderHealthSnapshot[]> {
+  const query = sql`
+    WITH recent_stats AS (
+      SELECT
+        final_provider_id,
+        COUNT(*)::integer AS recent_calls,
+        COUNT(*) FILTER (WHERE status_code >= 400)::integer AS recent_errors
+      FROM usage_ledger
+      WHERE blocked_by IS NULL
+        AND created_at >= CURRENT_TIMESTAMP - INTERVAL '1 hour'
+      GROUP BY final_provider_id
+    ),
+    last_success AS (
+      SELECT
+        final_provider_id,
+        MAX(created_at) AS last_success_time
+      FROM usage_ledger
+      WHERE blocked_by IS NULL
+        AND is_success = true
+        AND created_at >= CURRENT_TIMESTAMP - INTERVAL '7 days'
+      GROUP BY final_provider_id
+    )
+    SELECT
+      p.id,
+      p.is_enabled,
+      COALESCE(rs.recent_calls, 0) AS recent_calls,
+      COALESCE(rs.recent_errors, 0) AS recent_errors,
+      ls.last_success_time
+    FROM providers p
+    LEFT JOIN recent_stats rs ON p.id = rs.final_provider_id
+    LEFT JOIN last_success ls ON p.id = ls.final_provider_id
+    WHERE p.deleted_at IS NULL
+    ORDER BY p.id ASC
+  `;
+
+  const result = await db.execute(query);
+  return (Array.from(result) as ProviderHealthSnapshotRow[]).map((row) => {
+    const recentCalls = Number(row.recent_calls ?? 0);
+    const recentErrors = Number(row.recent_errors ?? 0);
+    return {
+      id: row.id,
+      isEnabled: row.is_enabled ?? false,
+      recentCalls,
+      recentErrors,
+      errorRate: recentCalls > 0 ? recentErrors / recentCalls : null,
+      lastSuccessTime: row.last_success_time ? new Date(row.last_success_time) : null,
+    };
+  });
+}
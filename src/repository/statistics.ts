@@ -2,23 +2,34 @@ import "server-only";
 
 import { fromZonedTime } from "date-fns-tz";
 import type { SQL } from "drizzle-orm";
-import { and, eq, gte, inArray, isNull, lt, sql } from "drizzle-orm";
+import { and, desc, eq, gt, gte, inArray, isNull, lt, sql } from "drizzle-orm";
 import { db } from "@/drizzle/db";
-import { keys, messageRequest, usageLedger } from "@/drizzle/schema";
+import { readDb } from "@/drizzle/read-db";
+import { keys, messageRequest, providers, usageLedger } from "@/drizzle/schema";
 import { TTLMap } from "@/lib/cache/ttl-map";
+import { resolveRateLimitMetadata } from "@/lib/rate-limit/metadata";
 import { resolveSystemTimezone } from "@/lib/utils/timezone";
 import type {
   DatabaseKey,
   DatabaseKeyStatRow,
   DatabaseStatRow,
   DatabaseUser,
+  DatabaseUserTotalRow,
   RateLimitEventFilters,
   RateLimitEventStats,
   RateLimitType,
   TimeRange,
 } from "@/types/statistics";
-import { LEDGER_BILLING_CONDITION } from "./_shared/ledger-conditions";
-import { EXCLUDE_WARMUP_CONDITION } from "./_shared/message-request-conditions";
+import { LEDGER_ACTIVE_CONDITION, LEDGER_BILLING_CONDITION } from "./_shared/ledger-conditions";
+import {
+  EXCLUDE_WARMUP_CONDITION,
+  WARMUP_ONLY_CONDITION,
+} from "./_shared/message-request-conditions";
+import {
+  executeStatisticsQuery,
+  runStatisticsQueryGroup,
+} from "./_shared/statistics-query-timeout";
+import { getSystemSettings } from "./system-config";
 
 /**
  * Key ID -> key string cache
@@ -181,7 +192,7 @@ function normalizeTotalCost(value: string | number | null): string | number {
 
 async function getTimeBuckets(timeRange: TimeRange, timezone: string): Promise<Date[]> {
   const { bucketSeriesQuery } = getTimeRangeSqlConfig(timeRange, timezone);
-  const result = await db.execute(bucketSeriesQuery);
+  const result = await readDb.execute(bucketSeriesQuery);
   return (Array.from(result) as Array<{ bucket: TimeBucketValue }>)
     .map((row) => normalizeBucketInstant(row.bucket, timezone))
     .filter((bucket): bucket is Date => bucket !== null)
@@ -320,13 +331,132 @@ export async function getUserStatisticsFromDB(
   const [users, buckets, statsResult] = await Promise.all([
     getActiveUsersFromDB(),
     getTimeBuckets(timeRange, timezone),
-    db.execute(statsQuery),
+    executeStatisticsQuery(statsQuery),
   ]);
 
   const rows = Array.from(statsResult) as UserBucketStatsRow[];
   return zeroFillUserStats(rows, users, buckets, timezone) as unknown as DatabaseStatRow[];
 }
 
+/**
+ * 按用户 id 键集分页获取用户统计时间线（CSV 导出等流式场景使用）
+ *
+ * 每页只查询 id > afterUserId 的前 limit 个有效用户，结果按用户 id、时间桶升序排列并补零；
+ * nextAfterUserId 为 null 表示已无更多用户。
+ */
+export async function getUserStatisticsPageFromDB(
+  timeRange: TimeRange,
+  page: { afterUserId?: number | null; limit: number },
+  timezoneOverride?: string
+): Promise<{ rows: DatabaseStatRow[]; nextAfterUserId: number | null }> {
+  const timezone = timezoneOverride ?? (await resolveSystemTimezone());
+  const { startTs, endTs, bucketExpr } = getTimeRangeSqlConfig(timeRange, timezone);
+  const limit = Math.max(1, Math.floor(page.limit));
+  const afterUserId = page.afterUserId ?? 0;
+
+  // 无调用的用户也会返回一行（bucket 为 NULL），用于确定本页用户集合
+  const statsQuery = sql`
+    WITH page_users AS (
+      SELECT id, name
+      FROM users
+      WHERE deleted_at IS NULL
+        AND id > ${afterUserId}
+      ORDER BY id ASC
+      LIMIT ${limit}
+    )
+    SELECT
+      u.id AS user_id,
+      u.name AS user_name,
+      ${bucketExpr} AS bucket,
+      COUNT(usage_ledger.id) AS api_calls,
+      COALESCE(SUM(usage_ledger.cost_usd), 0) AS total_cost
+    FROM page_users u
+    LEFT JOIN usage_ledger ON u.id = usage_ledger.user_id
+      AND usage_ledger.created_at >= ${startTs}
+      AND usage_ledger.created_at < ${endTs}
+      AND ${LEDGER_BILLING_CONDITION}
+    GROUP BY u.id, u.name, bucket
+    ORDER BY u.id ASC
+  `;
+
+  const [buckets, statsResult] = await Promise.all([
+    getTimeBuckets(timeRange, timezone),
+    executeStatisticsQuery(statsQuery),
+  ]);
+
+  const rowMap = new Map<string, { api_calls: number; total_cost: string | number }>();
+  const pageUsers = new Map<number, string>();
+  for (const row of Array.from(statsResult) as UserBucketStatsRow[]) {
+    const userId = Number(row.user_id);
+    pageUsers.set(userId, row.user_name);
+
+    const bucket = normalizeBucketInstant(row.bucket, timezone);
+    if (!bucket) continue;
+    rowMap.set(`${userId}:${bucket.getTime()}`, {
+      api_calls: normalizeApiCalls(row.api_calls),
+      total_cost: normalizeTotalCost(row.total_cost),
+    });
+  }
+
+  const userIds = [...pageUsers.keys()].sort((a, b) => a - b);
+  const rows: RuntimeDatabaseStatRow[] = [];
+  for (const userId of userIds) {
+    for (const bucket of buckets) {
+      const row = rowMap.get(`${userId}:${bucket.getTime()}`);
+      rows.push({
+        user_id: userId,
+        user_name: pageUsers.get(userId) ?? "",
+        date: new Date(bucket.getTime()),
+        api_calls: row?.api_calls ?? 0,
+        total_cost: row?.total_cost ?? 0,
+      });
+    }
+  }
+
+  return {
+    rows: rows as unknown as DatabaseStatRow[],
+    nextAfterUserId: userIds.length < limit ? null : userIds[userIds.length - 1],
+  };
+}
+
+/**
+ * 根据时间范围获取每个用户的调用次数与消费合计
+ *
+ * 不展开时间桶（无 generate_series / 补零），适用于排行榜、汇总卡片等只需总量的场景。
+ * 区间内无调用的用户同样返回，api_calls 与 total_cost 为 0。
+ */
+export async function getUserTotalsFromDB(
+  timeRange: TimeRange,
+  timezoneOverride?: string
+): Promise<DatabaseUserTotalRow[]> {
+  const timezone = timezoneOverride ?? (await resolveSystemTimezone());
+  const { startTs, endTs } = getTimeRangeSqlConfig(timeRange, timezone);
+
+  const query = sql`
+    SELECT
+      u.id AS user_id,
+      u.name AS user_name,
+      COUNT(usage_ledger.id) AS api_calls,
+      COALESCE(SUM(usage_ledger.cost_usd), 0) AS total_cost
+    FROM users u
+    LEFT JOIN usage_ledger ON u.id = usage_ledger.user_id
+      AND usage_ledger.created_at >= ${startTs}
+      AND usage_ledger.created_at < ${endTs}
+      AND ${LEDGER_BILLING_CONDITION}
+    WHERE u.deleted_at IS NULL
+    GROUP BY u.id, u.name
+    ORDER BY u.name ASC
+  `;
+
+  const result = await executeStatisticsQuery(query);
+  return (Array.from(result) as Omit<UserBucketStatsRow, "bucket">[]).map((row) => ({
+    user_id: row.user_id,
+    user_name: row.user_name,
+    api_calls: normalizeApiCalls(row.api_calls),
+    total_cost: normalizeTotalCost(row.total_cost),
+  }));
+}
+
 /**
  * 获取所有活跃用户列表
  */
@@ -338,20 +468,51 @@ export async function getActiveUsersFromDB(): Promise<DatabaseUser[]> {
     ORDER BY name ASC
   `;
 
-  const result = await db.execute(query);
+  const result = await readDb.execute(query);
   return Array.from(result) as unknown as DatabaseUser[];
 }
 
+/**
+ * 密钥统计的数据来源
+ *
+ * 默认直接读取 usage_ledger 并按计费条件过滤（warmup 不进入 ledger）。
+ * includeWarmup 时改为 ledger 计费行 UNION ALL message_request 中的 warmup 行，
+ * 派生表沿用 usage_ledger 别名以复用 bucketExpr；warmup 行 cost_usd 为 NULL，只计调用次数。
+ */
+function buildKeyStatsLedgerSource(includeWarmup: boolean): { source: SQL; condition: SQL } {
+  if (!includeWarmup) {
+    return { source: sql`usage_ledger`, condition: LEDGER_BILLING_CONDITION };
+  }
+
+  return {
+    source: sql`(
+      SELECT id, key, user_id, created_at, cost_usd
+      FROM usage_ledger
+      WHERE ${LEDGER_BILLING_CONDITION}
+      UNION ALL
+      SELECT id, key, user_id, created_at, NULL::numeric AS cost_usd
+      FROM message_request
+      WHERE ${WARMUP_ONLY_CONDITION}
+        AND deleted_at IS NULL
+    ) AS usage_ledger`,
+    condition: sql`TRUE`,
+  };
+}
+
 /**
  * 获取指定用户的密钥使用统计
+ *
+ * includeWarmup=true 时 warmup 请求计入 api_calls，但不计入 total_cost；默认排除。
  */
 export async function getKeyStatisticsFromDB(
   userId: number,
   timeRange: TimeRange,
-  timezoneOverride?: string
+  timezoneOverride?: string,
+  options: { includeWarmup?: boolean } = {}
 ): Promise<DatabaseKeyStatRow[]> {
   const timezone = timezoneOverride ?? (await resolveSystemTimezone());
   const { startTs, endTs, bucketExpr } = getTimeRangeSqlConfig(timeRange, timezone);
+  const ledger = buildKeyStatsLedgerSource(options.includeWarmup ?? false);
 
   const statsQuery = sql`
     SELECT
@@ -361,11 +522,11 @@ export async function getKeyStatisticsFromDB(
       COUNT(usage_ledger.id) AS api_calls,
       COALESCE(SUM(usage_ledger.cost_usd), 0) AS total_cost
     FROM keys k
-    LEFT JOIN usage_ledger ON usage_ledger.key = k.key
+    LEFT JOIN ${ledger.source} ON usage_ledger.key = k.key
       AND usage_ledger.user_id = ${userId}
       AND usage_ledger.created_at >= ${startTs}
       AND usage_ledger.created_at < ${endTs}
-      AND ${LEDGER_BILLING_CONDITION}
+      AND ${ledger.condition}
     WHERE k.user_id = ${userId}
       AND k.deleted_at IS NULL
     GROUP BY k.id, k.name, bucket
@@ -375,7 +536,7 @@ export async function getKeyStatisticsFromDB(
   const [activeKeys, buckets, statsResult] = await Promise.all([
     getActiveKeysForUserFromDB(userId),
     getTimeBuckets(timeRange, timezone),
-    db.execute(statsQuery),
+    executeStatisticsQuery(statsQuery),
   ]);
 
   const rows = Array.from(statsResult) as KeyBucketStatsRow[];
@@ -394,18 +555,22 @@ export async function getActiveKeysForUserFromDB(userId: number): Promise<Databa
     ORDER BY name ASC
   `;
 
-  const result = await db.execute(query);
+  const result = await readDb.execute(query);
   return Array.from(result) as unknown as DatabaseKey[];
 }
 
 /**
  * 获取混合统计数据：当前用户的密钥明细 + 其他用户的汇总
  * 用于非 admin 用户在 allowGlobalUsageView=true 时的数据展示
+ *
+ * 子查询并行执行：任一失败即中止其余尚未发出的查询；传入 signal 且被中止
+ * （如客户端断开）时立即以 AbortError 返回
  */
 export async function getMixedStatisticsFromDB(
   userId: number,
   timeRange: TimeRange,
-  timezoneOverride?: string
+  timezoneOverride?: string,
+  options: { signal?: AbortSignal } = {}
 ): Promise<{
   ownKeys: DatabaseKeyStatRow[];
   othersAggregate: DatabaseStatRow[];
@@ -446,12 +611,15 @@ export async function getMixedStatisticsFromDB(
     ORDER BY bucket ASC
   `;
 
-  const [activeKeys, buckets, ownKeysResult, othersResult] = await Promise.all([
-    getActiveKeysForUserFromDB(userId),
-    getTimeBuckets(timeRange, timezone),
-    db.execute(ownKeysQuery),
-    db.execute(othersQuery),
-  ]);
+  const [activeKeys, buckets, ownKeysResult, othersResult] = await runStatisticsQueryGroup(
+    [
+      () => getActiveKeysForUserFromDB(userId),
+      () => getTimeBuckets(timeRange, timezone),
+      (signal) => executeStatisticsQuery(ownKeysQuery, signal),
+      (signal) => executeStatisticsQuery(othersQuery, signal),
+    ] as const,
+    options.signal
+  );
 
   return {
     ownKeys: zeroFillKeyStats(
@@ -468,6 +636,261 @@ export async function getMixedStatisticsFromDB(
   };
 }
 
+export interface ModelCostBreakdownRow {
+  model: string;
+  apiCalls: number;
+  totalCost: number;
+  totalTokens: number;
+}
+
+/**
+ * 全局模型消费排行：按模型聚合所有用户在时间范围内的调用次数、消费与 token 总量，按消费降序
+ *
+ * @param useOriginalModel - 是否按 original_model（重定向前）归类；未指定时遵循系统设置 billingModelSource
+ */
+export async function getModelCostBreakdownFromDB(
+  timeRange: TimeRange,
+  timezoneOverride?: string,
+  useOriginalModel?: boolean
+): Promise<ModelCostBreakdownRow[]> {
+  const timezone = timezoneOverride ?? (await resolveSystemTimezone());
+  const { startTs, endTs } = getTimeRangeSqlConfig(timeRange, timezone);
+
+  const preferOriginal =
+    useOriginalModel ?? ((await getSystemSettings()).billingModelSource === "original");
+  const rawModelField = preferOriginal
+    ? sql<string>`COALESCE(${usageLedger.originalModel}, ${usageLedger.model})`
+    : sql<string>`COALESCE(${usageLedger.model}, ${usageLedger.originalModel})`;
+  const modelField = sql<string>`NULLIF(TRIM(${rawModelField}), '')`;
+
+  const rows = await readDb
+    .select({
+      model: modelField,
+      apiCalls: sql<number>`count(*)::int`,
+      totalCost: sql<string>`COALESCE(sum(${usageLedger.costUsd}), 0)`,
+      totalTokens: sql<number>`COALESCE(sum(
+        COALESCE(${usageLedger.inputTokens}, 0) +
+        COALESCE(${usageLedger.outputTokens}, 0) +
+        COALESCE(${usageLedger.cacheCreationInputTokens}, 0) +
+        COALESCE(${usageLedger.cacheReadInputTokens}, 0)
+      )::double precision, 0)`,
+    })
+    .from(usageLedger)
+    .where(
+      and(
+        sql`${usageLedger.createdAt} >= ${startTs}`,
+        sql`${usageLedger.createdAt} < ${endTs}`,
+        sql`${modelField} IS NOT NULL`,
+        LEDGER_BILLING_CONDITION
+      )
+    )
+    .groupBy(modelField)
+    .orderBy(desc(sql`sum(${usageLedger.costUsd})`), modelField);
+
+  return rows.map((row) => ({
+    model: row.model,
+    apiCalls: Number(row.apiCalls || 0),
+    totalCost: Number(row.totalCost || 0),
+    totalTokens: Number(row.totalTokens || 0),
+  }));
+}
+
+export interface EndpointStatRow {
+  endpoint: string;
+  apiCalls: number;
+  errorCount: number;
+  totalCost: number;
+}
+
+/**
+ * 按请求端点（/v1/messages、/v1/chat/completions、/v1/responses 等）聚合调用次数、
+ * 错误次数（status_code >= 400）与消费，按调用次数降序
+ *
+ * Warmup 请求不会进入 usage_ledger，这里只排除被阻断的请求；不使用计费条件 LEDGER_ACTIVE_CONDITION，
+ * 以免把 count_tokens / compact 等非计费端点从流量分布中剔除
+ */
+export async function getEndpointStatsFromDB(
+  timeRange: TimeRange,
+  timezoneOverride?: string
+): Promise<EndpointStatRow[]> {
+  const timezone = timezoneOverride ?? (await resolveSystemTimezone());
+  const { startTs, endTs } = getTimeRangeSqlConfig(timeRange, timezone);
+  const endpointField = sql<string>`NULLIF(TRIM(${usageLedger.endpoint}), '')`;
+
+  const rows = await readDb
+    .select({
+      endpoint: endpointField,
+      apiCalls: sql<number>`count(*)::int`,
+      errorCount: sql<number>`count(*) FILTER (WHERE ${usageLedger.statusCode} >= 400)::int`,
+      totalCost: sql<string>`COALESCE(sum(${usageLedger.costUsd}), 0)`,
+    })
+    .from(usageLedger)
+    .where(
+      and(
+        sql`${usageLedger.createdAt} >= ${startTs}`,
+        sql`${usageLedger.createdAt} < ${endTs}`,
+        sql`${endpointField} IS NOT NULL`,
+        isNull(usageLedger.blockedBy)
+      )
+    )
+    .groupBy(endpointField)
+    .orderBy(desc(sql`count(*)`), endpointField);
+
+  return rows.map((row) => ({
+    endpoint: row.endpoint,
+    apiCalls: Number(row.apiCalls || 0),
+    errorCount: Number(row.errorCount || 0),
+    totalCost: Number(row.totalCost || 0),
+  }));
+}
+
+export interface ModelLatencyRow {
+  model: string;
+  sampleCount: number;
+  p50TtfbMs: number | null;
+  p95TtfbMs: number | null;
+}
+
+// percentile_cont 返回 double precision，驱动可能给出字符串；统一为毫秒整数或 null
+function normalizePercentileMs(value: unknown): number | null {
+  if (value === null || value === undefined) return null;
+  const parsed = Number(value);
+  return Number.isFinite(parsed) ? Math.round(parsed) : null;
+}
+
+/**
+ * 按模型统计首字节时间（TTFB）p50/p95，用于对比各模型的流式响应速度
+ *
+ * 仅统计 ttfb_ms 非空的请求（非流式或失败请求没有 TTFB），sampleCount 为参与统计的样本数，
+ * 按样本数降序
+ */
+export async function getModelTtfbStatsFromDB(
+  timeRange: TimeRange,
+  timezoneOverride?: string
+): Promise<ModelLatencyRow[]> {
+  const timezone = timezoneOverride ?? (await resolveSystemTimezone());
+  const { startTs, endTs } = getTimeRangeSqlConfig(timeRange, timezone);
+  const modelField = sql<string>`NULLIF(TRIM(COALESCE(${usageLedger.model}, ${usageLedger.originalModel})), '')`;
+
+  const rows = await readDb
+    .select({
+      model: modelField,
+      sampleCount: sql<number>`count(*)::int`,
+      p50TtfbMs: sql<number | string | null>`percentile_cont(0.5) WITHIN GROUP (ORDER BY ${usageLedger.ttfbMs})`,
+      p95TtfbMs: sql<number | string | null>`percentile_cont(0.95) WITHIN GROUP (ORDER BY ${usageLedger.ttfbMs})`,
+    })
+    .from(usageLedger)
+    .where(
+      and(
+        sql`${usageLedger.createdAt} >= ${startTs}`,
+        sql`${usageLedger.createdAt} < ${endTs}`,
+        sql`${usageLedger.ttfbMs} IS NOT NULL`,
+        sql`${modelField} IS NOT NULL`,
+        LEDGER_ACTIVE_CONDITION
+      )
+    )
+    .groupBy(modelField)
+    .orderBy(desc(sql`count(*)`), modelField);
+
+  return rows.map((row) => ({
+    model: row.model,
+    sampleCount: Number(row.sampleCount || 0),
+    p50TtfbMs: normalizePercentileMs(row.p50TtfbMs),
+    p95TtfbMs: normalizePercentileMs(row.p95TtfbMs),
+  }));
+}
+
+/**
+ * 按拦截来源（blocked_by）统计被拦截的请求数，用于查看各防护规则的拦截量
+ *
+ * 被拦截请求不会写入 usage_ledger（warmup 在触发器层即被过滤），因此直接查询 message_request；
+ * 与成本统计不同，这里刻意包含 warmup
+ */
+export async function getBlockedStatsFromDB(
+  timeRange: TimeRange,
+  timezoneOverride?: string
+): Promise<Record<string, number>> {
+  const timezone = timezoneOverride ?? (await resolveSystemTimezone());
+  const { startTs, endTs } = getTimeRangeSqlConfig(timeRange, timezone);
+
+  const rows = await readDb
+    .select({
+      blockedBy: messageRequest.blockedBy,
+      count: sql<number>`count(*)::int`,
+    })
+    .from(messageRequest)
+    .where(
+      and(
+        sql`${messageRequest.createdAt} >= ${startTs}`,
+        sql`${messageRequest.createdAt} < ${endTs}`,
+        sql`${messageRequest.blockedBy} IS NOT NULL`,
+        isNull(messageRequest.deletedAt)
+      )
+    )
+    .groupBy(messageRequest.blockedBy);
+
+  const result: Record<string, number> = {};
+  for (const row of rows) {
+    if (row.blockedBy) {
+      result[row.blockedBy] = Number(row.count || 0);
+    }
+  }
+  return result;
+}
+
+export interface ProviderCostRow {
+  providerId: number;
+  providerName: string;
+  totalCost: number;
+  apiCalls: number;
+}
+
+/**
+ * 供应商消费排行：按最终供应商（final_provider_id，即 provider_chain 最后一项）归属，
+ * 重试请求计入实际提供服务的供应商，按消费降序取前 topN
+ */
+export async function getProviderCostLeaderboardFromDB(
+  timeRange: TimeRange,
+  topN: number,
+  timezoneOverride?: string
+): Promise<ProviderCostRow[]> {
+  const limit = Math.floor(topN);
+  if (!Number.isFinite(limit) || limit <= 0) return [];
+
+  const timezone = timezoneOverride ?? (await resolveSystemTimezone());
+  const { startTs, endTs } = getTimeRangeSqlConfig(timeRange, timezone);
+
+  const rows = await readDb
+    .select({
+      providerId: usageLedger.finalProviderId,
+      providerName: providers.name,
+      totalCost: sql<string>`COALESCE(sum(${usageLedger.costUsd}), 0)`,
+      apiCalls: sql<number>`count(*)::int`,
+    })
+    .from(usageLedger)
+    .innerJoin(
+      providers,
+      and(sql`${usageLedger.finalProviderId} = ${providers.id}`, isNull(providers.deletedAt))
+    )
+    .where(
+      and(
+        sql`${usageLedger.createdAt} >= ${startTs}`,
+        sql`${usageLedger.createdAt} < ${endTs}`,
+        LEDGER_BILLING_CONDITION
+      )
+    )
+    .groupBy(usageLedger.finalProviderId, providers.name)
+    .orderBy(desc(sql`COALESCE(sum(${usageLedger.costUsd}), 0)`), usageLedger.finalProviderId)
+    .limit(limit);
+
+  return rows.map((row) => ({
+    providerId: row.providerId,
+    providerName: row.providerName,
+    totalCost: Number(row.totalCost || 0),
+    apiCalls: Number(row.apiCalls || 0),
+  }));
+}
+
 /**
  * 查询用户今日总消费（所有 Key 的消费总和）
  * 用于用户层每日限额检查（Redis 降级）
@@ -757,12 +1180,22 @@ export async function sumUserCostInTimeRange(
 /**
  * 查询 Key 在指定时间范围内的消费总和
  * 用于 Key 层限额检查（Redis 降级）
+ *
+ * @param clipStart - 可选的消费重置时间（如 costResetAt）。实际下限取 max(startTime, clipStart)，
+ *   避免告警/额度展示计入重置前的消费；晚于 endTime 时直接返回 0
  */
 export async function sumKeyCostInTimeRange(
   keyId: number,
   startTime: Date,
-  endTime: Date
+  endTime: Date,
+  clipStart?: Date | null
 ): Promise<number> {
+  let effectiveStart = startTime;
+  if (clipStart instanceof Date && !Number.isNaN(clipStart.getTime()) && clipStart > startTime) {
+    effectiveStart = clipStart;
+  }
+  if (effectiveStart >= endTime) return 0;
+
   const keyString = await getKeyStringByIdCached(keyId);
   if (!keyString) return 0;
 
@@ -772,7 +1205,7 @@ export async function sumKeyCostInTimeRange(
     .where(
       and(
         eq(usageLedger.key, keyString), // 使用 key 字符串而非 ID
-        gte(usageLedger.createdAt, startTime),
+        gte(usageLedger.createdAt, effectiveStart),
         lt(usageLedger.createdAt, endTime),
         LEDGER_BILLING_CONDITION
       )
@@ -781,6 +1214,60 @@ export async function sumKeyCostInTimeRange(
   return Number(result[0]?.total || 0);
 }
 
+/**
+ * 批量查询多个 Key 在指定时间范围内的消费总和（单次 key 解析 + 单次聚合）
+ * 用于用户 Key 列表展示，避免逐个 Key 调用 sumKeyCostInTimeRange 产生 N 次往返
+ *
+ * - 已删除的 Key 不参与解析，与无消费的 Key 一样返回 0
+ * - 计费过滤与 sumKeyCostInTimeRange 保持一致（LEDGER_BILLING_CONDITION）
+ *
+ * @returns Map of keyId -> cost（所有入参 keyId 均存在于结果中）
+ */
+export async function sumKeyCostInTimeRangeByIds(
+  keyIds: number[],
+  startTime: Date,
+  endTime: Date
+): Promise<Map<number, number>> {
+  const result = new Map<number, number>();
+  if (keyIds.length === 0) return result;
+  for (const id of keyIds) result.set(id, 0);
+
+  const keyMappings = await db
+    .select({ id: keys.id, key: keys.key })
+    .from(keys)
+    .where(and(inArray(keys.id, keyIds), isNull(keys.deletedAt)));
+  if (keyMappings.length === 0) return result;
+
+  const keyStringToId = new Map<string, number>();
+  for (const mapping of keyMappings) {
+    keyStringToId.set(mapping.key, mapping.id);
+    keyStringByIdCache.set(mapping.id, mapping.key);
+  }
+
+  const rows = await db
+    .select({
+      key: usageLedger.key,
+      total: sql<number>`COALESCE(SUM(${usageLedger.costUsd}), 0)`,
+    })
+    .from(usageLedger)
+    .where(
+      and(
+        inArray(usageLedger.key, [...keyStringToId.keys()]),
+        gte(usageLedger.createdAt, startTime),
+        lt(usageLedger.createdAt, endTime),
+        LEDGER_BILLING_CONDITION
+      )
+    )
+    .groupBy(usageLedger.key);
+
+  for (const row of rows) {
+    const keyId = keyStringToId.get(row.key);
+    if (keyId !== undefined) result.set(keyId, Number(row.total || 0));
+  }
+
+  return result;
+}
+
 export interface QuotaCostRanges {
   range5h: { startTime: Date; endTime: Date };
   rangeDaily: { startTime: Date; endTime: Date };
@@ -1024,6 +1511,67 @@ export async function findProviderCostEntriesInTimeRange(
     .filter((row): row is CostEntryInTimeRange => row !== null);
 }
 
+export interface ProviderCostEntriesPage {
+  entries: CostEntryInTimeRange[];
+  /** 下一页游标（本页最后一条记录的 id），null 表示已无更多数据 */
+  nextCursor: number | null;
+}
+
+/**
+ * 按 id 游标分页查询供应商在指定时间范围内的消费明细（用于审计导出）
+ *
+ * 与 findProviderCostEntriesInTimeRange 过滤条件一致（cost_usd > 0、未删除、排除 warmup），
+ * 以返回的 nextCursor 作为下一次的 cursor 循环调用，直到 nextCursor 为 null。
+ *
+ * @param cursor 上一页返回的 nextCursor，首次调用传 null
+ * @param limit 每页数量（1-5000）
+ */
+export async function findProviderCostEntriesPaginated(
+  providerId: number,
+  startTime: Date,
+  endTime: Date,
+  cursor: number | null,
+  limit: number = 1000
+): Promise<ProviderCostEntriesPage> {
+  const pageSize = Math.min(Math.max(1, Math.trunc(limit) || 1), 5000);
+
+  const conditions: SQL[] = [
+    eq(messageRequest.providerId, providerId),
+    gte(messageRequest.createdAt, startTime),
+    lt(messageRequest.createdAt, endTime),
+    sql`${messageRequest.costUsd} > 0`,
+    isNull(messageRequest.deletedAt),
+    EXCLUDE_WARMUP_CONDITION,
+  ];
+  if (cursor !== null) {
+    conditions.push(gt(messageRequest.id, cursor));
+  }
+
+  // 多取一条用于判断是否还有下一页
+  const rows = await readDb
+    .select({
+      id: messageRequest.id,
+      createdAt: messageRequest.createdAt,
+      costUsd: messageRequest.costUsd,
+    })
+    .from(messageRequest)
+    .where(and(...conditions))
+    .orderBy(messageRequest.id)
+    .limit(pageSize + 1);
+
+  const hasMore = rows.length > pageSize;
+  const pageRows = hasMore ? rows.slice(0, pageSize) : rows;
+
+  return {
+    entries: pageRows.map((row) => ({
+      id: row.id,
+      createdAt: row.createdAt as Date,
+      costUsd: Number(row.costUsd || 0),
+    })),
+    nextCursor: hasMore ? pageRows[pageRows.length - 1].id : null,
+  };
+}
+
 /**
  * 查询 Key 在指定时间范围内的消费明细（用于滚动窗口 Redis 恢复）
  */
@@ -1064,7 +1612,7 @@ export async function findKeyCostEntriesInTimeRange(
 
 /**
  * 获取限流事件统计数据
- * 查询 message_request 表中包含 rate_limit_metadata 的错误记录
+ * 优先读取 message_request.rate_limit_metadata 列；该列上线前的旧记录回退解析 error_message
  *
  * @param filters - 过滤条件
  * @returns 聚合统计数据，包含 6 个维度的指标
@@ -1076,7 +1624,10 @@ export async function getRateLimitEventStats(
   const { user_id, provider_id, limit_type, start_time, end_time, key_id } = filters;
 
   const conditions: SQL[] = [
-    sql`${messageRequest.errorMessage} LIKE ${"%rate_limit_metadata%"}`,
+    sql`(
+      ${messageRequest.rateLimitMetadata} IS NOT NULL
+      OR ${messageRequest.errorMessage} LIKE ${"%rate_limit_metadata%"}
+    )`,
     isNull(messageRequest.deletedAt),
   ];
 
@@ -1125,18 +1676,20 @@ export async function getRateLimitEventStats(
       ${messageRequest.userId},
       ${messageRequest.providerId},
       ${messageRequest.errorMessage},
+      ${messageRequest.rateLimitMetadata},
       DATE_TRUNC('hour', ${messageRequest.createdAt} AT TIME ZONE ${timezone}) AS hour
     FROM ${messageRequest}
     WHERE ${and(...conditions)}
     ORDER BY ${messageRequest.createdAt}
   `;
 
-  const result = await db.execute(query);
+  const result = await readDb.execute(query);
   const rows = Array.from(result) as Array<{
     id: number;
     user_id: number;
     provider_id: number;
-    error_message: string;
+    error_message: string | null;
+    rate_limit_metadata: unknown;
     hour: Date;
   }>;
 
@@ -1145,36 +1698,31 @@ export async function getRateLimitEventStats(
   const eventsByUser: Record<number, number> = {};
   const eventsByProvider: Record<number, number> = {};
   const eventsByHour: Record<string, number> = {};
+  let totalEvents = 0;
   let totalCurrentUsage = 0;
   let usageCount = 0;
 
   // 处理每条记录
   for (const row of rows) {
-    // 解析 rate_limit_metadata JSON
-    const metadataMatch = row.error_message.match(/rate_limit_metadata:\s*(\{[^}]+\})/);
-    if (!metadataMatch) {
-      continue;
-    }
-
-    let metadata: { limit_type?: string; current?: number };
-    try {
-      metadata = JSON.parse(metadataMatch[1]);
-    } catch {
+    const metadata = resolveRateLimitMetadata({
+      rateLimitMetadata: row.rate_limit_metadata,
+      errorMessage: row.error_message,
+    });
+    if (!metadata) {
       continue;
     }
 
     const rowLimitType = metadata.limit_type;
-    const currentUsage = metadata.current;
 
     // 如果指定了 limit_type 过滤，跳过不匹配的记录
     if (limit_type && rowLimitType !== limit_type) {
       continue;
     }
 
+    totalEvents++;
+
     // 按类型统计
-    if (rowLimitType) {
-      eventsByType[rowLimitType] = (eventsByType[rowLimitType] || 0) + 1;
-    }
+    eventsByType[rowLimitType] = (eventsByType[rowLimitType] || 0) + 1;
 
     // 按用户统计
     eventsByUser[row.user_id] = (eventsByUser[row.user_id] || 0) + 1;
@@ -1187,10 +1735,8 @@ export async function getRateLimitEventStats(
     eventsByHour[hourKey] = (eventsByHour[hourKey] || 0) + 1;
 
     // 累计当前使用量
-    if (typeof currentUsage === "number") {
-      totalCurrentUsage += currentUsage;
-      usageCount++;
-    }
+    totalCurrentUsage += metadata.current_usage;
+    usageCount++;
   }
 
   // 计算平均当前使用量
@@ -1202,7 +1748,7 @@ export async function getRateLimitEventStats(
     .sort((a, b) => a.hour.localeCompare(b.hour));
 
   return {
-    total_events: rows.length,
+    total_events: totalEvents,
     events_by_type: eventsByType as Record<RateLimitType, number>,
     events_by_user: eventsByUser,
     events_by_provider: eventsByProvider,
@@ -146,6 +146,7 @@ function createFallbackSettings(): SystemSettings {
     siteTitle: DEFAULT_SITE_TITLE,
     allowGlobalUsageView: false,
     currencyDisplay: "USD",
+    currencyDisplayRate: "1",
     billingModelSource: "original",
     codexPriorityBillingSource: "requested",
     billNonSuccessfulRequests: false,
@@ -156,6 +157,7 @@ function createFallbackSettings(): SystemSettings {
     cleanupSchedule: "0 2 * * *",
     cleanupBatchSize: 10000,
     enableClientVersionCheck: false,
+    minClientVersion: null,
     verboseProviderError: false,
     passThroughUpstreamErrorMessage: true,
     enableHttp2: false,
@@ -264,6 +266,18 @@ const RECENT_COLUMN_LADDER: ReadonlyArray<{
   // 本层更新失败（仍有列缺失）时记录的告警
   updateWarn: string;
 }> = [
+  {
+    key: "currencyDisplayRate",
+    column: systemSettings.currencyDisplayRate,
+    selectWarn: "system_settings 表除 currencyDisplayRate 外仍有列缺失，继续回退到上一代字段集。",
+    updateWarn: "system_settings 表除 currencyDisplayRate 外仍有列缺失，继续降级更新。",
+  },
+  {
+    key: "minClientVersion",
+    column: systemSettings.minClientVersion,
+    selectWarn: "system_settings 表除 minClientVersion 外仍有列缺失，继续回退到上一代字段集。",
+    updateWarn: "system_settings 表除 minClientVersion 外仍有列缺失，继续降级更新。",
+  },
   {
     key: "enableGeminiFunctionIdRectifier",
     column: systemSettings.enableGeminiFunctionIdRectifier,
@@ -443,6 +457,9 @@ async function selectSettingsRow() {
   throw new Error("system_settings 降级读取链意外耗尽");
 }
 
+/** 单例设置行的固定主键，并发初始化时由主键冲突保证只插入一行 */
+const SETTINGS_SINGLETON_ID = 1;
+
 /**
  * 获取系统设置，如果不存在则创建默认记录
  */
@@ -458,6 +475,7 @@ export async function getSystemSettings(): Promise<SystemSettings> {
       await db
         .insert(systemSettings)
         .values({
+          id: SETTINGS_SINGLETON_ID,
           siteTitle: DEFAULT_SITE_TITLE,
           allowGlobalUsageView: false,
           currencyDisplay: "USD",
@@ -482,6 +500,7 @@ export async function getSystemSettings(): Promise<SystemSettings> {
       await db
         .insert(systemSettings)
         .values({
+          id: SETTINGS_SINGLETON_ID,
           siteTitle: DEFAULT_SITE_TITLE,
           allowGlobalUsageView: false,
           currencyDisplay: "USD",
@@ -601,6 +620,9 @@ export async function updateSystemSettings(
     if (payload.currencyDisplay !== undefined) {
       updates.currencyDisplay = payload.currencyDisplay;
     }
+    if (payload.currencyDisplayRate !== undefined) {
+      updates.currencyDisplayRate = payload.currencyDisplayRate.toString();
+    }
 
     // 计费模型来源配置字段（如果提供）
     if (payload.billingModelSource !== undefined) {
@@ -643,6 +665,9 @@ export async function updateSystemSettings(
     if (payload.enableClientVersionCheck !== undefined) {
       updates.enableClientVersionCheck = payload.enableClientVersionCheck;
     }
+    if (payload.minClientVersion !== undefined) {
+      updates.minClientVersion = payload.minClientVersion;
+    }
 
     // 供应商错误详情配置字段（如果提供）
     if (payload.verboseProviderError !== undefined) {
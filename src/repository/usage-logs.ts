@@ -1489,18 +1489,24 @@ export async function findUsageLogsWithDetails(filters: UsageLogFilters): Promis
 
 /**
  * 获取所有使用过的模型列表（用于筛选器）
+ *
+ * @param maxAgeDays - 可选，仅统计最近 N 天内出现过的模型；未传或非正数时不限时间
  */
-export async function getUsedModels(): Promise<string[]> {
+export async function getUsedModels(maxAgeDays?: number): Promise<string[]> {
+  const conditions = [
+    isNull(messageRequest.deletedAt),
+    sql`${messageRequest.model} IS NOT NULL`,
+    sql`btrim(${messageRequest.model}) <> ''`,
+  ];
+  if (maxAgeDays !== undefined && Number.isFinite(maxAgeDays) && maxAgeDays > 0) {
+    const since = new Date(Date.now() - Math.floor(maxAgeDays) * 24 * 60 * 60 * 1000);
+    conditions.push(gte(messageRequest.createdAt, since));
+  }
+
   const results = await db
     .selectDistinct({ model: messageRequest.model })
     .from(messageRequest)
-    .where(
-      and(
-        isNull(messageRequest.deletedAt),
-        sql`${messageRequest.model} IS NOT NULL`,
-        sql`btrim(${messageRequest.model}) <> ''`
-      )
-    )
+    .where(and(...conditions))
     .orderBy(messageRequest.model);
 
   return results.map((r) => r.model).filter(isNonBlankString);
@@ -1536,6 +1542,19 @@ export async function getUsedEndpoints(): Promise<string[]> {
   return results.map((r) => r.endpoint).filter((e): e is string => e !== null);
 }
 
+/**
+ * 获取所有出现过的 API 类型列表（用于筛选器），按字母序排列
+ */
+export async function getUsedApiTypes(): Promise<string[]> {
+  const results = await db
+    .selectDistinct({ apiType: messageRequest.apiType })
+    .from(messageRequest)
+    .where(and(isNull(messageRequest.deletedAt), sql`${messageRequest.apiType} IS NOT NULL`))
+    .orderBy(messageRequest.apiType);
+
+  return results.map((r) => r.apiType).filter(isNonBlankString);
+}
+
 export interface UsageLogSessionIdSuggestionFilters {
   term: string;
   userId?: number;
@@ -1,12 +1,27 @@
 "use server";
 
-import { and, asc, eq, isNull, type SQL, sql } from "drizzle-orm";
+import {
+  and,
+  asc,
+  count,
+  eq,
+  gte,
+  inArray,
+  isNotNull,
+  isNull,
+  lte,
+  ne,
+  type SQL,
+  sql,
+} from "drizzle-orm";
 import { db } from "@/drizzle/db";
 import { keys as keysTable, users } from "@/drizzle/schema";
 import { cacheUser, invalidateCachedUser } from "@/lib/security/api-key-auth-cache";
 import { parseProviderGroups } from "@/lib/utils/provider-group";
+import type { AuditLogInput } from "@/types/audit-log";
 import type { CreateUserData, UpdateUserData, User } from "@/types/user";
 import { toUser } from "./_shared/transformers";
+import { insertAuditLogInTransaction } from "./audit-log";
 
 export interface UserListBatchFilters {
   /** Cursor for pagination (JSON-encoded keyset or numeric offset) */
@@ -42,6 +57,15 @@ export interface UserListBatchResult {
   hasMore: boolean;
 }
 
+export interface UserWithKeyCount extends User {
+  /** 有效 Key 数量（已启用且未删除），与 countActiveKeysByUser 口径一致 */
+  activeKeyCount: number;
+}
+
+export interface UserListWithKeyCountsResult extends Omit<UserListBatchResult, "users"> {
+  users: UserWithKeyCount[];
+}
+
 export async function createUser(userData: CreateUserData): Promise<User> {
   const dbData = {
     name: userData.name,
@@ -63,6 +87,7 @@ export async function createUser(userData: CreateUserData): Promise<User> {
     allowedClients: userData.allowedClients ?? [],
     blockedClients: userData.blockedClients ?? [],
     allowedModels: userData.allowedModels ?? [],
+    timezone: userData.timezone ?? null,
   };
 
   const [user] = await db.insert(users).values(dbData).returning({
@@ -92,6 +117,7 @@ export async function createUser(userData: CreateUserData): Promise<User> {
     allowedClients: users.allowedClients,
     blockedClients: users.blockedClients,
     allowedModels: users.allowedModels,
+    timezone: users.timezone,
   });
 
   const created = toUser(user);
@@ -128,6 +154,7 @@ export async function findUserList(limit: number = 50, offset: number = 0): Prom
       allowedClients: users.allowedClients,
       blockedClients: users.blockedClients,
       allowedModels: users.allowedModels,
+      timezone: users.timezone,
     })
     .from(users)
     .where(isNull(users.deletedAt))
@@ -197,6 +224,18 @@ function encodeKeysetCursor(sortValue: unknown, id: number): string {
 export async function findUserListBatch(
   filters: UserListBatchFilters
 ): Promise<UserListBatchResult> {
+  const { rows, nextCursor, hasMore } = await findUserListBatchRows(filters, false);
+  return {
+    users: rows.map(({ activeKeyCount: _activeKeyCount, ...row }) => toUser(row)),
+    nextCursor,
+    hasMore,
+  };
+}
+
+async function findUserListBatchRows(
+  filters: UserListBatchFilters,
+  includeActiveKeyCount: boolean
+) {
   const {
     cursor,
     limit = 50,
@@ -378,6 +417,18 @@ export async function findUserListBatch(
       allowedClients: users.allowedClients,
       blockedClients: users.blockedClients,
       allowedModels: users.allowedModels,
+      timezone: users.timezone,
+      // 有效 Key 数量（已启用且未删除），与 countActiveKeysByUser 口径一致；
+      // 不需要时以常量占位，避免逐行执行相关子查询
+      activeKeyCount: includeActiveKeyCount
+        ? sql<number>`(
+            SELECT count(*)::int
+            FROM ${keysTable}
+            WHERE ${keysTable.userId} = ${users.id}
+              AND ${keysTable.isEnabled} = true
+              AND ${keysTable.deletedAt} IS NULL
+          )`
+        : sql<number>`0`,
     })
     .from(users)
     .where(and(...conditions))
@@ -404,7 +455,28 @@ export async function findUserListBatch(
   }
 
   return {
-    users: usersToReturn.map(toUser),
+    rows: usersToReturn,
+    nextCursor,
+    hasMore,
+  };
+}
+
+/**
+ * 与 findUserListBatch 相同的筛选/排序/分页，并附带每个用户的有效 Key 数量
+ *
+ * Key 数量在同一条查询中通过相关子查询统计（而非逐用户 countActiveKeysByUser），
+ * 没有 Key 的用户计为 0 并照常返回
+ */
+export async function findUserListBatchWithKeyCounts(
+  filters: UserListBatchFilters
+): Promise<UserListWithKeyCountsResult> {
+  const { rows, nextCursor, hasMore } = await findUserListBatchRows(filters, true);
+
+  return {
+    users: rows.map((row) => ({
+      ...toUser(row),
+      activeKeyCount: Number(row.activeKeyCount ?? 0),
+    })),
     nextCursor,
     hasMore,
   };
@@ -439,6 +511,7 @@ export async function findUserById(id: number): Promise<User | null> {
       allowedClients: users.allowedClients,
       blockedClients: users.blockedClients,
       allowedModels: users.allowedModels,
+      timezone: users.timezone,
     })
     .from(users)
     .where(and(eq(users.id, id), isNull(users.deletedAt)));
@@ -448,6 +521,90 @@ export async function findUserById(id: number): Promise<User | null> {
   return toUser(user);
 }
 
+/**
+ * 按名称查找未删除的用户（用于重名检测，返回冲突记录以便定位）
+ *
+ * @param excludeId - 编辑时排除用户自身
+ */
+export async function findUserByNameExcluding(
+  name: string,
+  excludeId?: number
+): Promise<User | null> {
+  const [row] = await db
+    .select({ id: users.id })
+    .from(users)
+    .where(
+      and(
+        eq(users.name, name),
+        isNull(users.deletedAt),
+        excludeId !== undefined ? ne(users.id, excludeId) : undefined
+      )
+    )
+    .orderBy(asc(users.id))
+    .limit(1);
+
+  return row ? findUserById(row.id) : null;
+}
+
+/**
+ * 批量按 ID 查询用户，返回以 id 为键的 Map
+ *
+ * 统计/日志视图中的非真实用户 ID（如 -1 "其他用户"）不会出现在结果中，由调用方自行处理。
+ *
+ * @param includeDeleted - 是否包含已软删除的用户（历史日志仍需解析用户名称）
+ */
+export async function findUsersByIds(
+  ids: number[],
+  includeDeleted = false
+): Promise<Map<number, User>> {
+  const uniqueIds = Array.from(new Set(ids));
+  if (uniqueIds.length === 0) return new Map();
+
+  const result = await db
+    .select({
+      id: users.id,
+      name: users.name,
+      description: users.description,
+      role: users.role,
+      rpm: users.rpmLimit,
+      dailyQuota: users.dailyLimitUsd,
+      providerGroup: users.providerGroup,
+      tags: users.tags,
+      createdAt: users.createdAt,
+      updatedAt: users.updatedAt,
+      deletedAt: users.deletedAt,
+      limit5hUsd: users.limit5hUsd,
+      limit5hResetMode: users.limit5hResetMode,
+      limitWeeklyUsd: users.limitWeeklyUsd,
+      limitMonthlyUsd: users.limitMonthlyUsd,
+      limitTotalUsd: users.limitTotalUsd,
+      costResetAt: users.costResetAt,
+      limit5hCostResetAt: users.limit5hCostResetAt,
+      limitConcurrentSessions: users.limitConcurrentSessions,
+      dailyResetMode: users.dailyResetMode,
+      dailyResetTime: users.dailyResetTime,
+      isEnabled: users.isEnabled,
+      expiresAt: users.expiresAt,
+      allowedClients: users.allowedClients,
+      blockedClients: users.blockedClients,
+      allowedModels: users.allowedModels,
+      timezone: users.timezone,
+    })
+    .from(users)
+    .where(
+      includeDeleted
+        ? inArray(users.id, uniqueIds)
+        : and(inArray(users.id, uniqueIds), isNull(users.deletedAt))
+    );
+
+  return new Map(
+    result.map((row) => {
+      const user = toUser(row);
+      return [user.id, user] as const;
+    })
+  );
+}
+
 export async function updateUser(id: number, userData: UpdateUserData): Promise<User | null> {
   if (Object.keys(userData).length === 0) {
     return findUserById(id);
@@ -474,6 +631,7 @@ export async function updateUser(id: number, userData: UpdateUserData): Promise<
     allowedClients?: string[];
     blockedClients?: string[];
     allowedModels?: string[];
+    timezone?: string | null;
   }
 
   const dbData: UpdateDbData = {
@@ -507,6 +665,7 @@ export async function updateUser(id: number, userData: UpdateUserData): Promise<
   if (userData.allowedClients !== undefined) dbData.allowedClients = userData.allowedClients;
   if (userData.blockedClients !== undefined) dbData.blockedClients = userData.blockedClients;
   if (userData.allowedModels !== undefined) dbData.allowedModels = userData.allowedModels;
+  if (userData.timezone !== undefined) dbData.timezone = userData.timezone;
 
   const [user] = await db
     .update(users)
@@ -539,6 +698,7 @@ export async function updateUser(id: number, userData: UpdateUserData): Promise<
       allowedClients: users.allowedClients,
       blockedClients: users.blockedClients,
       allowedModels: users.allowedModels,
+      timezone: users.timezone,
     });
 
   if (!user) return null;
@@ -561,6 +721,89 @@ export async function deleteUser(id: number): Promise<boolean> {
   return result.length > 0;
 }
 
+/**
+ * 恢复软删除的用户（不会自动恢复其名下已删除的 Key）
+ *
+ * @returns 用户不存在或未被删除时返回 false
+ */
+export async function restoreUser(id: number): Promise<boolean> {
+  const result = await db
+    .update(users)
+    .set({ deletedAt: null, updatedAt: new Date() })
+    .where(and(eq(users.id, id), isNotNull(users.deletedAt)))
+    .returning({ id: users.id });
+
+  if (result.length > 0) {
+    await invalidateCachedUser(id).catch(() => {});
+  }
+  return result.length > 0;
+}
+
+// 与 schemas.ts 中用户 tags 的校验保持一致：单个标签最多 32 个字符，最多 20 个标签
+const USER_TAG_MAX_LENGTH = 32;
+const USER_TAG_MAX_COUNT = 20;
+
+/**
+ * 为用户追加标签（幂等：已存在时不重复追加）
+ *
+ * 使用单条 UPDATE + JSONB 运算符在数据库内完成读改写，并发编辑不同标签不会互相覆盖；
+ * 标签数上限同样在 UPDATE 条件中判断，并发追加也不会超过 20 个
+ *
+ * @returns 标签已存在或追加成功时返回 true；用户不存在（或已删除）、标签超长、标签数已达上限时返回 false
+ */
+export async function addUserTag(userId: number, tag: string): Promise<boolean> {
+  const normalizedTag = tag.trim();
+  if (!normalizedTag || normalizedTag.length > USER_TAG_MAX_LENGTH) return false;
+
+  const currentTags = sql`coalesce(${users.tags}, '[]'::jsonb)`;
+  const tagArray = sql`jsonb_build_array(${normalizedTag}::text)`;
+  const result = await db
+    .update(users)
+    .set({
+      tags: sql`CASE WHEN ${currentTags} @> ${tagArray} THEN ${currentTags} ELSE ${currentTags} || ${tagArray} END`,
+      updatedAt: new Date(),
+    })
+    .where(
+      and(
+        eq(users.id, userId),
+        isNull(users.deletedAt),
+        sql`(${currentTags} @> ${tagArray} OR jsonb_array_length(${currentTags}) < ${USER_TAG_MAX_COUNT})`
+      )
+    )
+    .returning({ id: users.id });
+
+  if (result.length > 0) {
+    await invalidateCachedUser(userId).catch(() => {});
+  }
+  return result.length > 0;
+}
+
+/**
+ * 移除用户标签（标签不存在时为 no-op）
+ *
+ * 与 addUserTag 相同，在单条 UPDATE 中用 JSONB `-` 运算符删除，避免整体覆盖 tags 数组
+ *
+ * @returns 用户存在（未删除）时返回 true
+ */
+export async function removeUserTag(userId: number, tag: string): Promise<boolean> {
+  const normalizedTag = tag.trim();
+  if (!normalizedTag) return false;
+
+  const result = await db
+    .update(users)
+    .set({
+      tags: sql`coalesce(${users.tags}, '[]'::jsonb) - ${normalizedTag}::text`,
+      updatedAt: new Date(),
+    })
+    .where(and(eq(users.id, userId), isNull(users.deletedAt)))
+    .returning({ id: users.id });
+
+  if (result.length > 0) {
+    await invalidateCachedUser(userId).catch(() => {});
+  }
+  return result.length > 0;
+}
+
 export async function resetUserCostResetAt(userId: number, resetAt: Date | null): Promise<boolean> {
   return updateUserCostResetMarkers(userId, { costResetAt: resetAt });
 }
@@ -571,7 +814,8 @@ export async function updateUserCostResetMarkers(
     costResetAt?: Date | null;
     limit5hCostResetAt?: Date | null;
     enforceLimit5hMonotonic?: boolean;
-  }
+  },
+  audit?: AuditLogInput
 ): Promise<boolean> {
   const setClauses: {
     updatedAt: Date;
@@ -598,11 +842,19 @@ export async function updateUserCostResetMarkers(
     }
   }
 
-  const result = await db
-    .update(users)
-    .set(setClauses)
-    .where(and(eq(users.id, userId), isNull(users.deletedAt)))
-    .returning({ id: users.id });
+  // 传入 audit 时，审计记录与重置标记在同一事务内写入：任一失败则整体回滚
+  const result = await db.transaction(async (tx) => {
+    const rows = await tx
+      .update(users)
+      .set(setClauses)
+      .where(and(eq(users.id, userId), isNull(users.deletedAt)))
+      .returning({ id: users.id });
+
+    if (rows.length > 0 && audit) {
+      await insertAuditLogInTransaction(tx, audit);
+    }
+    return rows;
+  });
 
   if (result.length > 0) {
     await invalidateCachedUser(userId).catch(() => {});
@@ -610,6 +862,25 @@ export async function updateUserCostResetMarkers(
   return result.length > 0;
 }
 
+/**
+ * Count active users (enabled, not expired, not deleted)
+ * Uses the same predicate as the "active" status filter in findUserListBatch
+ */
+export async function countActiveUsers(): Promise<number> {
+  const [row] = await db
+    .select({ count: count() })
+    .from(users)
+    .where(
+      and(
+        isNull(users.deletedAt),
+        eq(users.isEnabled, true),
+        sql`(${users.expiresAt} IS NULL OR ${users.expiresAt} >= NOW())`
+      )
+    );
+
+  return Number(row?.count || 0);
+}
+
 /**
  * Mark an expired user as disabled (idempotent operation)
  * Only updates if the user is currently enabled
@@ -625,6 +896,76 @@ export async function markUserExpired(userId: number): Promise<boolean> {
   return result.length > 0;
 }
 
+/**
+ * Disable all enabled users whose expiresAt has passed in a single UPDATE
+ * Intended for the expiry sweeper; returns the number of users disabled
+ */
+export async function markExpiredUsersBatch(): Promise<number> {
+  const now = new Date();
+  const result = await db
+    .update(users)
+    .set({ isEnabled: false, updatedAt: now })
+    .where(and(lte(users.expiresAt, now), eq(users.isEnabled, true), isNull(users.deletedAt)))
+    .returning({ id: users.id });
+
+  await Promise.all(result.map((row) => invalidateCachedUser(row.id).catch(() => {})));
+  return result.length;
+}
+
+/**
+ * Find enabled users whose expiresAt falls within [now, now + withinMs]
+ * Used to drive expiry reminders; users without expiresAt or already expired are excluded
+ */
+export async function findUsersExpiringSoon(withinMs: number): Promise<User[]> {
+  if (!Number.isFinite(withinMs) || withinMs <= 0) return [];
+
+  const now = new Date();
+  const until = new Date(now.getTime() + withinMs);
+
+  const result = await db
+    .select({
+      id: users.id,
+      name: users.name,
+      description: users.description,
+      role: users.role,
+      rpm: users.rpmLimit,
+      dailyQuota: users.dailyLimitUsd,
+      providerGroup: users.providerGroup,
+      tags: users.tags,
+      createdAt: users.createdAt,
+      updatedAt: users.updatedAt,
+      deletedAt: users.deletedAt,
+      limit5hUsd: users.limit5hUsd,
+      limit5hResetMode: users.limit5hResetMode,
+      limitWeeklyUsd: users.limitWeeklyUsd,
+      limitMonthlyUsd: users.limitMonthlyUsd,
+      limitTotalUsd: users.limitTotalUsd,
+      costResetAt: users.costResetAt,
+      limit5hCostResetAt: users.limit5hCostResetAt,
+      limitConcurrentSessions: users.limitConcurrentSessions,
+      dailyResetMode: users.dailyResetMode,
+      dailyResetTime: users.dailyResetTime,
+      isEnabled: users.isEnabled,
+      expiresAt: users.expiresAt,
+      allowedClients: users.allowedClients,
+      blockedClients: users.blockedClients,
+      allowedModels: users.allowedModels,
+      timezone: users.timezone,
+    })
+    .from(users)
+    .where(
+      and(
+        isNull(users.deletedAt),
+        eq(users.isEnabled, true),
+        gte(users.expiresAt, now),
+        lte(users.expiresAt, until)
+      )
+    )
+    .orderBy(asc(users.expiresAt), users.id);
+
+  return result.map(toUser);
+}
+
 /**
  * Get all unique tags from all users (for tag filter dropdown)
  * Returns tags from all users regardless of current filters
@@ -644,6 +985,18 @@ export async function getAllUserTags(): Promise<string[]> {
   return Array.from(allTags).sort();
 }
 
+/**
+ * 获取所有未删除用户的 providerGroup 原始值（用于统计分组被引用的用户数）
+ */
+export async function findAllUserProviderGroupValues(): Promise<
+  Array<{ providerGroup: string | null }>
+> {
+  return db
+    .select({ providerGroup: users.providerGroup })
+    .from(users)
+    .where(isNull(users.deletedAt));
+}
+
 /**
  * Get all unique provider groups from users (for key group filter dropdown)
  * Returns groups from all users regardless of current filters
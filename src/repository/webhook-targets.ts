@@ -86,6 +86,15 @@ export async function getAllWebhookTargets(): Promise<WebhookTarget[]> {
   return rows.map(toWebhookTarget);
 }
 
+export async function getEnabledWebhookTargets(): Promise<WebhookTarget[]> {
+  const rows = await db
+    .select()
+    .from(webhookTargets)
+    .where(eq(webhookTargets.isEnabled, true))
+    .orderBy(desc(webhookTargets.id));
+  return rows.map(toWebhookTarget);
+}
+
 export async function getWebhookTargetById(id: number): Promise<WebhookTarget | null> {
   const [row] = await db.select().from(webhookTargets).where(eq(webhookTargets.id, id)).limit(1);
   return row ? toWebhookTarget(row) : null;
@@ -123,6 +123,16 @@ export type ModelPriceSource = "cloud" | "litellm" | "manual";
 /** 非本地(云端)来源集合,查询过滤用 */
 export const CLOUD_PRICE_SOURCES = ["cloud", "litellm"] as const;
 
+/**
+ * 两个价格版本之间的单个字段变化（嵌套字段用点号路径表示，如 long_context_pricing.input_multiplier）
+ * before/after 为 undefined 表示该字段在对应版本中不存在
+ */
+export interface ModelPriceFieldChange {
+  field: string;
+  before: unknown;
+  after: unknown;
+}
+
 /**
  * 模型价格记录
  */
@@ -27,3 +27,12 @@ export interface UpdateProviderGroupInput {
   costMultiplier?: number;
   description?: string | null;
 }
+
+/**
+ * Row counts touched by a provider group reassignment.
+ */
+export interface ReassignProviderGroupResult {
+  usersUpdated: number;
+  keysUpdated: number;
+  providersUpdated: number;
+}
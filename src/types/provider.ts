@@ -534,6 +534,10 @@ export interface ProviderDisplay {
 export interface ProviderStatistics {
   todayCost: string;
   todayCalls: number;
+  // Today's latency percentiles (ms); null when no completed request has a duration
+  p50DurationMs: number | null;
+  p95DurationMs: number | null;
+  p99DurationMs: number | null;
   lastCallTime: string | null;
   lastCallModel: string | null;
 }
@@ -51,6 +51,13 @@ export interface DatabaseStatRow {
   total_cost: string | number | null;
 }
 
+export interface DatabaseUserTotalRow {
+  user_id: number;
+  user_name: string;
+  api_calls: number;
+  total_cost: string | number;
+}
+
 export interface DatabaseUser {
   id: number;
   name: string;
@@ -93,6 +100,19 @@ export type RateLimitType =
   | "concurrent_sessions"
   | "daily_quota";
 
+/**
+ * 限流元数据（message_request.rate_limit_metadata，由 RateLimitError.toJSON() 生成）
+ */
+export interface RateLimitMetadata {
+  type: "rate_limit_error";
+  limit_type: RateLimitType;
+  current_usage: number;
+  limit_value: number;
+  reset_time: string | null;
+  provider_id: number | null;
+  message: string;
+}
+
 export interface EventTimeline {
   hour: string;
   count: number;
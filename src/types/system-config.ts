@@ -31,6 +31,8 @@ export interface SystemSettings {
 
   // 货币显示配置
   currencyDisplay: CurrencyCode;
+  // USD → currencyDisplay 的展示汇率（numeric 原样保留为十进制字符串，USD 时恒为 "1"）
+  currencyDisplayRate?: string;
 
   // 计费模型来源配置
   billingModelSource: BillingModelSource;
@@ -61,6 +63,8 @@ export interface SystemSettings {
 
   // 客户端版本检查配置
   enableClientVersionCheck: boolean;
+  // Claude 客户端最低版本（开启版本检查时生效，null 表示仅按 GA 版本检测）
+  minClientVersion?: string | null;
 
   // 供应商不可用时是否返回详细错误信息
   verboseProviderError: boolean;
@@ -157,6 +161,7 @@ export interface UpdateSystemSettingsInput {
 
   // 货币显示配置（可选）
   currencyDisplay?: CurrencyCode;
+  currencyDisplayRate?: number;
 
   // 计费模型来源配置（可选）
   billingModelSource?: BillingModelSource;
@@ -181,6 +186,7 @@ export interface UpdateSystemSettingsInput {
 
   // 客户端版本检查配置（可选）
   enableClientVersionCheck?: boolean;
+  minClientVersion?: string | null;
 
   // 供应商不可用时是否返回详细错误信息（可选）
   verboseProviderError?: boolean;
@@ -33,6 +33,7 @@ export interface User {
   blockedClients?: string[]; // Blocked client patterns (blacklist, checked before allowedClients)
   // Allowed models (AI model restrictions)
   allowedModels?: string[]; // 允许的AI模型（空数组=无限制）
+  timezone?: string | null; // 统计时区（IANA），null = 跟随系统时区
 }
 
 /**
@@ -63,6 +64,7 @@ export interface CreateUserData {
   blockedClients?: string[]; // Blocked client patterns (blacklist, checked before allowedClients)
   // Allowed models (AI model restrictions)
   allowedModels?: string[];
+  timezone?: string | null; // 统计时区（IANA），null = 跟随系统时区
 }
 
 /**
@@ -93,6 +95,7 @@ export interface UpdateUserData {
   blockedClients?: string[]; // Blocked client patterns (blacklist, checked before allowedClients)
   // Allowed models (AI model restrictions)
   allowedModels?: string[];
+  timezone?: string | null; // 统计时区（IANA），null = 跟随系统时区
 }
 
 /**
@@ -12,6 +12,7 @@ const getProxyStatusMock = vi.hoisted(() => vi.fn());
 const fetchClientVersionStatsMock = vi.hoisted(() => vi.fn());
 const simulateDispatchActionMock = vi.hoisted(() => vi.fn());
 const getSystemSettingsRepoMock = vi.hoisted(() => vi.fn());
+const createUserStatisticsCsvStreamMock = vi.hoisted(() => vi.fn());
 
 vi.mock("@/lib/auth", async (importOriginal) => {
   const actual = await importOriginal<typeof import("@/lib/auth")>();
@@ -34,6 +35,13 @@ vi.mock("@/actions/client-versions", () => ({
 vi.mock("@/actions/dispatch-simulator", () => ({
   simulateDispatchAction: simulateDispatchActionMock,
 }));
+vi.mock("@/lib/statistics/export-csv", () => ({
+  createUserStatisticsCsvStream: createUserStatisticsCsvStreamMock,
+}));
+vi.mock("@/lib/utils/timezone", async (importOriginal) => {
+  const actual = await importOriginal<typeof import("@/lib/utils/timezone")>();
+  return { ...actual, resolveSystemTimezone: vi.fn(async () => "UTC") };
+});
 vi.mock("@/repository/system-config", () => ({
   getSystemSettings: getSystemSettingsRepoMock,
 }));
@@ -97,7 +105,9 @@ describe("v1 dashboard endpoints", () => {
       headers,
     });
     expect(statistics.response.status).toBe(200);
-    expect(getUserStatisticsMock).toHaveBeenCalledWith("7days");
+    expect(getUserStatisticsMock).toHaveBeenCalledWith("7days", {
+      signal: expect.any(AbortSignal),
+    });
 
     const concurrent = await callV1Route({
       method: "GET",
@@ -107,6 +117,40 @@ describe("v1 dashboard endpoints", () => {
     expect(concurrent.json).toEqual({ count: 2 });
   });
 
+  test("streams the statistics CSV export for admins only", async () => {
+    createUserStatisticsCsvStreamMock.mockImplementation(
+      async () =>
+        new ReadableStream<string>({
+          start(controller) {
+            controller.enqueue("date,user_id,user_name,api_calls,total_cost\n");
+            controller.close();
+          },
+        })
+    );
+
+    const exported = await callV1Route({
+      method: "GET",
+      pathname: "/api/v1/dashboard/statistics/export?timeRange=30days",
+      headers: { Authorization: "Bearer admin-token" },
+    });
+    expect(exported.response.status).toBe(200);
+    expect(exported.response.headers.get("content-type")).toContain("text/csv");
+    expect(exported.response.headers.get("content-disposition")).toContain(
+      "statistics-30days.csv"
+    );
+    expect(exported.text).toBe("date,user_id,user_name,api_calls,total_cost\n");
+    expect(createUserStatisticsCsvStreamMock).toHaveBeenCalledWith("30days", "UTC");
+
+    validateAuthTokenMock.mockResolvedValue(userSession);
+    const forbidden = await callV1Route({
+      method: "GET",
+      pathname: "/api/v1/dashboard/statistics/export",
+      headers: { Authorization: "Bearer user-token" },
+    });
+    expect(forbidden.response.status).toBe(403);
+    expect(createUserStatisticsCsvStreamMock).toHaveBeenCalledTimes(1);
+  });
+
   test("guards global concurrent session count for non-admin callers", async () => {
     validateAuthTokenMock.mockResolvedValue(userSession);
     getSystemSettingsRepoMock.mockResolvedValueOnce({ allowGlobalUsageView: false });
@@ -208,6 +252,7 @@ describe("v1 dashboard endpoints", () => {
 
     expect(doc.paths).toHaveProperty("/api/v1/dashboard/overview");
     expect(doc.paths).toHaveProperty("/api/v1/dashboard/statistics");
+    expect(doc.paths).toHaveProperty("/api/v1/dashboard/statistics/export");
     expect(doc.paths).toHaveProperty("/api/v1/dashboard/concurrent-sessions");
     expect(doc.paths).toHaveProperty("/api/v1/dashboard/realtime");
     expect(doc.paths).toHaveProperty("/api/v1/dashboard/provider-slots");
@@ -9,6 +9,7 @@ const getFilterOptionsMock = vi.hoisted(() => vi.fn());
 const getModelListMock = vi.hoisted(() => vi.fn());
 const getStatusCodeListMock = vi.hoisted(() => vi.fn());
 const getEndpointListMock = vi.hoisted(() => vi.fn());
+const getApiTypeListMock = vi.hoisted(() => vi.fn());
 const getUsageLogSessionIdSuggestionsMock = vi.hoisted(() => vi.fn());
 const exportUsageLogsMock = vi.hoisted(() => vi.fn());
 const startUsageLogsExportMock = vi.hoisted(() => vi.fn());
@@ -28,6 +29,7 @@ vi.mock("@/actions/usage-logs", () => ({
   getModelList: getModelListMock,
   getStatusCodeList: getStatusCodeListMock,
   getEndpointList: getEndpointListMock,
+  getApiTypeList: getApiTypeListMock,
   getUsageLogSessionIdSuggestions: getUsageLogSessionIdSuggestionsMock,
   exportUsageLogs: exportUsageLogsMock,
   startUsageLogsExport: startUsageLogsExportMock,
@@ -69,6 +71,7 @@ describe("v1 usage log endpoints", () => {
     getModelListMock.mockResolvedValue({ ok: true, data: ["claude"] });
     getStatusCodeListMock.mockResolvedValue({ ok: true, data: [200, 500] });
     getEndpointListMock.mockResolvedValue({ ok: true, data: ["/v1/messages"] });
+    getApiTypeListMock.mockResolvedValue({ ok: true, data: ["codex", "messages"] });
     getUsageLogSessionIdSuggestionsMock.mockResolvedValue({
       ok: true,
       data: ["session-a"],
@@ -210,6 +213,13 @@ describe("v1 usage log endpoints", () => {
     });
     expect(endpoints.json).toEqual({ items: ["/v1/messages"] });
 
+    const apiTypes = await callV1Route({
+      method: "GET",
+      pathname: "/api/v1/usage-logs/api-types",
+      headers,
+    });
+    expect(apiTypes.json).toEqual({ items: ["codex", "messages"] });
+
     const suggestions = await callV1Route({
       method: "GET",
       pathname: "/api/v1/usage-logs/session-id-suggestions?q=session&userId=1",
@@ -270,6 +280,7 @@ describe("v1 usage log endpoints", () => {
       "/api/v1/usage-logs/models",
       "/api/v1/usage-logs/status-codes",
       "/api/v1/usage-logs/endpoints",
+      "/api/v1/usage-logs/api-types",
     ]) {
       const got = await callV1Route({
         method: "GET",
@@ -284,6 +295,7 @@ describe("v1 usage log endpoints", () => {
     expect(getModelListMock).not.toHaveBeenCalled();
     expect(getStatusCodeListMock).not.toHaveBeenCalled();
     expect(getEndpointListMock).not.toHaveBeenCalled();
+    expect(getApiTypeListMock).not.toHaveBeenCalled();
   });
 
   test("creates sync and async exports and downloads completed csv", async () => {
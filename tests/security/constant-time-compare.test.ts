@@ -1,5 +1,5 @@
 import { describe, expect, it } from "vitest";
-import { constantTimeEqual } from "@/lib/security/constant-time-compare";
+import { constantTimeEqual, isAdminToken } from "@/lib/security/constant-time-compare";
 
 describe("constantTimeEqual", () => {
   it("returns true for equal strings", () => {
@@ -41,3 +41,17 @@ describe("constantTimeEqual", () => {
     expect(constantTimeEqual(s, s)).toBe(true);
   });
 });
+
+describe("isAdminToken", () => {
+  it("matches the configured admin token", () => {
+    expect(isAdminToken("admin-secret", "admin-secret")).toBe(true);
+    expect(isAdminToken("admin-secreT", "admin-secret")).toBe(false);
+    expect(isAdminToken("admin", "admin-secret")).toBe(false);
+  });
+
+  it("never matches when the admin token is unset or empty", () => {
+    expect(isAdminToken("", "")).toBe(false);
+    expect(isAdminToken("", undefined)).toBe(false);
+    expect(isAdminToken("anything", null)).toBe(false);
+  });
+});
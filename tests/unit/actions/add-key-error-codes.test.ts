@@ -87,7 +87,7 @@ describe("addKey action error codes (self-service surfaceable)", () => {
     expect(result.ok).toBe(false);
     if (!result.ok) {
       expect(result.errorCode).toBe("DUPLICATE_NAME");
-      expect(result.errorParams).toMatchObject({ name: "work" });
+      expect(result.errorParams).toMatchObject({ name: "work", existingId: 99 });
     }
   });
 
@@ -150,4 +150,27 @@ describe("keys limit validation", () => {
     }
     expect(updateKeyMock).not.toHaveBeenCalled();
   });
+
+  it("editKey：改名与同用户其它生效 key 重名时返回冲突 key 的 id", async () => {
+    findActiveKeyByUserIdAndNameMock.mockResolvedValueOnce({ id: 7, name: "taken" } as never);
+    const { editKey } = await import("@/actions/keys");
+
+    const result = await editKey(1, { name: "taken", providerGroup: "default" });
+
+    expect(findActiveKeyByUserIdAndNameMock).toHaveBeenCalledWith(10, "taken", 1);
+    expect(result.ok).toBe(false);
+    if (!result.ok) {
+      expect(result.errorCode).toBe("DUPLICATE_NAME");
+      expect(result.errorParams).toEqual({ name: "taken", existingId: 7 });
+    }
+    expect(updateKeyMock).not.toHaveBeenCalled();
+  });
+
+  it("editKey：名称未变化时不做重名检查", async () => {
+    const { editKey } = await import("@/actions/keys");
+
+    await editKey(1, { name: "k", providerGroup: "default" });
+
+    expect(findActiveKeyByUserIdAndNameMock).not.toHaveBeenCalled();
+  });
 });
@@ -9,8 +9,8 @@ const revalidatePathMock = vi.fn();
 const findLatestPriceByModelMock = vi.fn();
 const findLatestPriceByModelAndSourceMock = vi.fn();
 const findAllLatestPricesMock = vi.fn();
-const createModelPriceMock = vi.fn();
 const upsertModelPriceMock = vi.fn();
+const upsertModelPricesBatchMock = vi.fn();
 const deleteModelPriceByNameMock = vi.fn();
 const findAllManualPricesMock = vi.fn();
 
@@ -40,8 +40,8 @@ vi.mock("@/repository/model-price", () => ({
   findLatestPriceByModel: () => findLatestPriceByModelMock(),
   findLatestPriceByModelAndSource: (...args: unknown[]) =>
     findLatestPriceByModelAndSourceMock(...args),
-  createModelPrice: (...args: unknown[]) => createModelPriceMock(...args),
   upsertModelPrice: (...args: unknown[]) => upsertModelPriceMock(...args),
+  upsertModelPricesBatch: (...args: unknown[]) => upsertModelPricesBatchMock(...args),
   deleteModelPriceByName: (...args: unknown[]) => deleteModelPriceByNameMock(...args),
   findAllManualPrices: () => findAllManualPricesMock(),
   findAllLatestPrices: () => findAllLatestPricesMock(),
@@ -152,6 +152,37 @@ describe("Model Price Actions", () => {
     });
   });
 
+  describe("getModelsSupporting", () => {
+    it("returns sorted model names whose latest price supports the capability", async () => {
+      findAllLatestPricesMock.mockResolvedValue([
+        makeMockPrice("gpt-4.1", { mode: "chat", supports_vision: true }),
+        makeMockPrice("claude-sonnet-4-6", { mode: "chat", supports_vision: true }),
+        makeMockPrice("gpt-3.5-turbo", { mode: "chat", supports_vision: false }),
+        makeMockPrice("o1-mini", { mode: "chat", supports_tool_choice: true }),
+      ]);
+
+      const { getModelsSupporting } = await import("@/actions/model-prices");
+
+      expect(await getModelsSupporting("vision")).toEqual({
+        ok: true,
+        data: ["claude-sonnet-4-6", "gpt-4.1"],
+      });
+      expect(await getModelsSupporting("function_calling")).toEqual({
+        ok: true,
+        data: ["o1-mini"],
+      });
+    });
+
+    it("rejects unknown capabilities without querying prices", async () => {
+      const { getModelsSupporting } = await import("@/actions/model-prices");
+      const result = await getModelsSupporting("telepathy");
+
+      expect(result.ok).toBe(false);
+      expect(result).toMatchObject({ errorCode: "INVALID_FORMAT" });
+      expect(findAllLatestPricesMock).not.toHaveBeenCalled();
+    });
+  });
+
   describe("upsertSingleModelPrice", () => {
     it("should create a new model price for admin", async () => {
       const mockResult = makeMockPrice("gpt-5.5", {
@@ -494,7 +525,7 @@ describe("Model Price Actions", () => {
       expect(result.ok).toBe(true);
       expect(result.data?.skippedConflicts).toContain("custom-model");
       expect(result.data?.unchanged).toContain("custom-model");
-      expect(createModelPriceMock).not.toHaveBeenCalled();
+      expect(upsertModelPricesBatchMock).not.toHaveBeenCalled();
     });
 
     it("should overwrite manual prices when specified", async () => {
@@ -505,16 +536,6 @@ describe("Model Price Actions", () => {
 
       findAllManualPricesMock.mockResolvedValue(new Map([["custom-model", manualPrice]]));
       findAllLatestPricesMock.mockResolvedValue([manualPrice]);
-      upsertModelPriceMock.mockResolvedValue(
-        makeMockPrice(
-          "custom-model",
-          {
-            mode: "chat",
-            input_cost_per_token: 0.000015,
-          },
-          "litellm"
-        )
-      );
 
       const { processPriceTableInternal } = await import("@/actions/model-prices");
       const result = await processPriceTableInternal(
@@ -530,9 +551,8 @@ describe("Model Price Actions", () => {
       expect(result.ok).toBe(true);
       expect(result.data?.updated).toContain("custom-model");
       // The overwrite is an atomic replace (delete + insert in one transaction).
-      expect(upsertModelPriceMock).toHaveBeenCalledWith(
-        "custom-model",
-        expect.any(Object),
+      expect(upsertModelPricesBatchMock).toHaveBeenCalledWith(
+        [expect.objectContaining({ modelName: "custom-model" })],
         "cloud"
       );
     });
@@ -540,15 +560,6 @@ describe("Model Price Actions", () => {
     it("should add new models with cloud source", async () => {
       findAllManualPricesMock.mockResolvedValue(new Map());
       findAllLatestPricesMock.mockResolvedValue([]);
-      createModelPriceMock.mockResolvedValue(
-        makeMockPrice(
-          "new-model",
-          {
-            mode: "chat",
-          },
-          "litellm"
-        )
-      );
 
       const { processPriceTableInternal } = await import("@/actions/model-prices");
       const result = await processPriceTableInternal(
@@ -562,7 +573,10 @@ describe("Model Price Actions", () => {
 
       expect(result.ok).toBe(true);
       expect(result.data?.added).toContain("new-model");
-      expect(createModelPriceMock).toHaveBeenCalledWith("new-model", expect.any(Object), "cloud");
+      expect(upsertModelPricesBatchMock).toHaveBeenCalledWith(
+        [expect.objectContaining({ modelName: "new-model" })],
+        "cloud"
+      );
     });
 
     it("should skip metadata fields like sample_spec", async () => {
@@ -626,15 +640,12 @@ describe("Model Price Actions", () => {
 
       expect(result.ok).toBe(true);
       expect(result.data?.unchanged).toContain("safe-model");
-      expect(createModelPriceMock).not.toHaveBeenCalled();
+      expect(upsertModelPricesBatchMock).not.toHaveBeenCalled();
     });
 
     it("should persist models with the manual source when source='manual' (local upload)", async () => {
       findAllManualPricesMock.mockResolvedValue(new Map());
       findAllLatestPricesMock.mockResolvedValue([]);
-      createModelPriceMock.mockResolvedValue(
-        makeMockPrice("new-model", { mode: "chat" }, "manual")
-      );
 
       const { processPriceTableInternal } = await import("@/actions/model-prices");
       const result = await processPriceTableInternal(
@@ -645,7 +656,10 @@ describe("Model Price Actions", () => {
 
       expect(result.ok).toBe(true);
       expect(result.data?.added).toContain("new-model");
-      expect(createModelPriceMock).toHaveBeenCalledWith("new-model", expect.any(Object), "manual");
+      expect(upsertModelPricesBatchMock).toHaveBeenCalledWith(
+        [expect.objectContaining({ modelName: "new-model" })],
+        "manual"
+      );
     });
 
     it("should protect a locally-uploaded (manual) model from later auto-sync overwrite", async () => {
@@ -665,7 +679,7 @@ describe("Model Price Actions", () => {
 
       expect(result.ok).toBe(true);
       expect(result.data?.skippedConflicts).toContain("my-custom-model");
-      expect(createModelPriceMock).not.toHaveBeenCalled();
+      expect(upsertModelPricesBatchMock).not.toHaveBeenCalled();
       expect(deleteModelPriceByNameMock).not.toHaveBeenCalled();
     });
 
@@ -677,9 +691,6 @@ describe("Model Price Actions", () => {
       );
       findAllManualPricesMock.mockResolvedValue(new Map());
       findAllLatestPricesMock.mockResolvedValue([existing]);
-      upsertModelPriceMock.mockResolvedValue(
-        makeMockPrice("cloud-model", { mode: "chat", input_cost_per_token: 0.002 }, "litellm")
-      );
 
       const { processPriceTableInternal } = await import("@/actions/model-prices");
       const result = await processPriceTableInternal(
@@ -689,8 +700,64 @@ describe("Model Price Actions", () => {
       expect(result.ok).toBe(true);
       expect(result.data?.updated).toContain("cloud-model");
       // Transactional replace, not a separate delete + insert.
-      expect(upsertModelPriceMock).toHaveBeenCalledWith("cloud-model", expect.any(Object), "cloud");
-      expect(createModelPriceMock).not.toHaveBeenCalled();
+      expect(upsertModelPricesBatchMock).toHaveBeenCalledWith(
+        [expect.objectContaining({ modelName: "cloud-model" })],
+        "cloud"
+      );
+    });
+
+    it("should write new and changed models in a single batch", async () => {
+      const existing = makeMockPrice(
+        "cloud-model",
+        { mode: "chat", input_cost_per_token: 0.001 },
+        "litellm"
+      );
+      findAllManualPricesMock.mockResolvedValue(new Map());
+      findAllLatestPricesMock.mockResolvedValue([existing]);
+
+      const { processPriceTableInternal } = await import("@/actions/model-prices");
+      const result = await processPriceTableInternal(
+        JSON.stringify({
+          "cloud-model": { mode: "chat", input_cost_per_token: 0.002 },
+          "new-model": { mode: "chat", input_cost_per_token: 0.003 },
+        })
+      );
+
+      expect(result.ok).toBe(true);
+      expect(result.data?.added).toEqual(["new-model"]);
+      expect(result.data?.updated).toEqual(["cloud-model"]);
+      expect(upsertModelPricesBatchMock).toHaveBeenCalledTimes(1);
+      expect(upsertModelPricesBatchMock).toHaveBeenCalledWith(
+        [
+          expect.objectContaining({
+            modelName: "cloud-model",
+            priceData: { mode: "chat", input_cost_per_token: 0.002 },
+          }),
+          expect.objectContaining({
+            modelName: "new-model",
+            priceData: { mode: "chat", input_cost_per_token: 0.003 },
+          }),
+        ],
+        "cloud"
+      );
+    });
+
+    it("should mark every pending model as failed when the batch write fails", async () => {
+      findAllManualPricesMock.mockResolvedValue(new Map());
+      findAllLatestPricesMock.mockResolvedValue([]);
+      upsertModelPricesBatchMock.mockRejectedValueOnce(new Error("Database error"));
+
+      const { processPriceTableInternal } = await import("@/actions/model-prices");
+      const result = await processPriceTableInternal(
+        JSON.stringify({
+          "model-a": { mode: "chat", input_cost_per_token: 0.001 },
+          "model-b": { mode: "chat", input_cost_per_token: 0.002 },
+        })
+      );
+
+      expect(result.ok).toBe(true);
+      expect(result.data?.added).toEqual([]);
+      expect(result.data?.failed).toEqual(["model-a", "model-b"]);
     });
 
     it("should convert an existing cloud (litellm) model to manual on local upload", async () => {
@@ -701,9 +768,6 @@ describe("Model Price Actions", () => {
       );
       findAllManualPricesMock.mockResolvedValue(new Map());
       findAllLatestPricesMock.mockResolvedValue([existing]);
-      upsertModelPriceMock.mockResolvedValue(
-        makeMockPrice("shared-model", { mode: "chat", input_cost_per_token: 0.001 }, "manual")
-      );
 
       const { processPriceTableInternal } = await import("@/actions/model-prices");
       const result = await processPriceTableInternal(
@@ -714,9 +778,8 @@ describe("Model Price Actions", () => {
 
       expect(result.ok).toBe(true);
       expect(result.data?.updated).toContain("shared-model");
-      expect(upsertModelPriceMock).toHaveBeenCalledWith(
-        "shared-model",
-        expect.any(Object),
+      expect(upsertModelPricesBatchMock).toHaveBeenCalledWith(
+        [expect.objectContaining({ modelName: "shared-model" })],
         "manual"
       );
     });
@@ -727,9 +790,6 @@ describe("Model Price Actions", () => {
       const manualPrice = makeMockPrice("my-model", { mode: "chat", input_cost_per_token: 0.1 });
       findAllManualPricesMock.mockResolvedValue(new Map([["my-model", manualPrice]]));
       findAllLatestPricesMock.mockResolvedValue([manualPrice]);
-      upsertModelPriceMock.mockResolvedValue(
-        makeMockPrice("my-model", { mode: "chat", input_cost_per_token: 0.2 }, "manual")
-      );
 
       const { processPriceTableInternal } = await import("@/actions/model-prices");
       const result = await processPriceTableInternal(
@@ -741,7 +801,10 @@ describe("Model Price Actions", () => {
       expect(result.ok).toBe(true);
       expect(result.data?.updated).toContain("my-model");
       expect(result.data?.skippedConflicts).not.toContain("my-model");
-      expect(upsertModelPriceMock).toHaveBeenCalledWith("my-model", expect.any(Object), "manual");
+      expect(upsertModelPricesBatchMock).toHaveBeenCalledWith(
+        [expect.objectContaining({ modelName: "my-model" })],
+        "manual"
+      );
     });
 
     it("should normalize whitespace in cloud model names before manual protection", async () => {
@@ -756,7 +819,7 @@ describe("Model Price Actions", () => {
 
       expect(result.ok).toBe(true);
       expect(result.data?.skippedConflicts).toContain("claude-3");
-      expect(createModelPriceMock).not.toHaveBeenCalled();
+      expect(upsertModelPricesBatchMock).not.toHaveBeenCalled();
     });
   });
 
@@ -764,9 +827,6 @@ describe("Model Price Actions", () => {
     it("should store uploaded models with manual source so auto-sync cannot overwrite them", async () => {
       findAllManualPricesMock.mockResolvedValue(new Map());
       findAllLatestPricesMock.mockResolvedValue([]);
-      createModelPriceMock.mockResolvedValue(
-        makeMockPrice("my-custom-model", { mode: "chat", input_cost_per_token: 0.001 }, "manual")
-      );
 
       const { uploadPriceTable } = await import("@/actions/model-prices");
       const result = await uploadPriceTable(
@@ -774,9 +834,8 @@ describe("Model Price Actions", () => {
       );
 
       expect(result.ok).toBe(true);
-      expect(createModelPriceMock).toHaveBeenCalledWith(
-        "my-custom-model",
-        expect.any(Object),
+      expect(upsertModelPricesBatchMock).toHaveBeenCalledWith(
+        [expect.objectContaining({ modelName: "my-custom-model" })],
         "manual"
       );
     });
@@ -789,7 +848,7 @@ describe("Model Price Actions", () => {
 
       expect(result.ok).toBe(false);
       expect(result.error).toContain("无权限");
-      expect(createModelPriceMock).not.toHaveBeenCalled();
+      expect(upsertModelPricesBatchMock).not.toHaveBeenCalled();
     });
   });
 
@@ -0,0 +1,278 @@
+import { afterEach, beforeEach, describe, expect, test, vi } from "vitest";
+import type { Provider } from "@/types/provider";
+
+const getSessionMock = vi.fn();
+const categorizeErrorAsyncMock = vi.fn();
+const fetchMock = vi.fn();
+const findProviderByIdMock = vi.fn();
+const getPresetsForProviderMock = vi.fn();
+const validateProviderUrlForConnectivityMock = vi.fn();
+const createProxyAgentForProviderMock = vi.fn();
+
+vi.mock("@/lib/auth", () => ({
+  getSession: getSessionMock,
+}));
+
+vi.mock("@/repository/provider", () => ({
+  createProvider: vi.fn(),
+  deleteProvider: vi.fn(),
+  findAllProviders: vi.fn(async () => []),
+  findAllProvidersFresh: vi.fn(async () => []),
+  findProviderById: findProviderByIdMock,
+  getProviderStatistics: vi.fn(),
+  resetProviderTotalCostResetAt: vi.fn(async () => {}),
+  updateProvider: vi.fn(),
+  updateProviderPrioritiesBatch: vi.fn(),
+}));
+
+vi.mock("@/lib/cache/provider-cache", () => ({
+  publishProviderCacheInvalidation: vi.fn(),
+}));
+
+vi.mock("@/lib/redis/circuit-breaker-config", () => ({
+  deleteProviderCircuitConfig: vi.fn(),
+  saveProviderCircuitConfig: vi.fn(),
+}));
+
+vi.mock("@/lib/circuit-breaker", () => ({
+  clearConfigCache: vi.fn(),
+  clearProviderState: vi.fn(),
+  getAllHealthStatusAsync: vi.fn(async () => ({})),
+  publishCircuitBreakerConfigInvalidation: vi.fn(),
+  forceCloseCircuitState: vi.fn(),
+  resetCircuit: vi.fn(),
+}));
+
+vi.mock("@/lib/session-manager", () => ({
+  SessionManager: {
+    terminateProviderSessionsBatch: vi.fn(),
+    terminateStickySessionsForProviders: vi.fn(),
+  },
+}));
+
+vi.mock("@/lib/logger", () => ({
+  logger: {
+    trace: vi.fn(),
+    debug: vi.fn(),
+    info: vi.fn(),
+    warn: vi.fn(),
+    error: vi.fn(),
+  },
+}));
+
+vi.mock("next/cache", () => ({
+  revalidatePath: vi.fn(),
+}));
+
+vi.mock("@/lib/provider-testing", () => ({
+  executeProviderTest: vi.fn(),
+}));
+
+vi.mock("@/app/v1/_lib/proxy/errors", async (importOriginal) => {
+  const actual = await importOriginal<typeof import("@/app/v1/_lib/proxy/errors")>();
+  return {
+    ...actual,
+    categorizeErrorAsync: categorizeErrorAsyncMock,
+  };
+});
+
+vi.mock("@/lib/provider-testing/presets", () => ({
+  getPresetsForProvider: getPresetsForProviderMock,
+}));
+
+vi.mock("@/lib/validation/provider-url", () => ({
+  validateProviderUrlForConnectivity: validateProviderUrlForConnectivityMock,
+}));
+
+vi.mock("@/lib/proxy-agent", () => ({
+  createProxyAgentForProvider: createProxyAgentForProviderMock,
+  isValidProxyUrl: vi.fn(() => true),
+}));
+
+const geminiGetAccessTokenMock = vi.fn(async (apiKey: string) => apiKey);
+const geminiIsJsonMock = vi.fn(() => false);
+
+vi.mock("@/app/v1/_lib/gemini/auth", () => ({
+  GeminiAuth: {
+    getAccessToken: geminiGetAccessTokenMock,
+    isJson: geminiIsJsonMock,
+  },
+}));
+
+function buildProvider(overrides: Partial<Provider> = {}): Provider {
+  return {
+    id: 7,
+    name: "p-claude",
+    url: "https://api.example.com",
+    key: "sk-stored-secret",
+    providerType: "claude",
+    proxyUrl: null,
+    proxyFallbackToDirect: false,
+    customHeaders: null,
+    ...overrides,
+  } as Provider;
+}
+
+describe("testProviderConnectivity", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    vi.stubGlobal("fetch", fetchMock);
+    getSessionMock.mockResolvedValue({ user: { id: 1, role: "admin" } });
+    validateProviderUrlForConnectivityMock.mockImplementation((providerUrl: string) => ({
+      valid: true,
+      normalizedUrl: providerUrl,
+    }));
+    createProxyAgentForProviderMock.mockReturnValue(null);
+    getPresetsForProviderMock.mockReturnValue([]);
+    findProviderByIdMock.mockResolvedValue(buildProvider());
+    fetchMock.mockResolvedValue(new Response('{"data":[]}', { status: 200 }));
+    geminiGetAccessTokenMock.mockImplementation(async (apiKey: string) => apiKey);
+    geminiIsJsonMock.mockReturnValue(false);
+  });
+
+  afterEach(() => {
+    vi.unstubAllGlobals();
+  });
+
+  test("非 admin 会话应返回未授权且不发请求", async () => {
+    getSessionMock.mockResolvedValue({ user: { id: 2, role: "user" } });
+
+    const { testProviderConnectivity } = await import("@/actions/providers");
+    const result = await testProviderConnectivity(7);
+
+    expect(result.ok).toBe(false);
+    expect(findProviderByIdMock).not.toHaveBeenCalled();
+    expect(fetchMock).not.toHaveBeenCalled();
+  });
+
+  test("供应商不存在时返回 provider.not_found", async () => {
+    findProviderByIdMock.mockResolvedValue(null);
+
+    const { testProviderConnectivity } = await import("@/actions/providers");
+    const result = await testProviderConnectivity(404);
+
+    expect(result.ok).toBe(false);
+    if (!result.ok) {
+      expect(result.errorCode).toBe("provider.not_found");
+    }
+    expect(fetchMock).not.toHaveBeenCalled();
+  });
+
+  test("成功时返回延迟与状态码，使用库内密钥请求模型列表", async () => {
+    findProviderByIdMock.mockResolvedValue(
+      buildProvider({
+        providerType: "codex",
+        url: "https://api.example.com/",
+        customHeaders: { "x-extra": "1" },
+      })
+    );
+
+    const { testProviderConnectivity } = await import("@/actions/providers");
+    const result = await testProviderConnectivity(7);
+
+    expect(result.ok).toBe(true);
+    if (result.ok) {
+      expect(result.data).toMatchObject({
+        success: true,
+        httpStatus: 200,
+        errorCategory: null,
+        errorMessage: null,
+      });
+      expect(result.data?.latencyMs).toBeGreaterThanOrEqual(0);
+    }
+    expect(categorizeErrorAsyncMock).not.toHaveBeenCalled();
+
+    const [url, init] = fetchMock.mock.calls[0] ?? [];
+    expect(url).toBe("https://api.example.com/v1/models");
+    expect(init).toMatchObject({
+      method: "GET",
+      headers: { Authorization: "Bearer sk-stored-secret", "x-extra": "1" },
+    });
+    expect(init?.signal).toBeInstanceOf(AbortSignal);
+  });
+
+  test("gemini 类型请求 v1beta/models 并使用 x-goog-api-key", async () => {
+    findProviderByIdMock.mockResolvedValue(buildProvider({ providerType: "gemini" }));
+
+    const { testProviderConnectivity } = await import("@/actions/providers");
+    await testProviderConnectivity(7);
+
+    const [url, init] = fetchMock.mock.calls[0] ?? [];
+    expect(url).toBe("https://api.example.com/v1beta/models?pageSize=100");
+    expect(init?.headers).toEqual({ "x-goog-api-key": "sk-stored-secret" });
+  });
+
+  test("gemini header 认证 401 时与模型获取一致回退为 URL key 参数", async () => {
+    findProviderByIdMock.mockResolvedValue(buildProvider({ providerType: "gemini" }));
+    fetchMock
+      .mockResolvedValueOnce(new Response("{}", { status: 401 }))
+      .mockResolvedValueOnce(new Response('{"models":[]}', { status: 200 }));
+
+    const { testProviderConnectivity } = await import("@/actions/providers");
+    const result = await testProviderConnectivity(7);
+
+    expect(fetchMock).toHaveBeenCalledTimes(2);
+    expect(fetchMock.mock.calls[1]?.[0]).toBe(
+      "https://api.example.com/v1beta/models?pageSize=100&key=sk-stored-secret"
+    );
+    expect(result.ok).toBe(true);
+    if (result.ok) {
+      expect(result.data).toMatchObject({ success: true, httpStatus: 200 });
+    }
+  });
+
+  test("查询供应商失败时返回 ok: false 而不是抛出", async () => {
+    findProviderByIdMock.mockRejectedValue(new Error("db down"));
+
+    const { testProviderConnectivity } = await import("@/actions/providers");
+    const result = await testProviderConnectivity(7);
+
+    expect(result).toEqual({ ok: false, error: "db down" });
+    expect(fetchMock).not.toHaveBeenCalled();
+  });
+
+  test("上游 HTTP 错误返回解析后的错误信息与分类", async () => {
+    const { ErrorCategory } = await import("@/app/v1/_lib/proxy/errors");
+    categorizeErrorAsyncMock.mockResolvedValue(ErrorCategory.PROVIDER_ERROR);
+    fetchMock.mockResolvedValue(
+      new Response('{"error":{"message":"invalid x-api-key"}}', {
+        status: 401,
+        headers: { "content-type": "application/json" },
+      })
+    );
+
+    const { testProviderConnectivity } = await import("@/actions/providers");
+    const result = await testProviderConnectivity(7);
+
+    expect(result.ok).toBe(true);
+    if (result.ok) {
+      expect(result.data).toMatchObject({
+        success: false,
+        httpStatus: 401,
+        errorCategory: "provider_error",
+        errorMessage: "invalid x-api-key",
+      });
+    }
+    const categorized = categorizeErrorAsyncMock.mock.calls[0]?.[0];
+    expect(categorized?.statusCode).toBe(401);
+  });
+
+  test("网络层失败时状态码为空并归类为系统错误", async () => {
+    const { ErrorCategory } = await import("@/app/v1/_lib/proxy/errors");
+    categorizeErrorAsyncMock.mockResolvedValue(ErrorCategory.SYSTEM_ERROR);
+    fetchMock.mockRejectedValue(new TypeError("fetch failed"));
+
+    const { testProviderConnectivity } = await import("@/actions/providers");
+    const result = await testProviderConnectivity(7);
+
+    expect(result.ok).toBe(true);
+    if (result.ok) {
+      expect(result.data).toMatchObject({
+        success: false,
+        httpStatus: null,
+        errorCategory: "system_error",
+        errorMessage: "fetch failed",
+      });
+    }
+  });
+});
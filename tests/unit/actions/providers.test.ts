@@ -423,6 +423,9 @@ describe("Provider Actions - Async Optimization", () => {
           id: 1,
           today_cost: "1.23",
           today_calls: 10,
+          p50_duration_ms: 820,
+          p95_duration_ms: 2400,
+          p99_duration_ms: 5100,
           last_call_time: new Date("2026-01-01T00:00:00.000Z"),
           last_call_model: "model-a",
         },
@@ -430,6 +433,9 @@ describe("Provider Actions - Async Optimization", () => {
           id: 2,
           today_cost: "0",
           today_calls: 0,
+          p50_duration_ms: null,
+          p95_duration_ms: null,
+          p99_duration_ms: null,
           last_call_time: "2026-01-02T00:00:00.000Z",
           last_call_model: null,
         },
@@ -441,12 +447,18 @@ describe("Provider Actions - Async Optimization", () => {
       expect(result[1]).toEqual({
         todayCost: "1.23",
         todayCalls: 10,
+        p50DurationMs: 820,
+        p95DurationMs: 2400,
+        p99DurationMs: 5100,
         lastCallTime: "2026-01-01T00:00:00.000Z",
         lastCallModel: "model-a",
       });
       expect(result[2]).toEqual({
         todayCost: "0",
         todayCalls: 0,
+        p50DurationMs: null,
+        p95DurationMs: null,
+        p99DurationMs: null,
         lastCallTime: "2026-01-02T00:00:00.000Z",
         lastCallModel: null,
       });
@@ -67,6 +67,7 @@ vi.mock("@/repository/usage-logs", () => {
     findUsageLogsBatch: findUsageLogsBatchMock,
     findUsageLogsStats: findUsageLogsStatsMock,
     findUsageLogsWithDetails: findUsageLogsWithDetailsMock,
+    getUsedApiTypes: vi.fn(async () => []),
     getUsedEndpoints: vi.fn(async () => []),
     getUsedModels: vi.fn(async () => []),
     getUsedStatusCodes: vi.fn(async () => []),
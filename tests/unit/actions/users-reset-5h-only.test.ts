@@ -15,6 +15,16 @@ vi.mock("next-intl/server", () => ({
 const emitActionAuditMock = vi.fn();
 vi.mock("@/lib/audit/emit", () => ({
   emitActionAudit: (...args: unknown[]) => emitActionAuditMock(...args),
+  buildActionAuditEntry: async (args: Record<string, unknown>) => ({
+    actionCategory: args.category,
+    actionType: args.action,
+    success: args.success,
+  }),
+}));
+
+const insertAuditLogInTransactionMock = vi.fn();
+vi.mock("@/repository/audit-log", () => ({
+  insertAuditLogInTransaction: insertAuditLogInTransactionMock,
 }));
 
 const revalidatePathMock = vi.fn();
@@ -313,6 +323,10 @@ describe("full reset compatibility with user 5h marker", () => {
       })
     );
     expect(invalidateCachedUserMock).toHaveBeenCalledWith(123);
+    expect(insertAuditLogInTransactionMock).toHaveBeenCalledWith(
+      expect.anything(),
+      expect.objectContaining({ actionType: "user.reset_statistics" })
+    );
   });
 
   test("full statistics reset fails when fixed 5h state exists but Redis is unavailable", async () => {
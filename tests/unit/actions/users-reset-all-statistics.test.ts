@@ -60,6 +60,13 @@ vi.mock("@/drizzle/db", () => ({
   },
 }));
 
+// Mock audit log insert (written inside the reset transaction)
+const insertAuditLogInTransactionMock = vi.fn();
+vi.mock("@/repository/audit-log", () => ({
+  createAuditLogAsync: vi.fn(),
+  insertAuditLogInTransaction: insertAuditLogInTransactionMock,
+}));
+
 // Mock logger
 const loggerMock = {
   info: vi.fn(),
@@ -149,13 +156,23 @@ describe("resetUserAllStatistics", () => {
     redisPipelineMock.exec.mockResolvedValue([]);
 
     const { resetUserAllStatistics } = await import("@/actions/users");
-    const result = await resetUserAllStatistics(123);
+    const result = await resetUserAllStatistics(123, "tenant offboarding");
 
     expect(result.ok).toBe(true);
     // DB transaction called (delete + update wrapped in transaction)
     expect(dbTransactionMock).toHaveBeenCalled();
     expect(txDeleteMock).toHaveBeenCalled();
     expect(txDeleteWhereMock).toHaveBeenCalled();
+    // Audit row written in the same transaction, with the optional reason
+    expect(insertAuditLogInTransactionMock).toHaveBeenCalledWith(
+      txMock,
+      expect.objectContaining({
+        actionCategory: "user",
+        actionType: "user.reset_statistics",
+        targetId: "123",
+        afterValue: expect.objectContaining({ logsDeleted: true, reason: "tenant offboarding" }),
+      })
+    );
     // Redis operations
     expect(redisMock.pipeline).toHaveBeenCalled();
     expect(redisPipelineMock.del).toHaveBeenCalled();
@@ -20,6 +20,22 @@ vi.mock("next/cache", () => ({
   revalidatePath: revalidatePathMock,
 }));
 
+// Mock audit emit: the reset audit entry is passed through to the repository
+// so it can be written in the same transaction as the reset markers
+const emitActionAuditMock = vi.fn();
+vi.mock("@/lib/audit/emit", () => ({
+  emitActionAudit: (...args: unknown[]) => emitActionAuditMock(...args),
+  buildActionAuditEntry: async (args: Record<string, unknown>) => ({
+    actionCategory: args.category,
+    actionType: args.action,
+    targetId: args.targetId,
+    targetName: args.targetName,
+    beforeValue: args.before,
+    afterValue: args.after,
+    success: args.success,
+  }),
+}));
+
 // Mock repository/user
 const findUserByIdMock = vi.fn();
 const resetUserCostResetAtMock = vi.fn();
@@ -129,6 +145,7 @@ describe("resetUserLimitsOnly", () => {
     expect(result.ok).toBe(false);
     expect(result.errorCode).toBe(ERROR_CODES.NOT_FOUND);
     expect(resetUserCostResetAtMock).not.toHaveBeenCalled();
+    expect(updateUserCostResetMarkersMock).not.toHaveBeenCalled();
   });
 
   test("should set costResetAt and clear Redis cost cache", async () => {
@@ -150,7 +167,8 @@ describe("resetUserLimitsOnly", () => {
       expect.objectContaining({
         costResetAt: expect.any(Date),
         limit5hCostResetAt: expect.any(Date),
-      })
+      }),
+      expect.objectContaining({ actionType: "user.reset_limits" })
     );
     // Redis cost keys scanned and deleted
     expect(scanPatternMock).toHaveBeenCalled();
@@ -161,6 +179,38 @@ describe("resetUserLimitsOnly", () => {
     expect(revalidatePathMock).toHaveBeenCalledWith("/dashboard/users");
     // No DB deletes (messageRequest/usageLedger must NOT be deleted)
     expect(dbDeleteMock).not.toHaveBeenCalled();
+    // Audit record is written by the repository inside the reset transaction
+    expect(emitActionAuditMock).not.toHaveBeenCalled();
+    expect(updateUserCostResetMarkersMock.mock.calls[0]?.[2]).toEqual({
+      actionCategory: "user",
+      actionType: "user.reset_limits",
+      targetId: "123",
+      targetName: "Test User",
+      beforeValue: { costResetAt: null, limit5hCostResetAt: null },
+      afterValue: {
+        costResetAt: expect.any(Date),
+        limit5hCostResetAt: expect.any(Date),
+        reason: null,
+      },
+      success: true,
+    });
+  });
+
+  test("should record the reset reason in the audit entry", async () => {
+    getSessionMock.mockResolvedValue({ user: { id: 1, role: "admin" } });
+    findUserByIdMock.mockResolvedValue({ id: 123, name: "Test User" });
+    findKeyListMock.mockResolvedValue([]);
+    scanPatternMock.mockResolvedValue([]);
+
+    const { resetUserLimitsOnly } = await import("@/actions/users");
+    const result = await resetUserLimitsOnly(123, "  quota appeal #42  ");
+
+    expect(result.ok).toBe(true);
+    expect(updateUserCostResetMarkersMock.mock.calls[0]?.[2]).toEqual(
+      expect.objectContaining({
+        afterValue: expect.objectContaining({ reason: "quota appeal #42" }),
+      })
+    );
   });
 
   test("should return partial failure when fixed 5h cleanup fails after DB markers advance", async () => {
@@ -185,7 +235,8 @@ describe("resetUserLimitsOnly", () => {
       expect.objectContaining({
         costResetAt: expect.any(Date),
         limit5hCostResetAt: expect.any(Date),
-      })
+      }),
+      expect.objectContaining({ actionType: "user.reset_limits" })
     );
   });
 
@@ -218,7 +269,8 @@ describe("resetUserLimitsOnly", () => {
       expect.objectContaining({
         costResetAt: expect.any(Date),
         limit5hCostResetAt: expect.any(Date),
-      })
+      }),
+      expect.objectContaining({ actionType: "user.reset_limits" })
     );
     // Redis pipeline NOT called
     expect(redisMock.pipeline).not.toHaveBeenCalled();
@@ -290,7 +342,8 @@ describe("resetUserLimitsOnly", () => {
       expect.objectContaining({
         costResetAt: expect.any(Date),
         limit5hCostResetAt: expect.any(Date),
-      })
+      }),
+      expect.objectContaining({ actionType: "user.reset_limits" })
     );
     // No DB deletes
     expect(dbDeleteMock).not.toHaveBeenCalled();
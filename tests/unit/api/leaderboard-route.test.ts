@@ -15,9 +15,15 @@ vi.mock("@/repository/system-config", () => ({
   getSystemSettings: mocks.getSystemSettings,
 }));
 
-vi.mock("@/lib/utils", () => ({
-  formatCurrency: mocks.formatCurrency,
-}));
+vi.mock("@/lib/utils", async () => {
+  const currency = await vi.importActual<typeof import("@/lib/utils/currency")>(
+    "@/lib/utils/currency"
+  );
+  return {
+    convertCurrency: currency.convertCurrency,
+    formatCurrency: mocks.formatCurrency,
+  };
+});
 
 vi.mock("@/lib/redis", () => ({
   getLeaderboardWithCache: mocks.getLeaderboardWithCache,
@@ -44,6 +50,28 @@ describe("GET /api/leaderboard", () => {
     expect(response.status).toBe(401);
   });
 
+  it("converts formatted costs with the display rate while keeping raw USD values", async () => {
+    mocks.getSession.mockResolvedValue({ user: { id: 1, name: "u", role: "admin" } });
+    mocks.getSystemSettings.mockResolvedValue({
+      currencyDisplay: "CNY",
+      currencyDisplayRate: "7.2",
+      allowGlobalUsageView: true,
+    });
+    mocks.getLeaderboardWithCache.mockResolvedValue([
+      { userId: 1, userName: "u", totalRequests: 1, totalCost: 1.25, totalTokens: 10 },
+    ]);
+
+    const { GET } = await import("@/app/api/leaderboard/route");
+    const url = "http://localhost/api/leaderboard?scope=user&period=daily";
+    const response = await GET({ nextUrl: new URL(url) } as any);
+    const body = await response.json();
+
+    expect(response.status).toBe(200);
+    expect(body[0].totalCost).toBe(1.25);
+    expect(body[0].totalCostFormatted).toBe("9");
+    expect(mocks.formatCurrency).toHaveBeenCalledWith(expect.anything(), "CNY");
+  });
+
   it("parses and trims userTags/userGroups and caps at 20 items", async () => {
     mocks.getSession.mockResolvedValue({ user: { id: 1, name: "u", role: "admin" } });
 
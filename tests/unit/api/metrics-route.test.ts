@@ -0,0 +1,81 @@
+import { NextRequest } from "next/server";
+import { beforeEach, describe, expect, it, vi } from "vitest";
+
+// -- mocks --
+
+const mocks = vi.hoisted(() => ({
+  collectDependencyMetrics: vi.fn(),
+  adminToken: "admin-secret" as string | undefined,
+}));
+
+vi.mock("@/lib/metrics/dependencies", () => ({
+  collectDependencyMetrics: mocks.collectDependencyMetrics,
+}));
+
+vi.mock("@/lib/config/config", () => ({
+  config: {
+    auth: {
+      get adminToken() {
+        return mocks.adminToken;
+      },
+    },
+  },
+}));
+
+vi.mock("@/lib/logger", () => ({
+  logger: { warn: vi.fn(), info: vi.fn(), error: vi.fn(), debug: vi.fn() },
+}));
+
+function metricsRequest(authorization?: string) {
+  return new NextRequest("http://localhost/api/metrics", {
+    headers: authorization ? { authorization } : {},
+  });
+}
+
+describe("GET /api/metrics", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    mocks.adminToken = "admin-secret";
+    mocks.collectDependencyMetrics.mockResolvedValue(undefined);
+  });
+
+  it("rejects requests without the admin token", async () => {
+    const { GET } = await import("@/app/api/metrics/route");
+
+    expect((await GET(metricsRequest())).status).toBe(401);
+    expect((await GET(metricsRequest("Bearer wrong"))).status).toBe(401);
+    expect(mocks.collectDependencyMetrics).not.toHaveBeenCalled();
+  });
+
+  it("rejects all requests when ADMIN_TOKEN is not configured", async () => {
+    mocks.adminToken = undefined;
+    const { GET } = await import("@/app/api/metrics/route");
+
+    expect((await GET(metricsRequest("Bearer admin-secret"))).status).toBe(401);
+  });
+
+  it("returns Prometheus text for the admin token", async () => {
+    const { recordProxyRequest } = await import("@/lib/metrics");
+    recordProxyRequest("post", "/v1/messages", 200, 1500);
+
+    const { GET } = await import("@/app/api/metrics/route");
+    const response = await GET(metricsRequest("Bearer admin-secret"));
+
+    expect(response.status).toBe(200);
+    expect(response.headers.get("content-type")).toContain("text/plain; version=0.0.4");
+    const body = await response.text();
+    expect(body).toContain("# TYPE cch_proxy_request_duration_seconds histogram");
+    expect(body).toContain(
+      'cch_proxy_requests_total{method="POST",path="/v1/messages",status="200"}'
+    );
+    expect(mocks.collectDependencyMetrics).toHaveBeenCalledTimes(1);
+  });
+
+  it("still serves metrics when dependency collection fails", async () => {
+    mocks.collectDependencyMetrics.mockRejectedValue(new Error("boom"));
+    const { GET } = await import("@/app/api/metrics/route");
+
+    const response = await GET(metricsRequest("Bearer admin-secret"));
+    expect(response.status).toBe(200);
+  });
+});
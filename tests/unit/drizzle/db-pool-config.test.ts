@@ -28,6 +28,7 @@ describe("drizzle/db 连接池配置", () => {
     "DB_POOL_IDLE_TIMEOUT",
     "DB_POOL_CONNECT_TIMEOUT",
     "MESSAGE_REQUEST_WRITE_MODE",
+    "PGAPPNAME",
   ];
 
   const postgresMock = vi.fn();
@@ -46,6 +47,7 @@ describe("drizzle/db 连接池配置", () => {
     delete process.env.DB_POOL_MAX;
     delete process.env.DB_POOL_IDLE_TIMEOUT;
     delete process.env.DB_POOL_CONNECT_TIMEOUT;
+    delete process.env.PGAPPNAME;
 
     vi.doMock("postgres", () => ({ default: postgresMock }));
     vi.doMock("drizzle-orm/postgres-js", () => ({
@@ -105,4 +107,30 @@ describe("drizzle/db 连接池配置", () => {
       })
     );
   });
+
+  it("默认设置 application_name=claude-code-hub，便于在 pg_stat_activity 中识别", async () => {
+    const { getDb } = await import("@/drizzle/db");
+    getDb();
+
+    expect(postgresMock).toHaveBeenCalledWith(
+      process.env.DSN,
+      expect.objectContaining({
+        connection: { application_name: "claude-code-hub" },
+      })
+    );
+  });
+
+  it("支持通过 PGAPPNAME 覆盖 application_name", async () => {
+    process.env.PGAPPNAME = "cch-worker";
+
+    const { getDb } = await import("@/drizzle/db");
+    getDb();
+
+    expect(postgresMock).toHaveBeenCalledWith(
+      process.env.DSN,
+      expect.objectContaining({
+        connection: { application_name: "cch-worker" },
+      })
+    );
+  });
 });
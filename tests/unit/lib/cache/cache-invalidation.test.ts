@@ -0,0 +1,114 @@
+import { describe, expect, test, vi } from "vitest";
+
+vi.mock("@/lib/logger", () => ({
+  logger: { debug: vi.fn(), info: vi.fn(), warn: vi.fn(), error: vi.fn() },
+}));
+
+vi.mock("@/lib/redis/pubsub", () => ({
+  publishCacheInvalidation: vi.fn(async () => undefined),
+  subscribeCacheInvalidation: vi.fn(async () => null),
+}));
+
+import {
+  CHANNEL_CACHE_INVALIDATE,
+  type CacheInvalidationTransport,
+  createCacheInvalidationBus,
+} from "@/lib/cache/invalidation";
+
+function createFakePubSub() {
+  const listeners = new Map<string, Set<(message: string) => void>>();
+  const published: Array<{ channel: string; message: string }> = [];
+
+  const transport: CacheInvalidationTransport = {
+    async publish(channel, message) {
+      published.push({ channel, message });
+      for (const listener of listeners.get(channel) ?? []) {
+        listener(message);
+      }
+    },
+    async subscribe(channel, callback) {
+      const set = listeners.get(channel) ?? new Set();
+      set.add(callback);
+      listeners.set(channel, set);
+      return () => set.delete(callback);
+    },
+  };
+
+  return { transport, published };
+}
+
+function createLocalCache(bus: ReturnType<typeof createCacheInvalidationBus>, name: string) {
+  const store = new Map<string, number>([
+    ["a", 1],
+    ["b", 2],
+  ]);
+  bus.register(name, (key) => {
+    if (key === null) store.clear();
+    else store.delete(key);
+  });
+  return store;
+}
+
+describe("createCacheInvalidationBus", () => {
+  test("invalidates the matching entry on every instance sharing the channel", async () => {
+    const { transport, published } = createFakePubSub();
+    const podA = createCacheInvalidationBus(transport, { instanceId: "pod-a" });
+    const podB = createCacheInvalidationBus(transport, { instanceId: "pod-b" });
+    const cacheA = createLocalCache(podA, "keys");
+    const cacheB = createLocalCache(podB, "keys");
+    const otherB = createLocalCache(podB, "providers");
+    await Promise.resolve();
+
+    await podA.invalidate("keys", "a");
+
+    expect(published).toEqual([
+      {
+        channel: CHANNEL_CACHE_INVALIDATE,
+        message: JSON.stringify({ cache: "keys", key: "a", origin: "pod-a" }),
+      },
+    ]);
+    expect([...cacheA.keys()]).toEqual(["b"]);
+    expect([...cacheB.keys()]).toEqual(["b"]);
+    expect([...otherB.keys()]).toEqual(["a", "b"]);
+  });
+
+  test("clears the whole cache when no key is given and skips its own echo", async () => {
+    const { transport } = createFakePubSub();
+    const podA = createCacheInvalidationBus(transport, { instanceId: "pod-a" });
+    const podB = createCacheInvalidationBus(transport, { instanceId: "pod-b" });
+    const handlerA = vi.fn();
+    podA.register("providers", handlerA);
+    const cacheB = createLocalCache(podB, "providers");
+    await Promise.resolve();
+
+    await podA.invalidate("providers");
+
+    expect(handlerA).toHaveBeenCalledTimes(1);
+    expect(handlerA).toHaveBeenCalledWith(null);
+    expect(cacheB.size).toBe(0);
+  });
+
+  test("stays local-only when the transport cannot subscribe (Redis disabled)", async () => {
+    const podA = createCacheInvalidationBus(
+      { publish: vi.fn(async () => undefined), subscribe: vi.fn(async () => null) },
+      { instanceId: "pod-a" }
+    );
+    const cacheA = createLocalCache(podA, "keys");
+
+    await podA.invalidate("keys", "b");
+
+    expect([...cacheA.keys()]).toEqual(["a"]);
+  });
+
+  test("ignores malformed messages", async () => {
+    const { transport } = createFakePubSub();
+    const podB = createCacheInvalidationBus(transport, { instanceId: "pod-b" });
+    const cacheB = createLocalCache(podB, "keys");
+    await Promise.resolve();
+
+    await transport.publish(CHANNEL_CACHE_INVALIDATE, "not-json");
+    await transport.publish(CHANNEL_CACHE_INVALIDATE, JSON.stringify({ key: "a" }));
+
+    expect(cacheB.size).toBe(2);
+  });
+});
@@ -0,0 +1,71 @@
+import { describe, expect, test } from "vitest";
+import { calculateRequestCostFromMessageRequest } from "@/lib/utils/cost-calculation";
+import type { ModelPriceData } from "@/types/model-price";
+
+function makePriceData(overrides: Partial<ModelPriceData> = {}): ModelPriceData {
+  return {
+    input_cost_per_token: 0.000003, // $3/MTok
+    output_cost_per_token: 0.000015, // $15/MTok
+    cache_creation_input_token_cost: 0.00000375, // 1.25x input (5m rate)
+    cache_read_input_token_cost: 0.0000003, // 0.1x input
+    cache_creation_input_token_cost_above_1hr: 0.000006, // 2x input (1h rate)
+    ...overrides,
+  };
+}
+
+describe("calculateRequestCostFromMessageRequest", () => {
+  test("bills 5m and 1h cache creation tokens at their own tiers and applies the stored multiplier", () => {
+    const cost = calculateRequestCostFromMessageRequest(
+      {
+        inputTokens: 1000,
+        outputTokens: 500,
+        cacheCreationInputTokens: 3000,
+        cacheCreation5mInputTokens: 2000,
+        cacheCreation1hInputTokens: 1000,
+        cacheReadInputTokens: 4000,
+        cacheTtlApplied: "mixed",
+        costMultiplier: 2,
+      },
+      makePriceData()
+    );
+
+    // (0.003 + 0.0075 + 0.0075 + 0.006 + 0.0012) * 2
+    expect(Number(cost.toString())).toBeCloseTo(0.0504, 9);
+  });
+
+  test("assigns unsplit cache creation tokens by cacheTtlApplied", () => {
+    const oneHour = calculateRequestCostFromMessageRequest(
+      { cacheCreationInputTokens: 1000, cacheTtlApplied: "1h" },
+      makePriceData()
+    );
+    const fiveMinutes = calculateRequestCostFromMessageRequest(
+      { cacheCreationInputTokens: 1000, cacheTtlApplied: null },
+      makePriceData()
+    );
+
+    expect(Number(oneHour.toString())).toBeCloseTo(0.006, 9);
+    expect(Number(fiveMinutes.toString())).toBeCloseTo(0.00375, 9);
+  });
+
+  test("applies 200k long-context pricing when input exceeds the threshold", () => {
+    const cost = calculateRequestCostFromMessageRequest(
+      { inputTokens: 250000, outputTokens: 100000 },
+      makePriceData({
+        input_cost_per_token_above_200k_tokens: 0.000006,
+        output_cost_per_token_above_200k_tokens: 0.0000225,
+      })
+    );
+
+    expect(Number(cost.toString())).toBe(3.75);
+  });
+
+  test("applies caller-provided group multiplier on top of the stored provider multiplier", () => {
+    const cost = calculateRequestCostFromMessageRequest(
+      { inputTokens: 1000, costMultiplier: 1.5 },
+      makePriceData(),
+      { groupMultiplier: 2 }
+    );
+
+    expect(Number(cost.toString())).toBeCloseTo(0.009, 9);
+  });
+});
@@ -0,0 +1,71 @@
+import { describe, expect, test } from "vitest";
+import {
+  calculateImageGenerationCost,
+  calculateRequestCost,
+  calculateRequestCostBreakdown,
+} from "@/lib/utils/cost-calculation";
+import type { ModelPriceData } from "@/types/model-price";
+
+const imagePriceData: ModelPriceData = {
+  mode: "image_generation",
+  input_cost_per_token: 0.000005,
+  output_cost_per_token: 0.00004,
+  output_cost_per_image: 0.04,
+};
+
+describe("calculateImageGenerationCost", () => {
+  test("按单价 × 张数计费", () => {
+    expect(calculateImageGenerationCost(imagePriceData, 3).toString()).toBe("0.12");
+  });
+
+  test("未配置单价或张数非正时返回 0", () => {
+    expect(calculateImageGenerationCost({}, 3).toString()).toBe("0");
+    expect(calculateImageGenerationCost(imagePriceData, 0).toString()).toBe("0");
+    expect(calculateImageGenerationCost(imagePriceData, undefined).toString()).toBe("0");
+  });
+});
+
+describe("calculateRequestCost - image generation", () => {
+  test("image_generation 模式按张计费，输出 token 不再计价", () => {
+    const cost = calculateRequestCost(
+      { input_tokens: 100, output_tokens: 4000, output_images: 2 },
+      imagePriceData
+    );
+
+    // 100 * 0.000005 + 2 * 0.04
+    expect(cost.toString()).toBe("0.0805");
+  });
+
+  test("chat 模式忽略图片张数，仍按 token 计费", () => {
+    const cost = calculateRequestCost(
+      { input_tokens: 100, output_tokens: 4000, output_images: 2 },
+      { ...imagePriceData, mode: "chat" }
+    );
+
+    // 100 * 0.000005 + 4000 * 0.00004
+    expect(cost.toString()).toBe("0.1605");
+  });
+
+  test("未返回图片张数时回退到 token 计费", () => {
+    const cost = calculateRequestCost({ input_tokens: 100, output_tokens: 4000 }, imagePriceData);
+
+    expect(cost.toString()).toBe("0.1605");
+  });
+
+  test("按张费用计入倍率", () => {
+    const cost = calculateRequestCost({ output_images: 1 }, imagePriceData, 2);
+
+    expect(cost.toString()).toBe("0.08");
+  });
+
+  test("breakdown 将按张费用计入 output 桶", () => {
+    const breakdown = calculateRequestCostBreakdown(
+      { input_tokens: 100, output_tokens: 4000, output_images: 2 },
+      imagePriceData
+    );
+
+    expect(breakdown.input).toBe(0.0005);
+    expect(breakdown.output).toBe(0.08);
+    expect(breakdown.total).toBe(0.0805);
+  });
+});
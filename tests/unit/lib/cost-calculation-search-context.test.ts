@@ -0,0 +1,62 @@
+import { describe, expect, test } from "vitest";
+import {
+  calculateRequestCost,
+  calculateRequestCostBreakdown,
+  calculateSearchContextCost,
+} from "@/lib/utils/cost-calculation";
+import type { ModelPriceData } from "@/types/model-price";
+
+const priceData: ModelPriceData = {
+  input_cost_per_token: 0.000001,
+  output_cost_per_token: 0.000002,
+  search_context_cost_per_query: {
+    search_context_size_low: 0.005,
+    search_context_size_medium: 0.01,
+    search_context_size_high: 0.025,
+  },
+};
+
+describe("calculateSearchContextCost", () => {
+  test.each([
+    ["low", "0.015"],
+    ["medium", "0.03"],
+    ["high", "0.075"],
+  ])("%s 档位按单价 × 查询次数计费", (contextSize, expected) => {
+    expect(calculateSearchContextCost(priceData, contextSize, 3).toString()).toBe(expected);
+  });
+
+  test("未知档位、未配置单价或查询次数非正时返回 0", () => {
+    expect(calculateSearchContextCost(priceData, "ultra", 3).toString()).toBe("0");
+    expect(calculateSearchContextCost(priceData, undefined, 3).toString()).toBe("0");
+    expect(calculateSearchContextCost({}, "high", 3).toString()).toBe("0");
+    expect(
+      calculateSearchContextCost(
+        { search_context_cost_per_query: { search_context_size_low: 0.005 } },
+        "high",
+        3
+      ).toString()
+    ).toBe("0");
+    expect(calculateSearchContextCost(priceData, "high", 0).toString()).toBe("0");
+  });
+});
+
+describe("calculateRequestCost: web search", () => {
+  test("搜索费用与 token 费用叠加，默认按 medium 档位计价", () => {
+    const cost = calculateRequestCost(
+      { input_tokens: 1000, output_tokens: 1000, web_search_requests: 2 },
+      priceData
+    );
+
+    expect(cost.toString()).toBe("0.023");
+  });
+
+  test("显式档位与倍率生效，并计入明细的 input 桶", () => {
+    const usage = { web_search_requests: 1, search_context_size: "high" as const };
+
+    expect(calculateRequestCost(usage, priceData, 2).toString()).toBe("0.05");
+    expect(calculateRequestCostBreakdown(usage, priceData)).toMatchObject({
+      input: 0.025,
+      total: 0.025,
+    });
+  });
+});
@@ -11,6 +11,7 @@ const mocks = vi.hoisted(() => ({
   },
   loggerWarn: vi.fn(),
   loggerError: vi.fn(),
+  checkMigrationStatus: vi.fn(async () => "up_to_date"),
 }));
 
 vi.mock("@/drizzle/db", () => ({
@@ -40,6 +41,10 @@ vi.mock("@/app/v1/[...route]/route", () => ({
   v1App: mocks.v1App,
 }));
 
+vi.mock("@/lib/health/migrations", () => ({
+  checkMigrationStatus: mocks.checkMigrationStatus,
+}));
+
 // -- tests --
 
 const originalDsn = process.env.DSN;
@@ -331,6 +336,42 @@ describe("health/checker", () => {
       expect(result.components?.redis?.status).toBe("unchecked");
     });
 
+    it("returns degraded with migrations=pending when migrations are not applied", async () => {
+      process.env.DSN = "postgres://test";
+      mocks.dbExecute.mockResolvedValue([{ "?column?": 1 }]);
+      mocks.getRedisClient.mockReturnValue(null);
+      mocks.checkMigrationStatus.mockResolvedValueOnce("pending");
+      mocks.v1App.request.mockResolvedValue(new Response('{"status":"pong"}', { status: 200 }));
+      const { checkReadiness } = await import("@/lib/health/checker");
+      const result = await checkReadiness();
+      expect(result.status).toBe("degraded");
+      expect(result.migrations).toBe("pending");
+      expect(result.components?.database?.status).toBe("up");
+    });
+
+    it("reports migrations=up_to_date when the database is current", async () => {
+      process.env.DSN = "postgres://test";
+      mocks.dbExecute.mockResolvedValue([{ "?column?": 1 }]);
+      mocks.getRedisClient.mockReturnValue(null);
+      mocks.v1App.request.mockResolvedValue(new Response('{"status":"pong"}', { status: 200 }));
+      const { checkReadiness } = await import("@/lib/health/checker");
+      const result = await checkReadiness();
+      expect(result.status).toBe("healthy");
+      expect(result.migrations).toBe("up_to_date");
+    });
+
+    it("skips the migrations check when DB is down", async () => {
+      process.env.DSN = "postgres://test";
+      mocks.dbExecute.mockRejectedValue(new Error("connection refused"));
+      mocks.getRedisClient.mockReturnValue(null);
+      mocks.v1App.request.mockResolvedValue(new Response('{"status":"pong"}', { status: 200 }));
+      const { checkReadiness } = await import("@/lib/health/checker");
+      const result = await checkReadiness();
+      expect(result.status).toBe("unhealthy");
+      expect(result.migrations).toBeUndefined();
+      expect(mocks.checkMigrationStatus).not.toHaveBeenCalled();
+    });
+
     it("returns healthy when DB is unchecked in test mode", async () => {
       process.env.REDIS_URL = "redis://localhost:6379";
       mocks.getRedisClient.mockReturnValue({
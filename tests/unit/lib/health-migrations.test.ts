@@ -0,0 +1,66 @@
+import { beforeEach, describe, expect, it, vi } from "vitest";
+
+const mocks = vi.hoisted(() => ({
+  readFile: vi.fn(),
+  dbExecute: vi.fn(),
+}));
+
+vi.mock("node:fs/promises", () => ({
+  readFile: mocks.readFile,
+}));
+
+vi.mock("@/drizzle/db", () => ({
+  db: { execute: mocks.dbExecute },
+}));
+
+function journal(...whens: number[]): string {
+  return JSON.stringify({ entries: whens.map((when, idx) => ({ idx, when })) });
+}
+
+describe("health/migrations", () => {
+  beforeEach(() => {
+    vi.clearAllMocks();
+    vi.resetModules();
+  });
+
+  it("returns up_to_date when the latest applied migration matches the journal", async () => {
+    mocks.readFile.mockResolvedValue(journal(1000, 3000, 2000));
+    mocks.dbExecute
+      .mockResolvedValueOnce([{ exists: true }])
+      .mockResolvedValueOnce([{ version: "3000" }]);
+
+    const { checkMigrationStatus } = await import("@/lib/health/migrations");
+
+    expect(await checkMigrationStatus()).toBe("up_to_date");
+  });
+
+  it("returns pending when the database is behind the journal", async () => {
+    mocks.readFile.mockResolvedValue(journal(1000, 3000));
+    mocks.dbExecute
+      .mockResolvedValueOnce([{ exists: true }])
+      .mockResolvedValueOnce([{ version: 1000 }]);
+
+    const { checkMigrationStatus } = await import("@/lib/health/migrations");
+
+    expect(await checkMigrationStatus()).toBe("pending");
+  });
+
+  it("returns pending when the migrations table does not exist", async () => {
+    mocks.readFile.mockResolvedValue(journal(1000));
+    mocks.dbExecute.mockResolvedValueOnce([{ exists: false }]);
+
+    const { checkMigrationStatus } = await import("@/lib/health/migrations");
+
+    expect(await checkMigrationStatus()).toBe("pending");
+    expect(mocks.dbExecute).toHaveBeenCalledTimes(1);
+  });
+
+  it("returns unchecked without querying when the journal is unreadable", async () => {
+    mocks.readFile.mockRejectedValue(new Error("ENOENT"));
+
+    const { checkMigrationStatus } = await import("@/lib/health/migrations");
+
+    expect(await checkMigrationStatus()).toBe("unchecked");
+    expect(mocks.dbExecute).not.toHaveBeenCalled();
+  });
+});
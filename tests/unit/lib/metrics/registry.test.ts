@@ -0,0 +1,64 @@
+import { describe, expect, test } from "vitest";
+import { normalizeProxyPath } from "@/lib/metrics";
+import { Counter, Gauge, Histogram, MetricsRegistry } from "@/lib/metrics/registry";
+
+describe("MetricsRegistry", () => {
+  test("renders counters and gauges in Prometheus text format", () => {
+    const registry = new MetricsRegistry();
+    const counter = registry.register(new Counter("test_total", "Test counter", ["path"]));
+    const gauge = registry.register(new Gauge("test_up", "Test gauge", ["component"]));
+
+    counter.inc({ path: "/v1/messages" });
+    counter.inc({ path: "/v1/messages" }, 2);
+    gauge.set({ component: "database" }, 1);
+
+    expect(registry.render()).toBe(
+      [
+        "# HELP test_total Test counter",
+        "# TYPE test_total counter",
+        'test_total{path="/v1/messages"} 3',
+        "# HELP test_up Test gauge",
+        "# TYPE test_up gauge",
+        'test_up{component="database"} 1',
+        "",
+      ].join("\n")
+    );
+  });
+
+  test("renders cumulative histogram buckets with +Inf, sum and count", () => {
+    const registry = new MetricsRegistry();
+    const histogram = registry.register(
+      new Histogram("test_seconds", "Test histogram", ["status"], [1, 60, 300])
+    );
+
+    histogram.observe({ status: 200 }, 0.5);
+    histogram.observe({ status: 200 }, 90);
+    histogram.observe({ status: 200 }, 1200);
+
+    const output = registry.render();
+    expect(output).toContain('test_seconds_bucket{status="200",le="1"} 1');
+    expect(output).toContain('test_seconds_bucket{status="200",le="60"} 1');
+    expect(output).toContain('test_seconds_bucket{status="200",le="300"} 2');
+    expect(output).toContain('test_seconds_bucket{status="200",le="+Inf"} 3');
+    expect(output).toContain('test_seconds_sum{status="200"} 1290.5');
+    expect(output).toContain('test_seconds_count{status="200"} 3');
+  });
+
+  test("escapes label values and rejects duplicate registration", () => {
+    const registry = new MetricsRegistry();
+    const counter = registry.register(new Counter("escape_total", "Escape", ["value"]));
+    counter.inc({ value: 'a"b\\c\nd' });
+
+    expect(registry.render()).toContain('escape_total{value="a\\"b\\\\c\\nd"} 1');
+    expect(() => registry.register(new Counter("escape_total", "Again"))).toThrow();
+  });
+});
+
+describe("normalizeProxyPath", () => {
+  test("keeps known endpoints and collapses the rest", () => {
+    expect(normalizeProxyPath("/v1/messages")).toBe("/v1/messages");
+    expect(normalizeProxyPath("/v1/chat/completions/")).toBe("/v1/chat/completions");
+    expect(normalizeProxyPath("/v1/messages?beta=true")).toBe("/v1/messages");
+    expect(normalizeProxyPath("/v1/some/random/path")).toBe("other");
+  });
+});
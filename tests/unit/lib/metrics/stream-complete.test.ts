@@ -0,0 +1,48 @@
+import { describe, expect, test, vi } from "vitest";
+import { onStreamComplete } from "@/lib/metrics";
+
+function sseBody(chunks: string[]): ReadableStream<Uint8Array> {
+  const encoder = new TextEncoder();
+  return new ReadableStream({
+    start(controller) {
+      for (const chunk of chunks) controller.enqueue(encoder.encode(chunk));
+      controller.close();
+    },
+  });
+}
+
+describe("onStreamComplete", () => {
+  test("fires once after the whole body has been read", async () => {
+    const onComplete = vi.fn();
+    const response = new Response(
+      onStreamComplete(sseBody(["data: a\n\n", "data: b\n\n"]), onComplete)
+    );
+
+    expect(onComplete).not.toHaveBeenCalled();
+    expect(await response.text()).toBe("data: a\n\ndata: b\n\n");
+    expect(onComplete).toHaveBeenCalledTimes(1);
+  });
+
+  test("fires when the client cancels the stream", async () => {
+    const onComplete = vi.fn();
+    const reader = onStreamComplete(sseBody(["data: a\n\n"]), onComplete).getReader();
+
+    await reader.cancel("client disconnected");
+
+    expect(onComplete).toHaveBeenCalledTimes(1);
+  });
+
+  test("fires when the upstream body errors", async () => {
+    const onComplete = vi.fn();
+    const failing = new ReadableStream<Uint8Array>({
+      pull(controller) {
+        controller.error(new Error("upstream reset"));
+      },
+    });
+
+    await expect(new Response(onStreamComplete(failing, onComplete)).text()).rejects.toThrow(
+      "upstream reset"
+    );
+    expect(onComplete).toHaveBeenCalledTimes(1);
+  });
+});
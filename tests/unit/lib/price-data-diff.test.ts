@@ -0,0 +1,40 @@
+import { describe, expect, test } from "vitest";
+import { diffPriceData } from "@/lib/utils/price-data";
+
+describe("diffPriceData", () => {
+  test("相同价格数据应返回空列表", () => {
+    const priceData = { input_cost_per_token: 0.000003, aliases: ["a", "b"] };
+    expect(diffPriceData(priceData, { ...priceData, aliases: ["a", "b"] })).toEqual([]);
+  });
+
+  test("应返回新增、删除和修改的字段，并按字段名排序", () => {
+    expect(
+      diffPriceData(
+        { output_cost_per_token: 0.000015, input_cost_per_token: 0.000003, mode: "chat" },
+        { output_cost_per_token: 0.00001, input_cost_per_token: 0.000003, supports_vision: true }
+      )
+    ).toEqual([
+      { field: "mode", before: "chat", after: undefined },
+      { field: "output_cost_per_token", before: 0.000015, after: 0.00001 },
+      { field: "supports_vision", before: undefined, after: true },
+    ]);
+  });
+
+  test("嵌套对象应展开为点号路径", () => {
+    expect(
+      diffPriceData(
+        { long_context_pricing: { threshold_tokens: 272000, input_multiplier: 2 } },
+        { long_context_pricing: { threshold_tokens: 272000, input_multiplier: 1.5 } }
+      )
+    ).toEqual([{ field: "long_context_pricing.input_multiplier", before: 2, after: 1.5 }]);
+  });
+
+  test("数组变化和缺失的一侧应整体记录", () => {
+    expect(diffPriceData(null, { aliases: ["x"] })).toEqual([
+      { field: "aliases", before: undefined, after: ["x"] },
+    ]);
+    expect(diffPriceData({ aliases: ["x"] }, { aliases: ["x", "y"] })).toEqual([
+      { field: "aliases", before: ["x"], after: ["x", "y"] },
+    ]);
+  });
+});
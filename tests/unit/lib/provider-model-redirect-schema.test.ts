@@ -78,4 +78,32 @@ describe("provider-model-redirect-schema", () => {
       expect(result.success).toBe(false);
     });
   });
+  it("rejects an exact redirect pointing a model to itself", () => {
+    const result = PROVIDER_MODEL_REDIRECT_RULE_LIST_SCHEMA.safeParse([
+      { matchType: "exact", source: "gpt-4o", target: " gpt-4o " },
+    ]);
+
+    expect(result.success).toBe(false);
+  });
+
+  it("rejects exact redirects that form a cycle", () => {
+    const result = PROVIDER_MODEL_REDIRECT_RULE_LIST_SCHEMA.safeParse([
+      { matchType: "exact", source: "a", target: "b" },
+      { matchType: "exact", source: "b", target: "c" },
+      { matchType: "exact", source: "c", target: "a" },
+    ]);
+
+    expect(result.success).toBe(false);
+    expect(result.error?.issues[0]?.message).toContain("a -> b -> c -> a");
+  });
+
+  it("accepts redirect chains without cycles", () => {
+    const result = PROVIDER_MODEL_REDIRECT_RULE_LIST_SCHEMA.safeParse([
+      { matchType: "exact", source: "a", target: "b" },
+      { matchType: "exact", source: "b", target: "c" },
+      { matchType: "prefix", source: "c", target: "a" },
+    ]);
+
+    expect(result.success).toBe(true);
+  });
 });
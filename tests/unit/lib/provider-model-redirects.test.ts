@@ -1,6 +1,7 @@
 import { describe, expect, it } from "vitest";
 import type { ProviderModelRedirectRule } from "@/types/provider";
 import {
+  buildModelRedirectSpecialSetting,
   findMatchingProviderModelRedirectRule,
   normalizeProviderModelRedirectRules,
 } from "@/lib/provider-model-redirects";
@@ -79,3 +80,28 @@ describe("provider model redirect rules", () => {
     );
   });
 });
+
+describe("buildModelRedirectSpecialSetting", () => {
+  const provider = { id: 7, name: "glm-relay", providerType: "claude" as const };
+
+  it("records the model path change for an effective redirect", () => {
+    expect(buildModelRedirectSpecialSetting(provider, "claude-sonnet-4-5", "glm-4.6")).toEqual({
+      type: "provider_parameter_override",
+      scope: "provider",
+      providerId: 7,
+      providerName: "glm-relay",
+      providerType: "claude",
+      hit: true,
+      changed: true,
+      changes: [{ path: "model", before: "claude-sonnet-4-5", after: "glm-4.6", changed: true }],
+    });
+  });
+
+  it("marks a same-name redirect as hit but unchanged", () => {
+    const setting = buildModelRedirectSpecialSetting(provider, "glm-4.6", "glm-4.6");
+
+    expect(setting.hit).toBe(true);
+    expect(setting.changed).toBe(false);
+    expect(setting.changes[0]?.changed).toBe(false);
+  });
+});
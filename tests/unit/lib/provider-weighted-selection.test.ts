@@ -0,0 +1,94 @@
+import { describe, expect, it } from "vitest";
+import { getEffectiveWeight, selectWeightedProvider } from "@/lib/provider-weighted-selection";
+
+/**
+ * 确定性伪随机数（mulberry32），用于稳定的分布断言
+ */
+function seededRandom(seed: number): () => number {
+  let state = seed >>> 0;
+  return () => {
+    state = (state + 0x6d2b79f5) >>> 0;
+    let t = state;
+    t = Math.imul(t ^ (t >>> 15), t | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+}
+
+describe("selectWeightedProvider", () => {
+  it("returns null for an empty list", () => {
+    expect(selectWeightedProvider([])).toBeNull();
+  });
+
+  it("only selects from the highest-priority (lowest number) tier", () => {
+    const providers = [
+      { id: 1, priority: 1, weight: 100 },
+      { id: 2, priority: 0, weight: 1 },
+      { id: 3, priority: 0, weight: 1 },
+    ];
+    const random = seededRandom(42);
+
+    for (let i = 0; i < 50; i++) {
+      expect(selectWeightedProvider(providers, { random: random })?.id).not.toBe(1);
+    }
+  });
+
+  it("uses the injected priority resolver for tiering (e.g. group overrides)", () => {
+    const providers = [
+      { id: 1, priority: 0, weight: 1, groupPriority: 5 },
+      { id: 2, priority: 9, weight: 1, groupPriority: 1 },
+    ];
+
+    const selected = selectWeightedProvider(providers, {
+      random: () => 0,
+      resolvePriority: (p) => p.groupPriority,
+    });
+
+    expect(selected?.id).toBe(2);
+  });
+
+  it("picks proportionally to weight at the boundaries", () => {
+    const providers = [
+      { id: 1, priority: 0, weight: 1 },
+      { id: 2, priority: 0, weight: 3 },
+    ];
+
+    expect(selectWeightedProvider(providers, { random: () => 0 })?.id).toBe(1);
+    expect(selectWeightedProvider(providers, { random: () => 0.24 })?.id).toBe(1);
+    expect(selectWeightedProvider(providers, { random: () => 0.25 })?.id).toBe(2);
+    expect(selectWeightedProvider(providers, { random: () => 0.999 })?.id).toBe(2);
+  });
+
+  it("treats zero weight as 1 so such providers are not starved", () => {
+    const providers = [
+      { id: 1, priority: 0, weight: 0 },
+      { id: 2, priority: 0, weight: 1 },
+    ];
+
+    expect(selectWeightedProvider(providers, { random: () => 0.1 })?.id).toBe(1);
+    expect(selectWeightedProvider(providers, { random: () => 0.6 })?.id).toBe(2);
+  });
+
+  it("is deterministic for the same seed", () => {
+    const providers = [
+      { id: 1, priority: 0, weight: 5 },
+      { id: 2, priority: 0, weight: 3 },
+      { id: 3, priority: 0, weight: 2 },
+    ];
+
+    const pick = (seed: number) => {
+      const random = seededRandom(seed);
+      return Array.from({ length: 20 }, () => selectWeightedProvider(providers, { random: random })?.id);
+    };
+
+    expect(pick(7)).toEqual(pick(7));
+  });
+});
+
+describe("getEffectiveWeight", () => {
+  it("treats non-positive weights as 1 so reported probabilities match selection", () => {
+    expect(getEffectiveWeight({ weight: 5 })).toBe(5);
+    expect(getEffectiveWeight({ weight: 0 })).toBe(1);
+    expect(getEffectiveWeight({ weight: -3 })).toBe(1);
+  });
+});
@@ -0,0 +1,92 @@
+import { beforeEach, describe, expect, it, vi } from "vitest";
+
+const { sumKeyCostInTimeRange } = vi.hoisted(() => {
+  const entries = [
+    { createdAt: new Date("2026-03-09T08:00:00.000Z"), costUsd: 4 }, // 上海 16:00（前一日）
+    { createdAt: new Date("2026-03-10T09:50:00.000Z"), costUsd: 2 }, // 上海 17:50（重置前）
+    { createdAt: new Date("2026-03-10T10:10:00.000Z"), costUsd: 1 }, // 上海 18:10（重置后）
+  ];
+
+  return {
+    sumKeyCostInTimeRange: vi.fn(async (_keyId: number, start: Date, end: Date, _clip?: unknown) =>
+      entries
+        .filter((entry) => entry.createdAt >= start && entry.createdAt < end)
+        .reduce((total, entry) => total + entry.costUsd, 0)
+    ),
+  };
+});
+
+vi.mock("@/repository/statistics", () => ({ sumKeyCostInTimeRange }));
+vi.mock("@/lib/utils/timezone", () => ({
+  resolveSystemTimezone: vi.fn(async () => "Asia/Shanghai"),
+}));
+
+import {
+  computeDailySpend,
+  computeFixedDailySpend,
+  computeRollingDailySpend,
+} from "@/lib/rate-limit/daily-spend";
+import { getDailyTimeRange } from "@/lib/rate-limit/time-utils";
+
+describe("getDailyTimeRange", () => {
+  it("rolling window covers the past 24h", () => {
+    const now = new Date("2026-03-10T10:30:00.000Z");
+    const range = getDailyTimeRange("rolling", "18:00", "Asia/Shanghai", now);
+
+    expect(range.startTime.toISOString()).toBe("2026-03-09T10:30:00.000Z");
+    expect(range.endTime).toBe(now);
+  });
+
+  it("fixed window starts at the latest reset in the given timezone", () => {
+    const before = getDailyTimeRange(
+      "fixed",
+      "18:00",
+      "Asia/Shanghai",
+      new Date("2026-03-10T09:59:00.000Z")
+    );
+    const after = getDailyTimeRange(
+      "fixed",
+      "18:00",
+      "Asia/Shanghai",
+      new Date("2026-03-10T10:00:00.000Z")
+    );
+
+    expect(before.startTime.toISOString()).toBe("2026-03-09T10:00:00.000Z");
+    expect(after.startTime.toISOString()).toBe("2026-03-10T10:00:00.000Z");
+  });
+});
+
+describe("computeDailySpend", () => {
+  beforeEach(() => {
+    sumKeyCostInTimeRange.mockClear();
+  });
+
+  it("fixed mode drops spend from before the reset boundary", async () => {
+    const beforeReset = new Date("2026-03-10T09:55:00.000Z");
+    const afterReset = new Date("2026-03-10T10:30:00.000Z");
+
+    expect(await computeFixedDailySpend(1, "18:00", undefined, beforeReset)).toBe(2);
+    expect(await computeFixedDailySpend(1, "18:00", undefined, afterReset)).toBe(1);
+  });
+
+  it("rolling mode keeps spend across the reset boundary", async () => {
+    const now = new Date("2026-03-10T10:30:00.000Z");
+
+    expect(await computeRollingDailySpend(1, now)).toBe(3);
+    expect(await computeDailySpend(1, { mode: "rolling", now })).toBe(3);
+  });
+
+  it("passes clipStart through to the repository", async () => {
+    const now = new Date("2026-03-10T10:30:00.000Z");
+    const clipStart = new Date("2026-03-10T10:00:00.000Z");
+
+    await computeDailySpend(7, { mode: "rolling", now, clipStart });
+
+    expect(sumKeyCostInTimeRange).toHaveBeenCalledWith(
+      7,
+      new Date("2026-03-09T10:30:00.000Z"),
+      now,
+      clipStart
+    );
+  });
+});
@@ -0,0 +1,71 @@
+import { describe, expect, it } from "vitest";
+import {
+  extractRateLimitMetadataFromErrorMessage,
+  parseRateLimitMetadata,
+  resolveRateLimitMetadata,
+} from "@/lib/rate-limit/metadata";
+
+const stored = {
+  type: "rate_limit_error",
+  limit_type: "usd_5h",
+  current_usage: 5.5,
+  limit_value: 5,
+  reset_time: "2026-05-01T00:00:00.000Z",
+  provider_id: null,
+  message: "5h limit exceeded",
+};
+
+describe("parseRateLimitMetadata", () => {
+  it("parses the stored column value", () => {
+    expect(parseRateLimitMetadata(stored)).toEqual(stored);
+  });
+
+  it("rejects non-objects and unknown limit types", () => {
+    expect(parseRateLimitMetadata(null)).toBeNull();
+    expect(parseRateLimitMetadata("rpm")).toBeNull();
+    expect(parseRateLimitMetadata([stored])).toBeNull();
+    expect(parseRateLimitMetadata({ ...stored, limit_type: "unknown" })).toBeNull();
+  });
+
+  it("accepts the legacy current field and numeric strings", () => {
+    const metadata = parseRateLimitMetadata({ limit_type: "rpm", current: "95", limit_value: 90 });
+
+    expect(metadata?.current_usage).toBe(95);
+    expect(metadata?.limit_value).toBe(90);
+    expect(metadata?.reset_time).toBeNull();
+  });
+});
+
+describe("extractRateLimitMetadataFromErrorMessage", () => {
+  it("parses metadata appended to legacy error messages", () => {
+    const message = `5h limit exceeded | rate_limit_metadata: ${JSON.stringify(stored)}`;
+
+    expect(extractRateLimitMetadataFromErrorMessage(message)).toEqual(stored);
+  });
+
+  it("returns null for messages without valid metadata", () => {
+    expect(extractRateLimitMetadataFromErrorMessage(null)).toBeNull();
+    expect(extractRateLimitMetadataFromErrorMessage("upstream failed")).toBeNull();
+    expect(extractRateLimitMetadataFromErrorMessage("x | rate_limit_metadata: {broken")).toBeNull();
+  });
+});
+
+describe("resolveRateLimitMetadata", () => {
+  it("prefers the structured column over the error message", () => {
+    const metadata = resolveRateLimitMetadata({
+      rateLimitMetadata: stored,
+      errorMessage: 'x | rate_limit_metadata: {"limit_type":"rpm","current":1}',
+    });
+
+    expect(metadata?.limit_type).toBe("usd_5h");
+  });
+
+  it("falls back to the error message for old rows", () => {
+    const metadata = resolveRateLimitMetadata({
+      rateLimitMetadata: null,
+      errorMessage: 'x | rate_limit_metadata: {"limit_type":"rpm","current":1}',
+    });
+
+    expect(metadata?.limit_type).toBe("rpm");
+  });
+});
@@ -33,6 +33,10 @@ const makePipeline = () => {
       pipelineCalls.push(["zadd", ...args]);
       return pipeline;
     }),
+    zrange: vi.fn((...args: unknown[]) => {
+      pipelineCalls.push(["zrange", ...args]);
+      return pipeline;
+    }),
     exec: vi.fn(async () => {
       pipelineCalls.push(["exec"]);
       return [];
@@ -338,6 +342,47 @@ describe("RateLimitService - other quota paths", () => {
     expect(writePipeline.zadd).toHaveBeenCalledTimes(1);
   });
 
+  it("checkUserRPM：拦截时 resetAt 应为窗口内最早请求 + 60s", async () => {
+    const { RateLimitService } = await import("@/lib/rate-limit");
+
+    const oldestMs = nowMs - 45_000;
+    const pipeline = makePipeline();
+    pipeline.exec.mockResolvedValueOnce([
+      [null, 0],
+      [null, 5],
+      [null, [`${oldestMs}:0.1`, String(oldestMs)]],
+    ]);
+
+    redisClientRef.pipeline.mockReturnValueOnce(pipeline);
+
+    const result = await RateLimitService.checkUserRPM(1, 5);
+    expect(result.allowed).toBe(false);
+    expect(result.remaining).toBe(0);
+    expect(result.resetAt).toEqual(new Date(oldestMs + 60_000));
+    expect(pipeline.zrange).toHaveBeenCalledWith("user:1:rpm_window", 0, 0, "WITHSCORES");
+  });
+
+  it("checkUserRPM：放行时应返回剩余次数，空窗口 resetAt 为 now + 60s", async () => {
+    const { RateLimitService } = await import("@/lib/rate-limit");
+
+    const readPipeline = makePipeline();
+    readPipeline.exec.mockResolvedValueOnce([
+      [null, 0],
+      [null, 0],
+      [null, []],
+    ]);
+
+    const writePipeline = makePipeline();
+    writePipeline.exec.mockResolvedValueOnce([]);
+
+    redisClientRef.pipeline.mockReturnValueOnce(readPipeline).mockReturnValueOnce(writePipeline);
+
+    const result = await RateLimitService.checkUserRPM(1, 5);
+    expect(result.allowed).toBe(true);
+    expect(result.remaining).toBe(4);
+    expect(result.resetAt).toEqual(new Date(nowMs + 60_000));
+  });
+
   it("checkRpmLimit：user 类型应复用 checkUserRPM 逻辑", async () => {
     const { RateLimitService } = await import("@/lib/rate-limit");
 
@@ -0,0 +1,77 @@
+import { describe, expect, test, vi } from "vitest";
+import { logger } from "@/lib/logger";
+import {
+  getRequestId,
+  getRequestLogger,
+  resolveRequestId,
+  runWithRequestId,
+} from "@/lib/request-id";
+
+describe("resolveRequestId", () => {
+  test("reuses a well-formed incoming id", () => {
+    expect(resolveRequestId(" req-123.abc:1 ")).toBe("req-123.abc:1");
+  });
+
+  test("generates a UUID for missing or unsafe ids", () => {
+    const uuid = /^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$/;
+    expect(resolveRequestId(null)).toMatch(uuid);
+    expect(resolveRequestId("")).toMatch(uuid);
+    expect(resolveRequestId("bad id\r\nx-injected: 1")).toMatch(uuid);
+    expect(resolveRequestId("a".repeat(129))).toMatch(uuid);
+  });
+});
+
+describe("runWithRequestId", () => {
+  test("exposes the id across async boundaries and clears it afterwards", async () => {
+    const seen = await runWithRequestId("req-1", async () => {
+      await new Promise((resolve) => setTimeout(resolve, 1));
+      return getRequestId();
+    });
+
+    expect(seen).toBe("req-1");
+    expect(getRequestId()).toBeUndefined();
+  });
+});
+
+describe("getRequestLogger", () => {
+  test("returns the global logger outside a request", () => {
+    expect(getRequestLogger()).toBe(logger);
+  });
+
+  test("attaches requestId to object and string log calls", () => {
+    const infoSpy = vi.spyOn(logger, "info").mockImplementation(() => {});
+
+    runWithRequestId("req-42", () => {
+      const requestLogger = getRequestLogger();
+      requestLogger.info({ action: "demo" }, "with object");
+      requestLogger.info("message first", { foo: 1 });
+      requestLogger.info("plain message");
+    });
+
+    expect(infoSpy).toHaveBeenNthCalledWith(
+      1,
+      { requestId: "req-42", action: "demo" },
+      "with object"
+    );
+    expect(infoSpy).toHaveBeenNthCalledWith(2, "message first", { requestId: "req-42", foo: 1 });
+    expect(infoSpy).toHaveBeenNthCalledWith(3, { requestId: "req-42" }, "plain message");
+
+    infoSpy.mockRestore();
+  });
+
+  test("keeps requestId when an Error is passed directly", () => {
+    const errorSpy = vi.spyOn(logger, "error").mockImplementation(() => {});
+    const error = new Error("boom");
+
+    runWithRequestId("req-7", () => {
+      const requestLogger = getRequestLogger();
+      requestLogger.error("query failed:", error);
+      requestLogger.error(error, "query failed");
+    });
+
+    expect(errorSpy).toHaveBeenNthCalledWith(1, "query failed:", { requestId: "req-7", error });
+    expect(errorSpy).toHaveBeenNthCalledWith(2, { requestId: "req-7", error }, "query failed");
+
+    errorSpy.mockRestore();
+  });
+});
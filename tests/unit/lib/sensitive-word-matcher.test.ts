@@ -0,0 +1,62 @@
+import { describe, expect, test, vi } from "vitest";
+
+vi.mock("@/lib/logger", () => ({
+  logger: { debug: vi.fn(), info: vi.fn(), warn: vi.fn(), error: vi.fn() },
+}));
+
+vi.mock("@/repository/sensitive-words", () => ({
+  getActiveSensitiveWords: vi.fn(async () => []),
+}));
+
+import { compileSensitiveWords } from "@/lib/sensitive-word-detector";
+
+describe("compileSensitiveWords", () => {
+  test("contains rules win over an overlapping regex rule", () => {
+    const matcher = compileSensitiveWords([
+      { word: "secret-\\d+", matchType: "regex", isEnabled: true },
+      { word: "secret", matchType: "contains", isEnabled: true },
+    ]);
+
+    const result = matcher.detect("leak secret-42 now");
+
+    expect(result).toMatchObject({ matched: true, matchType: "contains", word: "secret" });
+  });
+
+  test("falls back to regex when no contains/exact rule matches", () => {
+    const matcher = compileSensitiveWords([
+      { word: "password", matchType: "contains", isEnabled: true },
+      { word: "sk-[a-z0-9]{4}", matchType: "regex", isEnabled: true },
+    ]);
+
+    const result = matcher.detect("token SK-ab12 here");
+
+    expect(result).toMatchObject({ matched: true, matchType: "regex", matchedText: "SK-ab12" });
+  });
+
+  test("exact rules match the whole trimmed text only", () => {
+    const matcher = compileSensitiveWords([{ word: "Hello", matchType: "exact", isEnabled: true }]);
+
+    expect(matcher.detect("  hello ")).toMatchObject({ matched: true, matchType: "exact" });
+    expect(matcher.detect("hello world").matched).toBe(false);
+  });
+
+  test("ignores disabled rules and invalid regex patterns", () => {
+    const matcher = compileSensitiveWords([
+      { word: "blocked", matchType: "contains", isEnabled: false },
+      { word: "([", matchType: "regex", isEnabled: true },
+    ]);
+
+    expect(matcher.counts).toEqual({ containsCount: 0, exactCount: 0, regexCount: 0 });
+    expect(matcher.detect("blocked ([").matched).toBe(false);
+  });
+
+  test("reuses compiled patterns across calls", () => {
+    const matcher = compileSensitiveWords([
+      { word: "forbidden", matchType: "regex", isEnabled: true },
+    ]);
+
+    expect(matcher.detect("a forbidden word").matched).toBe(true);
+    expect(matcher.detect("another forbidden word").matched).toBe(true);
+    expect(matcher.detect("clean text").matched).toBe(false);
+  });
+});
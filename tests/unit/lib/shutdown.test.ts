@@ -9,6 +9,25 @@ vi.mock("@/lib/logger", () => ({
   },
 }));
 
+function mockBuiltinCleanupSteps() {
+  vi.doMock("@/lib/cache/session-cache", () => ({ stopCacheCleanup: () => {} }));
+  vi.doMock("@/lib/provider-endpoints/probe-scheduler", () => ({
+    stopEndpointProbeScheduler: () => {},
+  }));
+  vi.doMock("@/lib/public-status/scheduler", () => ({
+    stopPublicStatusRebuildScheduler: async () => {},
+  }));
+  vi.doMock("@/lib/provider-endpoints/probe-log-cleanup", () => ({
+    stopEndpointProbeLogCleanup: () => {},
+  }));
+  vi.doMock("@/lib/async-task-manager", () => ({ shutdownAllAsyncTasks: () => {} }));
+  vi.doMock("@/repository/message-write-buffer", () => ({
+    stopMessageRequestWriteBuffer: async () => {},
+  }));
+  vi.doMock("@/lib/langfuse", () => ({ shutdownLangfuse: async () => {} }));
+  vi.doMock("@/lib/redis", () => ({ closeRedis: async () => {} }));
+}
+
 describe.sequential("lifecycle/shutdown", () => {
   beforeEach(() => {
     vi.resetModules();
@@ -140,4 +159,58 @@ describe.sequential("lifecycle/shutdown", () => {
     expect(elapsed).toBeLessThan(2_000);
     releaseHang();
   });
+
+  it("runs registered hooks in LIFO order before the built-in steps", async () => {
+    mockBuiltinCleanupSteps();
+    const calls: string[] = [];
+    vi.doMock("@/lib/redis", () => ({
+      closeRedis: async () => {
+        calls.push("closeRedis");
+      },
+    }));
+
+    const { registerShutdownHook, runApplicationCleanup } = await import(
+      "@/lib/lifecycle/shutdown"
+    );
+    registerShutdownHook("first", () => {
+      calls.push("first");
+    });
+    registerShutdownHook("second", async () => {
+      calls.push("second");
+      throw new Error("simulated hook failure");
+    });
+    const unregister = registerShutdownHook("removed", () => {
+      calls.push("removed");
+    });
+    registerShutdownHook("third", async () => {
+      calls.push("third");
+    });
+    unregister();
+
+    await runApplicationCleanup("SIGTERM", { totalTimeoutMs: 5_000, perStepTimeoutMs: 500 });
+
+    expect(calls).toEqual(["third", "second", "first", "closeRedis"]);
+  });
+
+  it("skips hooks that have not started once the total timeout is reached", async () => {
+    mockBuiltinCleanupSteps();
+    let releaseHang: () => void = () => {};
+    const hang = new Promise<void>((resolve) => {
+      releaseHang = resolve;
+    });
+    const early = vi.fn();
+
+    const { registerShutdownHook, runApplicationCleanup } = await import(
+      "@/lib/lifecycle/shutdown"
+    );
+    registerShutdownHook("early", early);
+    registerShutdownHook("hanging", () => hang);
+
+    await runApplicationCleanup("SIGTERM", { totalTimeoutMs: 100, perStepTimeoutMs: 1_000 });
+    releaseHang();
+    await hang;
+    await new Promise((resolve) => setTimeout(resolve, 0));
+
+    expect(early).not.toHaveBeenCalled();
+  });
 });
@@ -0,0 +1,101 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+import type { DatabaseStatRow } from "@/types/statistics";
+
+const getUserStatisticsPageFromDBMock = vi.hoisted(() => vi.fn());
+
+vi.mock("@/repository/statistics", () => ({
+  getUserStatisticsPageFromDB: getUserStatisticsPageFromDBMock,
+}));
+
+function makeRow(overrides: Partial<DatabaseStatRow> = {}): DatabaseStatRow {
+  return {
+    user_id: 1,
+    user_name: "alice",
+    date: new Date("2026-05-01T00:00:00.000Z") as unknown as string,
+    api_calls: 3,
+    total_cost: "1.2345678901",
+    ...overrides,
+  };
+}
+
+async function readAll(stream: ReadableStream<string>): Promise<string[]> {
+  const chunks: string[] = [];
+  const reader = stream.getReader();
+  while (true) {
+    const { done, value } = await reader.read();
+    if (done) break;
+    chunks.push(value);
+  }
+  return chunks;
+}
+
+describe("statistics CSV export", () => {
+  beforeEach(() => {
+    getUserStatisticsPageFromDBMock.mockReset();
+  });
+
+  test("renders fixed 6-digit decimal costs without float rounding", async () => {
+    const { buildStatisticsCsvLine } = await import("@/lib/statistics/export-csv");
+
+    expect(buildStatisticsCsvLine(makeRow())).toBe("2026-05-01T00:00:00.000Z,1,alice,3,1.234568");
+    expect(buildStatisticsCsvLine(makeRow({ total_cost: "0.1000005" }))).toBe(
+      "2026-05-01T00:00:00.000Z,1,alice,3,0.100001"
+    );
+    expect(buildStatisticsCsvLine(makeRow({ total_cost: null, api_calls: 0 }))).toBe(
+      "2026-05-01T00:00:00.000Z,1,alice,0,0.000000"
+    );
+  });
+
+  test("escapes user names and neutralizes formula injection", async () => {
+    const { buildStatisticsCsvLine } = await import("@/lib/statistics/export-csv");
+
+    expect(buildStatisticsCsvLine(makeRow({ user_name: "a,b" }))).toContain(',"a,b",');
+    expect(buildStatisticsCsvLine(makeRow({ user_name: "=SUM(A1)" }))).toContain(",'=SUM(A1),");
+  });
+
+  test("streams header then one chunk per row", async () => {
+    getUserStatisticsPageFromDBMock.mockResolvedValue({
+      rows: [makeRow(), makeRow({ user_id: 2, user_name: "bob", api_calls: 1, total_cost: 2 })],
+      nextAfterUserId: null,
+    });
+
+    const { createUserStatisticsCsvStream, STATISTICS_CSV_HEADER } = await import(
+      "@/lib/statistics/export-csv"
+    );
+    const stream = await createUserStatisticsCsvStream("today", "UTC");
+    const chunks = await readAll(stream);
+
+    expect(getUserStatisticsPageFromDBMock).toHaveBeenCalledWith(
+      "today",
+      { afterUserId: null, limit: 200 },
+      "UTC"
+    );
+    expect(chunks).toEqual([
+      `${STATISTICS_CSV_HEADER}\n`,
+      "2026-05-01T00:00:00.000Z,1,alice,3,1.234568\n",
+      "2026-05-01T00:00:00.000Z,2,bob,1,2.000000\n",
+    ]);
+  });
+
+  test("walks user pages by keyset cursor until exhausted", async () => {
+    getUserStatisticsPageFromDBMock
+      .mockResolvedValueOnce({ rows: [makeRow()], nextAfterUserId: 1 })
+      .mockResolvedValueOnce({
+        rows: [makeRow({ user_id: 2, user_name: "bob" })],
+        nextAfterUserId: 2,
+      })
+      .mockResolvedValueOnce({ rows: [], nextAfterUserId: null });
+
+    const { createUserStatisticsCsvStream } = await import("@/lib/statistics/export-csv");
+    const chunks = await readAll(await createUserStatisticsCsvStream("7days", "UTC", 1));
+
+    expect(chunks).toHaveLength(3);
+    expect(chunks[1]).toContain(",alice,");
+    expect(chunks[2]).toContain(",bob,");
+    expect(getUserStatisticsPageFromDBMock.mock.calls.map((call) => call[1])).toEqual([
+      { afterUserId: null, limit: 1 },
+      { afterUserId: 1, limit: 1 },
+      { afterUserId: 2, limit: 1 },
+    ]);
+  });
+});
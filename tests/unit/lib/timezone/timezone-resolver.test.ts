@@ -184,3 +184,36 @@ describe("resolveSystemTimezone", () => {
     expect(result).toBe("Europe/Paris");
   });
 });
+
+describe("resolveUserTimezone", () => {
+  it("should return the user timezone when set and valid", async () => {
+    const { resolveUserTimezone } = await import("@/lib/utils/timezone");
+
+    getCachedSystemSettingsMock.mockResolvedValue(createSettings({ timezone: "America/New_York" }));
+    mockEnvConfig("Asia/Shanghai");
+
+    const result = await resolveUserTimezone("Europe/Berlin");
+    expect(result).toBe("Europe/Berlin");
+    expect(getCachedSystemSettingsMock).not.toHaveBeenCalled();
+  });
+
+  it("should fallback to system timezone when user timezone is null", async () => {
+    const { resolveUserTimezone } = await import("@/lib/utils/timezone");
+
+    getCachedSystemSettingsMock.mockResolvedValue(createSettings({ timezone: "America/New_York" }));
+    mockEnvConfig("Asia/Shanghai");
+
+    const result = await resolveUserTimezone(null);
+    expect(result).toBe("America/New_York");
+  });
+
+  it("should fallback to system timezone when user timezone is invalid", async () => {
+    const { resolveUserTimezone } = await import("@/lib/utils/timezone");
+
+    getCachedSystemSettingsMock.mockResolvedValue(createSettings({ timezone: null }));
+    mockEnvConfig("Asia/Tokyo");
+
+    const result = await resolveUserTimezone("Invalid/Zone");
+    expect(result).toBe("Asia/Tokyo");
+  });
+});
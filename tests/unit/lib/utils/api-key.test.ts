@@ -0,0 +1,29 @@
+import { describe, expect, it } from "vitest";
+import {
+  API_KEY_LOOKUP_PREFIX_LENGTH,
+  generateApiKey,
+  getApiKeyPrefix,
+} from "@/lib/utils/api-key";
+
+describe("api-key utils", () => {
+  it("generates sk- keys with 32 hex characters", () => {
+    const key = generateApiKey();
+    expect(key).toMatch(/^sk-[0-9a-f]{32}$/);
+    expect(generateApiKey()).not.toBe(key);
+  });
+
+  it("supports a custom prefix", () => {
+    expect(generateApiKey("cch_")).toMatch(/^cch_[0-9a-f]{32}$/);
+  });
+
+  it("extracts the lookup prefix of a generated key", () => {
+    const key = generateApiKey();
+
+    expect(getApiKeyPrefix(key)).toHaveLength(API_KEY_LOOKUP_PREFIX_LENGTH);
+    expect(key.startsWith(getApiKeyPrefix(key))).toBe(true);
+  });
+
+  it("does not pad keys shorter than the lookup prefix", () => {
+    expect(getApiKeyPrefix("sk-abc")).toBe("sk-abc");
+  });
+});
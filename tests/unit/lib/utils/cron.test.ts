@@ -0,0 +1,67 @@
+import { describe, expect, it } from "vitest";
+import { getNextCronRun, isValidCronExpression } from "@/lib/utils/cron";
+import { UpdateSystemSettingsSchema } from "@/lib/validation/schemas";
+
+describe("isValidCronExpression", () => {
+  it("accepts the default cleanup schedule and common forms", () => {
+    expect(isValidCronExpression("0 2 * * *")).toBe(true);
+    expect(isValidCronExpression("*/15 9-18 * * MON-FRI")).toBe(true);
+    expect(isValidCronExpression("0 0 1,15 JAN,JUL 0")).toBe(true);
+    expect(isValidCronExpression("30 8 * * 7")).toBe(true);
+  });
+
+  it("rejects out-of-range values and malformed expressions", () => {
+    expect(isValidCronExpression("99 * * * *")).toBe(false);
+    expect(isValidCronExpression("0 24 * * *")).toBe(false);
+    expect(isValidCronExpression("0 0 0 2 * * *")).toBe(false);
+    expect(isValidCronExpression("*/0 * * * *")).toBe(false);
+    expect(isValidCronExpression("every day")).toBe(false);
+    expect(isValidCronExpression("")).toBe(false);
+    expect(isValidCronExpression("   ")).toBe(false);
+  });
+
+  it("accepts the optional leading seconds field like the Bull scheduler", () => {
+    expect(isValidCronExpression("0 0 2 * * *")).toBe(true);
+  });
+});
+
+describe("getNextCronRun", () => {
+  it("computes the next daily run for the default schedule", () => {
+    const from = new Date("2026-03-01T03:00:00.000Z");
+    expect(getNextCronRun("0 2 * * *", from).toISOString()).toBe("2026-03-02T02:00:00.000Z");
+  });
+
+  it("is strictly after the reference instant", () => {
+    const from = new Date("2026-03-01T02:00:00.000Z");
+    expect(getNextCronRun("0 2 * * *", from).toISOString()).toBe("2026-03-02T02:00:00.000Z");
+  });
+
+  it("evaluates wall-clock time in the given timezone", () => {
+    // 2026-03-01 17:00 UTC = 2026-03-02 01:00 Asia/Shanghai
+    const from = new Date("2026-03-01T17:00:00.000Z");
+    expect(getNextCronRun("0 2 * * *", from, "Asia/Shanghai").toISOString()).toBe(
+      "2026-03-01T18:00:00.000Z"
+    );
+  });
+
+  it("matches either day-of-month or day-of-week when both are restricted", () => {
+    // 2026-03-02 is a Monday
+    const from = new Date("2026-02-28T12:00:00.000Z");
+    expect(getNextCronRun("0 0 15 * MON", from).toISOString()).toBe("2026-03-02T00:00:00.000Z");
+  });
+
+  it("throws for expressions that never fire", () => {
+    expect(() => getNextCronRun("0 0 30 2 *", new Date("2026-01-01T00:00:00.000Z"))).toThrow();
+  });
+});
+
+describe("UpdateSystemSettingsSchema cleanupSchedule", () => {
+  it("rejects invalid cron expressions", () => {
+    expect(UpdateSystemSettingsSchema.safeParse({ cleanupSchedule: "0 2 * * *" }).success).toBe(
+      true
+    );
+    expect(UpdateSystemSettingsSchema.safeParse({ cleanupSchedule: "99 * * * *" }).success).toBe(
+      false
+    );
+  });
+});
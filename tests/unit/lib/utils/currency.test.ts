@@ -1,5 +1,10 @@
 import { describe, expect, test } from "vitest";
-import { getCurrencySymbol, CURRENCY_CONFIG, type CurrencyCode } from "@/lib/utils/currency";
+import {
+  CURRENCY_CONFIG,
+  type CurrencyCode,
+  convertCurrency,
+  getCurrencySymbol,
+} from "@/lib/utils/currency";
 
 describe("getCurrencySymbol", () => {
   test("returns correct symbol for valid currency codes", () => {
@@ -34,3 +39,24 @@ describe("getCurrencySymbol", () => {
     }
   });
 });
+
+describe("convertCurrency", () => {
+  test("USD passes through with rate 1 regardless of the configured rate", () => {
+    expect(convertCurrency("0.000000000000001", "USD", 7.2).toString()).toBe("0.000000000000001");
+    expect(convertCurrency(12.5, "USD", 7.2).toString()).toBe("12.5");
+  });
+
+  test("multiplies by the display rate without floating point loss", () => {
+    expect(convertCurrency("0.1", "CNY", "0.2").toString()).toBe("0.02");
+    expect(convertCurrency("1234.567890123456789", "CNY", "7.12345678").toFixed(15)).toBe(
+      "8794.391007270233301"
+    );
+  });
+
+  test("falls back to rate 1 for missing or non-positive rates", () => {
+    expect(convertCurrency(3, "EUR").toString()).toBe("3");
+    expect(convertCurrency(3, "EUR", 0).toString()).toBe("3");
+    expect(convertCurrency(3, "EUR", -1).toString()).toBe("3");
+    expect(convertCurrency(null, "EUR", 2).toString()).toBe("0");
+  });
+});
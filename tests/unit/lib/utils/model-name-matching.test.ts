@@ -1,5 +1,9 @@
 import { describe, expect, it } from "vitest";
-import { buildModelNameFallbackCandidates } from "@/lib/utils/model-name-matching";
+import {
+  buildModelNameFallbackCandidates,
+  normalizeModelName,
+  pickFuzzyModelNameMatch,
+} from "@/lib/utils/model-name-matching";
 
 describe("buildModelNameFallbackCandidates", () => {
   it("returns empty for blank input", () => {
@@ -55,3 +59,47 @@ describe("buildModelNameFallbackCandidates", () => {
     expect(new Set(candidates).size).toBe(candidates.length);
   });
 });
+
+describe("normalizeModelName", () => {
+  it("lowercases and unifies separators", () => {
+    expect(normalizeModelName("Claude-3.5-Sonnet")).toBe("claude-3-5-sonnet");
+    expect(normalizeModelName("gemini_1.5  pro")).toBe("gemini-1-5-pro");
+    expect(normalizeModelName("--gpt--4o--")).toBe("gpt-4o");
+  });
+
+  it("strips trailing date versions", () => {
+    expect(normalizeModelName("claude-3-5-sonnet-20241022")).toBe("claude-3-5-sonnet");
+    expect(normalizeModelName("gpt-4o-2024-08-06")).toBe("gpt-4o");
+    expect(normalizeModelName("claude-3-5-sonnet@20240620")).toBe("claude-3-5-sonnet");
+  });
+
+  it("keeps short numeric suffixes and vendor paths", () => {
+    expect(normalizeModelName("gpt-3.5-turbo-0125")).toBe("gpt-3-5-turbo-0125");
+    expect(normalizeModelName("anthropic/claude-sonnet-4")).toBe("anthropic/claude-sonnet-4");
+  });
+
+  it("returns empty string for blank input", () => {
+    expect(normalizeModelName("   ")).toBe("");
+  });
+});
+
+describe("pickFuzzyModelNameMatch", () => {
+  it("prefers a normalized exact match over prefix matches", () => {
+    expect(
+      pickFuzzyModelNameMatch("claude-3-5-sonnet-20241022", ["claude-3", "claude-3.5-sonnet"])
+    ).toBe("claude-3.5-sonnet");
+  });
+
+  it("falls back to the longest prefix on a separator boundary", () => {
+    expect(pickFuzzyModelNameMatch("gpt-4o-mini-high", ["gpt-4", "gpt-4o", "gpt-4o-mini"])).toBe(
+      "gpt-4o-mini"
+    );
+    expect(pickFuzzyModelNameMatch("gpt-4o", ["gpt-4"])).toBeNull();
+  });
+
+  it("keeps candidate order for ties", () => {
+    expect(
+      pickFuzzyModelNameMatch("Claude-3.5-Sonnet", ["claude_3_5_sonnet", "claude-3.5-sonnet"])
+    ).toBe("claude_3_5_sonnet");
+  });
+});
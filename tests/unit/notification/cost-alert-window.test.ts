@@ -20,6 +20,7 @@ import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
 
 // Track mock calls
 const mockGetTimeRangeForPeriod = vi.fn();
+const mockGetTimeRangeForPeriodWithMode = vi.fn();
 const mockSumKeyCostInTimeRange = vi.fn();
 const mockSumProviderCostInTimeRange = vi.fn();
 const mockDbSelect = vi.fn();
@@ -59,6 +60,8 @@ vi.mock("@/lib/utils/timezone", () => ({
 // Mock the time-utils module
 vi.mock("@/lib/rate-limit/time-utils", () => ({
   getTimeRangeForPeriod: (...args: unknown[]) => mockGetTimeRangeForPeriod(...args),
+  getTimeRangeForPeriodWithMode: (...args: unknown[]) =>
+    mockGetTimeRangeForPeriodWithMode(...args),
 }));
 
 // Mock the statistics repository
@@ -104,6 +107,12 @@ describe("Cost Alert Time Windows", () => {
       }
     });
 
+    // Daily window: fixed 18:00 Shanghai reset (2024-01-22 18:00 +08:00 = 10:00 UTC)
+    mockGetTimeRangeForPeriodWithMode.mockImplementation(async () => ({
+      startTime: new Date("2024-01-22T10:00:00.000Z"),
+      endTime: new Date(nowMs),
+    }));
+
     // Default mock for cost queries
     mockSumKeyCostInTimeRange.mockResolvedValue(0);
     mockSumProviderCostInTimeRange.mockResolvedValue(0);
@@ -196,11 +205,12 @@ describe("Cost Alert Time Windows", () => {
       const { generateCostAlerts } = await import("@/lib/notification/tasks/cost-alert");
       await generateCostAlerts(0.5);
 
-      // Should call sumKeyCostInTimeRange with keyId (not key string) and time range
+      // Should call sumKeyCostInTimeRange with keyId (not key string), time range and reset clip
       expect(mockSumKeyCostInTimeRange).toHaveBeenCalledWith(
         1, // keyId
         expectedStart,
-        expectedEnd
+        expectedEnd,
+        null // costResetAt
       );
     });
 
@@ -252,6 +262,166 @@ describe("Cost Alert Time Windows", () => {
     });
   });
 
+  describe("evaluateKeyCostAlerts", () => {
+    it("checks daily and total limits in addition to 5h/weekly/monthly", async () => {
+      mockDbWhere.mockResolvedValue([
+        {
+          id: 1,
+          key: "test-key",
+          userName: "Test User",
+          limit5h: "10.00",
+          limitDaily: "20.00",
+          dailyResetMode: "fixed",
+          dailyResetTime: "18:00",
+          limitWeek: "100.00",
+          limitMonth: "1000.00",
+          limitTotal: "5000.00",
+          costResetAt: null,
+        },
+      ]);
+      mockSumKeyCostInTimeRange.mockResolvedValue(19);
+
+      const { evaluateKeyCostAlerts } = await import("@/lib/notification/tasks/cost-alert");
+      const alerts = await evaluateKeyCostAlerts(0.9);
+
+      expect(mockGetTimeRangeForPeriodWithMode).toHaveBeenCalledWith("daily", "18:00", "fixed");
+      expect(mockSumKeyCostInTimeRange).toHaveBeenCalledTimes(5);
+
+      const totalCall = mockSumKeyCostInTimeRange.mock.calls[4];
+      expect((totalCall[1] as Date).getTime()).toBe(0);
+
+      // 19 >= 10 * 0.9 (5h) and 19 >= 20 * 0.9 (daily); other windows stay below threshold
+      expect(alerts.map((alert) => alert.window)).toEqual(["5h", "daily"]);
+      expect(alerts[1]).toMatchObject({
+        keyId: 1,
+        keyName: "Test User",
+        currentCost: 19,
+        quotaLimit: 20,
+        usageRatio: 0.95,
+      });
+    });
+
+    it("skips only the windows whose limit is zero", async () => {
+      mockDbWhere.mockResolvedValue([
+        {
+          id: 1,
+          key: "test-key",
+          userName: "Test User",
+          limit5h: "0",
+          limitDaily: null,
+          dailyResetMode: "fixed",
+          dailyResetTime: "00:00",
+          limitWeek: "0.00",
+          limitMonth: null,
+          limitTotal: "50.00",
+          costResetAt: null,
+        },
+      ]);
+      mockSumKeyCostInTimeRange.mockResolvedValue(50);
+
+      const { evaluateKeyCostAlerts } = await import("@/lib/notification/tasks/cost-alert");
+      const alerts = await evaluateKeyCostAlerts(1);
+
+      expect(mockSumKeyCostInTimeRange).toHaveBeenCalledTimes(1);
+      expect(alerts).toEqual([
+        expect.objectContaining({ window: "total", currentCost: 50, quotaLimit: 50 }),
+      ]);
+    });
+
+    it("clips every window by the key costResetAt", async () => {
+      const costResetAt = new Date("2024-01-23T08:00:00.000Z");
+      mockDbWhere.mockResolvedValue([
+        {
+          id: 7,
+          key: "test-key",
+          userName: "Reset User",
+          limit5h: "10.00",
+          limitDaily: null,
+          dailyResetMode: null,
+          dailyResetTime: null,
+          limitWeek: null,
+          limitMonth: "100.00",
+          limitTotal: null,
+          costResetAt,
+        },
+      ]);
+
+      const { evaluateKeyCostAlerts } = await import("@/lib/notification/tasks/cost-alert");
+      await evaluateKeyCostAlerts(0.8);
+
+      expect(mockSumKeyCostInTimeRange).toHaveBeenCalledTimes(2);
+      for (const call of mockSumKeyCostInTimeRange.mock.calls) {
+        expect(call[0]).toBe(7);
+        expect(call[3]).toBe(costResetAt);
+      }
+    });
+
+    it("keeps alerts from other windows when one window query fails", async () => {
+      mockDbWhere.mockResolvedValue([
+        {
+          id: 1,
+          key: "test-key",
+          userName: "Test User",
+          limit5h: "10.00",
+          limitDaily: null,
+          dailyResetMode: null,
+          dailyResetTime: null,
+          limitWeek: "10.00",
+          limitMonth: null,
+          limitTotal: null,
+          costResetAt: null,
+        },
+      ]);
+      mockSumKeyCostInTimeRange
+        .mockRejectedValueOnce(new Error("statement timeout"))
+        .mockResolvedValueOnce(9);
+
+      const { evaluateKeyCostAlerts } = await import("@/lib/notification/tasks/cost-alert");
+      const alerts = await evaluateKeyCostAlerts(0.8);
+
+      expect(mockSumKeyCostInTimeRange).toHaveBeenCalledTimes(2);
+      expect(alerts).toEqual([expect.objectContaining({ window: "weekly", currentCost: 9 })]);
+    });
+
+    it("uses rolling daily windows per key reset mode", async () => {
+      mockDbWhere.mockResolvedValue([
+        {
+          id: 1,
+          key: "key-1",
+          userName: "User 1",
+          limit5h: null,
+          limitDaily: "10.00",
+          dailyResetMode: "rolling",
+          dailyResetTime: "00:00",
+          limitWeek: null,
+          limitMonth: null,
+          limitTotal: null,
+          costResetAt: null,
+        },
+        {
+          id: 2,
+          key: "key-2",
+          userName: "User 2",
+          limit5h: null,
+          limitDaily: "10.00",
+          dailyResetMode: "rolling",
+          dailyResetTime: "00:00",
+          limitWeek: null,
+          limitMonth: null,
+          limitTotal: null,
+          costResetAt: null,
+        },
+      ]);
+
+      const { evaluateKeyCostAlerts } = await import("@/lib/notification/tasks/cost-alert");
+      await evaluateKeyCostAlerts(0.8);
+
+      // Same (mode, resetTime) pair is computed once and shared
+      expect(mockGetTimeRangeForPeriodWithMode).toHaveBeenCalledTimes(1);
+      expect(mockGetTimeRangeForPeriodWithMode).toHaveBeenCalledWith("daily", "00:00", "rolling");
+    });
+  });
+
   describe("checkProviderQuotas", () => {
     it("should use getTimeRangeForPeriod('weekly') for provider weekly window", async () => {
       // First call returns empty keys, second call returns provider
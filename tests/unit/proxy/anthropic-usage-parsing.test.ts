@@ -120,4 +120,32 @@ describe("parseUsageFromResponseText (Anthropic/Claude SSE usage)", () => {
       cache_read_input_tokens: 14999,
     });
   });
+
+  test("keeps web search requests when usage appears in both message_start and message_delta", () => {
+    const sse = buildSse([
+      {
+        event: "message_start",
+        data: {
+          type: "message_start",
+          message: { usage: { input_tokens: 12, output_tokens: 1 } },
+        },
+      },
+      {
+        event: "message_delta",
+        data: {
+          type: "message_delta",
+          delta: { stop_reason: "end_turn" },
+          usage: { output_tokens: 512, server_tool_use: { web_search_requests: 3 } },
+        },
+      },
+    ]);
+
+    const { usageMetrics } = parseUsageFromResponseText(sse, "anthropic");
+
+    expect(usageMetrics).toMatchObject({
+      input_tokens: 12,
+      output_tokens: 512,
+      web_search_requests: 3,
+    });
+  });
 });
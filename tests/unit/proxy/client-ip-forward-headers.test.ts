@@ -0,0 +1,53 @@
+import { describe, expect, it } from "vitest";
+import { buildClientIpForwardHeaders, HeaderProcessor } from "@/app/v1/_lib/headers";
+
+function forward(headers: Headers, clientIp: string | null, preserve: boolean): Headers {
+  return HeaderProcessor.createForProxy({
+    preserveClientIpHeaders: preserve,
+    overrides: buildClientIpForwardHeaders(headers, clientIp, preserve),
+  }).process(headers);
+}
+
+describe("buildClientIpForwardHeaders", () => {
+  it("strips client IP headers when preserve is disabled", () => {
+    const headers = new Headers({
+      "x-forwarded-for": "203.0.113.7, 10.0.0.1",
+      "x-real-ip": "203.0.113.7",
+      "content-type": "application/json",
+    });
+
+    expect(buildClientIpForwardHeaders(headers, "203.0.113.7", false)).toEqual({});
+
+    const forwarded = forward(headers, "203.0.113.7", false);
+    expect(forwarded.get("x-forwarded-for")).toBeNull();
+    expect(forwarded.get("x-real-ip")).toBeNull();
+    expect(forwarded.get("content-type")).toBe("application/json");
+  });
+
+  it("sets both headers to the resolved client IP when preserve is enabled", () => {
+    const forwarded = forward(new Headers(), "198.51.100.23", true);
+
+    expect(forwarded.get("x-forwarded-for")).toBe("198.51.100.23");
+    expect(forwarded.get("x-real-ip")).toBe("198.51.100.23");
+  });
+
+  it("appends the client IP to an existing chain without duplicating it", () => {
+    const headers = new Headers({ "x-forwarded-for": "203.0.113.7, 10.0.0.1" });
+
+    expect(buildClientIpForwardHeaders(headers, "198.51.100.23", true)).toEqual({
+      "x-forwarded-for": "203.0.113.7, 10.0.0.1, 198.51.100.23",
+      "x-real-ip": "198.51.100.23",
+    });
+    expect(buildClientIpForwardHeaders(headers, "203.0.113.7", true)).toEqual({
+      "x-forwarded-for": "203.0.113.7, 10.0.0.1",
+      "x-real-ip": "203.0.113.7",
+    });
+  });
+
+  it("falls back to request headers when no client IP was resolved", () => {
+    expect(
+      buildClientIpForwardHeaders(new Headers({ "x-real-ip": " 192.0.2.4 " }), null, true)
+    ).toEqual({ "x-forwarded-for": "192.0.2.4", "x-real-ip": "192.0.2.4" });
+    expect(buildClientIpForwardHeaders(new Headers(), null, true)).toEqual({});
+  });
+});
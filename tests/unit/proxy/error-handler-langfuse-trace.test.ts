@@ -308,5 +308,16 @@ describe("ProxyErrorHandler.handle - Langfuse error traces", () => {
         errorMessage: "limit exceeded",
       })
     );
+    expect(mocks.updateMessageRequestDetails).toHaveBeenCalledWith(
+      session.messageContext.id,
+      expect.objectContaining({
+        errorMessage: expect.stringContaining("rate_limit_metadata:"),
+        rateLimitMetadata: expect.objectContaining({
+          limit_type: "daily_quota",
+          current_usage: 12,
+          limit_value: 20,
+        }),
+      })
+    );
   });
 });
@@ -914,4 +914,43 @@ describe("extractUsageMetrics", () => {
       expect(result.usageMetrics?.output_tokens).toBe(200);
     });
   });
+
+  describe("Claude server_tool_use", () => {
+    it("应提取 web_search_requests 用于搜索计费", () => {
+      const response = JSON.stringify({
+        usage: {
+          input_tokens: 10,
+          output_tokens: 5,
+          server_tool_use: { web_search_requests: 3 },
+        },
+      });
+
+      const result = parseUsageFromResponseText(response, "claude");
+
+      expect(result.usageMetrics?.web_search_requests).toBe(3);
+    });
+  });
+
+  describe("OpenAI Images API", () => {
+    it("应按 data[] 统计生成图片张数", () => {
+      const response = JSON.stringify({
+        created: 1713833628,
+        data: [{ b64_json: "aGVsbG8=" }, { url: "https://example.com/a.png" }],
+        usage: { input_tokens: 50, output_tokens: 4160 },
+      });
+
+      const result = parseUsageFromResponseText(response, "openai-compatible");
+
+      expect(result.usageMetrics?.output_images).toBe(2);
+      expect(result.usageMetrics?.input_tokens).toBe(50);
+    });
+
+    it("无 usage 时仍返回图片张数", () => {
+      const response = JSON.stringify({ created: 1713833628, data: [{ url: "https://x/a.png" }] });
+
+      const result = parseUsageFromResponseText(response, "openai-compatible");
+
+      expect(result.usageMetrics).toEqual({ output_images: 1 });
+    });
+  });
 });
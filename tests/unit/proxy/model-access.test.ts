@@ -0,0 +1,50 @@
+import { describe, expect, test } from "vitest";
+import { resolveModelAccess } from "@/app/v1/_lib/proxy/model-guard";
+import type { Provider } from "@/types/provider";
+
+const userRestricted = { allowedModels: ["claude-opus-4-1", "claude-sonnet-4-5"] };
+const userOpen = { allowedModels: [] };
+const providerRestricted: Pick<Provider, "allowedModels"> = {
+  allowedModels: [{ matchType: "prefix", pattern: "claude-opus-" }],
+};
+const providerOpen: Pick<Provider, "allowedModels"> = { allowedModels: null };
+
+describe("resolveModelAccess", () => {
+  test.each([
+    // [user, provider, model, expected]
+    ["open", "open", "gpt-4o", { allowed: true }],
+    ["open", "restricted", "claude-opus-4-1", { allowed: true }],
+    ["open", "restricted", "claude-sonnet-4-5", { allowed: false, layer: "provider" }],
+    ["restricted", "open", "claude-sonnet-4-5", { allowed: true }],
+    ["restricted", "open", "gpt-4o", { allowed: false, layer: "user" }],
+    ["restricted", "restricted", "claude-opus-4-1", { allowed: true }],
+    ["restricted", "restricted", "claude-sonnet-4-5", { allowed: false, layer: "provider" }],
+    ["restricted", "restricted", "gpt-4o", { allowed: false, layer: "user" }],
+  ] as const)("user=%s provider=%s model=%s", (userMode, providerMode, model, expected) => {
+    const user = userMode === "restricted" ? userRestricted : userOpen;
+    const provider = providerMode === "restricted" ? providerRestricted : providerOpen;
+
+    expect(resolveModelAccess(user, provider, model)).toEqual(
+      expected.allowed ? { allowed: true } : { ...expected, reason: "model_not_allowed" }
+    );
+  });
+
+  test("user layer matches case-insensitively", () => {
+    expect(resolveModelAccess(userRestricted, providerOpen, "Claude-Opus-4-1")).toEqual({
+      allowed: true,
+    });
+  });
+
+  test("missing model is rejected only when the user layer is restricted", () => {
+    expect(resolveModelAccess(userOpen, providerRestricted, null)).toEqual({ allowed: true });
+    expect(resolveModelAccess(userRestricted, providerOpen, "  ")).toEqual({
+      allowed: false,
+      reason: "model_not_allowed",
+      layer: "user",
+    });
+  });
+
+  test("missing user or provider context skips that layer", () => {
+    expect(resolveModelAccess(undefined, undefined, "gpt-4o")).toEqual({ allowed: true });
+  });
+});
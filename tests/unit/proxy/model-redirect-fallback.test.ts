@@ -182,6 +182,17 @@ describe("Model redirect across provider fallback", () => {
     expect(session.request.model).toBe(PROVIDER_B_REDIRECT_FROM_ORIGINAL);
     expect(session.request.message.model).toBe(PROVIDER_B_REDIRECT_FROM_ORIGINAL);
     expect(session.getOriginalModel()).toBe(REQUESTED_MODEL);
+
+    // Each effective redirect is audited against the provider that applied it
+    expect(
+      session
+        .getSpecialSettings()
+        ?.filter((setting) => setting.type === "provider_parameter_override")
+        .map((setting) => [setting.providerId, setting.changes[0]?.after])
+    ).toEqual([
+      [100, PROVIDER_A_REDIRECT],
+      [200, PROVIDER_B_REDIRECT_FROM_ORIGINAL],
+    ]);
   });
 
   test("Provider B redirect rule keyed on Provider A's REDIRECTED name does NOT fire", () => {
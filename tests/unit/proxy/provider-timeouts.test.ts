@@ -0,0 +1,71 @@
+import { describe, expect, test, vi } from "vitest";
+
+vi.mock("@/lib/config/env.schema", () => ({
+  getEnvConfig: () => ({ FETCH_HEADERS_TIMEOUT: 600_000, FETCH_BODY_TIMEOUT: 300_000 }),
+}));
+
+import { resolveProviderTimeouts } from "@/app/v1/_lib/proxy/provider-timeouts";
+
+const global = { headersTimeoutMs: 120_000, bodyTimeoutMs: 90_000 };
+
+function provider(overrides: Partial<Record<string, number>> = {}) {
+  return {
+    firstByteTimeoutStreamingMs: 0,
+    streamingIdleTimeoutMs: 0,
+    requestTimeoutNonStreamingMs: 0,
+    ...overrides,
+  };
+}
+
+describe("resolveProviderTimeouts", () => {
+  test("streaming uses provider first-byte and idle timeouts when configured", () => {
+    const resolved = resolveProviderTimeouts(
+      provider({ firstByteTimeoutStreamingMs: 15_000, streamingIdleTimeoutMs: 60_000 }),
+      true,
+      global
+    );
+
+    expect(resolved).toEqual({ firstByteMs: 15_000, idleMs: 60_000, totalMs: 0 });
+  });
+
+  test("zero provider values inherit the global fetch timeouts", () => {
+    expect(resolveProviderTimeouts(provider(), true, global)).toEqual({
+      firstByteMs: 120_000,
+      idleMs: 90_000,
+      totalMs: 0,
+    });
+    expect(resolveProviderTimeouts(provider(), false, global)).toEqual({
+      firstByteMs: 120_000,
+      idleMs: 90_000,
+      totalMs: 0,
+    });
+  });
+
+  test("non-streaming ignores streaming fields and applies the total timeout", () => {
+    const resolved = resolveProviderTimeouts(
+      provider({
+        firstByteTimeoutStreamingMs: 5_000,
+        streamingIdleTimeoutMs: 60_000,
+        requestTimeoutNonStreamingMs: 90_000,
+      }),
+      false,
+      global
+    );
+
+    expect(resolved).toEqual({ firstByteMs: 90_000, idleMs: 90_000, totalMs: 90_000 });
+  });
+
+  test("falls back to env configuration when no global config is passed", () => {
+    expect(resolveProviderTimeouts(provider(), true)).toEqual({
+      firstByteMs: 600_000,
+      idleMs: 300_000,
+      totalMs: 0,
+    });
+  });
+
+  test("non-positive global values mean unlimited", () => {
+    expect(
+      resolveProviderTimeouts(provider(), true, { headersTimeoutMs: 0, bodyTimeoutMs: -1 })
+    ).toEqual({ firstByteMs: 0, idleMs: 0, totalMs: 0 });
+  });
+});
@@ -368,6 +368,29 @@ describe("ProxyRateLimitGuard - key daily limit enforcement", () => {
     });
   });
 
+  it("User RPM 超限时 resetTime 应使用限流器返回的 resetAt", async () => {
+    const { ProxyRateLimitGuard } = await import("@/app/v1/_lib/proxy/rate-limit-guard");
+
+    const resetAt = new Date("2026-01-01T00:00:30.000Z");
+    rateLimitServiceMock.checkRpmLimit.mockResolvedValueOnce({
+      allowed: false,
+      current: 5,
+      remaining: 0,
+      resetAt,
+      reason: "用户每分钟请求数上限已达到（5/5）",
+    });
+
+    const session = createSession({
+      user: { rpm: 5 },
+    });
+
+    await expect(ProxyRateLimitGuard.ensure(session)).rejects.toMatchObject({
+      name: "RateLimitError",
+      limitType: "rpm",
+      resetTime: resetAt.toISOString(),
+    });
+  });
+
   it("Key 5h 超限应拦截（usd_5h）", async () => {
     const { ProxyRateLimitGuard } = await import("@/app/v1/_lib/proxy/rate-limit-guard");
 
@@ -12,7 +12,9 @@ import {
   MAX_COMPRESSED_REQUEST_BYTES,
   MAX_CONTENT_ENCODING_LAYERS,
   MAX_DECOMPRESSED_REQUEST_BYTES,
+  MAX_REQUEST_BODY_BYTES,
   parseContentEncoding,
+  readRequestBodyWithLimit,
 } from "@/app/v1/_lib/proxy/request-body-codec";
 
 const encoder = new TextEncoder();
@@ -212,6 +214,70 @@ describe("decodeRequestBody", () => {
   });
 });
 
+function chunkedRequest(chunks: Uint8Array[]): Request {
+  const stream = new ReadableStream<Uint8Array>({
+    start(controller) {
+      for (const chunk of chunks) controller.enqueue(chunk);
+      controller.close();
+    },
+  });
+  return new Request("http://localhost/v1/messages", {
+    method: "POST",
+    body: stream,
+    duplex: "half",
+  } as RequestInit);
+}
+
+describe("readRequestBodyWithLimit", () => {
+  it("reads a body exactly at the limit", async () => {
+    const body = raw();
+    const request = new Request("http://localhost/v1/messages", { method: "POST", body });
+
+    const buffer = await readRequestBodyWithLimit(request, body.byteLength);
+
+    expect(decoder.decode(buffer)).toBe(SAMPLE);
+  });
+
+  it("throws ProxyError(413) for a body one byte over the limit", async () => {
+    const body = raw();
+    const request = new Request("http://localhost/v1/messages", { method: "POST", body });
+
+    await expect(readRequestBodyWithLimit(request, body.byteLength - 1)).rejects.toMatchObject({
+      statusCode: 413,
+    });
+  });
+
+  it("rejects oversized chunked bodies without a content-length", async () => {
+    const request = chunkedRequest([raw("a".repeat(8)), raw("b".repeat(8))]);
+
+    const error = await readRequestBodyWithLimit(request, 15).catch((err) => err);
+
+    expect(error).toBeInstanceOf(ProxyError);
+    expect((error as ProxyError).statusCode).toBe(413);
+    expect((error as ProxyError).message).toContain("Request body exceeds");
+  });
+
+  it("concatenates chunked bodies within the limit", async () => {
+    const request = chunkedRequest([raw("hello "), raw("world")]);
+
+    const buffer = await readRequestBodyWithLimit(request, 11);
+
+    expect(decoder.decode(buffer)).toBe("hello world");
+  });
+
+  it("treats a zero limit as unlimited", async () => {
+    const request = new Request("http://localhost/v1/messages", { method: "POST", body: raw() });
+
+    const buffer = await readRequestBodyWithLimit(request, 0);
+
+    expect(decoder.decode(buffer)).toBe(SAMPLE);
+  });
+
+  it("defaults to a 10MB limit", () => {
+    expect(MAX_REQUEST_BODY_BYTES).toBe(10 * 1024 * 1024);
+  });
+});
+
 describe("decodeRequestBody env-configurable limits", () => {
   const ORIGINAL_ENV = { ...process.env };
 
@@ -234,6 +300,13 @@ describe("decodeRequestBody env-configurable limits", () => {
     expect(mod.MAX_COMPRESSED_REQUEST_BYTES).toBe(2 * 1024 * 1024);
   });
 
+  it("honors MAX_REQUEST_BODY_BYTES override, including 0 for unlimited", async () => {
+    process.env.MAX_REQUEST_BODY_BYTES = "0";
+    vi.resetModules();
+    const mod = await import("@/app/v1/_lib/proxy/request-body-codec");
+    expect(mod.MAX_REQUEST_BODY_BYTES).toBe(0);
+  });
+
   it("falls back to defaults for invalid/non-positive env values", async () => {
     process.env.MAX_DECOMPRESSED_REQUEST_BYTES = "not-a-number";
     process.env.MAX_COMPRESSED_REQUEST_BYTES = "-5";
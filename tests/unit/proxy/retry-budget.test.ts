@@ -0,0 +1,74 @@
+import { describe, expect, it } from "vitest";
+import { RetryBudget, resolveMaxAttemptsForProvider } from "@/app/v1/_lib/proxy/retry-budget";
+
+function drain(budget: RetryBudget): number[] {
+  const ids: number[] = [];
+  for (let id = budget.next(); id !== null; id = budget.next()) {
+    ids.push(id);
+  }
+  return ids;
+}
+
+describe("resolveMaxAttemptsForProvider", () => {
+  it("prefers the provider override and falls back to the default", () => {
+    expect(resolveMaxAttemptsForProvider({ maxRetryAttempts: 3 }, 2)).toBe(3);
+    expect(resolveMaxAttemptsForProvider({ maxRetryAttempts: null }, 2)).toBe(2);
+    expect(resolveMaxAttemptsForProvider(null, 2)).toBe(2);
+  });
+
+  it("clamps to the configured limits", () => {
+    expect(resolveMaxAttemptsForProvider({ maxRetryAttempts: 0 }, 2)).toBe(1);
+    expect(resolveMaxAttemptsForProvider({ maxRetryAttempts: 99 }, 2)).toBe(10);
+  });
+});
+
+describe("RetryBudget", () => {
+  it("walks providers in order honoring per-provider attempts until exhausted", () => {
+    const budget = new RetryBudget(
+      [
+        { id: 1, maxRetryAttempts: 2 },
+        { id: 2, maxRetryAttempts: null },
+        { id: 3, maxRetryAttempts: 1 },
+      ],
+      { defaultMaxAttempts: 3 }
+    );
+
+    expect(budget.remaining()).toBe(6);
+    expect(drain(budget)).toEqual([1, 1, 2, 2, 2, 3]);
+    expect(budget.next()).toBeNull();
+    expect(budget.remaining()).toBe(0);
+  });
+
+  it("skips providers already present in the chain", () => {
+    const budget = new RetryBudget(
+      [
+        { id: 1, maxRetryAttempts: 1 },
+        { id: 2, maxRetryAttempts: 1 },
+        { id: 3, maxRetryAttempts: 1 },
+      ],
+      { defaultMaxAttempts: 2, chain: [{ id: 1 }, { id: 3 }] }
+    );
+
+    expect(drain(budget)).toEqual([2]);
+  });
+
+  it("dedupes repeated providers in the candidate list", () => {
+    const budget = new RetryBudget(
+      [
+        { id: 1, maxRetryAttempts: 1 },
+        { id: 1, maxRetryAttempts: 5 },
+        { id: 2, maxRetryAttempts: 1 },
+      ],
+      { defaultMaxAttempts: 2 }
+    );
+
+    expect(drain(budget)).toEqual([1, 2]);
+  });
+
+  it("returns null immediately for an empty candidate list", () => {
+    const budget = new RetryBudget([], { defaultMaxAttempts: 2 });
+
+    expect(budget.next()).toBeNull();
+    expect(budget.remaining()).toBe(0);
+  });
+});
@@ -0,0 +1,87 @@
+import { describe, expect, it } from "vitest";
+import { ProxyError, truncateUpstreamBody } from "@/app/v1/_lib/proxy/errors";
+
+describe("truncateUpstreamBody", () => {
+  it("returns short bodies unchanged", () => {
+    expect(truncateUpstreamBody("Bad Gateway", 500)).toBe("Bad Gateway");
+  });
+
+  it("truncates plain text and reports omitted bytes", () => {
+    const body = "x".repeat(600);
+
+    expect(truncateUpstreamBody(body, 500)).toBe(`${"x".repeat(500)}…(truncated 100 bytes)`);
+  });
+
+  it("counts characters rather than bytes for multibyte text", () => {
+    const body = "错误".repeat(10); // 20 个字符，60 字节
+
+    const result = truncateUpstreamBody(body, 5);
+
+    expect(result).toBe("错误错误错…(truncated 45 bytes)");
+  });
+
+  it("does not split surrogate pairs", () => {
+    const body = "😀".repeat(4);
+
+    expect(truncateUpstreamBody(body, 2)).toBe("😀😀…(truncated 8 bytes)");
+  });
+
+  it("keeps top-level error/message fields of oversized JSON bodies", () => {
+    const body = JSON.stringify({
+      error: { type: "overloaded_error", message: "Overloaded" },
+      debug: "d".repeat(1000),
+    });
+
+    const result = truncateUpstreamBody(body, 200);
+    const kept = '{"error":{"type":"overloaded_error","message":"Overloaded"}}';
+
+    expect(result.startsWith(kept)).toBe(true);
+    expect(result).toBe(`${kept}…(truncated ${body.length - kept.length} bytes)`);
+  });
+
+  it("truncates the preserved fields when they are still too long", () => {
+    const body = JSON.stringify({ message: "m".repeat(100), extra: "e".repeat(100) });
+
+    const result = truncateUpstreamBody(body, 20);
+
+    expect(result.startsWith('{"message":"mmmmmmmm')).toBe(true);
+    expect(Array.from(result.split("…")[0])).toHaveLength(20);
+  });
+
+  it("falls back to plain truncation for JSON without error/message fields", () => {
+    const body = JSON.stringify({ data: "d".repeat(100) });
+
+    expect(truncateUpstreamBody(body, 10)).toBe(`${body.slice(0, 10)}…(truncated 101 bytes)`);
+  });
+});
+
+describe("ProxyError.fromUpstreamResponse body truncation", () => {
+  it("stores a truncated upstream body", async () => {
+    const response = new Response("e".repeat(1000), {
+      status: 502,
+      headers: { "content-type": "text/plain" },
+    });
+
+    const error = await ProxyError.fromUpstreamResponse(response, { id: 1, name: "p" });
+
+    expect(error.upstreamError?.body).toBe(`${"e".repeat(500)}…(truncated 500 bytes)`);
+    expect(error.upstreamError?.detectionBody).toBe("e".repeat(1000));
+  });
+
+  it("keeps fields dropped from the stored JSON body available for rule matching", async () => {
+    const payload = {
+      error: { type: "invalid_request_error", message: "bad request" },
+      detail: `${"d".repeat(3000)} context_length_exceeded`,
+    };
+    const response = new Response(JSON.stringify(payload), {
+      status: 400,
+      headers: { "content-type": "application/json" },
+    });
+
+    const error = await ProxyError.fromUpstreamResponse(response, { id: 1, name: "p" });
+
+    expect(error.upstreamError?.body).not.toContain("context_length_exceeded");
+    expect(error.upstreamError?.detectionBody).toBe(JSON.stringify(payload));
+    expect(error.getDetailedErrorMessage()).not.toContain("context_length_exceeded");
+  });
+});
@@ -0,0 +1,116 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+import { parseClaudeCodeVersion } from "@/lib/ua-parser";
+
+const getSystemSettingsMock = vi.fn();
+const shouldUpgradeMock = vi.fn();
+
+vi.mock("@/repository/system-config", () => ({
+  getSystemSettings: (...args: unknown[]) => getSystemSettingsMock(...args),
+}));
+
+vi.mock("@/lib/client-version-checker", () => ({
+  ClientVersionChecker: {
+    updateUserVersion: vi.fn(async () => {}),
+    shouldUpgrade: (...args: unknown[]) => shouldUpgradeMock(...args),
+  },
+}));
+
+vi.mock("@/lib/logger", () => ({
+  logger: { debug: vi.fn(), info: vi.fn(), warn: vi.fn(), error: vi.fn() },
+}));
+
+function createSession(userAgent: string | null) {
+  return { userAgent, authState: { user: { id: 7 } } } as never;
+}
+
+describe("parseClaudeCodeVersion", () => {
+  test("extracts the version from Claude Code user agents", () => {
+    expect(parseClaudeCodeVersion("claude-cli/2.0.31 (external, cli)")).toBe("2.0.31");
+    expect(parseClaudeCodeVersion("claude-cli/2.1.0 (external, claude-vscode)")).toBe("2.1.0");
+  });
+
+  test.each([
+    [null],
+    [""],
+    ["claude-cli"],
+    ["claude-cli/"],
+    ["claude-cli/2.0"],
+    ["claude-cli/latest (external, cli)"],
+    ["Mozilla/5.0 (Macintosh)"],
+    ["anthropic-sdk-typescript/1.0.0"],
+  ])("returns null for malformed or non-Claude user agent %j", (ua) => {
+    expect(parseClaudeCodeVersion(ua)).toBeNull();
+  });
+});
+
+describe("ProxyVersionGuard minClientVersion", () => {
+  beforeEach(() => {
+    getSystemSettingsMock.mockReset();
+    shouldUpgradeMock.mockReset();
+    shouldUpgradeMock.mockResolvedValue({ needsUpgrade: false, gaVersion: null });
+  });
+
+  test("rejects Claude clients older than the configured minimum", async () => {
+    getSystemSettingsMock.mockResolvedValue({
+      enableClientVersionCheck: true,
+      minClientVersion: "2.0.40",
+    });
+
+    const { ProxyVersionGuard } = await import("@/app/v1/_lib/proxy/version-guard");
+    const response = await ProxyVersionGuard.ensure(
+      createSession("claude-cli/2.0.31 (external, cli)")
+    );
+
+    expect(response?.status).toBe(400);
+    const body = await response?.json();
+    expect(body.error).toMatchObject({
+      type: "client_upgrade_required",
+      current_version: "2.0.31",
+      required_version: "2.0.40",
+    });
+    expect(shouldUpgradeMock).not.toHaveBeenCalled();
+  });
+
+  test("allows clients at or above the minimum and falls through to GA detection", async () => {
+    getSystemSettingsMock.mockResolvedValue({
+      enableClientVersionCheck: true,
+      minClientVersion: "2.0.40",
+    });
+
+    const { ProxyVersionGuard } = await import("@/app/v1/_lib/proxy/version-guard");
+    const response = await ProxyVersionGuard.ensure(
+      createSession("claude-cli/2.0.40 (external, cli)")
+    );
+
+    expect(response).toBeNull();
+    expect(shouldUpgradeMock).toHaveBeenCalledWith("claude-cli", "2.0.40");
+  });
+
+  test("does not apply the minimum to non-Claude clients or malformed user agents", async () => {
+    getSystemSettingsMock.mockResolvedValue({
+      enableClientVersionCheck: true,
+      minClientVersion: "9.0.0",
+    });
+
+    const { ProxyVersionGuard } = await import("@/app/v1/_lib/proxy/version-guard");
+
+    expect(
+      await ProxyVersionGuard.ensure(createSession("anthropic-sdk-typescript/1.0.0"))
+    ).toBeNull();
+    expect(await ProxyVersionGuard.ensure(createSession("claude-cli/unknown"))).toBeNull();
+  });
+
+  test("skips the minimum when version checks are disabled", async () => {
+    getSystemSettingsMock.mockResolvedValue({
+      enableClientVersionCheck: false,
+      minClientVersion: "9.0.0",
+    });
+
+    const { ProxyVersionGuard } = await import("@/app/v1/_lib/proxy/version-guard");
+    const response = await ProxyVersionGuard.ensure(
+      createSession("claude-cli/2.0.31 (external, cli)")
+    );
+
+    expect(response).toBeNull();
+  });
+});
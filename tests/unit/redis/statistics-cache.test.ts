@@ -2,6 +2,7 @@ import { beforeEach, describe, expect, it, vi } from "vitest";
 import { getRedisClient } from "@/lib/redis/client";
 import { getStatisticsWithCache, invalidateStatisticsCache } from "@/lib/redis/statistics-cache";
 import { resolveSystemTimezone } from "@/lib/utils/timezone";
+import { StatisticsQueryTimeoutError } from "@/repository/_shared/statistics-query-timeout";
 import {
   getKeyStatisticsFromDB,
   getMixedStatisticsFromDB,
@@ -119,6 +120,26 @@ describe("getStatisticsWithCache", () => {
     expect(getMixedStatisticsFromDB).not.toHaveBeenCalled();
   });
 
+  it("uses the caller-provided timezone for query and cache key", async () => {
+    const redis = createRedisMock();
+    const rows = createKeyStats();
+    redis.get.mockResolvedValueOnce(null);
+    redis.set.mockResolvedValueOnce("OK");
+    redis.setex.mockResolvedValueOnce("OK");
+    redis.del.mockResolvedValueOnce(1);
+
+    vi.mocked(getRedisClient).mockReturnValue(
+      redis as unknown as NonNullable<ReturnType<typeof getRedisClient>>
+    );
+    vi.mocked(getKeyStatisticsFromDB).mockResolvedValueOnce(rows);
+
+    await getStatisticsWithCache("today", "keys", 42, "Asia/Shanghai");
+
+    expect(redis.get).toHaveBeenCalledWith("statistics:today:keys:42:tz:Asia/Shanghai");
+    expect(getKeyStatisticsFromDB).toHaveBeenCalledWith(42, "today", "Asia/Shanghai");
+    expect(resolveSystemTimezone).not.toHaveBeenCalled();
+  });
+
   it("calls getKeyStatisticsFromDB for mode=keys on cache miss", async () => {
     const redis = createRedisMock();
     const rows = createKeyStats();
@@ -159,7 +180,9 @@ describe("getStatisticsWithCache", () => {
     const result = await getStatisticsWithCache("30days", "mixed", 42);
 
     expect(result).toEqual(mixedResult);
-    expect(getMixedStatisticsFromDB).toHaveBeenCalledWith(42, "30days", "UTC");
+    expect(getMixedStatisticsFromDB).toHaveBeenCalledWith(42, "30days", "UTC", {
+      signal: undefined,
+    });
     expect(getUserStatisticsFromDB).not.toHaveBeenCalled();
     expect(getKeyStatisticsFromDB).not.toHaveBeenCalled();
   });
@@ -267,6 +290,58 @@ describe("getStatisticsWithCache", () => {
     expect(getUserStatisticsFromDB).toHaveBeenCalledWith("today", "UTC");
   });
 
+  it("does not re-run the query after a statistics query timeout", async () => {
+    const redis = createRedisMock();
+    redis.get.mockResolvedValueOnce(null);
+    redis.set.mockResolvedValueOnce("OK");
+    redis.del.mockResolvedValueOnce(1);
+
+    vi.mocked(getRedisClient).mockReturnValue(
+      redis as unknown as NonNullable<ReturnType<typeof getRedisClient>>
+    );
+    vi.mocked(getUserStatisticsFromDB).mockRejectedValueOnce(
+      new StatisticsQueryTimeoutError(10_000)
+    );
+
+    await expect(getStatisticsWithCache("today", "users")).rejects.toBeInstanceOf(
+      StatisticsQueryTimeoutError
+    );
+    expect(getUserStatisticsFromDB).toHaveBeenCalledTimes(1);
+    expect(redis.setex).not.toHaveBeenCalled();
+  });
+
+  it("passes the caller's signal to the mixed query and rethrows the abort", async () => {
+    const redis = createRedisMock();
+    redis.get.mockResolvedValueOnce(null);
+    redis.set.mockResolvedValueOnce("OK");
+    redis.del.mockResolvedValueOnce(1);
+
+    vi.mocked(getRedisClient).mockReturnValue(
+      redis as unknown as NonNullable<ReturnType<typeof getRedisClient>>
+    );
+    vi.mocked(getMixedStatisticsFromDB).mockImplementationOnce(
+      (_userId, _timeRange, _timezone, options) =>
+        new Promise((_resolve, reject) => {
+          options?.signal?.addEventListener("abort", () => reject(options.signal?.reason));
+        })
+    );
+
+    const controller = new AbortController();
+    const pending = getStatisticsWithCache("today", "mixed", 42, "UTC", {
+      signal: controller.signal,
+    });
+    await vi.waitFor(() => expect(getMixedStatisticsFromDB).toHaveBeenCalledTimes(1));
+    controller.abort();
+
+    await expect(pending).rejects.toBe(controller.signal.reason);
+    expect(getMixedStatisticsFromDB).toHaveBeenCalledWith(42, "today", "UTC", {
+      signal: controller.signal,
+    });
+    expect(getMixedStatisticsFromDB).toHaveBeenCalledTimes(1);
+    expect(redis.setex).not.toHaveBeenCalled();
+    expect(redis.del).toHaveBeenCalledWith("statistics:today:mixed:42:tz:UTC:lock");
+  });
+
   it("uses different cache keys for different timeRanges", async () => {
     const redis = createRedisMock();
     const rows = createUserStats();
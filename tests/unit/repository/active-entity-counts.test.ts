@@ -0,0 +1,100 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function mockDb(rows: unknown[]) {
+  const whereArgs: unknown[] = [];
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return query;
+  });
+  const selectMock = vi.fn(() => query);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { selectMock, whereArgs };
+}
+
+describe("countActiveUsers", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("counts enabled, unexpired, non-deleted users with a single query", async () => {
+    const { selectMock, whereArgs } = mockDb([{ count: "12" }]);
+
+    const { countActiveUsers } = await import("@/repository/user");
+    const total = await countActiveUsers();
+
+    expect(total).toBe(12);
+    expect(selectMock).toHaveBeenCalledTimes(1);
+
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("deleted_at is null");
+    expect(whereSql).toContain("is_enabled");
+    expect(whereSql).toContain("expires_at IS NULL OR");
+  });
+
+  test("returns 0 when no row is returned", async () => {
+    mockDb([]);
+
+    const { countActiveUsers } = await import("@/repository/user");
+    expect(await countActiveUsers()).toBe(0);
+  });
+});
+
+describe("countActiveProviders", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("counts enabled, non-deleted providers", async () => {
+    const { whereArgs } = mockDb([{ count: 4 }]);
+
+    const { countActiveProviders } = await import("@/repository/provider");
+    const total = await countActiveProviders();
+
+    expect(total).toBe(4);
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("deleted_at is null");
+    expect(whereSql).toContain("is_enabled");
+  });
+});
@@ -0,0 +1,125 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function keyRow(id: number) {
+  return {
+    id,
+    userId: 1,
+    key: `sk-key-${id}`,
+    name: `key-${id}`,
+    isEnabled: true,
+    expiresAt: null,
+    canLoginWebUi: true,
+    limit5hUsd: null,
+    limit5hResetMode: "rolling",
+    limitDailyUsd: null,
+    dailyResetMode: "fixed",
+    dailyResetTime: "00:00",
+    limitWeeklyUsd: null,
+    limitMonthlyUsd: null,
+    limitTotalUsd: null,
+    costResetAt: null,
+    limitConcurrentSessions: 0,
+    providerGroup: null,
+    cacheTtlPreference: null,
+    createdAt: new Date("2026-01-01T00:00:00.000Z"),
+    updatedAt: new Date("2026-01-01T00:00:00.000Z"),
+    deletedAt: null,
+  };
+}
+
+function mockDb(rows: unknown[] = []) {
+  const whereArgs: unknown[] = [];
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.orderBy = vi.fn(() => query);
+  query.limit = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return query;
+  });
+  const selectMock = vi.fn(() => query);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { whereArgs, query };
+}
+
+describe("findActiveKeysPaginated", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("returns the page and the last id as next cursor when more rows exist", async () => {
+    const { whereArgs, query } = mockDb([keyRow(1), keyRow(2), keyRow(3)]);
+
+    const { findActiveKeysPaginated } = await import("@/repository/key");
+    const page = await findActiveKeysPaginated(null, 2);
+
+    expect(page.keys.map((key) => key.id)).toEqual([1, 2]);
+    expect(page.nextCursor).toBe(2);
+    expect(query.limit).toHaveBeenCalledWith(3);
+
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("deleted_at is null");
+    expect(whereSql).toContain("is_enabled");
+    expect(whereSql).toContain("expires_at");
+  });
+
+  test("filters by id cursor and returns null cursor on the last page", async () => {
+    const { whereArgs } = mockDb([keyRow(11)]);
+
+    const { findActiveKeysPaginated } = await import("@/repository/key");
+    const page = await findActiveKeysPaginated(10, 2);
+
+    expect(page.keys.map((key) => key.id)).toEqual([11]);
+    expect(page.nextCursor).toBeNull();
+    expect(sqlToString(whereArgs[0])).toMatch(/id > 10/);
+  });
+
+  test("clamps the batch size", async () => {
+    const { query } = mockDb();
+
+    const { findActiveKeysPaginated } = await import("@/repository/key");
+    await findActiveKeysPaginated(null, 100000);
+
+    expect(query.limit).toHaveBeenCalledWith(1001);
+  });
+});
@@ -0,0 +1,92 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const visited = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (!node || visited.has(node)) return "";
+    visited.add(node);
+
+    if (typeof node === "string") return node;
+
+    if (typeof node === "object") {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.value) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+    }
+
+    return "";
+  };
+
+  return walk(sqlObj);
+}
+
+function mockDb(rows: unknown[] = []) {
+  const whereArgs: unknown[] = [];
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.orderBy = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return query;
+  });
+  const selectMock = vi.fn(() => query);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { selectMock, whereArgs };
+}
+
+/**
+ * 用 SQL 中实际使用的拆分正则在 JS 中模拟 `group = ANY(regexp_split_to_array(...))`
+ */
+function matchesGroup(whereSql: string, providerGroup: string | null, group: string): boolean {
+  const match = /regexp_split_to_array\(coalesce\(.*?, ''\), '(.+?)'\)/s.exec(whereSql);
+  if (!match) throw new Error(`split pattern not found in: ${whereSql}`);
+  return (providerGroup ?? "").split(new RegExp(match[1])).includes(group);
+}
+
+describe("findKeysByProviderGroup", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("returns [] without querying for an empty group", async () => {
+    const { selectMock } = mockDb();
+
+    const { findKeysByProviderGroup } = await import("@/repository/key");
+
+    expect(await findKeysByProviderGroup("")).toEqual([]);
+    expect(await findKeysByProviderGroup("   ")).toEqual([]);
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+
+  test("matches single, multi-group and whitespace-padded provider_group values", async () => {
+    const { whereArgs } = mockDb();
+
+    const { findKeysByProviderGroup } = await import("@/repository/key");
+    await findKeysByProviderGroup(" b ");
+
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("ANY(regexp_split_to_array");
+    expect(whereSql).toContain("b");
+
+    expect(matchesGroup(whereSql, "b", "b")).toBe(true);
+    expect(matchesGroup(whereSql, "a,b,c", "b")).toBe(true);
+    expect(matchesGroup(whereSql, "a , b ,c", "b")).toBe(true);
+    expect(matchesGroup(whereSql, "a,bb,c", "b")).toBe(false);
+    expect(matchesGroup(whereSql, "", "b")).toBe(false);
+    expect(matchesGroup(whereSql, null, "b")).toBe(false);
+  });
+});
@@ -0,0 +1,110 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function mockDb(rows: unknown[] = []) {
+  const whereArgs: unknown[] = [];
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.orderBy = vi.fn(() => query);
+  query.limit = vi.fn(() => query);
+  query.offset = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return query;
+  });
+  const selectMock = vi.fn(() => query);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { selectMock, whereArgs, query };
+}
+
+describe("searchKeys", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("returns [] without querying for a blank search term", async () => {
+    const { selectMock } = mockDb();
+
+    const { searchKeys } = await import("@/repository/key");
+
+    expect(await searchKeys("   ")).toEqual([]);
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+
+  test("matches a key by its prefix, excludes deleted keys and paginates", async () => {
+    const { whereArgs, query } = mockDb();
+
+    const { searchKeys } = await import("@/repository/key");
+    await searchKeys(" cr_abc ", { limit: 20, offset: 40 });
+
+    const whereSql = sqlToString(whereArgs[0]).replaceAll(/\s+/g, " ");
+    expect(whereSql).toContain("deleted_at is null");
+    expect(whereSql).toContain("name ILIKE %cr\\_abc% ESCAPE '\\'");
+    expect(whereSql).toContain("provider_group ILIKE %cr\\_abc% ESCAPE '\\'");
+    expect(whereSql).toContain("left(key, 12) ILIKE cr\\_abc% ESCAPE '\\'");
+    expect(query.limit).toHaveBeenCalledWith(20);
+    expect(query.offset).toHaveBeenCalledWith(40);
+  });
+
+  test("escapes LIKE wildcards in the search term", async () => {
+    const { whereArgs } = mockDb();
+
+    const { searchKeys } = await import("@/repository/key");
+    await searchKeys("50%_off");
+
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("ILIKE %50\\%\\_off%");
+    expect(whereSql).not.toContain("ILIKE %50%_off%");
+  });
+
+  test("never compares the key column against a full secret", async () => {
+    const { whereArgs } = mockDb();
+    const fullSecret = "cr_abcdef0123456789abcdef0123456789";
+
+    const { searchKeys } = await import("@/repository/key");
+    await searchKeys(fullSecret);
+
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).not.toContain("left(key");
+    expect(whereSql).not.toMatch(/\bkey\b\s*(=|ILIKE)/);
+  });
+});
@@ -0,0 +1,107 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function createThenableQuery<T>(result: T) {
+  const query: any = Promise.resolve(result);
+
+  query.from = vi.fn(() => query);
+  query.innerJoin = vi.fn(() => query);
+  query.where = vi.fn(() => query);
+  query.groupBy = vi.fn(() => query);
+  query.orderBy = vi.fn(() => query);
+  query.limit = vi.fn(() => query);
+
+  return query;
+}
+
+function keyRow(id: number, key: string) {
+  return {
+    id,
+    userId: 1,
+    key,
+    name: `key-${id}`,
+    isEnabled: true,
+    expiresAt: null,
+    canLoginWebUi: true,
+    limit5hUsd: null,
+    limitDailyUsd: null,
+    dailyResetMode: "fixed",
+    dailyResetTime: "00:00",
+    limitWeeklyUsd: null,
+    limitMonthlyUsd: null,
+    limitTotalUsd: null,
+    limitConcurrentSessions: 0,
+    providerGroup: null,
+    cacheTtlPreference: null,
+    createdAt: new Date("2026-01-01T00:00:00.000Z"),
+    updatedAt: new Date("2026-01-01T00:00:00.000Z"),
+    deletedAt: null,
+  };
+}
+
+function mockDb(selectResults: unknown[][]) {
+  const queue = selectResults.map((rows) => createThenableQuery(rows));
+  const selectMock = vi.fn(() => queue.shift() ?? createThenableQuery([]));
+
+  vi.doMock("@/drizzle/db", () => ({
+    db: {
+      select: selectMock,
+      execute: vi.fn(async () => []),
+    },
+  }));
+}
+
+describe("key statistics average cost per call", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("batch: divides today's total cost by call count with decimal precision", async () => {
+    mockDb([
+      [keyRow(1, "sk-a"), keyRow(2, "sk-b")],
+      [{ key: "sk-a", count: 3, totalCost: "0.1" }],
+    ]);
+
+    const { findKeysWithStatisticsBatch } = await import("@/repository/key");
+    const stats = (await findKeysWithStatisticsBatch([1])).get(1) ?? [];
+
+    expect(stats.find((s) => s.keyId === 1)).toMatchObject({
+      todayCallCount: 3,
+      todayTotalCost: 0.1,
+      avgCostPerCall: 0.033333,
+    });
+    // 今日无调用：平均值为 0 而非 NaN
+    expect(stats.find((s) => s.keyId === 2)).toMatchObject({
+      todayCallCount: 0,
+      todayTotalCost: 0,
+      avgCostPerCall: 0,
+    });
+  });
+
+  test("single user: populates total and average from the today window query", async () => {
+    mockDb([[keyRow(1, "sk-a")], [{ count: 4, totalCost: "0.3" }], [], []]);
+
+    const { findKeysWithStatistics } = await import("@/repository/key");
+    const [stats] = await findKeysWithStatistics(1);
+
+    expect(stats).toMatchObject({
+      keyId: 1,
+      todayCallCount: 4,
+      todayTotalCost: 0.3,
+      avgCostPerCall: 0.075,
+    });
+  });
+
+  test("single user: includeWarmup adds warmup calls to the count but not to cost", async () => {
+    mockDb([[keyRow(1, "sk-a")], [{ count: 4, totalCost: "0.3" }], [], [], [{ count: 2 }]]);
+
+    const { findKeysWithStatistics } = await import("@/repository/key");
+    const [stats] = await findKeysWithStatistics(1, { includeWarmup: true });
+
+    expect(stats).toMatchObject({
+      keyId: 1,
+      todayCallCount: 6,
+      todayTotalCost: 0.3,
+      avgCostPerCall: 0.075,
+    });
+  });
+});
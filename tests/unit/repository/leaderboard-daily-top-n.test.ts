@@ -0,0 +1,123 @@
+import { afterEach, beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function mockDb(rows: unknown[]) {
+  const captured: { where: unknown[]; orderBy: unknown[][]; limit: unknown[] } = {
+    where: [],
+    orderBy: [],
+    limit: [],
+  };
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.innerJoin = vi.fn(() => query);
+  query.groupBy = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    captured.where.push(arg);
+    return query;
+  });
+  query.orderBy = vi.fn((...args: unknown[]) => {
+    captured.orderBy.push(args);
+    return query;
+  });
+  query.limit = vi.fn((arg: unknown) => {
+    captured.limit.push(arg);
+    return query;
+  });
+  const selectMock = vi.fn(() => query);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  vi.doMock("@/repository/system-config", () => ({ getSystemSettings: vi.fn() }));
+  return { selectMock, captured };
+}
+
+describe("findDailyLeaderboardTopN", () => {
+  beforeEach(() => {
+    vi.resetModules();
+    vi.useFakeTimers();
+    // 2026-03-01 20:00 UTC = 2026-03-02 04:00 Asia/Shanghai
+    vi.setSystemTime(new Date("2026-03-01T20:00:00.000Z"));
+  });
+
+  afterEach(() => {
+    vi.useRealTimers();
+  });
+
+  test("returns today's date in the given timezone without querying for topN <= 0", async () => {
+    const { selectMock } = mockDb([]);
+
+    const { findDailyLeaderboardTopN } = await import("@/repository/leaderboard");
+
+    expect(await findDailyLeaderboardTopN("Asia/Shanghai", 0)).toEqual({
+      date: "2026-03-02",
+      entries: [],
+    });
+    expect(await findDailyLeaderboardTopN("UTC", -3)).toEqual({ date: "2026-03-01", entries: [] });
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+
+  test("orders by cost desc with user name as tie-breaker and limits to topN", async () => {
+    const { captured } = mockDb([
+      { userId: 2, userName: "alice", totalRequests: 3, totalCost: "1.5", totalTokens: 300 },
+      { userId: 1, userName: "bob", totalRequests: 5, totalCost: "1.5", totalTokens: 500 },
+    ]);
+
+    const { findDailyLeaderboardTopN } = await import("@/repository/leaderboard");
+    const result = await findDailyLeaderboardTopN("Asia/Shanghai", 2);
+
+    expect(captured.limit).toEqual([2]);
+    expect(captured.orderBy[0]?.map((arg) => sqlToString(arg).trim())).toEqual([
+      "COALESCE(sum(cost_usd), 0) desc",
+      "name asc",
+      "user_id asc",
+    ]);
+
+    const whereSql = sqlToString(captured.where[0]);
+    expect(whereSql).toContain("blocked_by");
+    expect(whereSql).toContain("DATE_TRUNC('day'");
+    expect(whereSql).toContain("Asia/Shanghai");
+
+    expect(result.date).toBe("2026-03-02");
+    expect(result.entries.map((e) => [e.userName, e.totalCost])).toEqual([
+      ["alice", 1.5],
+      ["bob", 1.5],
+    ]);
+  });
+});
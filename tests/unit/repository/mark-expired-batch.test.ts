@@ -0,0 +1,133 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function mockDb(returningRows: unknown[]) {
+  const whereArgs: unknown[] = [];
+  const setArgs: Record<string, unknown>[] = [];
+  const updateQuery: any = {};
+  updateQuery.set = vi.fn((arg: Record<string, unknown>) => {
+    setArgs.push(arg);
+    return updateQuery;
+  });
+  updateQuery.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return updateQuery;
+  });
+  updateQuery.returning = vi.fn(async () => returningRows);
+  const updateMock = vi.fn(() => updateQuery);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { update: updateMock } }));
+  return { updateMock, whereArgs, setArgs };
+}
+
+function mockAuthCache() {
+  const invalidateCachedKey = vi.fn(async () => {});
+  const invalidateCachedUser = vi.fn(async () => {});
+  vi.doMock("@/lib/security/api-key-auth-cache", () => ({
+    cacheActiveKey: vi.fn(async () => {}),
+    cacheAuthResult: vi.fn(async () => {}),
+    cacheUser: vi.fn(async () => {}),
+    getCachedActiveKey: vi.fn(async () => null),
+    getCachedUser: vi.fn(async () => null),
+    invalidateCachedKey,
+    invalidateCachedUser,
+  }));
+  return { invalidateCachedKey, invalidateCachedUser };
+}
+
+describe("markExpiredKeysBatch", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("disables expired keys in one UPDATE and invalidates their cache", async () => {
+    const { updateMock, whereArgs, setArgs } = mockDb([{ key: "sk-a" }, { key: "sk-b" }]);
+    const { invalidateCachedKey } = mockAuthCache();
+
+    const { markExpiredKeysBatch } = await import("@/repository/key");
+    const count = await markExpiredKeysBatch();
+
+    expect(count).toBe(2);
+    expect(updateMock).toHaveBeenCalledTimes(1);
+    expect(setArgs[0]?.isEnabled).toBe(false);
+    expect(setArgs[0]?.updatedAt).toBeInstanceOf(Date);
+    expect(invalidateCachedKey).toHaveBeenCalledWith("sk-a");
+    expect(invalidateCachedKey).toHaveBeenCalledWith("sk-b");
+  });
+
+  test("only targets enabled, non-deleted keys whose expiry has passed", async () => {
+    const { whereArgs } = mockDb([]);
+    mockAuthCache();
+
+    const { markExpiredKeysBatch } = await import("@/repository/key");
+    expect(await markExpiredKeysBatch()).toBe(0);
+
+    const whereSql = sqlToString(whereArgs[0]);
+    // 尚未过期的 Key 被 expires_at <= now 排除
+    expect(whereSql).toContain("expires_at <= ");
+    // 已禁用的 Key 被 is_enabled = true 排除
+    expect(whereSql).toContain("is_enabled = true");
+    expect(whereSql).toContain("deleted_at is null");
+  });
+});
+
+describe("markExpiredUsersBatch", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("disables expired users in one UPDATE and invalidates their cache", async () => {
+    const { updateMock, whereArgs } = mockDb([{ id: 7 }]);
+    const { invalidateCachedUser } = mockAuthCache();
+
+    const { markExpiredUsersBatch } = await import("@/repository/user");
+    const count = await markExpiredUsersBatch();
+
+    expect(count).toBe(1);
+    expect(updateMock).toHaveBeenCalledTimes(1);
+    expect(invalidateCachedUser).toHaveBeenCalledWith(7);
+
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("expires_at <= ");
+    expect(whereSql).toContain("is_enabled = true");
+    expect(whereSql).toContain("deleted_at is null");
+  });
+});
@@ -0,0 +1,99 @@
+import { describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+  const walk = (node: unknown): string => {
+    if (!node || stack.has(node)) return "";
+    if (typeof node === "string") return node;
+    if (typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+    stack.add(node);
+    try {
+      // biome-ignore lint/suspicious/noExplicitAny: test-only structural walk
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) return anyNode.map(walk).join("");
+      if (anyNode.name && typeof anyNode.name === "string") return anyNode.name;
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) return anyNode.value.map(String).join("");
+        return String(anyNode.value);
+      }
+      if (anyNode.queryChunks) return walk(anyNode.queryChunks);
+      return Object.values(anyNode).map(walk).join(" ");
+    } finally {
+      stack.delete(node);
+    }
+  };
+  return walk(sqlObj);
+}
+
+function mockDb(count: number) {
+  const whereArgs: unknown[] = [];
+  const setArgs: unknown[] = [];
+  const update = vi.fn(() => ({
+    set: vi.fn((obj: unknown) => {
+      setArgs.push(obj);
+      return {
+        where: vi.fn((cond: unknown) => {
+          whereArgs.push(cond);
+          return Promise.resolve({ count });
+        }),
+      };
+    }),
+  }));
+  vi.doMock("@/drizzle/db", () => ({
+    db: {
+      update,
+      select: () => ({ from: () => ({ where: async () => [] }) }),
+      execute: vi.fn(async () => []),
+    },
+  }));
+  return { update, whereArgs, setArgs };
+}
+
+describe("recomputeProviderCosts", () => {
+  test("单条 UPDATE 按新倍率重算 cost_usd 并返回受影响行数", async () => {
+    vi.resetModules();
+    const { update, whereArgs, setArgs } = mockDb(3);
+
+    const { recomputeProviderCosts } = await import("@/repository/message");
+    const affected = await recomputeProviderCosts(7, 1.5, new Date("2026-01-01T00:00:00.000Z"));
+
+    expect(affected).toBe(3);
+    expect(update).toHaveBeenCalledTimes(1);
+
+    const setSql = sqlToString(setArgs[0]).replaceAll(/\s+/g, " ");
+    expect(setSql).toContain("cost_usd / cost_multiplier * 1.5::numeric");
+
+    const whereSql = sqlToString(whereArgs[0]).replaceAll(/\s+/g, " ");
+    expect(whereSql).toContain("cost_multiplier <> 0");
+    expect(whereSql).toContain("deleted_at");
+  });
+
+  test("无匹配记录时返回 0", async () => {
+    vi.resetModules();
+    mockDb(0);
+
+    const { recomputeProviderCosts } = await import("@/repository/message");
+    expect(await recomputeProviderCosts(7, 2, new Date())).toBe(0);
+  });
+
+  test("非法倍率直接拒绝，不执行 UPDATE", async () => {
+    vi.resetModules();
+    const { update } = mockDb(0);
+
+    const { recomputeProviderCosts } = await import("@/repository/message");
+    await expect(recomputeProviderCosts(7, Number.NaN, new Date())).rejects.toThrow(
+      "Invalid cost multiplier"
+    );
+    await expect(recomputeProviderCosts(7, -1, new Date())).rejects.toThrow(
+      "Invalid cost multiplier"
+    );
+    await expect(recomputeProviderCosts(7, 0, new Date())).rejects.toThrow(
+      "Invalid cost multiplier"
+    );
+    await expect(recomputeProviderCosts(7, 101, new Date())).rejects.toThrow(
+      "Invalid cost multiplier"
+    );
+    expect(update).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,125 @@
+import { describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const visited = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (!node || visited.has(node)) return "";
+    visited.add(node);
+
+    if (typeof node === "string") return node;
+
+    if (typeof node === "object") {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.name && typeof anyNode.name === "string") {
+        return anyNode.name;
+      }
+
+      if (anyNode.value) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+    }
+
+    return "";
+  };
+
+  return walk(sqlObj);
+}
+
+function createThenableQuery<T>(result: T, opts?: { orderByArgs?: unknown[][] }) {
+  const query: any = Promise.resolve(result);
+
+  query.from = vi.fn(() => query);
+  query.leftJoin = vi.fn(() => query);
+  query.where = vi.fn(() => query);
+  query.orderBy = vi.fn((...args: unknown[]) => {
+    opts?.orderByArgs?.push(args);
+    return query;
+  });
+  query.limit = vi.fn(() => query);
+  query.offset = vi.fn(() => query);
+
+  return query;
+}
+
+function requestRow(id: number, sequence: number | null) {
+  return {
+    id,
+    sequence,
+    model: "claude-sonnet-4-6",
+    statusCode: 200,
+    costUsd: "0.001",
+    createdAt: new Date("2026-03-01T00:00:00.000Z"),
+    inputTokens: 10,
+    outputTokens: 20,
+    errorMessage: null,
+    providerId: 3,
+    providerName: "provider-a",
+    userId: 7,
+    userName: null,
+  };
+}
+
+describe("repository/message findRequestsBySessionId", () => {
+  test("orders by sequence, then created_at and id, with provider/user names", async () => {
+    vi.resetModules();
+
+    const orderByArgs: unknown[][] = [];
+    const selectMock = vi
+      .fn()
+      .mockReturnValueOnce(createThenableQuery([{ count: 2 }]))
+      .mockReturnValueOnce(
+        createThenableQuery([requestRow(11, 1), requestRow(12, null)], { orderByArgs })
+      );
+
+    vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+
+    const { findRequestsBySessionId } = await import("@/repository/message");
+    const result = await findRequestsBySessionId("session-a");
+
+    expect(result.total).toBe(2);
+    expect(result.requests.map((r) => [r.id, r.sequence])).toEqual([
+      [11, 1],
+      [12, 1],
+    ]);
+    expect(result.requests[0]).toMatchObject({
+      providerId: 3,
+      providerName: "provider-a",
+      userId: 7,
+      userName: null,
+    });
+
+    const orderSql = orderByArgs[0].map((arg) => sqlToString(arg).toLowerCase());
+    expect(orderSql).toEqual(["request_sequence asc", "created_at asc", "id asc"]);
+  });
+
+  test("descending order applies to every sort key", async () => {
+    vi.resetModules();
+
+    const orderByArgs: unknown[][] = [];
+    const selectMock = vi
+      .fn()
+      .mockReturnValueOnce(createThenableQuery([{ count: 0 }]))
+      .mockReturnValueOnce(createThenableQuery([], { orderByArgs }));
+
+    vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+
+    const { findRequestsBySessionId } = await import("@/repository/message");
+    const result = await findRequestsBySessionId("unknown-session", { order: "desc" });
+
+    expect(result).toEqual({ requests: [], total: 0 });
+    const orderSql = orderByArgs[0].map((arg) => sqlToString(arg).toLowerCase());
+    expect(orderSql).toEqual(["request_sequence desc", "created_at desc", "id desc"]);
+  });
+});
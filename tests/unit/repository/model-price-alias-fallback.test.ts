@@ -67,3 +67,44 @@ describe("findLatestPriceByModel alias fallback", () => {
     }
   });
 });
+
+describe("findPriceByModelFuzzy", () => {
+  it("matches a normalized model name after exact and alias lookups miss", async () => {
+    const { db } = await import("@/drizzle/db");
+    const row = (modelName: string) => ({
+      id: 1,
+      modelName,
+      priceData: { mode: "chat", input_cost_per_token: 0.000003 },
+      source: "litellm",
+      createdAt: new Date("2026-01-01T00:00:00.000Z"),
+      updatedAt: new Date("2026-01-01T00:00:00.000Z"),
+    });
+    vi.mocked(db.execute)
+      .mockResolvedValueOnce([] as never)
+      .mockResolvedValueOnce([row("claude-3"), row("claude-3.5-sonnet")] as never);
+
+    const { findPriceByModelFuzzy } = await import("@/repository/model-price");
+    const price = await findPriceByModelFuzzy("claude-3-5-sonnet-20241022");
+
+    expect(price?.modelName).toBe("claude-3.5-sonnet");
+    const familyQuery = dialect.sqlToQuery(vi.mocked(db.execute).mock.calls[1][0] as SQL);
+    expect(familyQuery.sql).toContain("ESCAPE '\\'");
+    expect(familyQuery.params).toContain("claude%");
+  });
+
+  it("escapes LIKE wildcards in the model family", async () => {
+    const { findPriceByModelFuzzy } = await import("@/repository/model-price");
+
+    await findPriceByModelFuzzy("gpt%-turbo");
+
+    const familyQuery = dialect.sqlToQuery(executedQueries[1]);
+    expect(familyQuery.params).toContain("gpt\\%%");
+  });
+
+  it("is not used by the billing lookup", async () => {
+    const { findLatestPriceByModel } = await import("@/repository/model-price");
+
+    expect(await findLatestPriceByModel("gpt-4o-mini-2099")).toBeNull();
+    expect(executedQueries).toHaveLength(1);
+  });
+});
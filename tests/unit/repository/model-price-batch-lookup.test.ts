@@ -0,0 +1,73 @@
+import type { SQL } from "drizzle-orm";
+import { PgDialect } from "drizzle-orm/pg-core";
+import { beforeEach, describe, expect, it, vi } from "vitest";
+
+const dialect = new PgDialect();
+
+let executedQueries: SQL[] = [];
+let resultRows: Record<string, unknown>[] = [];
+
+vi.mock("server-only", () => ({}));
+
+vi.mock("@/drizzle/db", () => ({ db: {} }));
+
+vi.mock("@/drizzle/read-db", () => ({
+  readDb: {
+    execute: vi.fn((query: SQL) => {
+      executedQueries.push(query);
+      return Promise.resolve(resultRows);
+    }),
+  },
+}));
+
+vi.mock("@/lib/logger", () => ({
+  logger: { debug: vi.fn(), info: vi.fn(), warn: vi.fn(), error: vi.fn() },
+}));
+
+function row(id: number, modelName: string) {
+  return {
+    id,
+    modelName,
+    priceData: { mode: "chat", input_cost_per_token: 0.000003 },
+    source: "litellm",
+    createdAt: new Date("2026-01-01T00:00:00.000Z"),
+    updatedAt: new Date("2026-01-01T00:00:00.000Z"),
+  };
+}
+
+beforeEach(() => {
+  executedQueries = [];
+  resultRows = [];
+});
+
+describe("findLatestPricesByModels", () => {
+  it("returns prices for present models and omits absent ones in a single query", async () => {
+    resultRows = [row(3, "claude-sonnet-4-6"), row(7, "gpt-4o")];
+    const { findLatestPricesByModels } = await import("@/repository/model-price");
+
+    const prices = await findLatestPricesByModels([
+      "claude-sonnet-4-6",
+      "gpt-4o",
+      "missing-model",
+      " gpt-4o ",
+    ]);
+
+    expect(executedQueries).toHaveLength(1);
+    const query = dialect.sqlToQuery(executedQueries[0]);
+    expect(query.sql).toContain("DISTINCT ON (model_name)");
+    expect(query.params).toEqual(["claude-sonnet-4-6", "gpt-4o", "missing-model"]);
+
+    expect([...prices.keys()].sort()).toEqual(["claude-sonnet-4-6", "gpt-4o"]);
+    expect(prices.get("gpt-4o")?.id).toBe(7);
+    expect(prices.has("missing-model")).toBe(false);
+  });
+
+  it("skips the query when no model names are given", async () => {
+    const { findLatestPricesByModels } = await import("@/repository/model-price");
+
+    const prices = await findLatestPricesByModels(["", "  "]);
+
+    expect(prices.size).toBe(0);
+    expect(executedQueries).toHaveLength(0);
+  });
+});
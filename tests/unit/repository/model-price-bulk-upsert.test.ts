@@ -0,0 +1,99 @@
+import { beforeEach, describe, expect, it, vi } from "vitest";
+
+let insertedChunks: Array<Array<Record<string, unknown>>> = [];
+let historyChunks: Array<Array<Record<string, unknown>>> = [];
+let deleteCalls = 0;
+let existingModelNames = new Set<string>();
+
+vi.mock("server-only", () => ({}));
+
+vi.mock("@/drizzle/db", () => {
+  const tx = {
+    selectDistinct: vi.fn(() => ({
+      from: vi.fn(() => ({
+        // 不解析 inArray 条件，直接返回预置的"已存在"模型
+        where: vi.fn(() => {
+          const rows = Array.from(existingModelNames).map((modelName) => ({ modelName }));
+          existingModelNames = new Set();
+          return Promise.resolve(rows);
+        }),
+      })),
+    })),
+    delete: vi.fn(() => ({
+      where: vi.fn(() => {
+        deleteCalls++;
+        return Promise.resolve();
+      }),
+    })),
+    insert: vi.fn((table: unknown) => ({
+      values: vi.fn(async (values: Array<Record<string, unknown>>) => {
+        const { modelPriceHistory } = await import("@/drizzle/schema");
+        (table === modelPriceHistory ? historyChunks : insertedChunks).push(values);
+      }),
+    })),
+  };
+  return {
+    db: {
+      transaction: vi.fn(async (cb: (t: typeof tx) => Promise<unknown>) => cb(tx)),
+    },
+  };
+});
+
+vi.mock("@/lib/logger", () => ({
+  logger: { debug: vi.fn(), info: vi.fn(), warn: vi.fn(), error: vi.fn() },
+}));
+
+beforeEach(() => {
+  insertedChunks = [];
+  historyChunks = [];
+  deleteCalls = 0;
+  existingModelNames = new Set();
+});
+
+describe("upsertModelPricesBatch", () => {
+  it("imports 5000 models in chunked multi-row inserts", async () => {
+    const { upsertModelPricesBatch } = await import("@/repository/model-price");
+
+    const prices = Array.from({ length: 5000 }, (_, index) => ({
+      modelName: `synthetic-model-${index}`,
+      priceData: {
+        mode: "chat" as const,
+        input_cost_per_token: index / 1e9,
+        custom_extra: { tier: index % 3 },
+      },
+    }));
+    existingModelNames = new Set(["synthetic-model-0", "synthetic-model-1"]);
+
+    const result = await upsertModelPricesBatch(prices, "litellm");
+
+    expect(result).toEqual({ inserted: 4998, updated: 2 });
+    expect(insertedChunks).toHaveLength(5);
+    expect(insertedChunks.every((chunk) => chunk.length === 1000)).toBe(true);
+    expect(deleteCalls).toBe(1);
+    expect(insertedChunks[4][999]).toEqual({
+      modelName: "synthetic-model-4999",
+      priceData: prices[4999].priceData,
+      source: "litellm",
+    });
+    // 每个分块同时追加价格历史
+    expect(historyChunks).toEqual(insertedChunks);
+  });
+
+  it("dedupes model names within one call and skips empty input", async () => {
+    const { upsertModelPricesBatch } = await import("@/repository/model-price");
+
+    expect(await upsertModelPricesBatch([])).toEqual({ inserted: 0, updated: 0 });
+    expect(insertedChunks).toHaveLength(0);
+    expect(historyChunks).toHaveLength(0);
+
+    const result = await upsertModelPricesBatch([
+      { modelName: "dup", priceData: { mode: "chat", input_cost_per_token: 1 } },
+      { modelName: "dup", priceData: { mode: "chat", input_cost_per_token: 2 } },
+    ]);
+
+    expect(result).toEqual({ inserted: 1, updated: 0 });
+    expect(insertedChunks[0]).toEqual([
+      { modelName: "dup", priceData: { mode: "chat", input_cost_per_token: 2 }, source: "manual" },
+    ]);
+  });
+});
@@ -0,0 +1,89 @@
+import { beforeEach, describe, expect, it, vi } from "vitest";
+
+let resultRows: Record<string, unknown>[] = [];
+let limitArgs: number[] = [];
+let selectCalls = 0;
+
+vi.mock("server-only", () => ({}));
+
+vi.mock("@/drizzle/db", () => ({ db: {} }));
+
+vi.mock("@/drizzle/read-db", () => ({
+  readDb: {
+    select: vi.fn(() => {
+      selectCalls++;
+      const query: any = {};
+      query.from = vi.fn(() => query);
+      query.where = vi.fn(() => query);
+      query.orderBy = vi.fn(() => query);
+      query.limit = vi.fn((value: number) => {
+        limitArgs.push(value);
+        return Promise.resolve(resultRows);
+      });
+      return query;
+    }),
+  },
+}));
+
+vi.mock("@/lib/logger", () => ({
+  logger: { debug: vi.fn(), info: vi.fn(), warn: vi.fn(), error: vi.fn() },
+}));
+
+function row(id: number, createdAt: string, inputCost: number) {
+  return {
+    id,
+    modelName: "gpt-4o",
+    priceData: { mode: "chat", input_cost_per_token: inputCost },
+    source: "cloud",
+    createdAt: new Date(createdAt),
+    updatedAt: new Date(createdAt),
+  };
+}
+
+beforeEach(() => {
+  resultRows = [];
+  limitArgs = [];
+  selectCalls = 0;
+});
+
+describe("findPriceHistoryByModel", () => {
+  it("returns history rows newest first and diffs adjacent versions", async () => {
+    resultRows = [
+      row(9, "2026-03-01T00:00:00.000Z", 0.0000025),
+      row(4, "2026-01-01T00:00:00.000Z", 0.000005),
+    ];
+    const { findPriceHistoryByModel } = await import("@/repository/model-price");
+    const { diffPriceData } = await import("@/lib/utils/price-data");
+
+    const history = await findPriceHistoryByModel("gpt-4o", 5);
+
+    expect(limitArgs).toEqual([5]);
+    expect(history.map((price) => price.id)).toEqual([9, 4]);
+    expect(diffPriceData(history[1].priceData, history[0].priceData)).toEqual([
+      { field: "input_cost_per_token", before: 0.000005, after: 0.0000025 },
+    ]);
+  });
+
+  it("reads from the append-only model_price_history table", async () => {
+    const { readDb } = await import("@/drizzle/read-db");
+    const { modelPriceHistory } = await import("@/drizzle/schema");
+    const { findPriceHistoryByModel } = await import("@/repository/model-price");
+
+    await findPriceHistoryByModel("gpt-4o");
+
+    const query = vi.mocked(readDb.select).mock.results[0].value;
+    expect(query.from).toHaveBeenCalledWith(modelPriceHistory);
+  });
+
+  it("clamps the limit and skips blank model names", async () => {
+    const { findPriceHistoryByModel } = await import("@/repository/model-price");
+
+    await findPriceHistoryByModel("gpt-4o", 0);
+    await findPriceHistoryByModel("gpt-4o", 10_000);
+    await findPriceHistoryByModel("gpt-4o", Number.NaN);
+    expect(limitArgs).toEqual([1, 100, 20]);
+
+    expect(await findPriceHistoryByModel("  ")).toEqual([]);
+    expect(selectCalls).toBe(3);
+  });
+});
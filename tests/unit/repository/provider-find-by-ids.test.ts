@@ -0,0 +1,115 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function providerRow(id: number, overrides: Record<string, unknown> = {}) {
+  return {
+    id,
+    name: `provider-${id}`,
+    url: "https://api.example.com",
+    key: "sk-test",
+    isEnabled: true,
+    weight: 1,
+    priority: 0,
+    costMultiplier: "1.0",
+    providerType: "claude",
+    modelRedirects: null,
+    allowedModels: null,
+    createdAt: new Date("2026-01-01T00:00:00.000Z"),
+    updatedAt: new Date("2026-01-01T00:00:00.000Z"),
+    deletedAt: null,
+    ...overrides,
+  };
+}
+
+function mockDb(rows: unknown[]) {
+  const whereArgs: unknown[] = [];
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return query;
+  });
+  const selectMock = vi.fn(() => query);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { selectMock, whereArgs };
+}
+
+describe("findProvidersByIds", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("returns an empty map without querying for empty input", async () => {
+    const { selectMock } = mockDb([]);
+
+    const { findProvidersByIds } = await import("@/repository/provider");
+    const result = await findProvidersByIds([]);
+
+    expect(result.size).toBe(0);
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+
+  test("keys results by id and excludes deleted providers by default", async () => {
+    const { whereArgs } = mockDb([providerRow(1), providerRow(3)]);
+
+    const { findProvidersByIds } = await import("@/repository/provider");
+    const result = await findProvidersByIds([1, 3, 3, 5]);
+
+    expect([...result.keys()]).toEqual([1, 3]);
+    expect(result.get(3)?.name).toBe("provider-3");
+
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("deleted_at is null");
+    expect(whereSql).toContain("135");
+  });
+
+  test("includeDeleted resolves soft-deleted providers for historical logs", async () => {
+    const deletedAt = new Date("2026-02-01T00:00:00.000Z");
+    const { whereArgs } = mockDb([providerRow(2, { deletedAt })]);
+
+    const { findProvidersByIds } = await import("@/repository/provider");
+    const result = await findProvidersByIds([2], true);
+
+    expect(result.get(2)?.deletedAt).toEqual(deletedAt);
+    expect(sqlToString(whereArgs[0])).not.toContain("deleted_at is null");
+  });
+});
@@ -0,0 +1,161 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number" || typeof node === "boolean") {
+      return String(node);
+    }
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function mockDb(rows: unknown[] = []) {
+  const captured: { where: unknown[]; orderBy: unknown[][] } = { where: [], orderBy: [] };
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    captured.where.push(arg);
+    return query;
+  });
+  query.orderBy = vi.fn((...args: unknown[]) => {
+    captured.orderBy.push(args);
+    return query;
+  });
+  const selectMock = vi.fn(() => query);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { selectMock, captured };
+}
+
+/**
+ * 用 SQL 中实际使用的拆分正则在 JS 中模拟 `tag = ANY(regexp_split_to_array(...))`
+ */
+function matchesTag(whereSql: string, groupTag: string | null, tag: string): boolean {
+  const match = /regexp_split_to_array\(coalesce\(.*?, ''\), '(.+?)'\)/s.exec(whereSql);
+  if (!match) throw new Error(`split pattern not found in: ${whereSql}`);
+  return (groupTag ?? "").split(new RegExp(match[1])).includes(tag);
+}
+
+describe("findProvidersByGroupTag", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("returns [] without querying for a blank tag", async () => {
+    const { selectMock } = mockDb();
+
+    const { findProvidersByGroupTag } = await import("@/repository/provider");
+
+    expect(await findProvidersByGroupTag("  ")).toEqual([]);
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+
+  test("matches any tag of a comma-separated group_tag", async () => {
+    const { captured } = mockDb();
+
+    const { findProvidersByGroupTag } = await import("@/repository/provider");
+    await findProvidersByGroupTag(" pool-b ");
+
+    const whereSql = sqlToString(captured.where[0]);
+    expect(whereSql).toContain("deleted_at is null");
+    expect(whereSql).not.toContain("is_enabled");
+
+    expect(matchesTag(whereSql, "pool-b", "pool-b")).toBe(true);
+    expect(matchesTag(whereSql, "pool-a,pool-b", "pool-b")).toBe(true);
+    expect(matchesTag(whereSql, "pool-a , pool-b", "pool-b")).toBe(true);
+    expect(matchesTag(whereSql, "pool-a，pool-b", "pool-b")).toBe(true);
+    expect(matchesTag(whereSql, "pool-bb,pool-a", "pool-b")).toBe(false);
+    expect(matchesTag(whereSql, null, "pool-b")).toBe(false);
+  });
+
+  test("enabledOnly filters disabled providers and keeps selection ordering", async () => {
+    const { captured } = mockDb();
+
+    const { findProvidersByGroupTag } = await import("@/repository/provider");
+    await findProvidersByGroupTag("pool-a", { enabledOnly: true });
+
+    expect(sqlToString(captured.where[0])).toContain("is_enabled");
+    expect(captured.orderBy[0]?.map((arg) => sqlToString(arg).trim())).toEqual([
+      "priority",
+      "weight desc",
+      "id",
+    ]);
+  });
+
+  test("providerType narrows a mixed-type group to providers serving the endpoint", async () => {
+    const { captured } = mockDb();
+
+    const { findProvidersByGroupTag } = await import("@/repository/provider");
+    await findProvidersByGroupTag("mixed");
+    await findProvidersByGroupTag("mixed", {
+      enabledOnly: true,
+      providerType: "openai-compatible",
+    });
+
+    expect(sqlToString(captured.where[0])).not.toContain("provider_type");
+
+    const filteredSql = sqlToString(captured.where[1]);
+    expect(filteredSql).toContain("provider_type = openai-compatible");
+    expect(filteredSql).toContain("is_enabled");
+    expect(matchesTag(filteredSql, "mixed,pool-a", "mixed")).toBe(true);
+  });
+});
+
+describe("findEnabledProvidersByGroupTag", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("filters the shared provider cache instead of caching per tag", async () => {
+    const { selectMock } = mockDb();
+    const base = { modelRedirects: null, allowedModels: null };
+    const getCachedProviders = vi.fn(async () => [
+      { ...base, id: 1, isEnabled: true, groupTag: "pool-a", priority: 1, weight: 1 },
+      { ...base, id: 2, isEnabled: false, groupTag: "pool-a", priority: 0, weight: 1 },
+      { ...base, id: 3, isEnabled: true, groupTag: "pool-b，pool-a", priority: 0, weight: 1 },
+      { ...base, id: 4, isEnabled: true, groupTag: "pool-aa", priority: 0, weight: 1 },
+      { ...base, id: 5, isEnabled: true, groupTag: "pool-a", priority: 1, weight: 5 },
+    ]);
+    vi.doMock("@/lib/cache/provider-cache", () => ({ getCachedProviders }));
+
+    const { findEnabledProvidersByGroupTag } = await import("@/repository/provider");
+    const result = await findEnabledProvidersByGroupTag(" pool-a ");
+
+    expect(result.map((p) => p.id)).toEqual([3, 5, 1]);
+    expect(await findEnabledProvidersByGroupTag("  ")).toEqual([]);
+    expect(getCachedProviders).toHaveBeenCalledTimes(1);
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+});
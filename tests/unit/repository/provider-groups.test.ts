@@ -43,6 +43,9 @@ const returningMock = vi.fn();
 const onConflictDoNothingMock = vi.fn();
 const setMock = vi.fn();
 const deleteWhereMock = vi.fn();
+const transactionMock = vi.fn();
+const invalidateCachedUserMock = vi.fn();
+const invalidateCachedKeyMock = vi.fn();
 
 function createQuery<T>(result: T, whereArgs?: unknown[]) {
   const query: any = Promise.resolve(result);
@@ -74,6 +77,12 @@ function resetChainMocks() {
 
   deleteWhereMock.mockResolvedValue(undefined);
   deleteMock.mockReturnValue({ where: deleteWhereMock });
+
+  transactionMock.mockImplementation(async (fn: (tx: unknown) => unknown) =>
+    fn({ select: selectMock, update: updateMock })
+  );
+  invalidateCachedUserMock.mockResolvedValue(undefined);
+  invalidateCachedKeyMock.mockResolvedValue(undefined);
 }
 
 vi.mock("@/drizzle/db", () => ({
@@ -82,9 +91,20 @@ vi.mock("@/drizzle/db", () => ({
     insert: insertMock,
     update: updateMock,
     delete: deleteMock,
+    transaction: transactionMock,
   },
 }));
 
+vi.mock("@/lib/cache/invalidation", () => ({
+  invalidateCacheEntry: vi.fn(async () => undefined),
+  registerCacheInvalidationHandler: vi.fn(() => () => {}),
+}));
+
+vi.mock("@/lib/security/api-key-auth-cache", () => ({
+  invalidateCachedUser: invalidateCachedUserMock,
+  invalidateCachedKey: invalidateCachedKeyMock,
+}));
+
 vi.mock("@/drizzle/schema", () => ({
   providerGroups: {
     id: "id",
@@ -95,9 +115,21 @@ vi.mock("@/drizzle/schema", () => ({
     updatedAt: "updated_at",
   },
   providers: {
+    id: "id",
     groupTag: "group_tag",
     deletedAt: "deleted_at",
   },
+  users: {
+    id: "id",
+    providerGroup: "provider_group",
+    deletedAt: "deleted_at",
+  },
+  keys: {
+    id: "id",
+    key: "key",
+    providerGroup: "provider_group",
+    deletedAt: "deleted_at",
+  },
 }));
 
 function fakeRow(
@@ -299,4 +331,72 @@ describe("provider-groups repository", () => {
       await expect(deleteProviderGroup(2)).resolves.toBeUndefined();
     });
   });
+
+  describe("reassignProviderGroup", () => {
+    it("splices the group in users, keys and providers inside one transaction", async () => {
+      selectMock
+        .mockImplementationOnce(() =>
+          createQuery([
+            { id: 1, providerGroup: "legacy,premium" },
+            { id: 2, providerGroup: "legacy-old" },
+          ])
+        )
+        .mockImplementationOnce(() =>
+          createQuery([{ id: 10, key: "sk-a", providerGroup: "legacy" }])
+        )
+        .mockImplementationOnce(() =>
+          createQuery([
+            { id: 20, groupTag: "cn,legacy,us" },
+            { id: 21, groupTag: "target,legacy" },
+          ])
+        );
+
+      const { reassignProviderGroup } = await import("@/repository/provider-groups");
+      const result = await reassignProviderGroup("legacy", "target");
+
+      expect(result).toEqual({ usersUpdated: 1, keysUpdated: 1, providersUpdated: 2 });
+      expect(transactionMock).toHaveBeenCalledTimes(1);
+
+      const setArgs = setMock.mock.calls.map(([arg]) => {
+        const { updatedAt: _updatedAt, ...rest } = arg as Record<string, unknown>;
+        return rest;
+      });
+      expect(setArgs).toEqual([
+        { providerGroup: "premium,target" },
+        { providerGroup: "target" },
+        { groupTag: "cn,target,us" },
+        { groupTag: "target" },
+      ]);
+      expect(invalidateCachedUserMock).toHaveBeenCalledWith(1);
+      expect(invalidateCachedKeyMock).toHaveBeenCalledWith("sk-a");
+    });
+
+    it("removes the group when target is empty and falls back per column", async () => {
+      selectMock
+        .mockImplementationOnce(() => createQuery([{ id: 1, providerGroup: "legacy" }]))
+        .mockImplementationOnce(() => createQuery([]))
+        .mockImplementationOnce(() => createQuery([{ id: 20, groupTag: "legacy" }]));
+
+      const { reassignProviderGroup } = await import("@/repository/provider-groups");
+      const result = await reassignProviderGroup("legacy", "");
+
+      expect(result).toEqual({ usersUpdated: 1, keysUpdated: 0, providersUpdated: 1 });
+      expect(setMock.mock.calls[0][0]).toMatchObject({ providerGroup: "default" });
+      expect(setMock.mock.calls[1][0]).toMatchObject({ groupTag: null });
+    });
+
+    it("is a no-op when source is blank or equals target", async () => {
+      const { reassignProviderGroup } = await import("@/repository/provider-groups");
+
+      await expect(reassignProviderGroup(" ", "target")).resolves.toEqual({
+        usersUpdated: 0,
+        keysUpdated: 0,
+        providersUpdated: 0,
+      });
+      await expect(reassignProviderGroup("legacy", " legacy ")).resolves.toMatchObject({
+        usersUpdated: 0,
+      });
+      expect(transactionMock).not.toHaveBeenCalled();
+    });
+  });
 });
@@ -147,3 +147,173 @@ describe("provider repository - updateProviderPrioritiesBatch", () => {
     );
   });
 });
+
+describe("provider repository - getProviderStatistics latency percentiles", () => {
+  test("computes p50/p95/p99 with percentile_cont excluding null durations", async () => {
+    vi.resetModules();
+
+    const executeMock = vi.fn(async () => [
+      {
+        id: 1,
+        today_cost: "1.5",
+        today_calls: 3,
+        p50_duration_ms: "1200.5",
+        p95_duration_ms: 3400,
+        p99_duration_ms: 3999.6,
+        last_call_time: null,
+        last_call_model: null,
+      },
+      {
+        id: 2,
+        today_cost: "0",
+        today_calls: 0,
+        p50_duration_ms: null,
+        p95_duration_ms: null,
+        p99_duration_ms: null,
+        last_call_time: null,
+        last_call_model: null,
+      },
+    ]);
+
+    vi.doMock("@/drizzle/db", () => ({
+      db: {
+        execute: executeMock,
+      },
+    }));
+    vi.doMock("@/lib/utils/timezone", () => ({
+      resolveSystemTimezone: vi.fn(async () => "UTC"),
+    }));
+    vi.doMock("@/repository/_shared/statistics-query-timeout", () => ({
+      executeStatisticsQuery: executeMock,
+    }));
+
+    const { getProviderStatistics } = await import("@/repository/provider");
+    const result = await getProviderStatistics();
+
+    expect(result[0]).toEqual(
+      expect.objectContaining({
+        id: 1,
+        p50_duration_ms: 1201,
+        p95_duration_ms: 3400,
+        p99_duration_ms: 4000,
+      })
+    );
+    expect(result[1]).toEqual(
+      expect.objectContaining({
+        id: 2,
+        p50_duration_ms: null,
+        p95_duration_ms: null,
+        p99_duration_ms: null,
+      })
+    );
+
+    const sqlText = sqlToString(executeMock.mock.calls[0]?.[0]).replaceAll(/\s+/g, " ");
+    expect(sqlText).toContain("percentile_cont(0.5) WITHIN GROUP (ORDER BY duration_ms)");
+    expect(sqlText).toContain("percentile_cont(0.95) WITHIN GROUP (ORDER BY duration_ms)");
+    expect(sqlText).toContain("percentile_cont(0.99) WITHIN GROUP (ORDER BY duration_ms)");
+    expect(sqlText).toContain("FILTER (WHERE duration_ms IS NOT NULL)");
+    expect(sqlText).toContain("GROUP BY final_provider_id");
+  });
+});
+
+describe("provider repository - getProviderStatistics group tag filter", () => {
+  test("restricts providers and ledger aggregation to the requested group tag", async () => {
+    vi.resetModules();
+
+    const executeMock = vi.fn(async () => []);
+
+    vi.doMock("@/drizzle/db", () => ({
+      db: {
+        execute: executeMock,
+      },
+    }));
+    vi.doMock("@/lib/utils/timezone", () => ({
+      resolveSystemTimezone: vi.fn(async () => "UTC"),
+    }));
+    vi.doMock("@/repository/_shared/statistics-query-timeout", () => ({
+      executeStatisticsQuery: executeMock,
+    }));
+
+    const { getProviderStatistics } = await import("@/repository/provider");
+    await getProviderStatistics(" pool-a ");
+
+    const rawSqlText = sqlToString(executeMock.mock.calls[0]?.[0]);
+    // 与 findProvidersByGroupTag 使用同一拆分正则（sql 模板中 \s 需要双重转义）
+    expect(rawSqlText).toContain(
+      "pool-a = ANY(regexp_split_to_array(coalesce(p.group_tag, ''), '\\s*[,，\n\r]+\\s*'))"
+    );
+    const sqlText = rawSqlText.replaceAll(/\s+/g, " ");
+    expect(sqlText.match(/AND final_provider_id IN \(/g)).toHaveLength(2);
+    expect(sqlText).toContain("ORDER BY final_provider_id, created_at DESC, id DESC");
+
+    // 不同分组不复用缓存，全量查询不带分组条件
+    await getProviderStatistics();
+    const allSqlText = sqlToString(executeMock.mock.calls[1]?.[0]);
+    expect(executeMock).toHaveBeenCalledTimes(2);
+    expect(allSqlText).not.toContain("regexp_split_to_array");
+    expect(allSqlText).not.toContain("final_provider_id IN");
+
+    // 每个分组各占一个缓存条目，交替查询不会互相驱逐
+    await getProviderStatistics("pool-a");
+    await getProviderStatistics();
+    expect(executeMock).toHaveBeenCalledTimes(2);
+  });
+});
+
+describe("provider repository - getProviderHealthSnapshot", () => {
+  test("computes error rate from last-hour status codes and keeps last success time", async () => {
+    vi.resetModules();
+
+    const executeMock = vi.fn(async () => [
+      {
+        id: 1,
+        is_enabled: true,
+        recent_calls: 8,
+        recent_errors: "2",
+        last_success_time: "2026-01-01T00:00:00.000Z",
+      },
+      {
+        id: 2,
+        is_enabled: false,
+        recent_calls: 0,
+        recent_errors: 0,
+        last_success_time: null,
+      },
+    ]);
+
+    vi.doMock("@/drizzle/db", () => ({
+      db: {
+        execute: executeMock,
+      },
+    }));
+
+    const { getProviderHealthSnapshot } = await import("@/repository/provider");
+    const result = await getProviderHealthSnapshot();
+
+    expect(result).toEqual([
+      {
+        id: 1,
+        isEnabled: true,
+        recentCalls: 8,
+        recentErrors: 2,
+        errorRate: 0.25,
+        lastSuccessTime: new Date("2026-01-01T00:00:00.000Z"),
+      },
+      {
+        id: 2,
+        isEnabled: false,
+        recentCalls: 0,
+        recentErrors: 0,
+        errorRate: null,
+        lastSuccessTime: null,
+      },
+    ]);
+
+    const sqlText = sqlToString(executeMock.mock.calls[0]?.[0]).replaceAll(/\s+/g, " ");
+    expect(sqlText).toContain("WHERE blocked_by IS NULL");
+    expect(sqlText).toContain("COUNT(*) FILTER (WHERE status_code >= 400)");
+    expect(sqlText).toContain("INTERVAL '1 hour'");
+    expect(sqlText).toContain("GROUP BY final_provider_id");
+    expect(sqlText).toContain("WHERE p.deleted_at IS NULL");
+  });
+});
@@ -54,6 +54,50 @@ describe("getRateLimitEventStats query", () => {
     expect(chunks.strings.join(" ")).not.toContain("created_at >= $1");
     expect(chunks.strings.join(" ")).not.toContain("created_at <= $2");
   });
+
+  test("reads rate_limit_metadata column and falls back to error_message for old rows", async () => {
+    const hour = new Date("2026-04-30T08:00:00.000Z");
+
+    executeMock.mockResolvedValue([
+      {
+        id: 1,
+        user_id: 2,
+        provider_id: 3,
+        error_message: "RPM limit exceeded",
+        rate_limit_metadata: { limit_type: "rpm", current_usage: 100, limit_value: 90 },
+        hour,
+      },
+      {
+        id: 2,
+        user_id: 2,
+        provider_id: 3,
+        error_message:
+          '5h limit exceeded | rate_limit_metadata: {"limit_type":"usd_5h","current_usage":6,"message":"a}b"}',
+        rate_limit_metadata: null,
+        hour,
+      },
+      {
+        id: 3,
+        user_id: 4,
+        provider_id: 3,
+        error_message: "rate_limit_metadata: not json",
+        rate_limit_metadata: null,
+        hour,
+      },
+    ]);
+
+    const { getRateLimitEventStats } = await import("@/repository/statistics");
+    const stats = await getRateLimitEventStats();
+
+    expect(stats.total_events).toBe(2);
+    expect(stats.events_by_type).toEqual({ rpm: 1, usd_5h: 1 });
+    expect(stats.events_by_user).toEqual({ 2: 2 });
+    expect(stats.avg_current_usage).toBe(53);
+
+    const filtered = await getRateLimitEventStats({ limit_type: "rpm" });
+    expect(filtered.total_events).toBe(1);
+    expect(filtered.events_by_type).toEqual({ rpm: 1 });
+  });
 });
 
 function collectSqlChunks(value: unknown): { strings: string[]; dates: Date[] } {
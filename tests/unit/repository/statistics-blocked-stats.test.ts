@@ -0,0 +1,98 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const visited = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (!node || visited.has(node)) return "";
+    visited.add(node);
+
+    if (typeof node === "string") return node;
+
+    if (typeof node === "object") {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.value) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (anyNode.name && anyNode.table) {
+        return String(anyNode.name);
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+    }
+
+    return "";
+  };
+
+  return walk(sqlObj);
+}
+
+type Captured = { where?: unknown; groupBy?: unknown[] };
+
+function setup(rows: unknown[]) {
+  const captured: Captured = {};
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.groupBy = vi.fn((...args: unknown[]) => {
+    captured.groupBy = args;
+    return query;
+  });
+  query.where = vi.fn((arg: unknown) => {
+    captured.where = arg;
+    return query;
+  });
+
+  vi.doMock("@/drizzle/db", () => ({
+    db: {
+      select: vi.fn(() => query),
+    },
+  }));
+
+  return { captured };
+}
+
+describe("getBlockedStatsFromDB", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("returns counts keyed by blocked_by, including warmup and sensitive words", async () => {
+    setup([
+      { blockedBy: "warmup", count: "42" },
+      { blockedBy: "sensitive_word", count: 3 },
+      { blockedBy: null, count: 99 },
+    ]);
+
+    const { getBlockedStatsFromDB } = await import("@/repository/statistics");
+    const result = await getBlockedStatsFromDB("today", "UTC");
+
+    expect(result).toEqual({ warmup: 42, sensitive_word: 3 });
+  });
+
+  test("queries message_request for non-null blocked_by without excluding warmup", async () => {
+    const { captured } = setup([]);
+
+    const { getBlockedStatsFromDB } = await import("@/repository/statistics");
+    const result = await getBlockedStatsFromDB("7days", "Asia/Shanghai");
+
+    expect(result).toEqual({});
+
+    const whereSql = sqlToString(captured.where);
+    expect(whereSql).toContain("Asia/Shanghai");
+    expect(whereSql).toContain("blocked_by IS NOT NULL");
+    expect(whereSql).toContain("deleted_at");
+    expect(whereSql).not.toContain("warmup");
+
+    expect(sqlToString(captured.groupBy)).toContain("blocked_by");
+  });
+});
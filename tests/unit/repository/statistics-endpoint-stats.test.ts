@@ -0,0 +1,106 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const visited = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (!node || visited.has(node)) return "";
+    visited.add(node);
+
+    if (typeof node === "string") return node;
+
+    if (typeof node === "object") {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.value) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (anyNode.name && anyNode.table) {
+        return String(anyNode.name);
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+    }
+
+    return "";
+  };
+
+  return walk(sqlObj);
+}
+
+type Captured = { selection?: Record<string, unknown>; where?: unknown; orderBy?: unknown[] };
+
+function setup(rows: unknown[]) {
+  const captured: Captured = {};
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.groupBy = vi.fn(() => query);
+  query.orderBy = vi.fn((...args: unknown[]) => {
+    captured.orderBy = args;
+    return query;
+  });
+  query.where = vi.fn((arg: unknown) => {
+    captured.where = arg;
+    return query;
+  });
+
+  vi.doMock("@/drizzle/db", () => ({
+    db: {
+      select: vi.fn((selection: Record<string, unknown>) => {
+        captured.selection = selection;
+        return query;
+      }),
+    },
+  }));
+
+  return { captured };
+}
+
+describe("getEndpointStatsFromDB", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("normalizes aggregated rows into numbers", async () => {
+    setup([
+      { endpoint: "/v1/messages", apiCalls: "12", errorCount: 2, totalCost: "3.5" },
+      { endpoint: "/v1/responses", apiCalls: 4, errorCount: "0", totalCost: null },
+    ]);
+
+    const { getEndpointStatsFromDB } = await import("@/repository/statistics");
+    const result = await getEndpointStatsFromDB("today", "UTC");
+
+    expect(result).toEqual([
+      { endpoint: "/v1/messages", apiCalls: 12, errorCount: 2, totalCost: 3.5 },
+      { endpoint: "/v1/responses", apiCalls: 4, errorCount: 0, totalCost: 0 },
+    ]);
+  });
+
+  test("counts errors by status code and orders by call count", async () => {
+    const { captured } = setup([]);
+
+    const { getEndpointStatsFromDB } = await import("@/repository/statistics");
+    await getEndpointStatsFromDB("7days", "Asia/Shanghai");
+
+    const errorSql = sqlToString(captured.selection?.errorCount).replaceAll(/\s+/g, " ");
+    expect(errorSql).toContain("status_code >= 400");
+
+    const whereSql = sqlToString(captured.where);
+    expect(whereSql).toContain("Asia/Shanghai");
+    expect(whereSql).toContain("INTERVAL '6 days'");
+    expect(whereSql).toContain("blocked_by");
+    // 非计费端点（count_tokens / compact）同样属于真实流量，不能被排除
+    expect(whereSql).not.toContain("count_tokens");
+
+    expect(sqlToString(captured.orderBy?.[0])).toContain("count(*)");
+  });
+});
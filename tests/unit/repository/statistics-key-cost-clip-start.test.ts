@@ -0,0 +1,97 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function collectDates(node: unknown, seen = new Set<unknown>()): Date[] {
+  if (node instanceof Date) return [node];
+  if (!node || typeof node !== "object" || seen.has(node)) return [];
+  seen.add(node);
+  const values = Array.isArray(node) ? node : Object.values(node as Record<string, unknown>);
+  return values.flatMap((value) => collectDates(value, seen));
+}
+
+function mockDb(keyString: string | null, total: number) {
+  const whereArgs: unknown[] = [];
+  const selectMock = vi.fn((selection: Record<string, unknown>) => {
+    const isKeyLookup = "key" in selection && !("total" in selection);
+    const result = isKeyLookup ? (keyString ? [{ key: keyString }] : []) : [{ total }];
+    const query: any = Promise.resolve(result);
+    query.from = vi.fn(() => query);
+    query.limit = vi.fn(() => query);
+    query.where = vi.fn((arg: unknown) => {
+      if (!isKeyLookup) whereArgs.push(arg);
+      return query;
+    });
+    return query;
+  });
+
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { selectMock, whereArgs };
+}
+
+describe("sumKeyCostInTimeRange clipStart", () => {
+  const startTime = new Date("2026-03-01T00:00:00.000Z");
+  const endTime = new Date("2026-03-02T00:00:00.000Z");
+
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("without clipStart -- keeps startTime as the lower bound", async () => {
+    const { whereArgs } = mockDb("sk-test", 3.5);
+
+    const { sumKeyCostInTimeRange } = await import("@/repository/statistics");
+    const result = await sumKeyCostInTimeRange(1, startTime, endTime);
+
+    expect(result).toBe(3.5);
+    const dates = collectDates(whereArgs[0]).map((d) => d.toISOString());
+    expect(dates).toContain(startTime.toISOString());
+    expect(dates).toContain(endTime.toISOString());
+  });
+
+  test("with null clipStart -- behaves the same as undefined", async () => {
+    const { whereArgs } = mockDb("sk-test", 1);
+
+    const { sumKeyCostInTimeRange } = await import("@/repository/statistics");
+    await sumKeyCostInTimeRange(1, startTime, endTime, null);
+
+    const dates = collectDates(whereArgs[0]).map((d) => d.toISOString());
+    expect(dates).toContain(startTime.toISOString());
+  });
+
+  test("with clipStart inside the window -- uses clipStart as the lower bound", async () => {
+    const clipStart = new Date("2026-03-01T12:00:00.000Z");
+    const { whereArgs } = mockDb("sk-test", 2);
+
+    const { sumKeyCostInTimeRange } = await import("@/repository/statistics");
+    const result = await sumKeyCostInTimeRange(1, startTime, endTime, clipStart);
+
+    expect(result).toBe(2);
+    const dates = collectDates(whereArgs[0]).map((d) => d.toISOString());
+    expect(dates).toContain(clipStart.toISOString());
+    expect(dates).not.toContain(startTime.toISOString());
+  });
+
+  test("with clipStart before startTime -- keeps startTime", async () => {
+    const { whereArgs } = mockDb("sk-test", 2);
+
+    const { sumKeyCostInTimeRange } = await import("@/repository/statistics");
+    await sumKeyCostInTimeRange(1, startTime, endTime, new Date("2026-02-01T00:00:00.000Z"));
+
+    const dates = collectDates(whereArgs[0]).map((d) => d.toISOString());
+    expect(dates).toContain(startTime.toISOString());
+  });
+
+  test("with clipStart after endTime -- returns 0 without querying", async () => {
+    const { selectMock } = mockDb("sk-test", 99);
+
+    const { sumKeyCostInTimeRange } = await import("@/repository/statistics");
+    const result = await sumKeyCostInTimeRange(
+      1,
+      startTime,
+      endTime,
+      new Date("2026-03-05T00:00:00.000Z")
+    );
+
+    expect(result).toBe(0);
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+});
@@ -0,0 +1,76 @@
+import { beforeEach, describe, expect, it, vi } from "vitest";
+
+function createSelectChain<T>(result: T, whereArgs: unknown[]) {
+  const query: any = Promise.resolve(result);
+  query.from = vi.fn(() => query);
+  query.groupBy = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return query;
+  });
+  return query;
+}
+
+describe("sumKeyCostInTimeRangeByIds", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  it("returns an empty map without querying when keyIds is empty", async () => {
+    const selectMock = vi.fn();
+    vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+
+    const { sumKeyCostInTimeRangeByIds } = await import("@/repository/statistics");
+    const result = await sumKeyCostInTimeRangeByIds([], new Date(0), new Date());
+
+    expect(result.size).toBe(0);
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+
+  it("resolves key strings once, aggregates by key and maps back to ids", async () => {
+    const whereArgs: unknown[] = [];
+    const queue = [
+      createSelectChain(
+        [
+          { id: 1, key: "sk-one" },
+          { id: 2, key: "sk-two" },
+          { id: 3, key: "sk-three" },
+        ],
+        whereArgs
+      ),
+      createSelectChain(
+        [
+          { key: "sk-one", total: "1.25" },
+          { key: "sk-three", total: 0.5 },
+        ],
+        whereArgs
+      ),
+    ];
+    const selectMock = vi.fn(() => queue.shift());
+    vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+
+    const { sumKeyCostInTimeRangeByIds } = await import("@/repository/statistics");
+    const result = await sumKeyCostInTimeRangeByIds(
+      [1, 2, 3, 4],
+      new Date("2026-01-01T00:00:00.000Z"),
+      new Date("2026-01-02T00:00:00.000Z")
+    );
+
+    expect(selectMock).toHaveBeenCalledTimes(2);
+    expect(whereArgs).toHaveLength(2);
+    expect(Object.fromEntries(result)).toEqual({ 1: 1.25, 2: 0, 3: 0.5, 4: 0 });
+  });
+
+  it("skips the aggregate query when no active keys are found", async () => {
+    const whereArgs: unknown[] = [];
+    const queue = [createSelectChain([], whereArgs)];
+    const selectMock = vi.fn(() => queue.shift());
+    vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+
+    const { sumKeyCostInTimeRangeByIds } = await import("@/repository/statistics");
+    const result = await sumKeyCostInTimeRangeByIds([7], new Date(0), new Date());
+
+    expect(selectMock).toHaveBeenCalledTimes(1);
+    expect(Object.fromEntries(result)).toEqual({ 7: 0 });
+  });
+});
@@ -0,0 +1,121 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const visited = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (!node || visited.has(node)) return "";
+    visited.add(node);
+
+    if (typeof node === "string") return node;
+
+    if (typeof node === "object") {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.value) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (anyNode.name && anyNode.table) {
+        return String(anyNode.name);
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+    }
+
+    return "";
+  };
+
+  return walk(sqlObj);
+}
+
+function setup(rows: unknown[], billingModelSource: "original" | "redirected" = "redirected") {
+  const captured: { selection?: Record<string, unknown>; where?: unknown } = {};
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.groupBy = vi.fn(() => query);
+  query.orderBy = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    captured.where = arg;
+    return query;
+  });
+
+  const getSystemSettingsMock = vi.fn(async () => ({ billingModelSource }));
+
+  vi.doMock("@/drizzle/db", () => ({
+    db: {
+      select: vi.fn((selection: Record<string, unknown>) => {
+        captured.selection = selection;
+        return query;
+      }),
+    },
+  }));
+  vi.doMock("@/repository/system-config", () => ({
+    getSystemSettings: getSystemSettingsMock,
+  }));
+
+  return { captured, getSystemSettingsMock };
+}
+
+describe("getModelCostBreakdownFromDB", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("normalizes aggregated rows into numbers", async () => {
+    setup([
+      { model: "claude-opus-4", apiCalls: 3, totalCost: "4.5", totalTokens: "1200" },
+      { model: "claude-haiku-4", apiCalls: "10", totalCost: "0.25", totalTokens: 900 },
+    ]);
+
+    const { getModelCostBreakdownFromDB } = await import("@/repository/statistics");
+    const result = await getModelCostBreakdownFromDB("today", "UTC", false);
+
+    expect(result).toEqual([
+      { model: "claude-opus-4", apiCalls: 3, totalCost: 4.5, totalTokens: 1200 },
+      { model: "claude-haiku-4", apiCalls: 10, totalCost: 0.25, totalTokens: 900 },
+    ]);
+  });
+
+  test("uses the timezone-aware window and billing filter", async () => {
+    const { captured } = setup([]);
+
+    const { getModelCostBreakdownFromDB } = await import("@/repository/statistics");
+    await getModelCostBreakdownFromDB("7days", "Asia/Shanghai", false);
+
+    const whereSql = sqlToString(captured.where);
+    expect(whereSql).toContain("Asia/Shanghai");
+    expect(whereSql).toContain("INTERVAL '6 days'");
+    expect(whereSql).toContain("IS NULL");
+  });
+
+  test("explicit useOriginalModel prefers original_model without reading settings", async () => {
+    const { captured, getSystemSettingsMock } = setup([]);
+
+    const { getModelCostBreakdownFromDB } = await import("@/repository/statistics");
+    await getModelCostBreakdownFromDB("today", "UTC", true);
+
+    expect(getSystemSettingsMock).not.toHaveBeenCalled();
+    const modelSql = sqlToString(captured.selection?.model).replaceAll(/\s+/g, "");
+    expect(modelSql).toContain("COALESCE(original_model,model)");
+  });
+
+  test("falls back to billingModelSource when useOriginalModel is omitted", async () => {
+    const { captured, getSystemSettingsMock } = setup([], "redirected");
+
+    const { getModelCostBreakdownFromDB } = await import("@/repository/statistics");
+    await getModelCostBreakdownFromDB("today", "UTC");
+
+    expect(getSystemSettingsMock).toHaveBeenCalledTimes(1);
+    const modelSql = sqlToString(captured.selection?.model).replaceAll(/\s+/g, "");
+    expect(modelSql).toContain("COALESCE(model,original_model)");
+  });
+});
@@ -0,0 +1,102 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const visited = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (!node || visited.has(node)) return "";
+    visited.add(node);
+
+    if (typeof node === "string") return node;
+
+    if (typeof node === "object") {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.value) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (anyNode.name && anyNode.table) {
+        return String(anyNode.name);
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+    }
+
+    return "";
+  };
+
+  return walk(sqlObj);
+}
+
+type Captured = { selection?: Record<string, unknown>; where?: unknown; orderBy?: unknown[] };
+
+function setup(rows: unknown[]) {
+  const captured: Captured = {};
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.groupBy = vi.fn(() => query);
+  query.orderBy = vi.fn((...args: unknown[]) => {
+    captured.orderBy = args;
+    return query;
+  });
+  query.where = vi.fn((arg: unknown) => {
+    captured.where = arg;
+    return query;
+  });
+
+  vi.doMock("@/drizzle/db", () => ({
+    db: {
+      select: vi.fn((selection: Record<string, unknown>) => {
+        captured.selection = selection;
+        return query;
+      }),
+    },
+  }));
+
+  return { captured };
+}
+
+describe("getModelTtfbStatsFromDB", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("rounds percentiles and keeps null percentiles as null", async () => {
+    setup([
+      { model: "claude-sonnet-4-5", sampleCount: "20", p50TtfbMs: "812.4", p95TtfbMs: 2310.6 },
+      { model: "gpt-4o", sampleCount: 1, p50TtfbMs: null, p95TtfbMs: "not-a-number" },
+    ]);
+
+    const { getModelTtfbStatsFromDB } = await import("@/repository/statistics");
+    const result = await getModelTtfbStatsFromDB("today", "UTC");
+
+    expect(result).toEqual([
+      { model: "claude-sonnet-4-5", sampleCount: 20, p50TtfbMs: 812, p95TtfbMs: 2311 },
+      { model: "gpt-4o", sampleCount: 1, p50TtfbMs: null, p95TtfbMs: null },
+    ]);
+  });
+
+  test("uses percentile_cont over non-null ttfb_ms in the requested window", async () => {
+    const { captured } = setup([]);
+
+    const { getModelTtfbStatsFromDB } = await import("@/repository/statistics");
+    await getModelTtfbStatsFromDB("30days", "Asia/Shanghai");
+
+    expect(sqlToString(captured.selection?.p50TtfbMs)).toContain("percentile_cont(0.5)");
+    expect(sqlToString(captured.selection?.p95TtfbMs)).toContain("percentile_cont(0.95)");
+
+    const whereSql = sqlToString(captured.where);
+    expect(whereSql).toContain("ttfb_ms IS NOT NULL");
+    expect(whereSql).toContain("INTERVAL '29 days'");
+    expect(whereSql).toContain("Asia/Shanghai");
+  });
+});
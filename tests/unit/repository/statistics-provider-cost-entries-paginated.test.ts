@@ -0,0 +1,109 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function mockDb(rows: unknown[] = []) {
+  const whereArgs: unknown[] = [];
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.orderBy = vi.fn(() => query);
+  query.limit = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return query;
+  });
+  const selectMock = vi.fn(() => query);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { whereArgs, query };
+}
+
+function costRow(id: number, costUsd: string) {
+  return { id, createdAt: new Date("2026-01-01T00:00:00.000Z"), costUsd };
+}
+
+const start = new Date("2026-01-01T00:00:00.000Z");
+const end = new Date("2026-02-01T00:00:00.000Z");
+
+describe("findProviderCostEntriesPaginated", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("returns the page and the last id as next cursor when more rows exist", async () => {
+    const { whereArgs, query } = mockDb([costRow(1, "0.5"), costRow(2, "1.25"), costRow(3, "2")]);
+
+    const { findProviderCostEntriesPaginated } = await import("@/repository/statistics");
+    const page = await findProviderCostEntriesPaginated(7, start, end, null, 2);
+
+    expect(page.entries).toEqual([
+      { id: 1, createdAt: start, costUsd: 0.5 },
+      { id: 2, createdAt: start, costUsd: 1.25 },
+    ]);
+    expect(page.nextCursor).toBe(2);
+    expect(query.limit).toHaveBeenCalledWith(3);
+
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("provider_id");
+    expect(whereSql).toContain("cost_usd > 0");
+    expect(whereSql).toContain("deleted_at is null");
+    expect(whereSql).toContain("warmup");
+  });
+
+  test("filters by id cursor and returns null cursor on the last page", async () => {
+    const { whereArgs } = mockDb([costRow(11, "0.1")]);
+
+    const { findProviderCostEntriesPaginated } = await import("@/repository/statistics");
+    const page = await findProviderCostEntriesPaginated(7, start, end, 10, 2);
+
+    expect(page.entries.map((entry) => entry.id)).toEqual([11]);
+    expect(page.nextCursor).toBeNull();
+    expect(sqlToString(whereArgs[0])).toMatch(/id > 10/);
+  });
+
+  test("clamps the page size", async () => {
+    const { query } = mockDb();
+
+    const { findProviderCostEntriesPaginated } = await import("@/repository/statistics");
+    await findProviderCostEntriesPaginated(7, start, end, null, 100000);
+
+    expect(query.limit).toHaveBeenCalledWith(5001);
+  });
+});
@@ -0,0 +1,99 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const visited = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (!node || visited.has(node)) return "";
+    visited.add(node);
+
+    if (typeof node === "string") return node;
+
+    if (typeof node === "object") {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.value) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (anyNode.name && anyNode.table) {
+        return String(anyNode.name);
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+    }
+
+    return "";
+  };
+
+  return walk(sqlObj);
+}
+
+function setup(rows: unknown[]) {
+  const captured: { where?: unknown; join?: unknown; limit?: number } = {};
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.innerJoin = vi.fn((_table: unknown, on: unknown) => {
+    captured.join = on;
+    return query;
+  });
+  query.groupBy = vi.fn(() => query);
+  query.orderBy = vi.fn(() => query);
+  query.limit = vi.fn((n: number) => {
+    captured.limit = n;
+    return query;
+  });
+  query.where = vi.fn((arg: unknown) => {
+    captured.where = arg;
+    return query;
+  });
+
+  const selectMock = vi.fn(() => query);
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+
+  return { captured, selectMock };
+}
+
+describe("getProviderCostLeaderboardFromDB", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("attributes cost to the final provider and limits to topN", async () => {
+    const { captured } = setup([
+      { providerId: 2, providerName: "primary", totalCost: "12.5", apiCalls: 40 },
+      { providerId: 7, providerName: "fallback", totalCost: "0.75", apiCalls: "3" },
+    ]);
+
+    const { getProviderCostLeaderboardFromDB } = await import("@/repository/statistics");
+    const result = await getProviderCostLeaderboardFromDB("7days", 2, "UTC");
+
+    expect(result).toEqual([
+      { providerId: 2, providerName: "primary", totalCost: 12.5, apiCalls: 40 },
+      { providerId: 7, providerName: "fallback", totalCost: 0.75, apiCalls: 3 },
+    ]);
+    expect(captured.limit).toBe(2);
+    expect(sqlToString(captured.join)).toContain("final_provider_id");
+
+    const whereSql = sqlToString(captured.where);
+    expect(whereSql).toContain("INTERVAL '6 days'");
+    expect(whereSql).toContain("IS NULL");
+  });
+
+  test("returns [] without querying for non-positive topN", async () => {
+    const { selectMock } = setup([]);
+
+    const { getProviderCostLeaderboardFromDB } = await import("@/repository/statistics");
+
+    expect(await getProviderCostLeaderboardFromDB("today", 0, "UTC")).toEqual([]);
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+});
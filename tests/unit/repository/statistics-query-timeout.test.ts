@@ -0,0 +1,214 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const visited = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (!node || visited.has(node)) return "";
+    visited.add(node);
+
+    if (typeof node === "string") return node;
+
+    if (typeof node === "object") {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.value) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+    }
+
+    return "";
+  };
+
+  return walk(sqlObj);
+}
+
+function setup(timeoutMs: number, queryResult: () => Promise<unknown>) {
+  const txExecute = vi.fn(async (query: unknown) => {
+    if (sqlToString(query).includes("statement_timeout")) return [];
+    return queryResult();
+  });
+  const transactionMock = vi.fn(async (run: (tx: unknown) => Promise<unknown>) =>
+    run({ execute: txExecute })
+  );
+  const executeMock = vi.fn(async () => [{ id: 1 }]);
+
+  vi.doMock("@/drizzle/db", () => ({
+    db: { execute: executeMock, transaction: transactionMock },
+  }));
+  vi.doMock("@/lib/config/env.schema", () => ({
+    getEnvConfig: () => ({ STATISTICS_QUERY_TIMEOUT: timeoutMs }),
+  }));
+
+  return { txExecute, transactionMock, executeMock };
+}
+
+describe("executeStatisticsQuery", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("sets a transaction-local statement_timeout before running the query", async () => {
+    const { txExecute, executeMock } = setup(10_000, async () => [{ id: 1 }]);
+
+    const { sql } = await import("drizzle-orm");
+    const { executeStatisticsQuery } = await import(
+      "@/repository/_shared/statistics-query-timeout"
+    );
+    const result = await executeStatisticsQuery(sql`SELECT 1`);
+
+    expect(result).toEqual([{ id: 1 }]);
+    expect(executeMock).not.toHaveBeenCalled();
+    expect(txExecute).toHaveBeenCalledTimes(2);
+
+    const timeoutSql = sqlToString(txExecute.mock.calls[0]?.[0]);
+    expect(timeoutSql).toContain("set_config('statement_timeout'");
+    expect(timeoutSql).toContain("10000");
+    expect(timeoutSql).toContain("true");
+  });
+
+  test("maps query_canceled to StatisticsQueryTimeoutError with TIMEOUT code", async () => {
+    setup(5_000, async () => {
+      throw Object.assign(new Error("canceling statement due to statement timeout"), {
+        code: "57014",
+      });
+    });
+
+    const { sql } = await import("drizzle-orm");
+    const { executeStatisticsQuery, StatisticsQueryTimeoutError } = await import(
+      "@/repository/_shared/statistics-query-timeout"
+    );
+
+    const error = await executeStatisticsQuery(sql`SELECT 1`).catch((e: unknown) => e);
+    expect(error).toBeInstanceOf(StatisticsQueryTimeoutError);
+    expect(error).toMatchObject({ code: "TIMEOUT", timeoutMs: 5_000 });
+  });
+
+  test("rethrows other database errors unchanged", async () => {
+    const dbError = Object.assign(new Error("relation does not exist"), { code: "42P01" });
+    setup(5_000, async () => {
+      throw dbError;
+    });
+
+    const { sql } = await import("drizzle-orm");
+    const { executeStatisticsQuery } = await import(
+      "@/repository/_shared/statistics-query-timeout"
+    );
+
+    await expect(executeStatisticsQuery(sql`SELECT 1`)).rejects.toBe(dbError);
+  });
+
+  test("skips the transaction when the timeout is disabled", async () => {
+    const { transactionMock, executeMock } = setup(0, async () => []);
+
+    const { sql } = await import("drizzle-orm");
+    const { executeStatisticsQuery } = await import(
+      "@/repository/_shared/statistics-query-timeout"
+    );
+    await executeStatisticsQuery(sql`SELECT 1`);
+
+    expect(transactionMock).not.toHaveBeenCalled();
+    expect(executeMock).toHaveBeenCalledTimes(1);
+  });
+});
+
+describe("runStatisticsQueryGroup", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("resolves results in task order", async () => {
+    setup(0, async () => []);
+    const { runStatisticsQueryGroup } = await import(
+      "@/repository/_shared/statistics-query-timeout"
+    );
+
+    const result = await runStatisticsQueryGroup([async () => 1, async () => "two"] as const);
+
+    expect(result).toEqual([1, "two"]);
+  });
+
+  test("rejects with AbortError promptly when the caller signal is cancelled", async () => {
+    setup(0, async () => []);
+    const { runStatisticsQueryGroup } = await import(
+      "@/repository/_shared/statistics-query-timeout"
+    );
+
+    const controller = new AbortController();
+    let taskSignal: AbortSignal | undefined;
+    const pending = runStatisticsQueryGroup(
+      [
+        (signal) => {
+          taskSignal = signal;
+          return new Promise<never>(() => {});
+        },
+      ] as const,
+      controller.signal
+    );
+
+    await Promise.resolve();
+    controller.abort();
+
+    await expect(pending).rejects.toMatchObject({ name: "AbortError" });
+    expect(taskSignal?.aborted).toBe(true);
+  });
+
+  test("rejects immediately when the signal is already aborted", async () => {
+    setup(0, async () => []);
+    const { runStatisticsQueryGroup } = await import(
+      "@/repository/_shared/statistics-query-timeout"
+    );
+    const task = vi.fn(async () => 1);
+
+    await expect(
+      runStatisticsQueryGroup([task] as const, AbortSignal.abort())
+    ).rejects.toMatchObject({ name: "AbortError" });
+    expect(task).not.toHaveBeenCalled();
+  });
+
+  test("cancels sibling tasks when one task fails", async () => {
+    setup(0, async () => []);
+    const { runStatisticsQueryGroup } = await import(
+      "@/repository/_shared/statistics-query-timeout"
+    );
+
+    const failure = new Error("query failed");
+    let siblingSignal: AbortSignal | undefined;
+    const pending = runStatisticsQueryGroup([
+      async () => {
+        throw failure;
+      },
+      (signal) => {
+        siblingSignal = signal;
+        return new Promise<never>(() => {});
+      },
+    ] as const);
+
+    await expect(pending).rejects.toBe(failure);
+    expect(siblingSignal?.aborted).toBe(true);
+  });
+
+  test("executeStatisticsQuery does not run once the signal is aborted", async () => {
+    const { transactionMock, executeMock } = setup(10_000, async () => []);
+    const { sql } = await import("drizzle-orm");
+    const { executeStatisticsQuery } = await import(
+      "@/repository/_shared/statistics-query-timeout"
+    );
+
+    await expect(
+      executeStatisticsQuery(sql`SELECT 1`, AbortSignal.abort())
+    ).rejects.toMatchObject({ name: "AbortError" });
+    expect(transactionMock).not.toHaveBeenCalled();
+    expect(executeMock).not.toHaveBeenCalled();
+  });
+});
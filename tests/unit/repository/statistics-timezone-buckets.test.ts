@@ -7,6 +7,13 @@ vi.mock("@/drizzle/db", () => ({
   },
 }));
 
+vi.mock("@/repository/_shared/statistics-query-timeout", async () => {
+  const { db: mockedDb } = await import("@/drizzle/db");
+  return {
+    executeStatisticsQuery: (query: unknown) => mockedDb.execute(query as never),
+  };
+});
+
 describe("statistics timezone buckets", () => {
   beforeEach(() => {
     vi.clearAllMocks();
@@ -0,0 +1,66 @@
+import { beforeEach, describe, expect, it, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const visited = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (!node || visited.has(node)) return "";
+    visited.add(node);
+
+    if (typeof node === "string") return node;
+
+    if (typeof node === "object") {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.value) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+    }
+
+    return "";
+  };
+
+  return walk(sqlObj);
+}
+
+describe("getUserTotalsFromDB", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  it("returns one row per user without expanding time buckets", async () => {
+    const executeMock = vi.fn(async () => [
+      { user_id: 1, user_name: "alice", api_calls: "3", total_cost: "1.25" },
+      { user_id: 2, user_name: "bob", api_calls: "0", total_cost: null },
+    ]);
+
+    vi.doMock("@/drizzle/db", () => ({ db: { execute: executeMock } }));
+    vi.doMock("@/repository/_shared/statistics-query-timeout", () => ({
+      executeStatisticsQuery: executeMock,
+    }));
+
+    const { getUserTotalsFromDB } = await import("@/repository/statistics");
+    const rows = await getUserTotalsFromDB("7days", "Asia/Shanghai");
+
+    expect(rows).toEqual([
+      { user_id: 1, user_name: "alice", api_calls: 3, total_cost: "1.25" },
+      { user_id: 2, user_name: "bob", api_calls: 0, total_cost: 0 },
+    ]);
+
+    expect(executeMock).toHaveBeenCalledTimes(1);
+    const queryText = sqlToString(executeMock.mock.calls[0][0]);
+    expect(queryText).toContain("LEFT JOIN usage_ledger");
+    expect(queryText).toContain("GROUP BY u.id, u.name");
+    expect(queryText).not.toContain("generate_series");
+  });
+});
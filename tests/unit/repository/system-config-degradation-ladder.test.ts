@@ -7,6 +7,8 @@ import type { UpdateSystemSettingsInput } from "@/types/system-config";
 
 // 近代新增列（最新在前），降级链按引入顺序逐层累计剥离。
 const RECENT_COLUMNS = [
+  "currencyDisplayRate",
+  "minClientVersion",
   "enableGeminiFunctionIdRectifier",
   "enableThinkingEffortConflictRectifier",
   "billHedgeLosers",
@@ -16,8 +18,10 @@ const RECENT_COLUMNS = [
   "allowNonConversationEndpointProviderFallback",
 ] as const;
 
-// 全量字段集（44 列）。
+// 全量字段集（46 列）。
 const FULL_COLUMNS = [
+  "currencyDisplayRate",
+  "minClientVersion",
   "enableGeminiFunctionIdRectifier",
   "billHedgeLosers",
   "billNonSuccessfulRequests",
@@ -124,7 +128,7 @@ function createResolvingSelectQuery(rows: unknown[]) {
 }
 
 describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
-  test("getSystemSettings 全部列缺失时按既定顺序尝试 12 套字段集", async () => {
+  test("getSystemSettings 全部列缺失时按既定顺序尝试 14 套字段集", async () => {
     vi.resetModules();
 
     const selections: string[][] = [];
@@ -167,7 +171,7 @@ describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
     const selectMock = vi.fn((selection: Record<string, unknown>) => {
       selections.push(sortedKeys(selection));
       callIndex += 1;
-      if (callIndex < 9) {
+      if (callIndex < 11) {
         return createRejectingSelectQuery({ code: "42703" });
       }
       return createResolvingSelectQuery([
@@ -200,14 +204,14 @@ describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
 
     const result = await getSystemSettings();
 
-    expect(selectMock).toHaveBeenCalledTimes(9);
-    // 第 8 次（近代链末层）不含这两列；第 9 次（passThrough 世代）重新包含。
-    expect(selections[7]).not.toContain("enableThinkingEffortConflictRectifier");
-    expect(selections[7]).not.toContain("allowNonConversationEndpointProviderFallback");
-    expect(selections[7]).toContain("passThroughUpstreamErrorMessage");
-    expect(selections[8]).toContain("enableThinkingEffortConflictRectifier");
-    expect(selections[8]).toContain("allowNonConversationEndpointProviderFallback");
-    expect(selections[8]).not.toContain("passThroughUpstreamErrorMessage");
+    expect(selectMock).toHaveBeenCalledTimes(11);
+    // 第 10 次（近代链末层）不含这两列；第 11 次（passThrough 世代）重新包含。
+    expect(selections[9]).not.toContain("enableThinkingEffortConflictRectifier");
+    expect(selections[9]).not.toContain("allowNonConversationEndpointProviderFallback");
+    expect(selections[9]).toContain("passThroughUpstreamErrorMessage");
+    expect(selections[10]).toContain("enableThinkingEffortConflictRectifier");
+    expect(selections[10]).toContain("allowNonConversationEndpointProviderFallback");
+    expect(selections[10]).not.toContain("passThroughUpstreamErrorMessage");
 
     // 世代字段集选出的真实值要透传，缺失列由 transformer 落默认值。
     expect(result.siteTitle).toBe("Era Row");
@@ -218,7 +222,7 @@ describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
     expect(result.passThroughUpstreamErrorMessage).toBe(true);
   });
 
-  test("updateSystemSettings 全部列缺失时按既定顺序尝试 11 套 set/returning 组合", async () => {
+  test("updateSystemSettings 全部列缺失时按既定顺序尝试 13 套 set/returning 组合", async () => {
     vi.resetModules();
 
     const now = new Date("2026-01-04T00:00:00.000Z");
@@ -265,6 +269,7 @@ describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
 
     const payload: UpdateSystemSettingsInput = {
       siteTitle: "Ladder Pin",
+      currencyDisplayRate: 7.2,
       codexPriorityBillingSource: "actual",
       billNonSuccessfulRequests: true,
       billHedgeLosers: false,
@@ -273,6 +278,7 @@ describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
       enableHighConcurrencyMode: true,
       enableThinkingEffortConflictRectifier: false,
       enableGeminiFunctionIdRectifier: false,
+      minClientVersion: "2.0.0",
       allowNonConversationEndpointProviderFallback: false,
       fakeStreamingWhitelist: [],
       publicStatusWindowHours: 48,
@@ -285,7 +291,7 @@ describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
       "system_settings 表列缺失，请执行数据库迁移以升级数据库结构。"
     );
 
-    expect(updateMock).toHaveBeenCalledTimes(11);
+    expect(updateMock).toHaveBeenCalledTimes(13);
 
     const expectedReturningSequence = [
       [...FULL_COLUMNS],
@@ -299,6 +305,7 @@ describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
     const fullSetKeys = [
       "updatedAt",
       "siteTitle",
+      "currencyDisplayRate",
       "codexPriorityBillingSource",
       "billNonSuccessfulRequests",
       "billHedgeLosers",
@@ -307,6 +314,7 @@ describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
       "enableHighConcurrencyMode",
       "enableThinkingEffortConflictRectifier",
       "enableGeminiFunctionIdRectifier",
+      "minClientVersion",
       "allowNonConversationEndpointProviderFallback",
       "fakeStreamingWhitelist",
       "publicStatusWindowHours",
@@ -354,7 +362,7 @@ describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
     let updateCallIndex = 0;
     const updateMock = vi.fn(() => {
       updateCallIndex += 1;
-      const shouldResolve = updateCallIndex === 10;
+      const shouldResolve = updateCallIndex === 12;
       const query: Record<string, unknown> = {};
       query.set = vi.fn(() => query);
       query.where = vi.fn(() => query);
@@ -393,7 +401,7 @@ describe("SystemSettings：列降级阶梯的尝试序列锁定", () => {
       codexPriorityBillingSource: "actual",
     });
 
-    expect(updateMock).toHaveBeenCalledTimes(10);
+    expect(updateMock).toHaveBeenCalledTimes(12);
     expect(result.siteTitle).toBe("Tail Success");
     expect(result.codexPriorityBillingSource).toBe("actual");
   });
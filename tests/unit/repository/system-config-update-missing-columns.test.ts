@@ -291,7 +291,7 @@ describe("SystemSettings：数据库缺列时的保存兜底", () => {
     vi.useRealTimers();
   });
 
-  test("getSystemSettings 在仅缺 enable_gemini_function_id_rectifier 新列时应降级读取并默认开启", async () => {
+  test("getSystemSettings 在仅缺 currency_display_rate 新列时应降级读取并回落为 1", async () => {
     vi.resetModules();
 
     const now = new Date("2026-01-04T00:00:00.000Z");
@@ -299,7 +299,7 @@ describe("SystemSettings：数据库缺列时的保存兜底", () => {
     vi.setSystemTime(now);
 
     // 第一次 select(fullSelection) 因新列缺失而抛 42703；
-    // 第二次 select(selectionWithoutGeminiFunctionId) 命中——验证新列已加入降级链最外层。
+    // 第二次 select(selectionWithoutCurrencyDisplayRate) 命中——验证新列已加入降级链最外层。
     const selectMock = vi
       .fn()
       .mockReturnValueOnce(createRejectedThenableQuery({ code: "42703" }))
@@ -338,12 +338,13 @@ describe("SystemSettings：数据库缺列时的保存兜底", () => {
     expect(selectMock).toHaveBeenCalledTimes(2);
     expect(result.siteTitle).toBe("Claude Code Hub");
     expect(result.enableHttp2).toBe(true);
+    expect(result.currencyDisplayRate).toBe("1");
 
     // 关键回归保护：第二次 select 必须恰好剥离了新列（最外层降级），
-    // 而非旧行为先剥离 enableThinkingEffortConflictRectifier。若新列未加入降级链最外层，下面两条断言会失败。
+    // 而非旧行为先剥离 minClientVersion。若新列未加入降级链最外层，下面两条断言会失败。
     const secondSelection = selectMock.mock.calls[1]?.[0] as Record<string, unknown>;
-    expect(secondSelection).not.toHaveProperty("enableGeminiFunctionIdRectifier");
-    expect(secondSelection).toHaveProperty("enableThinkingEffortConflictRectifier");
+    expect(secondSelection).not.toHaveProperty("currencyDisplayRate");
+    expect(secondSelection).toHaveProperty("minClientVersion");
 
     vi.useRealTimers();
   });
@@ -401,6 +402,7 @@ describe("SystemSettings：数据库缺列时的保存兜底", () => {
     expect(insertMock).toHaveBeenCalledTimes(2);
     expect(legacyInsertQuery.values).toHaveBeenCalledWith(
       expect.objectContaining({
+        id: 1,
         siteTitle: "Claude Code Hub",
         allowGlobalUsageView: false,
         currencyDisplay: "USD",
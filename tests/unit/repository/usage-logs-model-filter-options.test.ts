@@ -1,17 +1,64 @@
-import { describe, expect, test, vi } from "vitest";
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+const whereArgs: unknown[] = [];
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
 
 function createThenableQuery<T>(result: T) {
   const query: any = Promise.resolve(result);
   query.from = vi.fn(() => query);
-  query.where = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return query;
+  });
   query.orderBy = vi.fn(() => query);
   return query;
 }
 
 describe("usage log model filter options", () => {
-  test("getUsedModels omits empty or blank model names", async () => {
+  beforeEach(() => {
     vi.resetModules();
+    whereArgs.length = 0;
+  });
 
+  test("getUsedModels omits empty or blank model names", async () => {
     const selectDistinctMock = vi.fn(() =>
       createThenableQuery([
         { model: "" },
@@ -30,4 +77,33 @@ describe("usage log model filter options", () => {
 
     await expect(getUsedModels()).resolves.toEqual(["claude-sonnet-4-5", "gpt-4o"]);
   });
+
+  test("getUsedModels limits the lookback window when maxAgeDays is given", async () => {
+    vi.doMock("@/drizzle/db", () => ({
+      db: { selectDistinct: vi.fn(() => createThenableQuery([{ model: "gpt-4o" }])) },
+    }));
+
+    const { getUsedModels } = await import("@/repository/usage-logs");
+
+    await expect(getUsedModels(7)).resolves.toEqual(["gpt-4o"]);
+    await getUsedModels();
+
+    expect(sqlToString(whereArgs[0])).toContain("created_at");
+    expect(sqlToString(whereArgs[1])).not.toContain("created_at");
+  });
+
+  test("getUsedApiTypes returns distinct non-blank api types", async () => {
+    const selectDistinctMock = vi.fn(() =>
+      createThenableQuery([{ apiType: "codex" }, { apiType: "" }, { apiType: "response" }])
+    );
+
+    vi.doMock("@/drizzle/db", () => ({
+      db: { selectDistinct: selectDistinctMock },
+    }));
+
+    const { getUsedApiTypes } = await import("@/repository/usage-logs");
+
+    await expect(getUsedApiTypes()).resolves.toEqual(["codex", "response"]);
+    expect(sqlToString(whereArgs[0])).toContain("api_type");
+  });
 });
@@ -0,0 +1,106 @@
+import { afterEach, beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined) return "";
+    if (typeof node === "string") return node;
+    if (node instanceof Date) return node.toISOString();
+    if (typeof node !== "object" || stack.has(node)) return "";
+    stack.add(node);
+    try {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) return anyNode.map(walk).join("");
+      if (anyNode.name && anyNode.table) return String(anyNode.name);
+      if (Object.hasOwn(anyNode, "value")) {
+        const { value } = anyNode;
+        if (Array.isArray(value)) return value.map(walk).join("");
+        return walk(value) || String(value ?? "");
+      }
+      if (anyNode.queryChunks) return walk(anyNode.queryChunks);
+    } finally {
+      stack.delete(node);
+    }
+    return "";
+  };
+  return walk(sqlObj);
+}
+
+function mockDb(rows: unknown[]) {
+  const whereArgs: unknown[] = [];
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.orderBy = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return query;
+  });
+  const selectMock = vi.fn(() => query);
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { selectMock, whereArgs, query };
+}
+
+describe("findUsersExpiringSoon", () => {
+  beforeEach(() => {
+    vi.resetModules();
+    vi.useFakeTimers();
+    vi.setSystemTime(new Date("2026-04-01T00:00:00.000Z"));
+  });
+
+  afterEach(() => {
+    vi.useRealTimers();
+  });
+
+  test("returns [] without querying for a non-positive window", async () => {
+    const { selectMock } = mockDb([]);
+
+    const { findUsersExpiringSoon } = await import("@/repository/user");
+
+    expect(await findUsersExpiringSoon(0)).toEqual([]);
+    expect(await findUsersExpiringSoon(-1000)).toEqual([]);
+    expect(await findUsersExpiringSoon(Number.NaN)).toEqual([]);
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+
+  test("bounds expires_at to [now, now + within] so null and already-expired users are excluded", async () => {
+    const { whereArgs, query } = mockDb([]);
+
+    const { findUsersExpiringSoon } = await import("@/repository/user");
+    await findUsersExpiringSoon(3 * 24 * 60 * 60 * 1000);
+
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("deleted_at is null");
+    expect(whereSql).toContain("is_enabled = true");
+    expect(whereSql).toContain("expires_at >= 2026-04-01T00:00:00.000Z");
+    expect(whereSql).toContain("expires_at <= 2026-04-04T00:00:00.000Z");
+    expect(whereSql).not.toContain("expires_at is null");
+    expect(query.orderBy).toHaveBeenCalledTimes(1);
+  });
+
+  test("maps rows through toUser", async () => {
+    mockDb([
+      {
+        id: 3,
+        name: "alice",
+        description: "",
+        role: "user",
+        rpm: null,
+        dailyQuota: null,
+        providerGroup: "default",
+        tags: [],
+        createdAt: new Date("2026-01-01T00:00:00.000Z"),
+        updatedAt: new Date("2026-01-01T00:00:00.000Z"),
+        deletedAt: null,
+        isEnabled: true,
+        expiresAt: new Date("2026-04-02T00:00:00.000Z"),
+      },
+    ]);
+
+    const { findUsersExpiringSoon } = await import("@/repository/user");
+    const result = await findUsersExpiringSoon(7 * 24 * 60 * 60 * 1000);
+
+    expect(result).toHaveLength(1);
+    expect(result[0]?.id).toBe(3);
+    expect(result[0]?.expiresAt).toEqual(new Date("2026-04-02T00:00:00.000Z"));
+  });
+});
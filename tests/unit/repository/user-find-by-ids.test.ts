@@ -0,0 +1,102 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined) return "";
+    if (typeof node === "string") return node;
+    if (typeof node === "number") return String(node);
+    if (typeof node !== "object" || stack.has(node)) return "";
+    stack.add(node);
+    try {
+      const anyNode = node as any;
+      if (Array.isArray(anyNode)) return anyNode.map(walk).join("");
+      if (anyNode.name && anyNode.table) return String(anyNode.name);
+      if (Object.hasOwn(anyNode, "value")) {
+        const { value } = anyNode;
+        if (Array.isArray(value)) return value.map(walk).join("");
+        return walk(value) || String(value ?? "");
+      }
+      if (anyNode.queryChunks) return walk(anyNode.queryChunks);
+    } finally {
+      stack.delete(node);
+    }
+    return "";
+  };
+  return walk(sqlObj);
+}
+
+function userRow(id: number, overrides: Record<string, unknown> = {}) {
+  return {
+    id,
+    name: `user-${id}`,
+    description: "",
+    role: "user",
+    rpm: null,
+    dailyQuota: null,
+    providerGroup: "default",
+    tags: [],
+    createdAt: new Date("2026-01-01T00:00:00.000Z"),
+    updatedAt: new Date("2026-01-01T00:00:00.000Z"),
+    deletedAt: null,
+    isEnabled: true,
+    expiresAt: null,
+    ...overrides,
+  };
+}
+
+function mockDb(rows: unknown[]) {
+  const whereArgs: unknown[] = [];
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.where = vi.fn((arg: unknown) => {
+    whereArgs.push(arg);
+    return query;
+  });
+  const selectMock = vi.fn(() => query);
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { selectMock, whereArgs };
+}
+
+describe("findUsersByIds", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("returns an empty map without querying for empty input", async () => {
+    const { selectMock } = mockDb([]);
+
+    const { findUsersByIds } = await import("@/repository/user");
+    const result = await findUsersByIds([]);
+
+    expect(result.size).toBe(0);
+    expect(selectMock).not.toHaveBeenCalled();
+  });
+
+  test("deduplicates ids and excludes deleted users by default", async () => {
+    const { selectMock, whereArgs } = mockDb([userRow(1), userRow(2)]);
+
+    const { findUsersByIds } = await import("@/repository/user");
+    const result = await findUsersByIds([2, 1, 2, -1]);
+
+    expect(selectMock).toHaveBeenCalledTimes(1);
+    expect(result.get(1)?.name).toBe("user-1");
+    expect(result.get(2)?.name).toBe("user-2");
+    expect(result.has(-1)).toBe(false);
+
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("deleted_at is null");
+    expect(whereSql).toContain("21-1");
+  });
+
+  test("includeDeleted resolves soft-deleted users for historical views", async () => {
+    const deletedAt = new Date("2026-03-01T00:00:00.000Z");
+    const { whereArgs } = mockDb([userRow(5, { deletedAt })]);
+
+    const { findUsersByIds } = await import("@/repository/user");
+    const result = await findUsersByIds([5], true);
+
+    expect(result.get(5)?.deletedAt).toEqual(deletedAt);
+    expect(sqlToString(whereArgs[0])).not.toContain("deleted_at is null");
+  });
+});
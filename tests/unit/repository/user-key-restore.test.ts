@@ -0,0 +1,145 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function mockDb(returningRows: unknown[]) {
+  const whereArgs: unknown[] = [];
+  const setArgs: unknown[] = [];
+  const returningMock = vi.fn(async () => returningRows);
+  const updateMock = vi.fn(() => ({
+    set: vi.fn((values: unknown) => {
+      setArgs.push(values);
+      return {
+        where: vi.fn((arg: unknown) => {
+          whereArgs.push(arg);
+          return { returning: returningMock };
+        }),
+      };
+    }),
+  }));
+
+  const subquery: any = {};
+  subquery.from = vi.fn(() => subquery);
+  subquery.where = vi.fn(() => subquery);
+  const selectMock = vi.fn(() => subquery);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { update: updateMock, select: selectMock } }));
+  return { whereArgs, setArgs, updateMock };
+}
+
+function mockCaches() {
+  const invalidateCachedUser = vi.fn(async () => {});
+  const noteExistingKey = vi.fn();
+  const publishCacheInvalidation = vi.fn(async () => {});
+
+  vi.doMock("@/lib/security/api-key-auth-cache", async (importOriginal) => ({
+    ...(await importOriginal<typeof import("@/lib/security/api-key-auth-cache")>()),
+    invalidateCachedUser,
+  }));
+  vi.doMock("@/lib/security/api-key-vacuum-filter", () => ({
+    apiKeyVacuumFilter: { noteExistingKey, isDefinitelyNotPresent: vi.fn(() => false) },
+  }));
+  vi.doMock("@/lib/redis/pubsub", async (importOriginal) => ({
+    ...(await importOriginal<typeof import("@/lib/redis/pubsub")>()),
+    publishCacheInvalidation,
+  }));
+
+  return { invalidateCachedUser, noteExistingKey, publishCacheInvalidation };
+}
+
+describe("restoreUser", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("clears deletedAt on a soft-deleted user and invalidates its cache", async () => {
+    const { whereArgs, setArgs } = mockDb([{ id: 5 }]);
+    const { invalidateCachedUser } = mockCaches();
+
+    const { restoreUser } = await import("@/repository/user");
+    await expect(restoreUser(5)).resolves.toBe(true);
+
+    expect(setArgs[0]).toMatchObject({ deletedAt: null, updatedAt: expect.any(Date) });
+    expect(sqlToString(whereArgs[0])).toContain("deleted_at is not null");
+    expect(invalidateCachedUser).toHaveBeenCalledWith(5);
+  });
+
+  test("returns false for a user that is not soft-deleted", async () => {
+    mockDb([]);
+    const { invalidateCachedUser } = mockCaches();
+
+    const { restoreUser } = await import("@/repository/user");
+    await expect(restoreUser(5)).resolves.toBe(false);
+
+    expect(invalidateCachedUser).not.toHaveBeenCalled();
+  });
+});
+
+describe("restoreKey", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("restores a soft-deleted key whose owner is active", async () => {
+    const { whereArgs, setArgs } = mockDb([{ id: 9, key: "sk-restored" }]);
+    const { noteExistingKey, publishCacheInvalidation } = mockCaches();
+
+    const { restoreKey } = await import("@/repository/key");
+    await expect(restoreKey(9)).resolves.toBe(true);
+
+    expect(setArgs[0]).toMatchObject({ deletedAt: null, updatedAt: expect.any(Date) });
+    const whereSql = sqlToString(whereArgs[0]);
+    expect(whereSql).toContain("deleted_at is not null");
+    expect(whereSql).toContain("exists");
+    expect(noteExistingKey).toHaveBeenCalledWith("sk-restored");
+    expect(publishCacheInvalidation).toHaveBeenCalled();
+  });
+
+  test("returns false when the key is not deleted or its owner is deleted", async () => {
+    mockDb([]);
+    const { noteExistingKey, publishCacheInvalidation } = mockCaches();
+
+    const { restoreKey } = await import("@/repository/key");
+    await expect(restoreKey(9)).resolves.toBe(false);
+
+    expect(noteExistingKey).not.toHaveBeenCalled();
+    expect(publishCacheInvalidation).not.toHaveBeenCalled();
+  });
+});
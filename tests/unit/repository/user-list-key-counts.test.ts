@@ -0,0 +1,114 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function userRow(id: number) {
+  return {
+    id,
+    name: `user-${id}`,
+    description: null,
+    role: "user",
+    isEnabled: true,
+    createdAt: new Date("2026-01-01T00:00:00.000Z"),
+    updatedAt: new Date("2026-01-01T00:00:00.000Z"),
+    deletedAt: null,
+  };
+}
+
+function mockDb(userRows: unknown[]) {
+  const selections: Record<string, unknown>[] = [];
+
+  const usersQuery: any = Promise.resolve(userRows);
+  usersQuery.from = vi.fn(() => usersQuery);
+  usersQuery.where = vi.fn(() => usersQuery);
+  usersQuery.orderBy = vi.fn(() => usersQuery);
+  usersQuery.limit = vi.fn(() => usersQuery);
+  usersQuery.offset = vi.fn(() => usersQuery);
+
+  const selectMock = vi.fn((selection: Record<string, unknown>) => {
+    selections.push(selection);
+    return usersQuery;
+  });
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+
+  return { selectMock, selections };
+}
+
+describe("findUserListBatchWithKeyCounts", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("counts active keys in the same query and keeps users without keys at 0", async () => {
+    const { selectMock, selections } = mockDb([
+      { ...userRow(1), activeKeyCount: 2 },
+      { ...userRow(2), activeKeyCount: 0 },
+      { ...userRow(3), activeKeyCount: "5" },
+    ]);
+
+    const { findUserListBatchWithKeyCounts } = await import("@/repository/user");
+    const page = await findUserListBatchWithKeyCounts({ limit: 10 });
+
+    expect(page.hasMore).toBe(false);
+    expect(page.nextCursor).toBeNull();
+    expect(page.users.map((user) => [user.id, user.activeKeyCount])).toEqual([
+      [1, 2],
+      [2, 0],
+      [3, 5],
+    ]);
+    expect(selectMock).toHaveBeenCalledTimes(1);
+
+    const countSql = sqlToString(selections[0].activeKeyCount);
+    expect(countSql).toContain("count(*)::int");
+    expect(countSql).toContain("user_id");
+    expect(countSql).toContain("is_enabled");
+    expect(countSql).toContain("IS NULL");
+  });
+
+  test("findUserListBatch skips the key count subquery", async () => {
+    const { selections } = mockDb([{ ...userRow(1), activeKeyCount: 0 }]);
+
+    const { findUserListBatch } = await import("@/repository/user");
+    const page = await findUserListBatch({});
+
+    expect(sqlToString(selections[0].activeKeyCount)).not.toContain("count(*)");
+    expect(page.users[0]).not.toHaveProperty("activeKeyCount");
+  });
+});
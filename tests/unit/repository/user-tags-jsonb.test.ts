@@ -0,0 +1,132 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function sqlToString(sqlObj: unknown): string {
+  const stack = new Set<unknown>();
+
+  const walk = (node: unknown): string => {
+    if (node === null || node === undefined || stack.has(node)) return "";
+    if (typeof node === "string" || typeof node === "number") return String(node);
+    if (typeof node !== "object") return "";
+
+    const anyNode = node as any;
+    stack.add(node);
+    try {
+      if (Array.isArray(anyNode)) {
+        return anyNode.map(walk).join("");
+      }
+
+      if (anyNode.queryChunks) {
+        return walk(anyNode.queryChunks);
+      }
+
+      if (anyNode.value !== undefined) {
+        if (Array.isArray(anyNode.value)) {
+          return anyNode.value.map(String).join("");
+        }
+        return String(anyNode.value);
+      }
+
+      if (typeof anyNode.name === "string" && anyNode.table) {
+        return anyNode.name;
+      }
+
+      return "";
+    } finally {
+      stack.delete(node);
+    }
+  };
+
+  return walk(sqlObj);
+}
+
+function mockDb(returningRows: unknown[] = [{ id: 1 }]) {
+  const setArgs: Array<Record<string, unknown>> = [];
+  const whereArgs: unknown[] = [];
+  const selectMock = vi.fn();
+  const updateMock = vi.fn(() => ({
+    set: vi.fn((values: Record<string, unknown>) => {
+      setArgs.push(values);
+      return {
+        where: vi.fn((arg: unknown) => {
+          whereArgs.push(arg);
+          return { returning: vi.fn(async () => returningRows) };
+        }),
+      };
+    }),
+  }));
+  const invalidateCachedUser = vi.fn(async () => {});
+
+  vi.doMock("@/drizzle/db", () => ({ db: { update: updateMock, select: selectMock } }));
+  vi.doMock("@/lib/security/api-key-auth-cache", () => ({
+    cacheUser: vi.fn(),
+    invalidateCachedUser,
+  }));
+
+  return { setArgs, whereArgs, selectMock, updateMock, invalidateCachedUser };
+}
+
+describe("user tag JSONB operations", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("addUserTag appends only when the tag is missing", async () => {
+    const { setArgs, whereArgs, invalidateCachedUser } = mockDb();
+
+    const { addUserTag } = await import("@/repository/user");
+    await expect(addUserTag(1, "  vip  ")).resolves.toBe(true);
+
+    const tagsSql = sqlToString(setArgs[0].tags);
+    expect(tagsSql).toContain("@>");
+    expect(tagsSql).toContain("||");
+    expect(tagsSql).toContain("jsonb_build_array(vip::text)");
+    expect(sqlToString(whereArgs[0])).toContain("deleted_at is null");
+    expect(invalidateCachedUser).toHaveBeenCalledWith(1);
+  });
+
+  test("addUserTag enforces the tag length and count limits", async () => {
+    const { updateMock, whereArgs } = mockDb();
+
+    const { addUserTag } = await import("@/repository/user");
+    await expect(addUserTag(1, "x".repeat(33))).resolves.toBe(false);
+    expect(updateMock).not.toHaveBeenCalled();
+
+    await expect(addUserTag(1, "x".repeat(32))).resolves.toBe(true);
+    expect(sqlToString(whereArgs[0])).toContain(
+      "jsonb_array_length(coalesce(tags, '[]'::jsonb)) < 20"
+    );
+  });
+
+  test("removeUserTag deletes with the JSONB minus operator", async () => {
+    const { setArgs } = mockDb();
+
+    const { removeUserTag } = await import("@/repository/user");
+    await expect(removeUserTag(1, "vip")).resolves.toBe(true);
+
+    expect(sqlToString(setArgs[0].tags)).toMatch(/'\[\]'::jsonb\) - vip::text/);
+  });
+
+  test("concurrent adds of different tags each issue an atomic update without reading tags", async () => {
+    const { setArgs, selectMock, updateMock } = mockDb();
+
+    const { addUserTag } = await import("@/repository/user");
+    await Promise.all([addUserTag(1, "alpha"), addUserTag(1, "beta")]);
+
+    expect(selectMock).not.toHaveBeenCalled();
+    expect(updateMock).toHaveBeenCalledTimes(2);
+    const tagSqls = setArgs.map((values) => sqlToString(values.tags));
+    expect(tagSqls.some((value) => value.includes("alpha") && !value.includes("beta"))).toBe(true);
+    expect(tagSqls.some((value) => value.includes("beta") && !value.includes("alpha"))).toBe(true);
+  });
+
+  test("returns false for blank tags or missing users", async () => {
+    const { updateMock, invalidateCachedUser } = mockDb([]);
+
+    const { addUserTag, removeUserTag } = await import("@/repository/user");
+    await expect(addUserTag(1, "   ")).resolves.toBe(false);
+    expect(updateMock).not.toHaveBeenCalled();
+
+    await expect(removeUserTag(404, "vip")).resolves.toBe(false);
+    expect(invalidateCachedUser).not.toHaveBeenCalled();
+  });
+});
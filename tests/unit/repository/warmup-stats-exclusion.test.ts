@@ -188,6 +188,10 @@ describe("Warmup 请求：不计入任何聚合统计", () => {
       },
     }));
 
+    vi.doMock("@/repository/_shared/statistics-query-timeout", () => ({
+      executeStatisticsQuery: executeMock,
+    }));
+
     const { getProviderStatistics } = await import("@/repository/provider");
     const result = await getProviderStatistics();
 
@@ -199,4 +203,61 @@ describe("Warmup 请求：不计入任何聚合统计", () => {
     expect(querySql.toLowerCase()).toContain("blocked_by");
     expect(querySql.toLowerCase()).toContain("is null");
   });
+
+  test.each([
+    { includeWarmup: undefined, expectWarmupBranch: false },
+    { includeWarmup: true, expectWarmupBranch: true },
+  ])("key statistics：includeWarmup=$includeWarmup 时按需补回 warmup 调用次数", async ({
+    includeWarmup,
+    expectWarmupBranch,
+  }) => {
+    vi.resetModules();
+
+    const executeMock = vi.fn(async () => []);
+    const readExecuteMock = vi.fn(async () => []);
+
+    vi.doMock("@/drizzle/db", () => ({
+      db: {
+        execute: vi.fn(async () => []),
+        select: () => ({
+          from: () => ({
+            where: async () => [],
+          }),
+        }),
+      },
+    }));
+    vi.doMock("@/drizzle/read-db", () => ({
+      readDb: { execute: readExecuteMock },
+    }));
+    vi.doMock("@/lib/utils/timezone", () => ({
+      resolveSystemTimezone: vi.fn(async () => "UTC"),
+    }));
+    vi.doMock("@/repository/_shared/statistics-query-timeout", () => ({
+      executeStatisticsQuery: executeMock,
+      runStatisticsQueryGroup: vi.fn(),
+    }));
+
+    const { getKeyStatisticsFromDB } = await import("@/repository/statistics");
+    await getKeyStatisticsFromDB(
+      1,
+      "today",
+      "UTC",
+      includeWarmup === undefined ? undefined : { includeWarmup }
+    );
+
+    expect(executeMock).toHaveBeenCalledTimes(1);
+    const querySql = sqlToString(executeMock.mock.calls[0]?.[0]).toLowerCase();
+
+    // 费用始终只来自计费 ledger 行
+    expect(querySql).toContain("is null");
+    if (expectWarmupBranch) {
+      expect(querySql).toContain("union all");
+      expect(querySql).toContain("from message_request");
+      expect(querySql).toContain("= 'warmup'");
+      expect(querySql).toContain("null::numeric as cost_usd");
+    } else {
+      expect(querySql).not.toContain("union all");
+      expect(querySql).not.toContain("message_request");
+    }
+  });
 });
@@ -0,0 +1,50 @@
+import { beforeEach, describe, expect, test, vi } from "vitest";
+
+function targetRow(id: number, isEnabled: boolean) {
+  return {
+    id,
+    name: `target-${id}`,
+    providerType: "custom",
+    webhookUrl: "https://hooks.example.com",
+    telegramBotToken: null,
+    telegramChatId: null,
+    dingtalkSecret: null,
+    customTemplate: null,
+    customHeaders: null,
+    proxyUrl: null,
+    proxyFallbackToDirect: false,
+    isEnabled,
+    lastTestAt: null,
+    lastTestResult: null,
+    createdAt: new Date("2026-01-01T00:00:00.000Z"),
+    updatedAt: new Date("2026-01-01T00:00:00.000Z"),
+  };
+}
+
+function mockDb(rows: unknown[]) {
+  const query: any = Promise.resolve(rows);
+  query.from = vi.fn(() => query);
+  query.where = vi.fn(() => query);
+  query.orderBy = vi.fn(() => query);
+  const selectMock = vi.fn(() => query);
+
+  vi.doMock("@/drizzle/db", () => ({ db: { select: selectMock } }));
+  return { query };
+}
+
+describe("getEnabledWebhookTargets", () => {
+  beforeEach(() => {
+    vi.resetModules();
+  });
+
+  test("filters on is_enabled and maps rows to webhook targets", async () => {
+    const { query } = mockDb([targetRow(2, true)]);
+
+    const { getEnabledWebhookTargets } = await import("@/repository/webhook-targets");
+    const targets = await getEnabledWebhookTargets();
+
+    expect(query.where).toHaveBeenCalledTimes(1);
+    expect(targets).toHaveLength(1);
+    expect(targets[0]).toMatchObject({ id: 2, name: "target-2", isEnabled: true });
+  });
+});
@@ -0,0 +1,35 @@
+import { describe, expect, it } from "vitest";
+import { collectGroupUsage } from "@/lib/provider-groups/usage";
+
+describe("collectGroupUsage", () => {
+  it("unions provider and user groups with per-group counts", async () => {
+    const result = await collectGroupUsage({
+      findAllProvidersFresh: async () => [
+        { groupTag: "openai,claude" },
+        { groupTag: "claude" },
+        { groupTag: null },
+      ],
+      findAllUserProviderGroupValues: async () => [
+        { providerGroup: "claude，vip" },
+        { providerGroup: "default" },
+        { providerGroup: "" },
+      ],
+    });
+
+    expect(result).toEqual([
+      { name: "claude", providerCount: 2, userCount: 1 },
+      { name: "default", providerCount: 1, userCount: 2 },
+      { name: "openai", providerCount: 1, userCount: 0 },
+      { name: "vip", providerCount: 0, userCount: 1 },
+    ]);
+  });
+
+  it("counts a record once even when it repeats a group", async () => {
+    const result = await collectGroupUsage({
+      findAllProvidersFresh: async () => [{ groupTag: "a,a" }],
+      findAllUserProviderGroupValues: async () => [{ providerGroup: "a\na" }],
+    });
+
+    expect(result).toEqual([{ name: "a", providerCount: 1, userCount: 1 }]);
+  });
+});
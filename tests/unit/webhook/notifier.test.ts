@@ -1,3 +1,4 @@
+import { createHmac } from "node:crypto";
 import { afterEach, beforeEach, describe, expect, it, vi } from "vitest";
 import { WebhookNotifier } from "@/lib/webhook/notifier";
 import type { StructuredMessage } from "@/lib/webhook/types";
@@ -128,6 +129,32 @@ describe("WebhookNotifier", () => {
       expect(url.searchParams.get("sign")).toBeTruthy();
     });
 
+    it("should sign dingtalk url per the documented HMAC-SHA256 algorithm", async () => {
+      vi.spyOn(Date, "now").mockReturnValue(1700000000000);
+
+      mockFetch.mockResolvedValue({
+        ok: true,
+        json: () => Promise.resolve({ errcode: 0, errmsg: "ok" }),
+      });
+
+      const notifier = new WebhookNotifier({
+        providerType: "dingtalk",
+        webhookUrl: "https://oapi.dingtalk.com/robot/send?access_token=token",
+        dingtalkSecret: "SEC000000000000000000000",
+      });
+
+      await notifier.send(createMessage());
+
+      // 官方算法：base64(HmacSHA256(secret, `${timestamp}\n${secret}`))，再做 URL 编码
+      const expectedSign = createHmac("sha256", "SEC000000000000000000000")
+        .update("1700000000000\nSEC000000000000000000000")
+        .digest("base64");
+      const calledUrl = String(mockFetch.mock.calls[0]?.[0]);
+
+      expect(calledUrl).toContain(`sign=${encodeURIComponent(expectedSign)}`);
+      expect(new URL(calledUrl).searchParams.get("sign")).toBe(expectedSign);
+    });
+
     it("should send telegram message to bot endpoint", async () => {
       mockFetch.mockResolvedValue({
         ok: true,
@@ -153,6 +180,32 @@ describe("WebhookNotifier", () => {
       expect(body.parse_mode).toBe("HTML");
     });
 
+    it("should surface telegram error description on non-2xx responses", async () => {
+      mockFetch.mockResolvedValue({
+        ok: false,
+        status: 400,
+        statusText: "Bad Request",
+        text: () =>
+          Promise.resolve(
+            JSON.stringify({
+              ok: false,
+              error_code: 400,
+              description: "Bad Request: chat not found",
+            })
+          ),
+      });
+
+      const notifier = new WebhookNotifier(
+        { providerType: "telegram", telegramBotToken: "token", telegramChatId: "123" },
+        { maxRetries: 1 }
+      );
+
+      const result = await notifier.send(createMessage());
+
+      expect(result.success).toBe(false);
+      expect(result.error).toBe("Telegram API Error (HTTP 400): Bad Request: chat not found");
+    });
+
     it("should treat custom webhook as success without parsing json", async () => {
       const arrayBuffer = vi.fn(async () => new ArrayBuffer(0));
       mockFetch.mockResolvedValue({
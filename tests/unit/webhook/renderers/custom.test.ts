@@ -34,6 +34,30 @@ describe("CustomRenderer", () => {
     expect(body.meta.when).toContain("2025-01-02T12:00:00.000Z");
   });
 
+  it("should render header placeholders and blank out unknown keys", () => {
+    const renderer = new CustomRenderer(
+      { text: "{{title}}|{{missing_key}}|{{ level }}" },
+      { "X-Title": "{{title}}", "X-Unknown": "v={{nope}}" }
+    );
+
+    const result = renderer.render(message);
+
+    const body = JSON.parse(result.body) as any;
+    expect(body.text).toBe("测试标题||info");
+    expect(result.headers).toEqual({ "X-Title": "测试标题", "X-Unknown": "v=" });
+  });
+
+  it("should not re-interpolate placeholders inside substituted values", () => {
+    const renderer = new CustomRenderer({ text: "{{last_error}}" }, null);
+
+    const result = renderer.render(message, {
+      notificationType: "circuit_breaker",
+      data: { lastError: "bad {{title}}" },
+    });
+
+    expect((JSON.parse(result.body) as any).text).toBe("bad {{title}}");
+  });
+
   it("should use templateOverride when provided", () => {
     const renderer = new CustomRenderer({ foo: "{{title}}" }, null);
 